@@ -21,6 +21,8 @@ var (
 	Appath string // 当前路径 // @suppress SpellCheckingInspection
 	Dbg    bool   // Debug 模式
 
+	CmdSets []string // 命令行 --set key.path=value 指定的配置覆盖项
+
 	CstSh = time.FixedZone("CST", 8*3600) // 东八区
 
 	PublicKeyByt         []byte // 公钥
@@ -9,6 +9,7 @@ import (
 	loadIni2 "helay.net/go/utils/v3/config/loadIni"
 	loadJson2 "helay.net/go/utils/v3/config/loadJson"
 	"helay.net/go/utils/v3/config/loadYaml"
+	"helay.net/go/utils/v3/config/validate"
 )
 
 var (
@@ -19,7 +20,7 @@ var (
 	}
 )
 
-// Load 载入配置文件
+// Load 载入配置文件，载入后按 valid tag 校验，不通过时 panic 并列出全部错误项
 func Load[T any](i T) {
 	ext := filepath.Ext(config.Cpath)
 	var err error
@@ -27,6 +28,7 @@ func Load[T any](i T) {
 	if ok {
 		delete(loadFunc, ext)
 		if err = loadFirst(i); err == nil {
+			mustValid(i)
 			return
 		}
 		log.Printf("默认配置解析器解析失败，尝试其他解析器 %v\n", err)
@@ -34,8 +36,15 @@ func Load[T any](i T) {
 	for _, v := range loadFunc {
 		err = v(i)
 		if err == nil {
+			mustValid(i)
 			return
 		}
 	}
 	panic(fmt.Errorf("解析配置文件失败 %v", err))
 }
+
+func mustValid(i any) {
+	if err := validate.Struct(i); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,258 @@
+package loadLayer
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fieldName 配置项的标准名称，依次取 yaml、json、ini tag，都没有时使用小写的字段名
+func fieldName(f reflect.StructField) string {
+	for _, key := range []string{"yaml", "json", "ini"} {
+		name, _, _ := strings.Cut(f.Tag.Get(key), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return strings.ToLower(f.Name)
+}
+
+// fieldMatch 判断路径片段是否对应该字段，yaml、json、ini tag 和字段名任意一个匹配即可，大小写不敏感
+func fieldMatch(f reflect.StructField, seg string) bool {
+	if strings.EqualFold(f.Name, seg) {
+		return true
+	}
+	for _, key := range []string{"yaml", "json", "ini"} {
+		name, _, _ := strings.Cut(f.Tag.Get(key), ",")
+		if name != "" && name != "-" && strings.EqualFold(name, seg) {
+			return true
+		}
+	}
+	return false
+}
+
+// ignored 所有 tag 都为 - 的字段不参与配置
+func ignored(f reflect.StructField) bool {
+	if !f.IsExported() {
+		return true
+	}
+	for _, key := range []string{"yaml", "json", "ini"} {
+		if f.Tag.Get(key) == "-" {
+			return true
+		}
+	}
+	return false
+}
+
+// inline 匿名嵌入或者 yaml inline 的结构体，字段展开到上一级
+func inline(f reflect.StructField) bool {
+	if derefType(f.Type).Kind() != reflect.Struct || leafType(f.Type) {
+		return false
+	}
+	if _, opts, _ := strings.Cut(f.Tag.Get("yaml"), ","); strings.Contains(opts, "inline") {
+		return true
+	}
+	return f.Anonymous && f.Tag.Get("yaml") == "" && f.Tag.Get("json") == ""
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+var (
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	yamlUnmarshalerType = reflect.TypeFor[yaml.Unmarshaler]()
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+	timeType            = reflect.TypeFor[time.Time]()
+)
+
+// leafType 自定义了反序列化的结构体当作单个值处理，不再向下展开
+func leafType(t reflect.Type) bool {
+	t = derefType(t)
+	if t.Kind() != reflect.Struct {
+		return true
+	}
+	if t == timeType {
+		return true
+	}
+	pt := reflect.PointerTo(t)
+	return pt.Implements(textUnmarshalerType) || pt.Implements(yamlUnmarshalerType) || pt.Implements(jsonUnmarshalerType)
+}
+
+// walkFields 遍历结构体字段，展开内嵌结构体，fn 返回 false 时停止遍历
+func walkFields(t reflect.Type, fn func(index []int, f reflect.StructField) bool) bool {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if ignored(f) && !f.Anonymous {
+			continue
+		}
+		if inline(f) {
+			ok := walkFields(derefType(f.Type), func(idx []int, sf reflect.StructField) bool {
+				return fn(append([]int{i}, idx...), sf)
+			})
+			if !ok {
+				return false
+			}
+			continue
+		}
+		if ignored(f) {
+			continue
+		}
+		if !fn([]int{i}, f) {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldByIndex 按索引获取字段，途经的空指针会自动初始化
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 {
+			v = indirect(v)
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// indirect 解引用指针，空指针会初始化
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// resolve 按路径片段找到目标值，返回标准路径。map 类型的下一段作为 key，此时返回 map 和 key。
+// 先按类型校验完整路径，通过后才初始化途经的空指针和 map，路径无效时不会修改配置。
+func resolve(root reflect.Value, segs []string) (target reflect.Value, mapKey *reflect.Value, path []string, err error) {
+	steps, path, err := resolvePath(root.Type(), segs)
+	if err != nil {
+		return root, nil, nil, err
+	}
+	v := root
+	for _, index := range steps {
+		v = fieldByIndex(indirect(v), index)
+	}
+	if len(steps) == len(segs) {
+		return v, nil, path, nil
+	}
+	// 最后一段为 map 的 key
+	v = indirect(v)
+	if v.IsNil() {
+		v.Set(reflect.MakeMap(v.Type()))
+	}
+	key := reflect.New(v.Type().Key()).Elem()
+	key.SetString(segs[len(segs)-1])
+	return v, &key, path, nil
+}
+
+// resolvePath 按类型解析路径，返回每一段对应的字段索引和标准路径，最后一段为 map 的 key 时不返回索引
+func resolvePath(t reflect.Type, segs []string) (steps [][]int, path []string, err error) {
+	for i, seg := range segs {
+		t = derefType(t)
+		switch t.Kind() {
+		case reflect.Struct:
+			var found []int
+			walkFields(t, func(index []int, f reflect.StructField) bool {
+				if fieldMatch(f, seg) {
+					found = index
+					path = append(path, fieldName(f))
+					return false
+				}
+				return true
+			})
+			if found == nil {
+				return nil, nil, fmt.Errorf("配置项 %s 不存在", strings.Join(segs[:i+1], "."))
+			}
+			t = t.FieldByIndex(found).Type
+			if i < len(segs)-1 && leafType(t) && derefType(t).Kind() != reflect.Map {
+				return nil, nil, fmt.Errorf("配置项 %s 不能再包含子项", strings.Join(segs[:i+1], "."))
+			}
+			steps = append(steps, found)
+		case reflect.Map:
+			if i != len(segs)-1 {
+				return nil, nil, fmt.Errorf("配置项 %s 只支持设置 map 的一级 key", strings.Join(segs, "."))
+			}
+			if t.Key().Kind() != reflect.String {
+				return nil, nil, fmt.Errorf("配置项 %s 的 key 不是字符串类型", strings.Join(segs[:i], "."))
+			}
+			path = append(path, seg)
+		default:
+			return nil, nil, fmt.Errorf("配置项 %s 不能再包含子项", strings.Join(segs[:i], "."))
+		}
+	}
+	return steps, path, nil
+}
+
+// setValue 将字符串转换为目标类型后赋值
+//   - 字符串直接赋值
+//   - 实现了 encoding.TextUnmarshaler 的类型使用 UnmarshalText
+//   - 切片在值不是 [ 开头时按逗号拆分
+//   - 其他类型（数字、布尔、time.Duration、map、结构体）按 yaml 解析，因此也支持 json 写法
+func setValue(v reflect.Value, raw string) error {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return setValue(v.Elem(), raw)
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+		return nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			v.SetBytes([]byte(raw))
+			return nil
+		}
+		if trimmed := strings.TrimSpace(raw); !strings.HasPrefix(trimmed, "[") {
+			items := reflect.MakeSlice(v.Type(), 0, 0)
+			if trimmed != "" {
+				for _, item := range strings.Split(raw, ",") {
+					elem := reflect.New(v.Type().Elem()).Elem()
+					if err := setValue(elem, strings.TrimSpace(item)); err != nil {
+						return err
+					}
+					items = reflect.Append(items, elem)
+				}
+			}
+			v.Set(items)
+			return nil
+		}
+	}
+	ptr := reflect.New(v.Type())
+	if err := yaml.Unmarshal([]byte(raw), ptr.Interface()); err != nil {
+		return err
+	}
+	v.Set(ptr.Elem())
+	return nil
+}
+
+// assign 赋值到普通字段或者 map 的 key 上
+func assign(target reflect.Value, mapKey *reflect.Value, raw string) error {
+	if mapKey == nil {
+		return setValue(target, raw)
+	}
+	elem := reflect.New(target.Type().Elem()).Elem()
+	if err := setValue(elem, raw); err != nil {
+		return err
+	}
+	target.SetMapIndex(*mapKey, elem)
+	return nil
+}
@@ -0,0 +1,41 @@
+package loadLayer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testSection struct {
+	Host string            `yaml:"host"`
+	Tags map[string]string `yaml:"tags"`
+}
+
+type testConfig struct {
+	DB    *testSection `yaml:"db"`
+	Cache *testSection `yaml:"cache"`
+}
+
+func TestResolveInvalidPathKeepsNil(t *testing.T) {
+	cfg := &testConfig{}
+	root := reflect.ValueOf(cfg).Elem()
+	for _, p := range []string{"db.port", "db.host.x", "db.tags.a.b"} {
+		if _, _, _, err := resolve(root, strings.Split(p, ".")); err == nil {
+			t.Fatalf("resolve(%s) accepted", p)
+		}
+	}
+	if cfg.DB != nil {
+		t.Fatalf("invalid path allocated section: %+v", cfg.DB)
+	}
+
+	target, key, path, err := resolve(root, []string{"Cache", "TAGS", "env"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Cache == nil || cfg.Cache.Tags == nil || key == nil || key.String() != "env" || strings.Join(path, ".") != "cache.tags.env" {
+		t.Fatalf("target = %v, key = %v, path = %v", target, key, path)
+	}
+	if cfg.DB != nil {
+		t.Fatal("unrelated section allocated")
+	}
+}
@@ -0,0 +1,255 @@
+package loadLayer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gopkg.in/ini.v1"
+	"gopkg.in/yaml.v3"
+	"helay.net/go/utils/v3/config"
+	"helay.net/go/utils/v3/config/validate"
+	"helay.net/go/utils/v3/tools"
+	"helay.net/go/utils/v3/tools/fileinclude"
+)
+
+// Options 分层加载配置
+type Options struct {
+	File       string   // 配置文件路径，默认 config.Cpath，为空时跳过文件层
+	EnvPrefix  string   // 环境变量前缀，默认 APP
+	DisableEnv bool     // 不读取环境变量
+	Sets       []string // key.path=value 格式的覆盖项，默认 config.CmdSets
+}
+
+// Load 按 结构体 default tag、配置文件、环境变量、命令行 --set 的顺序加载配置，后面的覆盖前面的。
+// 加载完成后按 valid tag 校验，失败时 panic，与其他 loadXxx 包保持一致。
+func Load(i any) *Report {
+	report, err := LoadWithOptions(i, Options{})
+	if err != nil {
+		panic(fmt.Errorf("解析配置文件失败 %v", err))
+	}
+	return report
+}
+
+// LoadWithOptions 分层加载配置，i 必须是结构体指针。
+// 环境变量名为 前缀_ 加上用 __ 连接的配置路径，如 APP_SERVER__TLS__ENABLE 对应 server.tls.enable，
+// 路径片段可以匹配字段的 yaml、json、ini tag 或字段名，大小写不敏感。
+func LoadWithOptions(i any, opt Options) (*Report, error) {
+	rv := reflect.ValueOf(i)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || derefType(rv.Type()).Kind() != reflect.Struct {
+		return nil, fmt.Errorf("配置对象必须是结构体指针")
+	}
+	root := indirect(rv)
+	report := newReport()
+
+	if err := applyDefaults(root, nil, report); err != nil {
+		return nil, err
+	}
+
+	file := tools.Ternary(opt.File == "", config.Cpath, opt.File)
+	if file != "" {
+		if err := loadFile(i, root, tools.Fileabs(file), report); err != nil {
+			return nil, err
+		}
+	}
+
+	if !opt.DisableEnv {
+		prefix := strings.ToUpper(tools.Ternary(opt.EnvPrefix == "", "APP", opt.EnvPrefix)) + "_"
+		for _, kv := range os.Environ() {
+			name, val, _ := strings.Cut(kv, "=")
+			if !strings.HasPrefix(name, prefix) || len(name) == len(prefix) {
+				continue
+			}
+			segs := strings.Split(strings.ToLower(name[len(prefix):]), "__")
+			target, key, path, err := resolve(root, segs)
+			if err != nil {
+				// 同前缀的环境变量可能不是给配置使用的，记录下来便于排查
+				report.unknown = append(report.unknown, name)
+				continue
+			}
+			if err = assign(target, key, val); err != nil {
+				return nil, fmt.Errorf("环境变量 %s 解析失败 %w", name, err)
+			}
+			report.set(path, LayerEnv, name)
+		}
+	}
+
+	sets := opt.Sets
+	if sets == nil {
+		sets = config.CmdSets
+	}
+	for _, kv := range sets {
+		k, val, ok := strings.Cut(kv, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			return nil, fmt.Errorf("参数 --set %s 格式错误，应为 key.path=value", kv)
+		}
+		target, key, path, err := resolve(root, strings.Split(k, "."))
+		if err != nil {
+			return nil, fmt.Errorf("参数 --set %s 错误 %w", kv, err)
+		}
+		if err = assign(target, key, val); err != nil {
+			return nil, fmt.Errorf("参数 --set %s 解析失败 %w", kv, err)
+		}
+		report.set(path, LayerFlag, "--set "+kv)
+	}
+	// 全部层合并后再按 valid tag 校验，一次返回所有不通过的配置项
+	if err := validate.Struct(i); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// applyDefaults 为零值字段设置 default tag 中的默认值，空指针结构体不会初始化
+func applyDefaults(v reflect.Value, path []string, report *Report) error {
+	var err error
+	walkFields(v.Type(), func(index []int, f reflect.StructField) bool {
+		fv, ok := fieldByIndexNoAlloc(v, index)
+		if !ok {
+			return true
+		}
+		p := append(path[:len(path):len(path)], fieldName(f))
+		if def, has := f.Tag.Lookup("default"); has && fv.IsZero() {
+			if err = setValue(fv, def); err != nil {
+				err = fmt.Errorf("配置项 %s 默认值 %s 解析失败 %w", strings.Join(p, "."), def, err)
+				return false
+			}
+			report.set(p, LayerDefault, def)
+			return true
+		}
+		if !leafType(f.Type) {
+			if fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					return true
+				}
+				fv = fv.Elem()
+			}
+			if err = applyDefaults(fv, p, report); err != nil {
+				return false
+			}
+		}
+		return true
+	})
+	return err
+}
+
+// fieldByIndexNoAlloc 按索引获取字段，途经空指针时返回 false
+func fieldByIndexNoAlloc(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 {
+			for v.Kind() == reflect.Pointer {
+				if v.IsNil() {
+					return v, false
+				}
+				v = v.Elem()
+			}
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// loadFile 解析配置文件，同时把文件中出现的 key 记录到来源报告中
+func loadFile(i any, root reflect.Value, file string, report *Report) error {
+	var tree map[string]any
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".ini":
+		f, err := ini.Load(file)
+		if err != nil {
+			return fmt.Errorf("打开配置文件失败 %w", err)
+		}
+		if err = f.MapTo(i); err != nil {
+			return err
+		}
+		tree = iniTree(f)
+	case ".json":
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("打开配置文件失败 %w", err)
+		}
+		if err = json.Unmarshal(b, i); err != nil {
+			return err
+		}
+		_ = json.Unmarshal(b, &tree)
+	default:
+		// yaml 以及其他后缀，与 loadYaml 一样支持 #include
+		b, err := yamlInclude(file)
+		if err != nil {
+			return err
+		}
+		if err = yaml.Unmarshal(b, i); err != nil {
+			return err
+		}
+		_ = yaml.Unmarshal(b, &tree)
+	}
+	markFile(root.Type(), tree, nil, file, report)
+	return nil
+}
+
+// ReadFile 读取配置文件内容，yaml 文件会展开 #include，用于判断配置文件是否发生变化
+func ReadFile(file string) ([]byte, error) {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".ini", ".json":
+		return os.ReadFile(file)
+	default:
+		return yamlInclude(file)
+	}
+}
+
+func yamlInclude(file string) ([]byte, error) {
+	reader, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("打开配置文件失败 %w", err)
+	}
+	defer func() { _ = reader.Close() }()
+	inc := fileinclude.NewProcessor()
+	inc.SetPrefix("#include ")
+	inc.FromReader(reader, filepath.Dir(file))
+	rd, err := inc.ToReader()
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(rd)
+}
+
+// iniTree 将 ini 转换为两层 map，默认分区的 key 放在顶层
+func iniTree(f *ini.File) map[string]any {
+	tree := make(map[string]any)
+	for _, sec := range f.Sections() {
+		keys := make(map[string]any)
+		for _, k := range sec.Keys() {
+			keys[k.Name()] = k.Value()
+		}
+		if sec.Name() == ini.DefaultSection {
+			for k, v := range keys {
+				tree[k] = v
+			}
+			continue
+		}
+		tree[sec.Name()] = keys
+	}
+	return tree
+}
+
+// markFile 按结构体定义遍历文件内容，文件中出现的叶子节点记录为文件来源
+func markFile(t reflect.Type, tree map[string]any, path []string, file string, report *Report) {
+	t = derefType(t)
+	for key, val := range tree {
+		walkFields(t, func(_ []int, f reflect.StructField) bool {
+			if !fieldMatch(f, key) {
+				return true
+			}
+			p := append(path[:len(path):len(path)], fieldName(f))
+			if sub, ok := val.(map[string]any); ok && !leafType(f.Type) {
+				markFile(f.Type, sub, p, file, report)
+			} else {
+				report.set(p, LayerFile, file)
+			}
+			return false
+		})
+	}
+}
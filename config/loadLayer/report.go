@@ -0,0 +1,84 @@
+package loadLayer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Layer 配置值的来源层
+type Layer string
+
+const (
+	LayerDefault Layer = "default" // 结构体 default tag
+	LayerFile    Layer = "file"    // 配置文件
+	LayerEnv     Layer = "env"     // 环境变量
+	LayerFlag    Layer = "flag"    // 命令行 --set
+)
+
+// Source 配置值来源
+type Source struct {
+	Layer Layer  // 来源层
+	From  string // 默认值、文件路径、环境变量名或者命令行参数
+}
+
+// Report 记录每个配置项最终生效值的来源，key 为 . 连接的配置路径，如 server.tls.enable
+type Report struct {
+	sources map[string]Source
+	unknown []string // 带有前缀但没有对应配置项的环境变量
+}
+
+func newReport() *Report {
+	return &Report{sources: make(map[string]Source)}
+}
+
+func (r *Report) set(path []string, layer Layer, from string) {
+	p := strings.Join(path, ".")
+	// 上层覆盖父节点时，子节点之前的来源失效
+	for k := range r.sources {
+		if strings.HasPrefix(k, p+".") {
+			delete(r.sources, k)
+		}
+	}
+	r.sources[p] = Source{Layer: layer, From: from}
+}
+
+// Source 查询配置项来源，未在任何一层设置的配置项返回 false。
+// 父节点整体被设置时（如 map 或切片），子路径返回父节点的来源。
+func (r *Report) Source(path string) (Source, bool) {
+	for p := path; ; {
+		if s, ok := r.sources[p]; ok {
+			return s, true
+		}
+		i := strings.LastIndex(p, ".")
+		if i < 0 {
+			return Source{}, false
+		}
+		p = p[:i]
+	}
+}
+
+// Paths 返回所有被设置过的配置路径，按字典序排列
+func (r *Report) Paths() []string {
+	paths := make([]string, 0, len(r.sources))
+	for p := range r.sources {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Unknown 返回带有配置前缀、但没有匹配到配置项的环境变量
+func (r *Report) Unknown() []string {
+	return r.unknown
+}
+
+// String 每行输出一个配置项及其来源，不包含配置值，避免泄露敏感信息
+func (r *Report) String() string {
+	var sb strings.Builder
+	for _, p := range r.Paths() {
+		s := r.sources[p]
+		_, _ = fmt.Fprintf(&sb, "%s\t%s\t%s\n", p, s.Layer, s.From)
+	}
+	return sb.String()
+}
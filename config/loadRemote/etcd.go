@@ -0,0 +1,71 @@
+package loadRemote
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// EtcdClient etcd 客户端中用到的方法，*clientv3.Client 直接满足
+type EtcdClient interface {
+	Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error)
+	Watch(ctx context.Context, key string, opts ...clientv3.OpOption) clientv3.WatchChan
+}
+
+type etcdProvider struct {
+	client EtcdClient
+	key    string
+	rev    atomic.Int64 // 最近一次读取或者监听到的修订版本，监听从下一个版本开始
+}
+
+// Etcd 从 etcd 的 key 中读取配置文档，客户端可以通过 db/etcd 创建
+func Etcd(client EtcdClient, key string) Provider {
+	return &etcdProvider{client: client, key: key}
+}
+
+func (p *etcdProvider) Name() string {
+	return "etcd:" + p.key
+}
+
+func (p *etcdProvider) Get(ctx context.Context) ([]byte, error) {
+	resp, err := p.client.Get(ctx, p.key)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Header != nil {
+		p.rev.Store(resp.Header.Revision)
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, fmt.Errorf("配置 %s 不存在", p.key)
+	}
+	return resp.Kvs[0].Value, nil
+}
+
+// Watch 删除 key 时不会清空配置，继续使用当前配置
+// 从上次读取的下一个修订版本开始监听，读取和监听之间的修改不会遗漏。
+func (p *etcdProvider) Watch(ctx context.Context, fn func(data []byte)) error {
+	// 监听断开时 etcd 会关闭 channel，需要由调用方重新监听
+	wctx, cancel := context.WithCancel(clientv3.WithRequireLeader(ctx))
+	defer cancel()
+	var opts []clientv3.OpOption
+	if rev := p.rev.Load(); rev > 0 {
+		opts = append(opts, clientv3.WithRev(rev+1))
+	}
+	for resp := range p.client.Watch(wctx, p.key, opts...) {
+		if err := resp.Err(); err != nil {
+			return err
+		}
+		for _, ev := range resp.Events {
+			p.rev.Store(ev.Kv.ModRevision)
+			if ev.Type == clientv3.EventTypePut {
+				fn(ev.Kv.Value)
+			}
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return fmt.Errorf("监听配置 %s 已断开", p.key)
+}
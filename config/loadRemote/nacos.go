@@ -0,0 +1,63 @@
+package loadRemote
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+// NacosClient nacos 配置中心客户端中用到的方法，config_client.IConfigClient 直接满足，可以通过 db/nacos 的 NewConfigClient 创建
+type NacosClient interface {
+	GetConfig(param vo.ConfigParam) (string, error)
+	ListenConfig(param vo.ConfigParam) error
+	CancelListenConfig(param vo.ConfigParam) error
+}
+
+type nacosProvider struct {
+	client NacosClient
+	dataId string
+	group  string
+}
+
+// Nacos 从 nacos 配置中心读取配置文档，group 为空时使用 DEFAULT_GROUP
+func Nacos(client NacosClient, dataId, group string) Provider {
+	if group == "" {
+		group = "DEFAULT_GROUP"
+	}
+	return &nacosProvider{client: client, dataId: dataId, group: group}
+}
+
+func (p *nacosProvider) Name() string {
+	return "nacos:" + p.group + "/" + p.dataId
+}
+
+func (p *nacosProvider) Get(_ context.Context) ([]byte, error) {
+	content, err := p.client.GetConfig(vo.ConfigParam{DataId: p.dataId, Group: p.group})
+	if err != nil {
+		return nil, err
+	}
+	if content == "" {
+		return nil, fmt.Errorf("配置 %s 不存在", p.Name())
+	}
+	return []byte(content), nil
+}
+
+// Watch nacos 客户端自带重连和长轮询，这里注册监听后等待 ctx 结束
+func (p *nacosProvider) Watch(ctx context.Context, fn func(data []byte)) error {
+	err := p.client.ListenConfig(vo.ConfigParam{
+		DataId: p.dataId,
+		Group:  p.group,
+		OnChange: func(_, _, _, data string) {
+			if data != "" {
+				fn([]byte(data))
+			}
+		},
+	})
+	if err != nil {
+		return err
+	}
+	<-ctx.Done()
+	_ = p.client.CancelListenConfig(vo.ConfigParam{DataId: p.dataId, Group: p.group})
+	return ctx.Err()
+}
@@ -0,0 +1,301 @@
+package loadRemote
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"gopkg.in/ini.v1"
+	"gopkg.in/yaml.v3"
+	"helay.net/go/utils/v3/config/loadWatch"
+	"helay.net/go/utils/v3/config/validate"
+	"helay.net/go/utils/v3/logger/ulogs"
+	"helay.net/go/utils/v3/logger/zaploger"
+	"helay.net/go/utils/v3/tools"
+	"helay.net/go/utils/v3/tools/backoff"
+)
+
+// Provider 远程配置来源，读取一份完整的 yaml、json 或 ini 文档
+type Provider interface {
+	// Name 配置来源的名称，如 etcd:/app/config.yaml，用于日志、缓存文件名和推断文档格式
+	Name() string
+	// Get 读取配置文档
+	Get(ctx context.Context) ([]byte, error)
+	// Watch 监听配置变化，每次变化调用 fn，阻塞直到 ctx 结束或者监听异常
+	Watch(ctx context.Context, fn func(data []byte)) error
+}
+
+// Options 远程配置参数
+type Options[T any] struct {
+	Provider      Provider       // 配置来源
+	Format        string         // 文档格式 yaml、json、ini，为空时按 Provider.Name 的后缀判断，默认 yaml
+	CacheFile     string         // 本地缓存文件，远程不可用时从这里加载，默认 runtime/remote_config/ 下按来源命名的文件
+	DisableCache  bool           // 不使用本地缓存
+	Timeout       time.Duration  // 首次读取远程配置的超时时间，默认 10 秒
+	RetryInterval time.Duration  // 监听断开后重试的最大间隔，默认 30 秒
+	Validate      func(*T) error // 新配置的校验函数，校验失败的配置不会生效
+}
+
+// Remote 远程配置。
+// 启动时从远程读取配置，读取失败时使用上次成功加载后写入的本地缓存；
+// Run 之后监听远程变化，新配置解析、校验通过后原子替换快照，写入缓存并通知订阅者。
+// 结构体与 loadAuto 使用的相同，可以配合 loadWatch.Subscribe 订阅配置段的变化。
+type Remote[T any] struct {
+	opt       Options[T]
+	format    string
+	current   atomic.Pointer[T]
+	fromCache atomic.Bool
+
+	mu     sync.Mutex // 串行化更新
+	digest uint64     // 当前配置文档的摘要
+
+	subMu sync.RWMutex
+	subs  []func(old, new *T) error
+
+	Loger *zaploger.Logger
+}
+
+// New 加载初始配置，远程和本地缓存都不可用时返回错误
+func New[T any](ctx context.Context, opt Options[T]) (*Remote[T], error) {
+	if opt.Provider == nil {
+		return nil, errors.New("远程配置来源不能为空")
+	}
+	opt.Timeout = tools.AutoTimeDuration(opt.Timeout, time.Second, 10*time.Second)
+	opt.RetryInterval = tools.AutoTimeDuration(opt.RetryInterval, time.Second, 30*time.Second)
+	if opt.CacheFile == "" {
+		opt.CacheFile = filepath.Join("runtime", "remote_config", cacheName(opt.Provider.Name()))
+	}
+	opt.CacheFile = tools.Fileabs(opt.CacheFile)
+	r := &Remote[T]{opt: opt, format: format(opt.Format, opt.Provider.Name())}
+
+	tctx, cancel := context.WithTimeout(ctx, opt.Timeout)
+	data, err := opt.Provider.Get(tctx)
+	cancel()
+	if err == nil {
+		var cfg *T
+		if cfg, err = r.decode(data); err == nil {
+			r.current.Store(cfg)
+			r.digest = xxhash.Sum64(data)
+			r.writeCache(data)
+			return r, nil
+		}
+	}
+	if opt.DisableCache {
+		return nil, fmt.Errorf("读取远程配置 %s 失败 %w", opt.Provider.Name(), err)
+	}
+	r.error("读取远程配置失败，使用本地缓存", err, "来源", opt.Provider.Name(), "缓存", opt.CacheFile)
+	cached, cerr := os.ReadFile(opt.CacheFile)
+	if cerr != nil {
+		return nil, fmt.Errorf("读取远程配置 %s 失败 %w，读取本地缓存失败 %w", opt.Provider.Name(), err, cerr)
+	}
+	cfg, cerr := r.decode(cached)
+	if cerr != nil {
+		return nil, fmt.Errorf("读取远程配置 %s 失败 %w，本地缓存无效 %w", opt.Provider.Name(), err, cerr)
+	}
+	r.current.Store(cfg)
+	r.digest = xxhash.Sum64(cached)
+	r.fromCache.Store(true)
+	return r, nil
+}
+
+// Get 获取当前配置快照，不要修改返回的对象
+func (r *Remote[T]) Get() *T {
+	return r.current.Load()
+}
+
+// FromCache 当前配置是否来自本地缓存，远程恢复并读取成功后变为 false
+func (r *Remote[T]) FromCache() bool {
+	return r.fromCache.Load()
+}
+
+// OnChange 订阅整个配置的变化，每次配置替换后都会调用
+func (r *Remote[T]) OnChange(fn func(old, new *T) error) {
+	r.subMu.Lock()
+	defer r.subMu.Unlock()
+	r.subs = append(r.subs, fn)
+}
+
+// Run 监听远程配置变化，直到 ctx 结束。
+// 监听中断后按退避间隔重试，重新监听前会先读取一次，避免遗漏中断期间的修改。
+func (r *Remote[T]) Run(ctx context.Context) {
+	retry := backoff.NewBackoff(backoff.Exponential, time.Second, r.opt.RetryInterval, 2.0)
+	if r.FromCache() {
+		if err := r.refresh(ctx); err != nil {
+			r.error("读取远程配置失败，继续使用当前配置", err, "来源", r.opt.Provider.Name())
+		}
+	}
+	for {
+		err := r.opt.Provider.Watch(ctx, func(data []byte) {
+			if err := r.apply(data); err != nil {
+				r.error("远程配置更新失败，继续使用当前配置", err, "来源", r.opt.Provider.Name())
+			}
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			r.error("监听远程配置失败", err, "来源", r.opt.Provider.Name())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retry.Next()):
+		}
+		if err = r.refresh(ctx); err != nil {
+			r.error("读取远程配置失败，继续使用当前配置", err, "来源", r.opt.Provider.Name())
+		} else {
+			retry.Reset()
+		}
+	}
+}
+
+// refresh 主动读取一次远程配置
+func (r *Remote[T]) refresh(ctx context.Context) error {
+	tctx, cancel := context.WithTimeout(ctx, r.opt.Timeout)
+	defer cancel()
+	data, err := r.opt.Provider.Get(tctx)
+	if err != nil {
+		return err
+	}
+	return r.apply(data)
+}
+
+// apply 内容变化时解析并替换配置
+func (r *Remote[T]) apply(data []byte) error {
+	digest := xxhash.Sum64(data)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if digest == r.digest {
+		r.fromCache.Store(false)
+		return nil
+	}
+	cfg, err := r.decode(data)
+	if err != nil {
+		return err
+	}
+	r.digest = digest
+	r.fromCache.Store(false)
+	r.writeCache(data)
+	old := r.current.Swap(cfg)
+	r.log("远程配置已更新", "来源", r.opt.Provider.Name())
+	r.notify(old, cfg)
+	return nil
+}
+
+// decode 按格式解析并校验配置
+func (r *Remote[T]) decode(data []byte) (*T, error) {
+	cfg := new(T)
+	var err error
+	switch r.format {
+	case "json":
+		err = json.Unmarshal(data, cfg)
+	case "ini":
+		err = ini.MapTo(cfg, data)
+	default:
+		err = yaml.Unmarshal(data, cfg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("解析远程配置失败 %w", err)
+	}
+	if err = validate.Struct(cfg); err != nil {
+		return nil, err
+	}
+	if v, ok := any(cfg).(loadWatch.Validator); ok {
+		if err = v.Validate(); err != nil {
+			return nil, fmt.Errorf("配置校验失败 %w", err)
+		}
+	}
+	if r.opt.Validate != nil {
+		if err = r.opt.Validate(cfg); err != nil {
+			return nil, fmt.Errorf("配置校验失败 %w", err)
+		}
+	}
+	return cfg, nil
+}
+
+// writeCache 先写临时文件再替换，避免进程中断时留下不完整的缓存
+func (r *Remote[T]) writeCache(data []byte) {
+	if r.opt.DisableCache {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(r.opt.CacheFile), 0755); err != nil {
+		r.error("创建远程配置缓存目录失败", err, "缓存", r.opt.CacheFile)
+		return
+	}
+	tmp := r.opt.CacheFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		r.error("写入远程配置缓存失败", err, "缓存", r.opt.CacheFile)
+		return
+	}
+	if err := os.Rename(tmp, r.opt.CacheFile); err != nil {
+		r.error("写入远程配置缓存失败", err, "缓存", r.opt.CacheFile)
+	}
+}
+
+// notify 按订阅顺序通知，单个订阅者失败或 panic 不影响其他订阅者
+func (r *Remote[T]) notify(old, new *T) {
+	r.subMu.RLock()
+	subs := r.subs
+	r.subMu.RUnlock()
+	for _, fn := range subs {
+		func() {
+			defer func() {
+				if e := recover(); e != nil {
+					r.error("配置变更订阅处理异常", errors.New(fmt.Sprint(e)))
+				}
+			}()
+			if err := fn(old, new); err != nil {
+				r.error("配置变更订阅处理失败", err)
+			}
+		}()
+	}
+}
+
+// format 确定文档格式
+func format(f, name string) string {
+	f = strings.ToLower(strings.TrimPrefix(f, "."))
+	if f == "" {
+		f = strings.ToLower(strings.TrimPrefix(filepath.Ext(name), "."))
+	}
+	switch f {
+	case "json", "ini":
+		return f
+	default:
+		return "yaml"
+	}
+}
+
+// cacheName 将来源名称转换为可用的文件名
+func cacheName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|', ' ':
+			return '_'
+		}
+		return r
+	}, strings.Trim(name, "/"))
+}
+
+func (r *Remote[T]) error(title string, err error, args ...any) {
+	if r.Loger == nil {
+		ulogs.Error(append([]any{title, err}, args...)...)
+	} else {
+		r.Loger.Error(context.Background(), title, zaploger.Auto2Field(append([]any{"错误信息", err}, args...)...))
+	}
+}
+
+func (r *Remote[T]) log(title string, args ...any) {
+	if r.Loger == nil {
+		ulogs.Log(append([]any{title}, args...)...)
+	} else {
+		r.Loger.Info(context.Background(), title, zaploger.Auto2Field(args...))
+	}
+}
@@ -0,0 +1,220 @@
+package loadRemote
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+type testConfig struct {
+	Name string `yaml:"name"`
+	Port int    `yaml:"port"`
+}
+
+// fakeProvider 内存配置来源，Watch 从 updates 中读取变化
+type fakeProvider struct {
+	mu      sync.Mutex
+	data    []byte
+	err     error
+	updates chan []byte
+}
+
+func (p *fakeProvider) Name() string { return "fake:/app/config.yaml" }
+
+func (p *fakeProvider) Get(context.Context) ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.data, p.err
+}
+
+func (p *fakeProvider) Watch(ctx context.Context, fn func(data []byte)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case data := <-p.updates:
+			fn(data)
+		}
+	}
+}
+
+func TestRemoteUpdateAndCache(t *testing.T) {
+	cache := filepath.Join(t.TempDir(), "config.yaml")
+	p := &fakeProvider{data: []byte("name: a\nport: 1\n"), updates: make(chan []byte)}
+	r, err := New[testConfig](context.Background(), Options[testConfig]{
+		Provider:  p,
+		CacheFile: cache,
+		Validate: func(c *testConfig) error {
+			if c.Port <= 0 {
+				return errors.New("port")
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Get().Name != "a" || r.FromCache() {
+		t.Fatalf("config = %+v", r.Get())
+	}
+	changed := make(chan *testConfig, 1)
+	r.OnChange(func(_, n *testConfig) error {
+		changed <- n
+		return nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Run(ctx)
+
+	p.updates <- []byte("name: b\nport: 0\n") // 校验失败，不生效
+	p.updates <- []byte("name: c\nport: 2\n")
+	select {
+	case n := <-changed:
+		if n.Name != "c" {
+			t.Fatalf("changed = %+v", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no change notification")
+	}
+	if data, _ := os.ReadFile(cache); string(data) != "name: c\nport: 2\n" {
+		t.Fatalf("cache = %q", data)
+	}
+
+	// 远程不可用时使用本地缓存
+	p.mu.Lock()
+	p.err = errors.New("down")
+	p.mu.Unlock()
+	r2, err := New[testConfig](context.Background(), Options[testConfig]{Provider: p, CacheFile: cache})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r2.Get().Name != "c" || !r2.FromCache() {
+		t.Fatalf("cached config = %+v, %v", r2.Get(), r2.FromCache())
+	}
+}
+
+// fakeEtcd 记录每次 Watch 的起始版本
+type fakeEtcd struct {
+	rev     int64
+	value   string
+	watches chan int64
+	events  chan clientv3.WatchResponse
+}
+
+func (c *fakeEtcd) Get(_ context.Context, _ string, _ ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	return &clientv3.GetResponse{
+		Header: &etcdserverpb.ResponseHeader{Revision: c.rev},
+		Kvs:    []*mvccpb.KeyValue{{Value: []byte(c.value), ModRevision: c.rev}},
+	}, nil
+}
+
+func (c *fakeEtcd) Watch(_ context.Context, key string, opts ...clientv3.OpOption) clientv3.WatchChan {
+	c.watches <- clientv3.OpGet(key, opts...).Rev()
+	ch := make(chan clientv3.WatchResponse)
+	go func() {
+		defer close(ch)
+		for resp := range c.events {
+			if resp.Canceled {
+				return
+			}
+			ch <- resp
+		}
+	}()
+	return ch
+}
+
+func TestEtcdWatchFromRevision(t *testing.T) {
+	c := &fakeEtcd{rev: 5, value: "name: a", watches: make(chan int64, 2), events: make(chan clientv3.WatchResponse)}
+	p := Etcd(c, "/app/config.yaml")
+	if data, err := p.Get(context.Background()); err != nil || string(data) != "name: a" {
+		t.Fatalf("get = %q, %v", data, err)
+	}
+
+	got := make(chan string, 1)
+	done := make(chan error, 1)
+	go func() { done <- p.Watch(context.Background(), func(data []byte) { got <- string(data) }) }()
+	if rev := <-c.watches; rev != 6 {
+		t.Fatalf("watch rev = %d, want 6", rev)
+	}
+	c.events <- clientv3.WatchResponse{Events: []*clientv3.Event{{Type: clientv3.EventTypePut, Kv: &mvccpb.KeyValue{Value: []byte("name: b"), ModRevision: 9}}}}
+	if v := <-got; v != "name: b" {
+		t.Fatalf("watch data = %q", v)
+	}
+	c.events <- clientv3.WatchResponse{Canceled: true}
+	if err := <-done; err == nil {
+		t.Fatal("closed watch should return error")
+	}
+
+	// 重新监听从最后一个事件的下一个版本开始
+	go func() { done <- p.Watch(context.Background(), func([]byte) {}) }()
+	if rev := <-c.watches; rev != 10 {
+		t.Fatalf("rewatch rev = %d, want 10", rev)
+	}
+	c.events <- clientv3.WatchResponse{Canceled: true}
+	<-done
+}
+
+// fakeZK 每次 GetW 返回当前数据和新的 watch
+type fakeZK struct {
+	mu    sync.Mutex
+	data  string
+	watch chan zk.Event
+}
+
+func (c *fakeZK) set(data string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.data = data
+}
+
+func (c *fakeZK) Get(string) ([]byte, *zk.Stat, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return []byte(c.data), &zk.Stat{}, nil
+}
+
+func (c *fakeZK) GetW(string) ([]byte, *zk.Stat, <-chan zk.Event, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.watch = make(chan zk.Event, 1)
+	return []byte(c.data), &zk.Stat{}, c.watch, nil
+}
+
+func (c *fakeZK) fire(ev zk.Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.watch <- ev
+}
+
+func TestZookeeperWatchAppliesRearmData(t *testing.T) {
+	c := &fakeZK{data: "v1"}
+	p := Zookeeper(func() ZKConn { return c }, "/app/config.yaml")
+	got := make(chan string, 4)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- p.Watch(ctx, func(data []byte) { got <- string(data) }) }()
+	if v := <-got; v != "v1" {
+		t.Fatalf("first = %q", v)
+	}
+
+	// 触发后、重新注册前的修改通过 GetW 的返回值生效
+	c.set("v2")
+	c.set("v3")
+	c.fire(zk.Event{Type: zk.EventNodeDataChanged})
+	if v := <-got; v != "v3" {
+		t.Fatalf("after change = %q", v)
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package loadRemote
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-zookeeper/zk"
+	"helay.net/go/utils/v3/db/zookeeper"
+)
+
+// ZKConn zookeeper 连接中用到的方法，*zk.Conn 直接满足
+type ZKConn interface {
+	Get(path string) ([]byte, *zk.Stat, error)
+	GetW(path string) ([]byte, *zk.Stat, <-chan zk.Event, error)
+}
+
+type zkProvider struct {
+	conn func() ZKConn // 会话过期重连后连接会被替换，每次使用时重新获取
+	path string
+}
+
+// Zookeeper 从 zookeeper 节点中读取配置文档，conn 返回当前可用的连接
+func Zookeeper(conn func() ZKConn, path string) Provider {
+	return &zkProvider{conn: conn, path: path}
+}
+
+// ZookeeperClient 使用 db/zookeeper 的客户端读取配置，path 为完整路径
+func ZookeeperClient(client *zookeeper.Client, path string) Provider {
+	return Zookeeper(func() ZKConn {
+		if conn := client.GetConn(); conn != nil {
+			return conn
+		}
+		return nil
+	}, path)
+}
+
+func (p *zkProvider) Name() string {
+	return "zookeeper:" + p.path
+}
+
+func (p *zkProvider) Get(_ context.Context) ([]byte, error) {
+	conn := p.conn()
+	if conn == nil {
+		return nil, fmt.Errorf("连接未建立")
+	}
+	data, _, err := conn.Get(p.path)
+	return data, err
+}
+
+// Watch zookeeper 的 watch 只触发一次，每次触发后重新注册
+// 注册时读取到的非空内容都会交给 fn，触发和重新注册之间的修改不会遗漏，内容未变化时由调用方忽略。
+func (p *zkProvider) Watch(ctx context.Context, fn func(data []byte)) error {
+	for {
+		conn := p.conn()
+		if conn == nil {
+			return fmt.Errorf("连接未建立")
+		}
+		data, _, ch, err := conn.GetW(p.path)
+		if err != nil {
+			return err
+		}
+		if len(data) > 0 {
+			fn(data)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ev := <-ch:
+			if ev.Err != nil {
+				return ev.Err
+			}
+			if ev.Type == zk.EventNotWatching {
+				return fmt.Errorf("监听配置 %s 已断开", p.path)
+			}
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package loadWatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"helay.net/go/utils/v3/config"
+	"helay.net/go/utils/v3/config/loadLayer"
+	"helay.net/go/utils/v3/logger/ulogs"
+	"helay.net/go/utils/v3/logger/zaploger"
+	"helay.net/go/utils/v3/tools"
+)
+
+// Options 热更新配置
+type Options[T any] struct {
+	Layer    loadLayer.Options // 分层加载参数，文件、环境变量、--set 每次重载都会重新合并
+	Interval time.Duration     // 文件检查间隔，默认 3 秒
+	Validate func(*T) error    // 新配置的校验函数，校验失败的配置不会生效
+}
+
+// Validator 配置结构体实现该接口时，重载前会自动调用
+type Validator interface {
+	Validate() error
+}
+
+// Notifier 能够通知配置变化的对象，Watcher 和远程配置 loadRemote.Remote 都实现了该接口
+type Notifier[T any] interface {
+	OnChange(fn func(old, new *T) error)
+}
+
+// Watcher 配置热更新。
+// 定时读取配置文件（yaml 会重新展开 #include，被包含的文件修改同样能感知），内容变化后重新分层加载并校验，
+// 校验通过才原子替换当前快照，并通知关注的配置段发生变化的订阅者。
+// 快照在替换后不会再被修改，使用方只读即可，不要修改 Get 返回的对象。
+type Watcher[T any] struct {
+	opt     Options[T]
+	file    string
+	current atomic.Pointer[T]
+	report  atomic.Pointer[loadLayer.Report]
+
+	mu     sync.Mutex // 串行化重载
+	digest uint64     // 上次读取的文件内容摘要
+
+	subMu sync.RWMutex
+	subs  []func(old, new *T) error
+
+	Loger *zaploger.Logger
+}
+
+// New 加载初始配置，初始配置无效时返回错误
+func New[T any](opt Options[T]) (*Watcher[T], error) {
+	if opt.Interval <= 0 {
+		opt.Interval = 3 * time.Second
+	}
+	w := &Watcher[T]{opt: opt}
+	file := tools.Ternary(opt.Layer.File == "", config.Cpath, opt.Layer.File)
+	if file != "" {
+		w.file = tools.Fileabs(file)
+	}
+	if w.file != "" {
+		b, err := loadLayer.ReadFile(w.file)
+		if err != nil {
+			return nil, err
+		}
+		w.digest = xxhash.Sum64(b)
+	}
+	cfg, report, err := w.load()
+	if err != nil {
+		return nil, err
+	}
+	w.current.Store(cfg)
+	w.report.Store(report)
+	return w, nil
+}
+
+// Get 获取当前配置快照
+func (w *Watcher[T]) Get() *T {
+	return w.current.Load()
+}
+
+// Report 当前配置各项的来源
+func (w *Watcher[T]) Report() *loadLayer.Report {
+	return w.report.Load()
+}
+
+// OnChange 订阅整个配置的变化，每次配置替换后都会调用
+func (w *Watcher[T]) OnChange(fn func(old, new *T) error) {
+	w.subMu.Lock()
+	defer w.subMu.Unlock()
+	w.subs = append(w.subs, fn)
+}
+
+// Subscribe 订阅配置段的变化，section 从配置中取出关注的部分，只有这部分发生变化（reflect.DeepEqual）时才调用 fn。
+// 例如：
+//
+//	loadWatch.Subscribe(w, func(c *Conf) *ipmatch.Config { return c.Security.IPAccess.Allow }, allow.Reload)
+//	loadWatch.Subscribe(w, func(c *Conf) lockpolicy.Policies { return c.LockPolicies }, func(p lockpolicy.Policies) error {
+//		manager.UpdatePolices(p)
+//		return nil
+//	})
+//	loadWatch.Subscribe(w, func(c *Conf) string { return c.Logger.LogLevel }, logger.SetLevel)
+func Subscribe[T, S any](w Notifier[T], section func(*T) S, fn func(S) error) {
+	w.OnChange(func(old, new *T) error {
+		s := section(new)
+		if reflect.DeepEqual(section(old), s) {
+			return nil
+		}
+		return fn(s)
+	})
+}
+
+// Run 定时检查配置文件，直到 ctx 结束
+func (w *Watcher[T]) Run(ctx context.Context) {
+	if w.file == "" {
+		return
+	}
+	tck := time.NewTicker(w.opt.Interval)
+	defer tck.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tck.C:
+			if err := w.check(); err != nil {
+				w.error("配置热更新失败，继续使用当前配置", err, "文件", w.file)
+			}
+		}
+	}
+}
+
+// check 文件内容变化时重载
+func (w *Watcher[T]) check() error {
+	b, err := loadLayer.ReadFile(w.file)
+	if err != nil {
+		return err
+	}
+	digest := xxhash.Sum64(b)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if digest == w.digest {
+		return nil
+	}
+	// 无效配置也记录摘要，避免每个周期重复报错，直到文件再次修改
+	w.digest = digest
+	return w.reload()
+}
+
+// Reload 立即重新加载配置，可用于接收 SIGHUP 或者管理接口触发。
+// 新配置加载或校验失败时返回错误，当前配置保持不变。
+func (w *Watcher[T]) Reload() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.reload()
+}
+
+func (w *Watcher[T]) reload() error {
+	cfg, report, err := w.load()
+	if err != nil {
+		return err
+	}
+	old := w.current.Swap(cfg)
+	w.report.Store(report)
+	w.log("配置已更新", "文件", w.file)
+	w.notify(old, cfg)
+	return nil
+}
+
+// load 分层加载并校验新配置
+func (w *Watcher[T]) load() (*T, *loadLayer.Report, error) {
+	cfg := new(T)
+	report, err := loadLayer.LoadWithOptions(cfg, w.opt.Layer)
+	if err != nil {
+		return nil, nil, err
+	}
+	if v, ok := any(cfg).(Validator); ok {
+		if err = v.Validate(); err != nil {
+			return nil, nil, fmt.Errorf("配置校验失败 %w", err)
+		}
+	}
+	if w.opt.Validate != nil {
+		if err = w.opt.Validate(cfg); err != nil {
+			return nil, nil, fmt.Errorf("配置校验失败 %w", err)
+		}
+	}
+	return cfg, report, nil
+}
+
+// notify 按订阅顺序通知，单个订阅者失败或 panic 不影响其他订阅者
+func (w *Watcher[T]) notify(old, new *T) {
+	w.subMu.RLock()
+	subs := w.subs
+	w.subMu.RUnlock()
+	for _, fn := range subs {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					w.error("配置变更订阅处理异常", errors.New(fmt.Sprint(r)))
+				}
+			}()
+			if err := fn(old, new); err != nil {
+				w.error("配置变更订阅处理失败", err)
+			}
+		}()
+	}
+}
+
+func (w *Watcher[T]) error(title string, err error, args ...any) {
+	if w.Loger == nil {
+		ulogs.Error(append([]any{title, err}, args...)...)
+	} else {
+		w.Loger.Error(context.Background(), title, zaploger.Auto2Field(append([]any{"错误信息", err}, args...)...))
+	}
+}
+
+func (w *Watcher[T]) log(title string, args ...any) {
+	if w.Loger == nil {
+		ulogs.Log(append([]any{title}, args...)...)
+	} else {
+		w.Loger.Info(context.Background(), title, zaploger.Auto2Field(args...))
+	}
+}
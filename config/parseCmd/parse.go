@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"helay.net/go/utils/v3"
 	"helay.net/go/utils/v3/config"
@@ -23,6 +24,7 @@ func Parseparams(f ...func()) {
 	flag.BoolVar(&config.Dbg, "debug", false, "Debug 模式")
 	flag.StringVar(&logLevel, "log-level", "info", "日志级别:\ndebug info warn error fatal")
 	flag.BoolVar(&vers, "version", false, "查看版本")
+	flag.Var((*setFlag)(&config.CmdSets), "set", "覆盖配置项，格式 key.path=value，可重复指定")
 	for _, v := range f {
 		if v != nil {
 			v()
@@ -62,3 +64,18 @@ func Parseparams(f ...func()) {
 		log.Println("运行参数解析完成...")
 	}
 }
+
+// setFlag 可重复指定的 --set 参数
+type setFlag []string
+
+func (s *setFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *setFlag) Set(v string) error {
+	if !strings.Contains(v, "=") {
+		return fmt.Errorf("格式应为 key.path=value")
+	}
+	*s = append(*s, v)
+	return nil
+}
@@ -0,0 +1,255 @@
+package validate
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"helay.net/go/utils/v3/rule-engine/validator/operators"
+	"helay.net/go/utils/v3/rule-engine/validator/types"
+	"helay.net/go/utils/v3/tools"
+)
+
+// Field 规则的校验对象
+type Field struct {
+	Value  reflect.Value // 字段值
+	Parent reflect.Value // 字段所在的结构体，用于关联字段的规则
+	Param  string        // 规则参数，即 = 后面的部分
+}
+
+// String 字段值的字符串形式
+func (f Field) String() string {
+	v, ok := deref(f.Value)
+	if !ok {
+		return ""
+	}
+	if v.Kind() == reflect.String {
+		return v.String()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// Sibling 查找同一结构体中的字段，name 可以是字段名或者 yaml、json、ini tag
+func (f Field) Sibling(name string) (reflect.Value, string, bool) {
+	t := f.Parent.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.Name == name || fieldName(sf) == name {
+			return f.Parent.Field(i), fieldName(sf), true
+		}
+	}
+	return reflect.Value{}, "", false
+}
+
+// RuleFunc 规则实现，通过时返回空字符串，否则返回错误说明
+type RuleFunc func(f Field) string
+
+type rule struct {
+	fn        RuleFunc
+	checkZero bool // 零值时也执行
+}
+
+var (
+	rulesMu sync.RWMutex
+	rules   = map[string]rule{
+		"required":         {fn: required, checkZero: true},
+		"required_with":    {fn: requiredWith, checkZero: true},
+		"required_without": {fn: requiredWithout, checkZero: true},
+		"excluded_with":    {fn: excludedWith, checkZero: true},
+		"min":              {fn: bound(true)},
+		"max":              {fn: bound(false)},
+		"oneof":            {fn: oneof},
+		"regex":            {fn: regex},
+		"cidr":             {fn: cidr},
+		"hostport":         {fn: hostport},
+		"file":             {fn: fileExists(false)},
+		"dir":              {fn: fileExists(true)},
+	}
+)
+
+func init() {
+	// 格式类规则直接使用规则引擎中的实现
+	for _, op := range []types.Operator{types.FormatEmail, types.FormatURL, types.FormatIP, types.FormatPhone, types.FormatJSON, types.FormatBase64, types.FormatUUID, types.FormatHexColor} {
+		rules[string(op)] = rule{fn: func(f Field) string {
+			msg, _ := operators.ValidateFormat(op, f.String(), nil)
+			return msg
+		}}
+	}
+}
+
+// Register 注册自定义规则，与内置规则同名时覆盖。零值字段不会执行自定义规则。
+func Register(name string, fn RuleFunc) {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	rules[name] = rule{fn: fn}
+}
+
+func getRule(name string) (rule, bool) {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+	r, ok := rules[name]
+	return r, ok
+}
+
+func required(f Field) string {
+	if f.Value.IsZero() {
+		return types.ContentChineseMap[types.Required]
+	}
+	return ""
+}
+
+// siblings 按空格拆分参数，返回关联字段中非零值字段的配置名和全部配置名
+func siblings(f Field) (set []string, all []string) {
+	for _, name := range strings.Fields(f.Param) {
+		v, n, ok := f.Sibling(name)
+		if !ok {
+			all = append(all, name)
+			continue
+		}
+		all = append(all, n)
+		if !v.IsZero() {
+			set = append(set, n)
+		}
+	}
+	return set, all
+}
+
+func requiredWith(f Field) string {
+	if !f.Value.IsZero() {
+		return ""
+	}
+	if set, _ := siblings(f); len(set) > 0 {
+		return fmt.Sprintf("设置了 %s 时不能为空", strings.Join(set, "、"))
+	}
+	return ""
+}
+
+func requiredWithout(f Field) string {
+	if !f.Value.IsZero() {
+		return ""
+	}
+	if set, all := siblings(f); len(set) == 0 {
+		return fmt.Sprintf("与 %s 至少需要设置一项", strings.Join(all, "、"))
+	}
+	return ""
+}
+
+func excludedWith(f Field) string {
+	if f.Value.IsZero() {
+		return ""
+	}
+	if set, _ := siblings(f); len(set) > 0 {
+		return fmt.Sprintf("不能与 %s 同时设置", strings.Join(set, "、"))
+	}
+	return ""
+}
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// bound 最小值、最大值。数字比较数值，time.Duration 的参数按时长解析，字符串比较长度，切片和 map 比较元素数量。
+func bound(isMin bool) RuleFunc {
+	return func(f Field) string {
+		v, ok := deref(f.Value)
+		if !ok {
+			return ""
+		}
+		if v.Type() == durationType {
+			limit, err := time.ParseDuration(f.Param)
+			if err != nil {
+				return fmt.Sprintf("规则参数 %s 不是有效的时长", f.Param)
+			}
+			d := time.Duration(v.Int())
+			if isMin && d < limit {
+				return fmt.Sprintf("不能小于 %s", limit)
+			}
+			if !isMin && d > limit {
+				return fmt.Sprintf("不能大于 %s", limit)
+			}
+			return ""
+		}
+		var num float64
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			num = float64(v.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			num = float64(v.Uint())
+		case reflect.Float32, reflect.Float64:
+			num = v.Float()
+		case reflect.String:
+			msg, _ := operators.ValidateLength(tools.Ternary(isMin, types.LenMin, types.LenMax), v.String(), []any{f.Param})
+			return msg
+		case reflect.Slice, reflect.Map, reflect.Array:
+			limit, err := strconv.Atoi(f.Param)
+			if err != nil {
+				return fmt.Sprintf("规则参数 %s 不是整数", f.Param)
+			}
+			if isMin && v.Len() < limit {
+				return fmt.Sprintf("数量不能少于%d个", limit)
+			}
+			if !isMin && v.Len() > limit {
+				return fmt.Sprintf("数量不能超过%d个", limit)
+			}
+			return ""
+		default:
+			return fmt.Sprintf("类型 %s 不支持该规则", v.Type())
+		}
+		msg, _ := operators.ValidateContent(tools.Ternary(isMin, types.GreaterEqual, types.LessEqual), "float", num, nil, []any{f.Param})
+		return msg
+	}
+}
+
+// oneof 枚举值，参数用空格分隔
+func oneof(f Field) string {
+	var enum []any
+	for _, s := range strings.Fields(f.Param) {
+		enum = append(enum, s)
+	}
+	msg, _ := operators.ValidateContent(types.InEnum, "", f.String(), f.String(), enum)
+	return msg
+}
+
+func regex(f Field) string {
+	msg, _ := operators.ValidateContent(types.RegexMatch, "", f.String(), nil, []any{f.Param})
+	return msg
+}
+
+func cidr(f Field) string {
+	if _, _, err := net.ParseCIDR(f.String()); err != nil {
+		return "请输入有效的CIDR网段"
+	}
+	return ""
+}
+
+// hostport 主机:端口 格式，主机可以为空，如 :8080
+func hostport(f Field) string {
+	_, port, err := net.SplitHostPort(f.String())
+	if err != nil {
+		return "请输入有效的地址，格式为 主机:端口"
+	}
+	if p, err := strconv.Atoi(port); err != nil || p < 0 || p > 65535 {
+		return "端口必须在0到65535之间"
+	}
+	return ""
+}
+
+// fileExists 文件或目录必须存在，相对路径相对于程序所在目录
+func fileExists(isDir bool) RuleFunc {
+	return func(f Field) string {
+		info, err := os.Stat(tools.Fileabs(f.String()))
+		if err != nil {
+			return tools.Ternary(isDir, "目录不存在", "文件不存在")
+		}
+		if isDir && !info.IsDir() {
+			return "不是目录"
+		}
+		if !isDir && info.IsDir() {
+			return "不是文件"
+		}
+		return ""
+	}
+}
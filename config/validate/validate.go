@@ -0,0 +1,182 @@
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldError 单个配置项的校验错误
+type FieldError struct {
+	Path    string // 配置路径，与 yaml 中的层级一致，如 server.tls.cert_file、routes[0].path
+	Rule    string // 未通过的规则，如 required、max
+	Message string // 错误说明
+}
+
+func (e FieldError) Error() string {
+	return e.Path + " " + e.Message
+}
+
+// Errors 一次校验中的全部错误
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "配置校验失败，共 %d 项", len(e))
+	for _, fe := range e {
+		sb.WriteString("\n  ")
+		sb.WriteString(fe.Error())
+	}
+	return sb.String()
+}
+
+// Struct 按结构体字段的 valid tag 校验配置，返回全部不通过的项，全部通过时返回 nil。
+// 多个规则用逗号分隔，例如：
+//
+//	Port     int           `yaml:"port" valid:"required,min=1,max=65535"`
+//	Mode     string        `yaml:"mode" valid:"oneof=debug release"`
+//	Timeout  time.Duration `yaml:"timeout" valid:"min=1s,max=5m"`
+//	CertFile string        `yaml:"cert_file" valid:"required_with=KeyFile,file"`
+//	Socket   string        `yaml:"socket" valid:"excluded_with=Addr"`
+//	Pattern  string        `yaml:"pattern" valid:"regex=^[a-z]+$"`
+//
+// 除 required、required_with、required_without、excluded_with 外，零值字段跳过其他规则。
+// 嵌套结构体、结构体指针、切片和 map 中的结构体会递归校验，空指针跳过。
+func Struct(v any) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	var errs Errors
+	walk(rv, "", &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// walk 递归校验结构体
+func walk(v reflect.Value, path string, errs *Errors) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		// 没有命名的内嵌结构体，字段展开到当前层级
+		if f.Anonymous && f.Tag.Get("yaml") == "" && f.Tag.Get("json") == "" {
+			if sv, ok := deref(fv); ok && sv.Kind() == reflect.Struct {
+				walk(sv, path, errs)
+			}
+			continue
+		}
+		name := fieldName(f)
+		if name == "-" {
+			continue
+		}
+		p := join(path, name)
+		if tag := f.Tag.Get("valid"); tag != "" && tag != "-" {
+			check(v, fv, p, tag, errs)
+		}
+		dive(fv, p, errs)
+	}
+}
+
+// dive 进入字段内部，继续校验其中的结构体
+func dive(v reflect.Value, path string, errs *Errors) {
+	v, ok := deref(v)
+	if !ok {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		if v.Type() != timeType {
+			walk(v, path, errs)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			dive(v.Index(i), path+"["+strconv.Itoa(i)+"]", errs)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			dive(iter.Value(), join(path, fmt.Sprint(iter.Key().Interface())), errs)
+		}
+	default:
+	}
+}
+
+// check 逐条执行字段上的规则
+func check(parent, v reflect.Value, path, tag string, errs *Errors) {
+	for _, item := range splitRules(tag) {
+		name, param, _ := strings.Cut(item, "=")
+		name = strings.TrimSpace(name)
+		r, ok := getRule(name)
+		if !ok {
+			*errs = append(*errs, FieldError{Path: path, Rule: name, Message: fmt.Sprintf("未知的校验规则 %s", name)})
+			continue
+		}
+		if !r.checkZero && v.IsZero() {
+			continue
+		}
+		if msg := r.fn(Field{Value: v, Parent: parent, Param: param}); msg != "" {
+			*errs = append(*errs, FieldError{Path: path, Rule: name, Message: msg})
+		}
+	}
+}
+
+// splitRules 按逗号拆分规则，regex 的参数可能包含逗号，必须放在最后
+func splitRules(tag string) []string {
+	var rules []string
+	for tag != "" {
+		if strings.HasPrefix(tag, "regex=") {
+			return append(rules, tag)
+		}
+		item, rest, _ := strings.Cut(tag, ",")
+		if item = strings.TrimSpace(item); item != "" {
+			rules = append(rules, item)
+		}
+		tag = strings.TrimSpace(rest)
+	}
+	return rules
+}
+
+// fieldName 配置项名称，依次取 yaml、json、ini tag，都没有时使用小写的字段名
+func fieldName(f reflect.StructField) string {
+	for _, key := range []string{"yaml", "json", "ini"} {
+		name, _, _ := strings.Cut(f.Tag.Get(key), ",")
+		if name != "" {
+			return name
+		}
+	}
+	return strings.ToLower(f.Name)
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// deref 解引用指针和接口，空值返回 false
+func deref(v reflect.Value) (reflect.Value, bool) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return v, false
+		}
+		v = v.Elem()
+	}
+	return v, true
+}
@@ -3,6 +3,7 @@ package dataType
 import (
 	"database/sql/driver"
 	"fmt"
+	"math"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/schema"
@@ -77,6 +78,10 @@ func (u *Uint64) EqualsUint64(other uint64) bool {
 
 // noinspection all
 func (u Uint64) Value() (driver.Value, error) {
+	// uint64 不是标准的 driver.Value，能用 int64 表示时优先返回 int64，兼容 sqlite、postgres 等驱动
+	if u.uint64 <= math.MaxInt64 {
+		return int64(u.uint64), nil
+	}
 	return u.uint64, nil
 }
 
@@ -0,0 +1,205 @@
+package exportkit
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/araddon/dateparse"
+	"helay.net/go/utils/v3/tools"
+)
+
+// ColumnKind 列的数据类型，决定单元格格式和 parquet 的字段类型
+type ColumnKind int
+
+const (
+	KindString ColumnKind = iota
+	KindInt
+	KindFloat
+	KindBool
+	KindDate // 日期，不含时间部分
+	KindTime // 日期时间
+)
+
+func (k ColumnKind) String() string {
+	switch k {
+	case KindInt:
+		return "int"
+	case KindFloat:
+		return "float"
+	case KindBool:
+		return "bool"
+	case KindDate:
+		return "date"
+	case KindTime:
+		return "time"
+	}
+	return "string"
+}
+
+// Column 导出的列
+type Column struct {
+	Name  string     // 字段名，ndjson 和 parquet 使用字段名作为键
+	Title string     // 表头，csv 和 xlsx 使用，为空时使用字段名
+	Kind  ColumnKind // 数据类型
+}
+
+func (c Column) title() string {
+	return tools.Ternary(c.Title == "", c.Name, c.Title)
+}
+
+// SQLColumnKind 根据数据库字段类型推断列类型
+func SQLColumnKind(ct *sql.ColumnType) ColumnKind {
+	name := strings.ToUpper(ct.DatabaseTypeName())
+	switch {
+	case name == "DATE":
+		return KindDate
+	case strings.Contains(name, "TIMESTAMP"), strings.Contains(name, "DATETIME"):
+		return KindTime
+	case strings.Contains(name, "BOOL"):
+		return KindBool
+	case strings.Contains(name, "INT") && !strings.Contains(name, "INTERVAL") && !strings.Contains(name, "POINT"):
+		return KindInt
+	case strings.Contains(name, "FLOAT"), strings.Contains(name, "DOUBLE"), strings.Contains(name, "REAL"):
+		return KindFloat
+	case strings.Contains(name, "DECIMAL"), strings.Contains(name, "NUMERIC"), strings.Contains(name, "NUMBER"),
+		strings.Contains(name, "MONEY"):
+		// 定点数经过 float64 会丢失精度，没有小数位且不超过 int64 范围的按整数，其余按文本原样导出
+		if precision, scale, ok := ct.DecimalSize(); ok && scale == 0 && precision > 0 && precision <= 18 {
+			return KindInt
+		}
+		return KindString
+	}
+	if t := ct.ScanType(); t != nil {
+		if t == reflect.TypeOf(time.Time{}) || t == reflect.TypeOf(sql.NullTime{}) {
+			return KindTime
+		}
+		switch t.Kind() {
+		case reflect.Bool:
+			return KindBool
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return KindInt
+		case reflect.Float32, reflect.Float64:
+			return KindFloat
+		}
+	}
+	return KindString
+}
+
+// ValueKind 根据值推断列类型，用于 elasticsearch 等没有字段类型的数据源
+// 符合 RFC3339 或者 2006-01-02 15:04:05、2006-01-02 格式的字符串视为时间。
+func ValueKind(v any) ColumnKind {
+	switch val := v.(type) {
+	case bool:
+		return KindBool
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return KindInt
+	case float32, float64:
+		return KindFloat
+	case time.Time:
+		return KindTime
+	case interface{ Int64() (int64, error) }: // json.Number
+		if _, err := val.Int64(); err == nil {
+			return KindInt
+		}
+		return KindFloat
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, time.DateTime} {
+			if _, err := time.Parse(layout, val); err == nil {
+				return KindTime
+			}
+		}
+		if _, err := time.Parse(time.DateOnly, val); err == nil {
+			return KindDate
+		}
+	}
+	return KindString
+}
+
+// Normalize 把数据源的值转换为列类型对应的 go 类型
+// 返回 nil、int64、float64、bool、time.Time 或 string，转换失败时返回原值和 false。
+func Normalize(v any, kind ColumnKind, loc *time.Location) (any, bool) {
+	if valuer, ok := v.(driver.Valuer); ok {
+		var err error
+		if v, err = valuer.Value(); err != nil {
+			return nil, false
+		}
+	}
+	if v == nil {
+		return nil, true
+	}
+	if b, ok := v.([]byte); ok {
+		v = string(b)
+	}
+	rv := reflect.ValueOf(v)
+	switch kind {
+	case KindInt:
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return rv.Int(), true
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if u := rv.Uint(); u <= math.MaxInt64 {
+				return int64(u), true
+			}
+		case reflect.Float32, reflect.Float64:
+			if f := rv.Float(); f == float64(int64(f)) {
+				return int64(f), true
+			}
+		case reflect.Bool:
+			return tools.Ternary(rv.Bool(), int64(1), int64(0)), true
+		case reflect.String:
+			if i, err := strconv.ParseInt(strings.TrimSpace(rv.String()), 10, 64); err == nil {
+				return i, true
+			}
+		}
+	case KindFloat:
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return float64(rv.Int()), true
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return float64(rv.Uint()), true
+		case reflect.Float32, reflect.Float64:
+			return rv.Float(), true
+		case reflect.String:
+			if f, err := strconv.ParseFloat(strings.TrimSpace(rv.String()), 64); err == nil {
+				return f, true
+			}
+		}
+	case KindBool:
+		switch rv.Kind() {
+		case reflect.Bool:
+			return rv.Bool(), true
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return rv.Int() != 0, true
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return rv.Uint() != 0, true
+		case reflect.String:
+			if b, err := strconv.ParseBool(strings.TrimSpace(rv.String())); err == nil {
+				return b, true
+			}
+		}
+	case KindDate, KindTime:
+		switch val := v.(type) {
+		case time.Time:
+			return val, true
+		case string:
+			if t, err := dateparse.ParseIn(strings.TrimSpace(val), loc); err == nil {
+				return t, true
+			}
+		}
+	default:
+		if s, ok := v.(string); ok {
+			return s, true
+		}
+		if t, ok := v.(time.Time); ok {
+			return t.In(loc).Format(time.RFC3339), true
+		}
+		return tools.Any2string(v), true
+	}
+	return v, false
+}
@@ -0,0 +1,50 @@
+package exportkit
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// csvWriter 带 UTF-8 BOM 的 csv，便于 Excel 直接打开
+type csvWriter struct {
+	w       io.Writer
+	cw      *csv.Writer
+	format  CellFormat
+	columns []Column
+	record  []string
+}
+
+func newCsvWriter(w io.Writer, cfg ExportConfig) (RowWriter, error) {
+	return &csvWriter{w: w, cw: csv.NewWriter(w), format: cfg.Format}, nil
+}
+
+func (w *csvWriter) WriteHeader(columns []Column) error {
+	if _, err := w.w.Write([]byte("\xef\xbb\xbf")); err != nil {
+		return fmt.Errorf("写入csv头失败：%w", err)
+	}
+	w.columns = columns
+	w.record = make([]string, len(columns))
+	for i, c := range columns {
+		w.record[i] = c.title()
+	}
+	if err := w.cw.Write(w.record); err != nil {
+		return fmt.Errorf("写入csv头失败：%w", err)
+	}
+	return nil
+}
+
+func (w *csvWriter) WriteRow(row []any) error {
+	for i, v := range row {
+		w.record[i] = w.format.Text(v, w.columns[i].Kind)
+	}
+	if err := w.cw.Write(w.record); err != nil {
+		return fmt.Errorf("写入csv行失败：%w", err)
+	}
+	return nil
+}
+
+func (w *csvWriter) Close() error {
+	w.cw.Flush()
+	return w.cw.Error()
+}
@@ -1,4 +1,8 @@
 package exportkit
 
+import "io"
+
+// Export 数据源导出，按 ExportType 选择文件格式写入 w
 type Export interface {
+	Execute(et ExportType, w io.Writer) error
 }
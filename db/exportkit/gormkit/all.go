@@ -3,38 +3,19 @@ package gormkit
 import (
 	"fmt"
 
-	"github.com/xuri/excelize/v2"
 	"helay.net/go/utils/v3/db/exportkit"
-	"helay.net/go/utils/v3/tools"
 )
 
-func (e *Export) all(et exportkit.ExportType, columns []string) error {
+func (e *Export) all(rw exportkit.RowWriter, columns []exportkit.Column) error {
 	var records []map[string]any
 	if err := e.db.Find(&records).Error; err != nil {
 		return fmt.Errorf("查询数据失败：%v", err)
 	}
+	row := make([]any, len(columns))
 	// 遍历数据
-	for rowIdx, record := range records {
-		switch et {
-		case exportkit.ExportTypeExcel:
-			row := make([]any, len(columns))
-			for colIdx, columnName := range columns {
-				row[colIdx] = tools.Any2string(record[columnName])
-			}
-			cell, _ := excelize.CoordinatesToCellName(1, rowIdx+2)
-			if err := e.sw.SetRow(cell, row); err != nil {
-				return fmt.Errorf("写入数据失败：%v", err)
-			}
-		case exportkit.ExportTypeCsv:
-			row := make([]string, len(columns))
-			for colIdx, columnName := range columns {
-				row[colIdx] = tools.Any2string(record[columnName])
-			}
-			if err := e.cw.Write(row); err != nil {
-				return fmt.Errorf("写入数据失败：%v", err)
-			}
-		default:
-			return exportkit.ErrExportTypeNotSupport
+	for _, record := range records {
+		if err := writeRecord(rw, columns, record, row); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -3,40 +3,85 @@ package gormkit
 import (
 	"fmt"
 
-	"github.com/xuri/excelize/v2"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"helay.net/go/utils/v3/db/exportkit"
-	"helay.net/go/utils/v3/tools"
 )
 
-func (e *Export) batch(et exportkit.ExportType, columns []string) error {
-	var records []map[string]any
-	rowIdx := 0
-	return e.db.FindInBatches(&records, e.config.BatchSize, func(tx *gorm.DB, batch int) error {
+// batch 按 BatchSize 分页查询
+// FindInBatches 需要把结果扫描到模型，查询结果为 map 时无法使用，这里自行分页：
+//   - 没有设置排序时按模型主键排序，并按主键分页（WHERE pk > 上一页最后的主键），深分页时不会越来越慢；
+//     查询结果中没有主键字段时改为 offset 分页
+//   - 调用方设置了排序时按 offset 分页
+//   - 保留调用方设置的 Limit、Offset，分页不会超过调用方的 Limit
+//   - 无法确定主键或者是原生 SQL 时改为游标方式一次查询
+func (e *Export) batch(rw exportkit.RowWriter, columns []exportkit.Column) error {
+	base := e.db.Session(&gorm.Session{})
+	if base.Statement.SQL.Len() > 0 {
+		return e.cursor(rw, columns)
+	}
+	var pk string // 按主键分页时的主键字段
+	if _, ok := base.Statement.Clauses["ORDER BY"]; !ok {
+		if pk = e.primaryKey(); pk == "" {
+			return e.cursor(rw, columns)
+		}
+		base = base.Order(clause.OrderByColumn{Column: clause.Column{Table: clause.CurrentTable, Name: pk}}).Session(&gorm.Session{})
+	}
+	offset, total := 0, -1 // total 为调用方的 Limit，-1 表示不限制
+	if c, ok := base.Statement.Clauses["LIMIT"]; ok {
+		if limit, ok := c.Expression.(clause.Limit); ok {
+			offset = limit.Offset
+			if limit.Limit != nil && *limit.Limit >= 0 {
+				total = *limit.Limit
+			}
+		}
+	}
+	var (
+		row  = make([]any, len(columns))
+		last any // 上一页最后一行的主键
+	)
+	for done := 0; total < 0 || done < total; {
+		size := e.config.BatchSize
+		if total >= 0 {
+			size = min(size, total-done)
+		}
+		query := base.Offset(offset + done)
+		if pk != "" && done > 0 {
+			// gorm 合并 LIMIT 子句时 Offset(0) 会保留原来的 offset，-1 才会清除
+			query = base.Where(clause.Gt{Column: clause.Column{Table: clause.CurrentTable, Name: pk}, Value: last}).Offset(-1)
+		}
+		var records []map[string]any
+		if err := query.Limit(size).Find(&records).Error; err != nil {
+			return fmt.Errorf("查询数据失败：%v", err)
+		}
 		for _, record := range records {
-			rowIdx++
-			switch et {
-			case exportkit.ExportTypeExcel:
-				row := make([]any, len(columns))
-				for i, column := range columns {
-					row[i] = tools.Any2string(record[column])
-				}
-				cell, _ := excelize.CoordinatesToCellName(1, rowIdx+2)
-				if err := e.sw.SetRow(cell, row); err != nil {
-					return fmt.Errorf("写入数据失败：%v", err)
-				}
-			case exportkit.ExportTypeCsv:
-				row := make([]string, len(columns))
-				for i, column := range columns {
-					row[i] = tools.Any2string(record[column])
-				}
-				if err := e.cw.Write(row); err != nil {
-					return fmt.Errorf("写入数据失败：%v", err)
-				}
-			default:
-				return exportkit.ErrExportTypeNotSupport
+			if err := writeRecord(rw, columns, record, row); err != nil {
+				return err
 			}
 		}
-		return nil
-	}).Error
+		done += len(records)
+		if len(records) < size {
+			break
+		}
+		if pk != "" {
+			var ok bool
+			if last, ok = records[len(records)-1][pk]; !ok {
+				pk = ""
+			}
+		}
+	}
+	return nil
+}
+
+// primaryKey 查询模型的主键字段名，没有设置模型或者没有主键时返回空
+func (e *Export) primaryKey() string {
+	model := e.db.Statement.Model
+	if model == nil {
+		return ""
+	}
+	stmt := &gorm.Statement{DB: e.db}
+	if err := stmt.Parse(model); err != nil || stmt.Schema.PrioritizedPrimaryField == nil {
+		return ""
+	}
+	return stmt.Schema.PrioritizedPrimaryField.DBName
 }
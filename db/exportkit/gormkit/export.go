@@ -1,42 +1,36 @@
 package gormkit
 
 import (
-	"encoding/csv"
 	"fmt"
 	"io"
 
-	"github.com/xuri/excelize/v2"
 	"gorm.io/gorm"
 	"helay.net/go/utils/v3/close/vclose"
 	"helay.net/go/utils/v3/db/exportkit"
-	"helay.net/go/utils/v3/excelTools"
-	"helay.net/go/utils/v3/tools"
 )
 
 type Export struct {
 	db             *gorm.DB
 	header         map[string]string
 	config         exportkit.ExportConfig
-	excel          *excelize.File
-	sw             *excelize.StreamWriter
-	cw             *csv.Writer
 	onInitColumn   exportkit.Callback
 	beforeFinalize exportkit.Callback
 }
 
+var _ exportkit.Export = (*Export)(nil)
+
 // New 创建一个导出对象
 func New(db *gorm.DB, config ...exportkit.ExportConfig) *Export {
 	e := &Export{
 		db: db,
 		config: exportkit.ExportConfig{
 			IterationType: exportkit.IterationBatch,
-			BatchSize:     1000,
-			SheetName:     "Sheet1",
 		},
 	}
 	if len(config) > 0 {
 		e.config = config[0]
 	}
+	e.config.SetDefaults()
 	return e
 }
 
@@ -54,10 +48,6 @@ func (e *Export) BeforeFinalize(callback exportkit.Callback) {
 	e.beforeFinalize = callback
 }
 
-func (e *Export) close() {
-	excelTools.CloseExcel(e.excel)
-}
-
 // Execute 执行导出
 func (e *Export) Execute(et exportkit.ExportType, f io.Writer) error {
 	// 第一步，查询表头字段
@@ -70,86 +60,60 @@ func (e *Export) Execute(et exportkit.ExportType, f io.Writer) error {
 			return err
 		}
 	}
-	defer e.close()
-	if err = e.setFile(et, f, columns); err != nil {
+	rw, err := exportkit.NewWriter(et, f, e.config)
+	if err != nil {
+		return err
+	}
+	if err = rw.WriteHeader(columns); err != nil {
 		return err
 	}
 	// 根据数据获取方式进行导出
 	switch e.config.IterationType {
 	case exportkit.IterationCursor: // 游标方式导出
-		err = e.cursor(et, columns)
+		err = e.cursor(rw, columns)
 	case exportkit.IterationBatch: // 批量查询方式
-		err = e.batch(et, columns)
+		err = e.batch(rw, columns)
 	case exportkit.IterationAll: // 直接全部查询出来
-		err = e.all(et, columns)
+		err = e.all(rw, columns)
 	default: // 批量查询
-		err = e.batch(et, columns)
+		err = e.batch(rw, columns)
 	}
 	if err != nil {
+		_ = rw.Close()
 		return err
 	}
 	if e.beforeFinalize != nil {
 		if err = e.beforeFinalize(et); err != nil {
+			_ = rw.Close()
 			return err
 		}
 	}
 	// 输出文件
-	switch et {
-	case exportkit.ExportTypeExcel:
-		_ = e.sw.Flush()
-		return e.excel.Write(f) // 写入文件
-	case exportkit.ExportTypeCsv:
-		e.cw.Flush() // csv就再同步一次文件
-	}
-	return nil
+	return rw.Close()
 }
 
-// 获取表头字段
-func (e *Export) getColumns() ([]string, error) {
+// 获取表头字段，列类型按数据库字段类型推断
+func (e *Export) getColumns() ([]exportkit.Column, error) {
 	rows, err := e.db.Rows()
 	if err != nil {
 		return nil, err
 	}
 	defer vclose.Close(rows)
-	columnNames, err := rows.Columns()
+	columnTypes, err := rows.ColumnTypes()
 	if err != nil {
 		return nil, fmt.Errorf("查询字段失败：%s", err.Error())
 	}
-	// 表头处理替换
-	for idx, columnName := range columnNames {
-		if name, ok := e.header[columnName]; ok {
-			columnNames[idx] = name
-		}
-	}
-	return columnNames, nil
-}
-
-func (e *Export) setFile(et exportkit.ExportType, f io.Writer, columns []string) error {
-	switch et {
-	case exportkit.ExportTypeCsv:
-		_, _ = f.Write([]byte("\xef\xbb\xbf"))
-		e.cw = csv.NewWriter(f)
-	case exportkit.ExportTypeExcel:
-		e.excel = excelize.NewFile()
-		sw, err := e.excel.NewStreamWriter(e.config.SheetName)
-		if err != nil {
-			return fmt.Errorf("创建流写入器失败：%s", err.Error())
-		}
-		e.sw = sw
-	default:
-		return exportkit.ErrExportTypeNotSupport
+	columns := make([]exportkit.Column, len(columnTypes))
+	for idx, ct := range columnTypes {
+		columns[idx] = exportkit.Column{Name: ct.Name(), Title: e.header[ct.Name()], Kind: exportkit.SQLColumnKind(ct)}
 	}
-	return e.setFileHeader(et, columns)
+	return columns, nil
 }
 
-// 设置文件header
-func (e *Export) setFileHeader(et exportkit.ExportType, columns []string) error {
-	switch et {
-	case exportkit.ExportTypeCsv:
-		return e.cw.Write(columns)
-	case exportkit.ExportTypeExcel:
-		return e.sw.SetRow("A1", tools.SliceToAny(columns))
-	default:
-		return exportkit.ErrExportTypeNotSupport
+// writeRecord 按列顺序写入一条 map 记录
+func writeRecord(rw exportkit.RowWriter, columns []exportkit.Column, record map[string]any, row []any) error {
+	for i, c := range columns {
+		row[i] = record[c.Name]
 	}
+	return rw.WriteRow(row)
 }
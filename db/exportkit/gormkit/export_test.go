@@ -0,0 +1,106 @@
+package gormkit
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"helay.net/go/utils/v3/db/exportkit"
+)
+
+type testItem struct {
+	ID   int `gorm:"primaryKey"`
+	Name string
+}
+
+func newTestDB(t *testing.T, n int) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1) // 每个连接都是独立的内存数据库
+	if err = db.AutoMigrate(&testItem{}); err != nil {
+		t.Fatal(err)
+	}
+	// 倒序插入，没有排序时分页结果不稳定
+	for i := n; i >= 1; i-- {
+		db.Create(&testItem{ID: i, Name: fmt.Sprintf("n%d", i)})
+	}
+	return db
+}
+
+func exportIDs(t *testing.T, db *gorm.DB, cfg exportkit.ExportConfig) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := New(db, cfg).Execute(exportkit.ExportTypeCsv, &buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")[1:]
+	ids := make([]string, len(lines))
+	for i, line := range lines {
+		ids[i], _, _ = strings.Cut(line, ",")
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestBatchHonorsLimitAndOffset(t *testing.T) {
+	db := newTestDB(t, 10)
+	cfg := exportkit.ExportConfig{IterationType: exportkit.IterationBatch, BatchSize: 3}
+
+	if got := exportIDs(t, db.Model(&testItem{}), cfg); got != "1,2,3,4,5,6,7,8,9,10" {
+		t.Fatalf("all = %s", got)
+	}
+	if got := exportIDs(t, db.Model(&testItem{}).Offset(2).Limit(5), cfg); got != "3,4,5,6,7" {
+		t.Fatalf("limit = %s", got)
+	}
+	if got := exportIDs(t, db.Model(&testItem{}).Order("id desc").Limit(4), cfg); got != "10,9,8,7" {
+		t.Fatalf("order = %s", got)
+	}
+	// 没有模型时无法按主键排序，改为游标方式
+	if got := exportIDs(t, db.Table("test_items").Where("id > ?", 7), cfg); len(strings.Split(got, ",")) != 3 {
+		t.Fatalf("table = %s", got)
+	}
+}
+
+func TestBatchKeyset(t *testing.T) {
+	db := newTestDB(t, 10)
+	var queries []string
+	err := db.Callback().Query().After("gorm:query").Register("test:record", func(tx *gorm.DB) {
+		queries = append(queries, tx.Statement.SQL.String())
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := exportkit.ExportConfig{IterationType: exportkit.IterationBatch, BatchSize: 3}
+
+	if got := exportIDs(t, db.Model(&testItem{}).Offset(1).Limit(7), cfg); got != "2,3,4,5,6,7,8" {
+		t.Fatalf("keyset = %s", got)
+	}
+	// 第一页使用调用方的 offset，之后按主键分页
+	if len(queries) != 3 || !strings.Contains(queries[0], "OFFSET 1") {
+		t.Fatalf("queries = %q", queries)
+	}
+	for _, q := range queries[1:] {
+		if !strings.Contains(q, "`test_items`.`id` > ") || strings.Contains(q, "OFFSET") {
+			t.Fatalf("page query = %q", q)
+		}
+	}
+
+	// 查询结果中没有主键时改为 offset 分页
+	queries = nil
+	var buf bytes.Buffer
+	if err = New(db.Model(&testItem{}).Select("name"), cfg).Execute(exportkit.ExportTypeCsv, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(buf.String(), "\n"); got != 11 || !strings.Contains(queries[1], "OFFSET 3") {
+		t.Fatalf("lines = %d, queries = %q", got, queries)
+	}
+}
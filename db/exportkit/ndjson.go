@@ -0,0 +1,63 @@
+package exportkit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ndjsonWriter 每行一个 json 对象，键为字段名，数值和布尔保持 json 类型，时间按 CellFormat 格式化
+type ndjsonWriter struct {
+	bw      *bufio.Writer
+	format  CellFormat
+	columns []Column
+	keys    [][]byte
+}
+
+func newNDJSONWriter(w io.Writer, cfg ExportConfig) (RowWriter, error) {
+	return &ndjsonWriter{bw: bufio.NewWriter(w), format: cfg.Format}, nil
+}
+
+func (w *ndjsonWriter) WriteHeader(columns []Column) error {
+	w.columns = columns
+	w.keys = make([][]byte, len(columns))
+	for i, c := range columns {
+		key, err := json.Marshal(c.Name)
+		if err != nil {
+			return err
+		}
+		w.keys[i] = key
+	}
+	return nil
+}
+
+// WriteRow 按列顺序拼接对象，保持字段顺序与表头一致
+func (w *ndjsonWriter) WriteRow(row []any) error {
+	_ = w.bw.WriteByte('{')
+	for i, v := range row {
+		if i > 0 {
+			_ = w.bw.WriteByte(',')
+		}
+		_, _ = w.bw.Write(w.keys[i])
+		_ = w.bw.WriteByte(':')
+		if t, ok := v.(time.Time); ok {
+			v = w.format.Text(t, w.columns[i].Kind)
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("字段 %s 编码失败：%w", w.columns[i].Name, err)
+		}
+		_, _ = w.bw.Write(b)
+	}
+	_ = w.bw.WriteByte('}')
+	if err := w.bw.WriteByte('\n'); err != nil {
+		return fmt.Errorf("写入ndjson行失败：%w", err)
+	}
+	return nil
+}
+
+func (w *ndjsonWriter) Close() error {
+	return w.bw.Flush()
+}
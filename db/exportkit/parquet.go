@@ -0,0 +1,124 @@
+package exportkit
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// parquetWriter 按列类型生成 schema 的 parquet，全部字段可为空，使用 snappy 压缩
+// 值无法转换为列类型时返回错误，不会静默写入 null。
+type parquetWriter struct {
+	w       io.Writer
+	pw      *parquet.Writer
+	columns []Column
+	leaves  []int // 列在 parquet 中的叶子序号，parquet 按字段名排序
+	row     parquet.Row
+	rows    []parquet.Row
+}
+
+func newParquetWriter(w io.Writer, _ ExportConfig) (RowWriter, error) {
+	return &parquetWriter{w: w}, nil
+}
+
+func (w *parquetWriter) WriteHeader(columns []Column) error {
+	group := make(parquet.Group, len(columns))
+	for _, c := range columns {
+		if _, ok := group[c.Name]; ok {
+			return fmt.Errorf("parquet字段名重复：%s", c.Name)
+		}
+		group[c.Name] = parquet.Optional(parquetNode(c.Kind))
+	}
+	schema := parquet.NewSchema("export", group)
+	w.columns = columns
+	w.leaves = make([]int, len(columns))
+	for i, c := range columns {
+		leaf, ok := schema.Lookup(c.Name)
+		if !ok {
+			return fmt.Errorf("parquet字段不存在：%s", c.Name)
+		}
+		w.leaves[i] = leaf.ColumnIndex
+	}
+	w.row = make(parquet.Row, len(columns))
+	w.pw = parquet.NewWriter(w.w, schema, parquet.Compression(&parquet.Snappy))
+	return nil
+}
+
+func parquetNode(kind ColumnKind) parquet.Node {
+	switch kind {
+	case KindInt:
+		return parquet.Int(64)
+	case KindFloat:
+		return parquet.Leaf(parquet.DoubleType)
+	case KindBool:
+		return parquet.Leaf(parquet.BooleanType)
+	case KindDate:
+		return parquet.Date()
+	case KindTime:
+		return parquet.Timestamp(parquet.Millisecond)
+	}
+	return parquet.String()
+}
+
+// parquetValue 转换为 parquet 值，nil 写入 null，类型不一致时返回错误
+func parquetValue(v any, kind ColumnKind) (parquet.Value, error) {
+	if v == nil {
+		return parquet.NullValue(), nil
+	}
+	switch kind {
+	case KindInt:
+		if i, ok := v.(int64); ok {
+			return parquet.Int64Value(i), nil
+		}
+	case KindFloat:
+		if f, ok := v.(float64); ok {
+			return parquet.DoubleValue(f), nil
+		}
+	case KindBool:
+		if b, ok := v.(bool); ok {
+			return parquet.BooleanValue(b), nil
+		}
+	case KindDate:
+		if t, ok := v.(time.Time); ok {
+			// 按日期所在时区的年月日计算距 1970-01-01 的天数
+			days := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400
+			return parquet.Int32Value(int32(days)), nil
+		}
+	case KindTime:
+		if t, ok := v.(time.Time); ok {
+			return parquet.Int64Value(t.UnixMilli()), nil
+		}
+	default:
+		if s, ok := v.(string); ok {
+			return parquet.ByteArrayValue([]byte(s)), nil
+		}
+	}
+	return parquet.Value{}, fmt.Errorf("值 %v 无法转换为 %s 类型", v, kind)
+}
+
+func (w *parquetWriter) WriteRow(row []any) error {
+	for i, v := range row {
+		value, err := parquetValue(v, w.columns[i].Kind)
+		if err != nil {
+			return fmt.Errorf("写入parquet字段 %s 失败：%w", w.columns[i].Name, err)
+		}
+		w.row[w.leaves[i]] = value.Level(0, 1, w.leaves[i])
+		if value.IsNull() {
+			w.row[w.leaves[i]] = value.Level(0, 0, w.leaves[i])
+		}
+	}
+	w.rows = append(w.rows[:0], w.row)
+	if _, err := w.pw.WriteRows(w.rows); err != nil {
+		return fmt.Errorf("写入parquet行失败：%w", err)
+	}
+	return nil
+}
+
+func (w *parquetWriter) Close() error {
+	if w.pw == nil {
+		return nil
+	}
+	return w.pw.Close()
+}
@@ -2,6 +2,7 @@ package exportkit
 
 import (
 	"errors"
+	"time"
 )
 
 type ExportType string
@@ -11,8 +12,10 @@ func (t ExportType) String() string {
 }
 
 const (
-	ExportTypeExcel = "excel"
-	ExportTypeCsv   = "csv"
+	ExportTypeExcel   = "excel"   // xlsx，超过单个sheet的行数上限时自动拆分为多个sheet
+	ExportTypeCsv     = "csv"     // 带 UTF-8 BOM 的 csv
+	ExportTypeNDJSON  = "ndjson"  // 每行一个 json 对象
+	ExportTypeParquet = "parquet" // 按列类型推断 schema 的 parquet
 )
 
 // IterationType 迭代方式枚举
@@ -24,12 +27,59 @@ const (
 	IterationAll                         // 一次性查询
 )
 
+// ExcelMaxRows xlsx 单个sheet的最大行数
+const ExcelMaxRows = 1048576
+
 var ErrExportTypeNotSupport = errors.New("不支持的导出类型")
 
 type ExportConfig struct {
 	IterationType IterationType
 	BatchSize     int // 批量大小
 	SheetName     string
+	SheetMaxRows  int        // xlsx 每个sheet的最大行数，包括表头，默认 ExcelMaxRows
+	Format        CellFormat // 单元格格式
+}
+
+// SetDefaults 设置未配置项的默认值
+func (c *ExportConfig) SetDefaults() {
+	if c.BatchSize <= 0 {
+		c.BatchSize = 1000
+	}
+	if c.SheetName == "" {
+		c.SheetName = "Sheet1"
+	}
+	if c.SheetMaxRows <= 1 || c.SheetMaxRows > ExcelMaxRows {
+		c.SheetMaxRows = ExcelMaxRows
+	}
+	c.Format.setDefaults()
+}
+
+// CellFormat 单元格格式
+// 文本类文件（csv、ndjson）中的日期时间按 Layout 格式化，xlsx 中写入日期时间类型的单元格并使用 Excel 格式。
+type CellFormat struct {
+	DateLayout      string         // 文本中日期的格式，默认 2006-01-02
+	TimeLayout      string         // 文本中时间的格式，默认 2006-01-02 15:04:05
+	ExcelDateFormat string         // xlsx 日期单元格格式，默认 yyyy-mm-dd
+	ExcelTimeFormat string         // xlsx 时间单元格格式，默认 yyyy-mm-dd hh:mm:ss
+	Location        *time.Location // 时间输出的时区，也用于解析不带时区的时间字符串，默认本地时区
+}
+
+func (f *CellFormat) setDefaults() {
+	if f.DateLayout == "" {
+		f.DateLayout = time.DateOnly
+	}
+	if f.TimeLayout == "" {
+		f.TimeLayout = time.DateTime
+	}
+	if f.ExcelDateFormat == "" {
+		f.ExcelDateFormat = "yyyy-mm-dd"
+	}
+	if f.ExcelTimeFormat == "" {
+		f.ExcelTimeFormat = "yyyy-mm-dd hh:mm:ss"
+	}
+	if f.Location == nil {
+		f.Location = time.Local
+	}
 }
 
 type Callback func(et ExportType) error
@@ -0,0 +1,112 @@
+package exportkit
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"helay.net/go/utils/v3/tools"
+)
+
+// RowWriter 按行写入导出文件
+// 写入的值已经按列类型规范化，为 nil、int64、float64、bool、time.Time 或 string，
+// 规范化失败的值保持原样，实现需要能够处理与列类型不一致的值。
+type RowWriter interface {
+	WriteHeader(columns []Column) error // 写入表头，在 WriteRow 之前调用一次
+	WriteRow(row []any) error           // 写入一行，值的顺序与表头一致
+	Close() error                       // 完成写入并输出剩余内容，不会关闭底层的 io.Writer
+}
+
+// Factory 创建 RowWriter
+type Factory func(w io.Writer, cfg ExportConfig) (RowWriter, error)
+
+// Format 导出文件格式
+type Format struct {
+	ContentType string  // http 响应的 Content-Type
+	Extension   string  // 文件扩展名，不带点
+	New         Factory // 创建写入器
+}
+
+var (
+	formatsMu sync.RWMutex
+	formats   = map[ExportType]Format{
+		ExportTypeCsv:     {ContentType: "text/csv; charset=utf-8", Extension: "csv", New: newCsvWriter},
+		ExportTypeExcel:   {ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", Extension: "xlsx", New: newXlsxWriter},
+		ExportTypeNDJSON:  {ContentType: "application/x-ndjson", Extension: "ndjson", New: newNDJSONWriter},
+		ExportTypeParquet: {ContentType: "application/vnd.apache.parquet", Extension: "parquet", New: newParquetWriter},
+	}
+)
+
+// Register 注册导出格式，已存在时覆盖
+func Register(et ExportType, f Format) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	formats[et] = f
+}
+
+// Lookup 获取导出格式
+func Lookup(et ExportType) (Format, bool) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	f, ok := formats[et]
+	return f, ok
+}
+
+// NewWriter 创建导出类型对应的 RowWriter，写入的值会先按列类型规范化
+func NewWriter(et ExportType, w io.Writer, cfg ExportConfig) (RowWriter, error) {
+	f, ok := Lookup(et)
+	if !ok {
+		return nil, fmt.Errorf("%w %s", ErrExportTypeNotSupport, et)
+	}
+	cfg.SetDefaults()
+	rw, err := f.New(w, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &normalizeWriter{RowWriter: rw, loc: cfg.Format.Location}, nil
+}
+
+// normalizeWriter 按列类型规范化每一行的值
+type normalizeWriter struct {
+	RowWriter
+	columns []Column
+	loc     *time.Location
+	row     []any
+}
+
+func (w *normalizeWriter) WriteHeader(columns []Column) error {
+	w.columns = columns
+	w.row = make([]any, len(columns))
+	return w.RowWriter.WriteHeader(columns)
+}
+
+func (w *normalizeWriter) WriteRow(row []any) error {
+	if len(row) != len(w.columns) {
+		return fmt.Errorf("数据列数 %d 与表头列数 %d 不一致", len(row), len(w.columns))
+	}
+	for i, v := range row {
+		w.row[i], _ = Normalize(v, w.columns[i].Kind, w.loc)
+	}
+	return w.RowWriter.WriteRow(w.row)
+}
+
+// Text 把规范化后的值格式化为文本，用于 csv 等文本格式
+func (f CellFormat) Text(v any, kind ColumnKind) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	case time.Time:
+		return val.In(f.Location).Format(tools.Ternary(kind == KindDate, f.DateLayout, f.TimeLayout))
+	}
+	return tools.Any2string(v)
+}
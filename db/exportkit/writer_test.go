@@ -0,0 +1,157 @@
+package exportkit
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/xuri/excelize/v2"
+)
+
+var testColumns = []Column{
+	{Name: "id", Title: "编号", Kind: KindInt},
+	{Name: "name", Title: "名称", Kind: KindString},
+	{Name: "price", Kind: KindFloat},
+	{Name: "ok", Kind: KindBool},
+	{Name: "day", Kind: KindDate},
+	{Name: "at", Kind: KindTime},
+}
+
+func writeAll(t *testing.T, et ExportType, cfg ExportConfig, rows ...[]any) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	rw, err := NewWriter(et, &buf, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = rw.WriteHeader(testColumns); err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range rows {
+		if err = rw.WriteRow(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = rw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestXlsxSheetSplit(t *testing.T) {
+	var rows [][]any
+	for i := range 5 {
+		rows = append(rows, []any{i, "n", 1.5, true, nil, nil})
+	}
+	// 每个sheet 3 行，包括表头，5 行数据拆分为 3 个sheet
+	data := writeAll(t, ExportTypeExcel, ExportConfig{SheetName: "数据", SheetMaxRows: 3}, rows...)
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	sheets := f.GetSheetList()
+	if strings.Join(sheets, ",") != "数据,数据_2,数据_3" {
+		t.Fatalf("sheets = %v", sheets)
+	}
+	for i, want := range []int{3, 3, 2} {
+		got, _ := f.GetRows(sheets[i])
+		if len(got) != want || got[0][0] != "编号" {
+			t.Fatalf("sheet %s rows = %v", sheets[i], got)
+		}
+	}
+	if v, _ := f.GetCellValue("数据_3", "A2"); v != "4" {
+		t.Fatalf("last row id = %q", v)
+	}
+}
+
+func TestParquetSchemaAndNull(t *testing.T) {
+	day := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	at := time.Date(2024, 3, 5, 10, 20, 30, 0, time.UTC)
+	data := writeAll(t, ExportTypeParquet, ExportConfig{},
+		[]any{int64(1), "a", 2.5, true, day, at},
+		[]any{nil, nil, nil, nil, nil, nil},
+	)
+	f, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"id": "INT64", "name": "BYTE_ARRAY", "price": "DOUBLE", "ok": "BOOLEAN", "day": "INT32", "at": "INT64"}
+	for _, field := range f.Schema().Fields() {
+		if !field.Optional() {
+			t.Fatalf("%s should be optional", field.Name())
+		}
+		if got := field.Type().Kind().String(); got != want[field.Name()] {
+			t.Fatalf("%s type = %s, want %s", field.Name(), got, want[field.Name()])
+		}
+	}
+
+	type record struct {
+		ID    *int64     `parquet:"id,optional"`
+		Name  *string    `parquet:"name,optional"`
+		Price *float64   `parquet:"price,optional"`
+		OK    *bool      `parquet:"ok,optional"`
+		Day   *time.Time `parquet:"day,optional,date"`
+		At    *time.Time `parquet:"at,optional,timestamp(millisecond)"`
+	}
+	rd := parquet.NewGenericReader[record](bytes.NewReader(data))
+	defer rd.Close()
+	got := make([]record, 2)
+	if n, _ := rd.Read(got); n != 2 {
+		t.Fatalf("read %d rows", n)
+	}
+	if got[0].ID == nil || *got[0].ID != 1 || *got[0].Name != "a" || *got[0].Price != 2.5 || !*got[0].OK ||
+		!got[0].Day.Equal(day) || !got[0].At.Equal(at) {
+		t.Fatalf("row 0 = %+v", got[0])
+	}
+	// 空值写入 null
+	if got[1].ID != nil || got[1].Name != nil || got[1].Price != nil || got[1].OK != nil || got[1].Day != nil || got[1].At != nil {
+		t.Fatalf("row 1 = %+v, want all null", got[1])
+	}
+}
+
+func TestParquetMismatch(t *testing.T) {
+	w, err := NewWriter(ExportTypeParquet, io.Discard, ExportConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = w.WriteHeader(testColumns); err != nil {
+		t.Fatal(err)
+	}
+	// 与列类型不一致的值返回错误，不写入 null
+	err = w.WriteRow([]any{int64(1), "a", "bad", true, nil, nil})
+	if err == nil || !strings.Contains(err.Error(), "price") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestCsvAndNDJSON(t *testing.T) {
+	at := time.Date(2024, 3, 5, 10, 20, 30, 0, time.UTC)
+	cfg := ExportConfig{Format: CellFormat{Location: time.UTC}}
+	row := []any{"7", []byte("名"), "1.25", 1, at, at.Format(time.RFC3339)}
+
+	csv := string(writeAll(t, ExportTypeCsv, cfg, row))
+	want := "\xef\xbb\xbf编号,名称,price,ok,day,at\n7,名,1.25,true,2024-03-05,2024-03-05 10:20:30\n"
+	if csv != want {
+		t.Fatalf("csv = %q", csv)
+	}
+
+	ndjson := string(writeAll(t, ExportTypeNDJSON, cfg, row))
+	want = `{"id":7,"name":"名","price":1.25,"ok":true,"day":"2024-03-05","at":"2024-03-05 10:20:30"}` + "\n"
+	if ndjson != want {
+		t.Fatalf("ndjson = %q", ndjson)
+	}
+}
+
+func TestNormalizeOverflow(t *testing.T) {
+	if v, ok := Normalize(uint64(math.MaxUint64), KindInt, time.UTC); ok || v != uint64(math.MaxUint64) {
+		t.Fatalf("normalize = %v, %v, want original value", v, ok)
+	}
+	if v, ok := Normalize(uint32(7), KindInt, time.UTC); !ok || v != int64(7) {
+		t.Fatalf("normalize = %v, %v", v, ok)
+	}
+}
@@ -0,0 +1,136 @@
+package exportkit
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+	"helay.net/go/utils/v3/excelTools"
+	"helay.net/go/utils/v3/tools"
+)
+
+// xlsxWriter 流式写入 xlsx，行数达到 SheetMaxRows 时新建 sheet 并重复写入表头
+// 新 sheet 命名为 SheetName_2、SheetName_3 ……
+type xlsxWriter struct {
+	w         io.Writer
+	cfg       ExportConfig
+	file      *excelize.File
+	sw        *excelize.StreamWriter
+	header    []any
+	columns   []Column
+	sheets    int
+	rows      int // 当前 sheet 已写入的行数，包括表头
+	dateStyle int
+	timeStyle int
+	cells     []any
+}
+
+func newXlsxWriter(w io.Writer, cfg ExportConfig) (RowWriter, error) {
+	return &xlsxWriter{w: w, cfg: cfg}, nil
+}
+
+func (w *xlsxWriter) WriteHeader(columns []Column) error {
+	w.columns = columns
+	w.header = make([]any, len(columns))
+	for i, c := range columns {
+		w.header[i] = c.title()
+	}
+	w.cells = make([]any, len(columns))
+	w.file = excelize.NewFile()
+	var err error
+	if w.dateStyle, err = w.file.NewStyle(&excelize.Style{CustomNumFmt: &w.cfg.Format.ExcelDateFormat}); err != nil {
+		return fmt.Errorf("创建日期格式失败：%w", err)
+	}
+	if w.timeStyle, err = w.file.NewStyle(&excelize.Style{CustomNumFmt: &w.cfg.Format.ExcelTimeFormat}); err != nil {
+		return fmt.Errorf("创建时间格式失败：%w", err)
+	}
+	return w.nextSheet()
+}
+
+// nextSheet 结束当前 sheet，新建 sheet 并写入表头
+func (w *xlsxWriter) nextSheet() error {
+	if w.sw != nil {
+		if err := w.sw.Flush(); err != nil {
+			return fmt.Errorf("写入sheet失败：%w", err)
+		}
+	}
+	w.sheets++
+	name := w.sheetName(w.sheets)
+	if w.sheets == 1 {
+		// 新文件默认有一个 Sheet1
+		if err := w.file.SetSheetName(w.file.GetSheetName(0), name); err != nil {
+			return fmt.Errorf("设置sheet名称失败：%w", err)
+		}
+	} else if _, err := w.file.NewSheet(name); err != nil {
+		return fmt.Errorf("创建sheet失败：%w", err)
+	}
+	sw, err := w.file.NewStreamWriter(name)
+	if err != nil {
+		return fmt.Errorf("创建流写入器失败：%w", err)
+	}
+	w.sw = sw
+	w.rows = 1
+	if err = sw.SetRow("A1", w.header); err != nil {
+		return fmt.Errorf("导出excel失败，表头写入失败：%w", err)
+	}
+	return nil
+}
+
+// sheetName 第 n 个 sheet 的名称，不超过 excel 的31个字符限制
+func (w *xlsxWriter) sheetName(n int) string {
+	if n == 1 {
+		return truncate(w.cfg.SheetName, 31)
+	}
+	suffix := fmt.Sprintf("_%d", n)
+	return truncate(w.cfg.SheetName, 31-len(suffix)) + suffix
+}
+
+func (w *xlsxWriter) WriteRow(row []any) error {
+	if w.rows >= w.cfg.SheetMaxRows {
+		if err := w.nextSheet(); err != nil {
+			return err
+		}
+	}
+	for i, v := range row {
+		w.cells[i] = w.cell(v, w.columns[i].Kind)
+	}
+	w.rows++
+	cell, _ := excelize.CoordinatesToCellName(1, w.rows)
+	if err := w.sw.SetRow(cell, w.cells); err != nil {
+		return fmt.Errorf("写入Excel行失败：%w", err)
+	}
+	return nil
+}
+
+// cell 数值和布尔写入对应类型，时间转换到配置的时区后按日期格式写入
+func (w *xlsxWriter) cell(v any, kind ColumnKind) any {
+	t, ok := v.(time.Time)
+	if !ok {
+		return v
+	}
+	// excel 的时间没有时区，按配置时区的墙上时间写入
+	t = t.In(w.cfg.Format.Location)
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	return excelize.Cell{StyleID: tools.Ternary(kind == KindDate, w.dateStyle, w.timeStyle), Value: t}
+}
+
+func (w *xlsxWriter) Close() error {
+	if w.file == nil {
+		return nil
+	}
+	defer excelTools.CloseExcel(w.file)
+	if err := w.sw.Flush(); err != nil {
+		return fmt.Errorf("写入sheet失败：%w", err)
+	}
+	return w.file.Write(w.w)
+}
+
+// truncate 按字符截断
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
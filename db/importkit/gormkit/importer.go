@@ -0,0 +1,149 @@
+package gormkit
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"helay.net/go/utils/v3/db/importkit"
+	"helay.net/go/utils/v3/excelTools"
+)
+
+// Importer 把文件数据导入到模型 T 对应的表
+// 表头可以匹配字段的 excel 标签、数据库列名和结构体字段名，字段名统一为数据库列名；
+// 写入时按冲突列 upsert，冲突时只更新该行有值的列，空单元格不会覆盖已有数据。
+// Importer 不保存导入过程中的状态，可以并发调用 Import。
+type Importer[T any] struct {
+	db       *gorm.DB
+	schema   *schema.Schema
+	conflict []string
+	fields   []importkit.Field
+}
+
+// record Prepare 转换后的一行数据
+type record[T any] struct {
+	value   *T
+	columns []string // 有值的列，按 fields 的顺序排列
+}
+
+var _ importkit.Sink = (*Importer[struct{}])(nil)
+
+// New 创建导入对象，conflict 为 upsert 的冲突列，默认为主键
+func New[T any](db *gorm.DB, conflict ...string) (*Importer[T], error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return nil, fmt.Errorf("解析模型失败 %w", err)
+	}
+	i := &Importer[T]{db: db, schema: stmt.Schema, conflict: conflict}
+	if len(i.conflict) == 0 {
+		i.conflict = stmt.Schema.PrimaryFieldDBNames
+	}
+	for _, f := range stmt.Schema.Fields {
+		if f.DBName == "" || (!f.Creatable && !f.Updatable) {
+			continue
+		}
+		aliases := []string{f.Name}
+		if header, ok := excelTools.TagHeader(f.Tag.Get("excel")); ok {
+			aliases = append(aliases, header)
+		}
+		i.fields = append(i.fields, importkit.Field{Name: f.DBName, Aliases: aliases})
+	}
+	return i, nil
+}
+
+// Import 导入数据，opt.SheetName 为空且模型实现了 excelTools.HasSheetName 时读取模型指定的sheet
+func (i *Importer[T]) Import(ctx context.Context, rd io.Reader, opt importkit.Options) (*importkit.Report, error) {
+	if opt.SheetName == "" {
+		if h, ok := any(new(T)).(excelTools.HasSheetName); ok {
+			opt.SheetName = h.SheetName()
+		}
+	}
+	return importkit.Run(ctx, rd, i, opt)
+}
+
+func (i *Importer[T]) Fields() []importkit.Field {
+	return i.fields
+}
+
+// Prepare 把一行数据转换为 *T，字符串按字段类型转换，空值保持零值并且冲突时不更新
+func (i *Importer[T]) Prepare(row map[string]any) (any, error) {
+	value := new(T)
+	rv := reflect.ValueOf(value).Elem()
+	var reasons []string
+	present := make(map[string]bool, len(row))
+	for name, v := range row {
+		f := i.schema.LookUpField(name)
+		if f == nil || v == nil || v == "" {
+			continue
+		}
+		if err := f.Set(context.Background(), rv, v); err != nil {
+			reasons = append(reasons, fmt.Sprintf("%s 类型转换失败 %s", name, err.Error()))
+			continue
+		}
+		present[f.DBName] = true
+	}
+	if len(reasons) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(reasons, "；"))
+	}
+	r := &record[T]{value: value}
+	for _, f := range i.fields {
+		if present[f.Name] {
+			r.columns = append(r.columns, f.Name)
+		}
+	}
+	return r, nil
+}
+
+// Write 批量 upsert，有值的列相同的数据一起写入
+func (i *Importer[T]) Write(ctx context.Context, records []any) error {
+	var (
+		keys   []string
+		groups = make(map[string][]*T)
+		cols   = make(map[string][]string)
+	)
+	for _, v := range records {
+		r := v.(*record[T])
+		key := strings.Join(r.columns, ",")
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+			cols[key] = r.columns
+		}
+		groups[key] = append(groups[key], r.value)
+	}
+	return i.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, key := range keys {
+			batch := groups[key]
+			if err := tx.Clauses(i.onConflict(cols[key])).Create(&batch).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// onConflict 冲突时更新 columns 中的非冲突列
+func (i *Importer[T]) onConflict(columns []string) clause.OnConflict {
+	conflict := make(map[string]bool, len(i.conflict))
+	oc := clause.OnConflict{}
+	for _, name := range i.conflict {
+		conflict[name] = true
+		oc.Columns = append(oc.Columns, clause.Column{Name: name})
+	}
+	var updates []string
+	for _, name := range columns {
+		if !conflict[name] {
+			updates = append(updates, name)
+		}
+	}
+	if len(updates) == 0 {
+		oc.DoNothing = true
+		return oc
+	}
+	oc.DoUpdates = clause.AssignmentColumns(updates)
+	return oc
+}
@@ -0,0 +1,88 @@
+package gormkit
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"helay.net/go/utils/v3/dataType"
+	"helay.net/go/utils/v3/db/importkit"
+)
+
+type testUser struct {
+	ID   int    `gorm:"primaryKey" excel:"编号;col:A"`
+	Name string `excel:"姓名;required"`
+	Age  int    `excel:"年龄;note:周岁"`
+}
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1) // 每个连接都是独立的内存数据库
+	if err = db.AutoMigrate(&testUser{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestImporterTagHeaders(t *testing.T) {
+	db := newTestDB(t)
+	db.Create(&testUser{ID: 1, Name: "old", Age: 1})
+
+	im, err := New[testUser](db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := "编号,姓名,年龄\n1,张三,20\n2,李四,30\n"
+	report, err := im.Import(context.Background(), strings.NewReader(data), importkit.Options{FileType: dataType.CSV})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Imported != 2 || report.Rejected != 0 {
+		t.Fatalf("report = %+v", report)
+	}
+	var users []testUser
+	db.Order("id").Find(&users)
+	if len(users) != 2 || users[0].Name != "张三" || users[0].Age != 20 || users[1].Name != "李四" {
+		t.Fatalf("users = %+v", users)
+	}
+}
+
+func TestImporterEmptyCellKeepsValue(t *testing.T) {
+	db := newTestDB(t)
+	db.Create([]testUser{{ID: 1, Name: "old", Age: 18}, {ID: 2, Name: "old", Age: 19}})
+
+	im, err := New[testUser](db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 第 1 行年龄为空，只更新姓名；第 2 行姓名为空，只更新年龄
+	data := "编号,姓名,年龄\n1,张三,\n2,,30\n3,王五,40\n"
+	report, err := im.Import(context.Background(), strings.NewReader(data), importkit.Options{FileType: dataType.CSV})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Imported != 3 {
+		t.Fatalf("report = %+v", report)
+	}
+	var users []testUser
+	db.Order("id").Find(&users)
+	want := []testUser{{ID: 1, Name: "张三", Age: 18}, {ID: 2, Name: "old", Age: 30}, {ID: 3, Name: "王五", Age: 40}}
+	if len(users) != len(want) {
+		t.Fatalf("users = %+v", users)
+	}
+	for i := range want {
+		if users[i] != want[i] {
+			t.Fatalf("users = %+v, want %+v", users, want)
+		}
+	}
+}
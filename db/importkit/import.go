@@ -0,0 +1,225 @@
+package importkit
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"helay.net/go/utils/v3/dataType"
+	"helay.net/go/utils/v3/tools"
+	"helay.net/go/utils/v3/tools/decode/streamdecode_json"
+)
+
+// pending 等待写入的行
+type pending struct {
+	row    int
+	data   map[string]any
+	record any
+}
+
+type pipeline struct {
+	ctx     context.Context
+	sink    Sink
+	opt     Options
+	fields  map[string]string // 表头 → 字段名
+	report  *Report
+	seq     int // 已读取的行数，包括空行，用于计算行号
+	batch   []pending
+	records []any
+}
+
+// Run 流式读取 csv、excel、json 数据并导入到 sink
+// 每一行依次经过：表头映射 → 校验 → 格式化 → Sink.Prepare 转换，失败的行记录到 Report.Errors，
+// 通过的行按 BatchSize 批量写入；批量写入失败时逐行重试，找出出错的行。
+// 返回错误时 Report 仍然包含已处理的结果。
+func Run(ctx context.Context, rd io.Reader, sink Sink, opt Options) (*Report, error) {
+	opt.setDefaults()
+	fields := sink.Fields()
+	p := &pipeline{
+		ctx:    ctx,
+		sink:   sink,
+		opt:    opt,
+		fields: headerMapping(fields, opt.Headers),
+		report: &Report{DryRun: opt.DryRun, order: make(map[string]int, len(fields))},
+	}
+	p.report.fields = p.fields
+	for i, f := range fields {
+		p.report.order[f.Name] = i
+	}
+	im := streamdecode_json.New(ctx, opt.FileType, rd)
+	im.SetFieldRow(opt.FieldRow)
+	im.SetDataRow(opt.DataRow)
+	im.SetSep(opt.Sep)
+	im.SetSheetName(opt.SheetName)
+	im.SetBigLine(opt.BigLine)
+	bytes, err := im.ImportWithHandler(p.handle)
+	p.report.Bytes = bytes
+	if err == nil {
+		err = p.flush()
+	}
+	sort.SliceStable(p.report.Errors, func(i, j int) bool {
+		return p.report.Errors[i].Row < p.report.Errors[j].Row
+	})
+	p.progress(err == nil)
+	return p.report, err
+}
+
+// headerMapping 表头 → 字段名，与 excelTools.GetTplSheetFieldIndex 一样按去除首尾空格后的表头文本匹配
+func headerMapping(fields []Field, headers map[string]string) map[string]string {
+	mapping := make(map[string]string)
+	for _, f := range fields {
+		mapping[f.Name] = f.Name
+		for _, alias := range f.Aliases {
+			mapping[strings.TrimSpace(alias)] = f.Name
+		}
+	}
+	for header, name := range headers {
+		mapping[strings.TrimSpace(header)] = name
+	}
+	return mapping
+}
+
+func (p *pipeline) handle(ctx context.Context, obj map[string]any) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	p.seq++
+	row := tools.Ternary(p.opt.FileType == dataType.CSV || p.opt.FileType == dataType.Excel, p.opt.DataRow+p.seq-1, p.seq)
+	mapped, empty := p.mapRow(obj)
+	if empty {
+		return nil
+	}
+	p.report.Total++
+	reasons := p.validate(mapped)
+	if len(reasons) == 0 {
+		reasons = p.format(mapped)
+	}
+	var record any
+	if len(reasons) == 0 {
+		var err error
+		if record, err = p.sink.Prepare(mapped); err != nil {
+			reasons = append(reasons, err.Error())
+		}
+	}
+	if len(reasons) > 0 {
+		return p.reject(row, obj, reasons...)
+	}
+	p.batch = append(p.batch, pending{row: row, data: obj, record: record})
+	if len(p.batch) >= p.opt.BatchSize {
+		if err := p.flush(); err != nil {
+			return err
+		}
+		p.progress(false)
+	}
+	return nil
+}
+
+// mapRow 按表头映射字段，未匹配的表头忽略；所有值都为空的行视为空行
+func (p *pipeline) mapRow(obj map[string]any) (map[string]any, bool) {
+	mapped := make(map[string]any, len(obj))
+	empty := true
+	for header, v := range obj {
+		name, ok := p.fields[strings.TrimSpace(header)]
+		if !ok {
+			continue
+		}
+		if s, isStr := v.(string); isStr {
+			v = strings.TrimSpace(s)
+		}
+		if v != nil && v != "" {
+			empty = false
+		}
+		mapped[name] = v
+	}
+	return mapped, empty
+}
+
+func (p *pipeline) validate(mapped map[string]any) []string {
+	var reasons []string
+	for _, rule := range p.opt.Rules {
+		if verr, ok := rule.Validate(mapped); !ok {
+			if verr == nil {
+				reasons = append(reasons, "校验失败")
+				continue
+			}
+			reasons = append(reasons, tools.Ternary(verr.Message == "", verr.Error(), verr.Message))
+		}
+	}
+	return reasons
+}
+
+// format 格式化字段值，空值不处理
+func (p *pipeline) format(mapped map[string]any) []string {
+	var reasons []string
+	for name, rule := range p.opt.Formats {
+		v, ok := mapped[name]
+		if !ok || v == nil || v == "" {
+			continue
+		}
+		out, err := rule.Format(v)
+		if err != nil {
+			reasons = append(reasons, fmt.Sprintf("%s 格式化失败 %s", name, err.Error()))
+			continue
+		}
+		mapped[name] = out
+	}
+	return reasons
+}
+
+func (p *pipeline) reject(row int, data map[string]any, reasons ...string) error {
+	p.report.Rejected++
+	p.report.Errors = append(p.report.Errors, RowError{Row: row, Data: data, Reasons: reasons})
+	if p.opt.MaxErrors > 0 && p.report.Rejected > p.opt.MaxErrors {
+		return ErrTooManyErrors
+	}
+	return nil
+}
+
+// flush 写入当前批次，批量写入失败时逐行写入定位错误行
+func (p *pipeline) flush() error {
+	if len(p.batch) == 0 {
+		return nil
+	}
+	defer func() {
+		p.batch = p.batch[:0]
+	}()
+	if p.opt.DryRun {
+		p.report.Imported += len(p.batch)
+		return nil
+	}
+	p.records = p.records[:0]
+	for _, item := range p.batch {
+		p.records = append(p.records, item.record)
+	}
+	if err := p.sink.Write(p.ctx, p.records); err == nil {
+		p.report.Imported += len(p.batch)
+		return nil
+	}
+	for _, item := range p.batch {
+		if err := p.ctx.Err(); err != nil {
+			return err
+		}
+		if err := p.sink.Write(p.ctx, []any{item.record}); err != nil {
+			if err = p.reject(item.row, item.data, "写入失败 "+err.Error()); err != nil {
+				return err
+			}
+			continue
+		}
+		p.report.Imported++
+	}
+	return nil
+}
+
+func (p *pipeline) progress(done bool) {
+	if p.opt.OnProgress == nil {
+		return
+	}
+	p.opt.OnProgress(Progress{
+		Total:    p.report.Total,
+		Imported: p.report.Imported,
+		Rejected: p.report.Rejected,
+		Done:     done,
+	})
+}
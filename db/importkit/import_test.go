@@ -0,0 +1,118 @@
+package importkit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+	"helay.net/go/utils/v3/dataType"
+	"helay.net/go/utils/v3/db/exportkit"
+)
+
+// memorySink 记录写入的数据，name 为 bad 的记录写入失败
+type memorySink struct {
+	written []string
+	writes  int
+}
+
+func (s *memorySink) Fields() []Field {
+	return []Field{{Name: "name", Aliases: []string{"姓名"}}, {Name: "age", Aliases: []string{"年龄"}}}
+}
+
+func (s *memorySink) Prepare(row map[string]any) (any, error) {
+	if row["age"] == "x" {
+		return nil, errors.New("年龄不是数字")
+	}
+	return row["name"], nil
+}
+
+func (s *memorySink) Write(_ context.Context, records []any) error {
+	s.writes++
+	for _, r := range records {
+		if r == "bad" {
+			return errors.New("写入失败")
+		}
+	}
+	for _, r := range records {
+		s.written = append(s.written, r.(string))
+	}
+	return nil
+}
+
+func runCSV(t *testing.T, sink Sink, data string, opt Options) (*Report, error) {
+	t.Helper()
+	opt.FileType = dataType.CSV
+	return Run(context.Background(), strings.NewReader(data), sink, opt)
+}
+
+func TestRunBatchFallback(t *testing.T) {
+	sink := &memorySink{}
+	report, err := runCSV(t, sink, "姓名,年龄\na,1\nbad,2\nc,x\n\nd,4\n", Options{BatchSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Total != 4 || report.Imported != 2 || report.Rejected != 2 {
+		t.Fatalf("report = %+v", report)
+	}
+	// 一次批量写入失败，之后逐行写入 3 条
+	if sink.writes != 4 || strings.Join(sink.written, ",") != "a,d" {
+		t.Fatalf("writes = %d, written = %v", sink.writes, sink.written)
+	}
+	if report.Errors[0].Row != 3 || report.Errors[1].Row != 4 {
+		t.Fatalf("error rows = %d, %d", report.Errors[0].Row, report.Errors[1].Row)
+	}
+}
+
+func TestRunDryRun(t *testing.T) {
+	sink := &memorySink{}
+	report, err := runCSV(t, sink, "姓名,年龄\na,1\nb,x\n", Options{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sink.writes != 0 || !report.DryRun || report.Imported != 1 || report.Rejected != 1 {
+		t.Fatalf("writes = %d, report = %+v", sink.writes, report)
+	}
+}
+
+func TestRunMaxErrors(t *testing.T) {
+	sink := &memorySink{}
+	report, err := runCSV(t, sink, "姓名,年龄\na,x\nb,x\nc,x\nd,1\n", Options{MaxErrors: 1})
+	if !errors.Is(err, ErrTooManyErrors) {
+		t.Fatalf("err = %v", err)
+	}
+	if report.Rejected != 2 || sink.writes != 0 {
+		t.Fatalf("writes = %d, report = %+v", sink.writes, report)
+	}
+}
+
+func TestReportWriteErrors(t *testing.T) {
+	report, err := runCSV(t, &memorySink{}, "年龄,姓名,备注\nx,a,n1\n1,b,n2\n", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err = report.WriteErrors(&buf, exportkit.ExportTypeExcel); err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := f.GetRows("错误数据")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"行号", "姓名", "年龄", "备注", "错误原因"}, {"2", "a", "x", "n1", "年龄不是数字"}}
+	if len(rows) != len(want) {
+		t.Fatalf("rows = %v", rows)
+	}
+	for i := range want {
+		if strings.Join(rows[i], "|") != strings.Join(want[i], "|") {
+			t.Fatalf("row %d = %v, want %v", i, rows[i], want[i])
+		}
+	}
+}
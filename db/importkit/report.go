@@ -0,0 +1,88 @@
+package importkit
+
+import (
+	"io"
+	"sort"
+	"strings"
+
+	"helay.net/go/utils/v3/db/exportkit"
+)
+
+// Report 导入结果
+type Report struct {
+	Total    int        `json:"total"`    // 数据行数，不包括空行
+	Imported int        `json:"imported"` // 写入的行数，dry-run 时为校验通过的行数
+	Rejected int        `json:"rejected"` // 被拒绝的行数
+	Bytes    int64      `json:"bytes"`    // 读取的字节数
+	DryRun   bool       `json:"dry_run"`  // 是否只校验未写入
+	Errors   []RowError `json:"errors"`   // 被拒绝的行，按行号顺序
+
+	fields map[string]string // 表头 → 字段名
+	order  map[string]int    // 字段名 → 在模型中的位置
+}
+
+// ErrorHeaders 错误报告的表头：已映射的表头按模型字段顺序在前，未映射的按名称排序在后
+func (r *Report) ErrorHeaders() []string {
+	seen := make(map[string]struct{})
+	var headers []string
+	for _, e := range r.Errors {
+		for h := range e.Data {
+			if _, ok := seen[h]; !ok {
+				seen[h] = struct{}{}
+				headers = append(headers, h)
+			}
+		}
+	}
+	rank := func(h string) int {
+		if i, ok := r.order[r.fields[strings.TrimSpace(h)]]; ok {
+			return i
+		}
+		return len(r.order)
+	}
+	sort.Slice(headers, func(i, j int) bool {
+		ri, rj := rank(headers[i]), rank(headers[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return headers[i] < headers[j]
+	})
+	return headers
+}
+
+// WriteErrors 输出错误报告，包含行号、原始数据和拒绝原因，可以修正后重新导入
+// et 通常为 exportkit.ExportTypeExcel，也可以是其它已注册的导出类型。
+func (r *Report) WriteErrors(w io.Writer, et exportkit.ExportType, config ...exportkit.ExportConfig) error {
+	var cfg exportkit.ExportConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.SheetName == "" {
+		cfg.SheetName = "错误数据"
+	}
+	rw, err := exportkit.NewWriter(et, w, cfg)
+	if err != nil {
+		return err
+	}
+	headers := r.ErrorHeaders()
+	columns := make([]exportkit.Column, 0, len(headers)+2)
+	columns = append(columns, exportkit.Column{Name: "row", Title: "行号", Kind: exportkit.KindInt})
+	for _, h := range headers {
+		columns = append(columns, exportkit.Column{Name: h, Title: h, Kind: exportkit.KindString})
+	}
+	columns = append(columns, exportkit.Column{Name: "reasons", Title: "错误原因", Kind: exportkit.KindString})
+	if err = rw.WriteHeader(columns); err != nil {
+		return err
+	}
+	row := make([]any, len(columns))
+	for _, e := range r.Errors {
+		row[0] = e.Row
+		for i, h := range headers {
+			row[i+1] = e.Data[h]
+		}
+		row[len(row)-1] = strings.Join(e.Reasons, "；")
+		if err = rw.WriteRow(row); err != nil {
+			return err
+		}
+	}
+	return rw.Close()
+}
@@ -0,0 +1,72 @@
+package importkit
+
+import (
+	"context"
+	"errors"
+
+	"helay.net/go/utils/v3/dataType"
+	"helay.net/go/utils/v3/rule-engine/formatter"
+	"helay.net/go/utils/v3/rule-engine/validator"
+)
+
+var ErrTooManyErrors = errors.New("错误行数超过上限，导入已中止")
+
+// Field 目标字段
+type Field struct {
+	Name    string   // 字段名，校验规则、格式化规则和 Sink 使用的名称
+	Aliases []string // 可以匹配的表头名称
+}
+
+// Sink 导入的目标
+// Prepare 和 Write 在同一个协程中按顺序调用。
+type Sink interface {
+	Fields() []Field                                // 目标字段，按模型顺序
+	Prepare(row map[string]any) (any, error)        // 把映射后的一行转换为记录，错误作为该行的拒绝原因
+	Write(ctx context.Context, records []any) error // 批量写入记录
+}
+
+// Options 导入配置
+type Options struct {
+	FileType  dataType.ContentType `json:"file_type" yaml:"file_type" ini:"file_type"`    // 文件类型，PlainText 为 json lines
+	SheetName string               `json:"sheet_name" yaml:"sheet_name" ini:"sheet_name"` // excel 读取的sheet，默认第一个
+	FieldRow  int                  `json:"field_row" yaml:"field_row" ini:"field_row"`    // 表头所在行，默认 1
+	DataRow   int                  `json:"data_row" yaml:"data_row" ini:"data_row"`       // 数据开始行，默认表头的下一行
+	Sep       string               `json:"sep" yaml:"sep" ini:"sep"`                      // csv 分割符，只能是一个字符，默认逗号
+	BigLine   bool                 `json:"big_line" yaml:"big_line" ini:"big_line"`       // json lines 是否启用大行模式
+
+	Headers map[string]string                    `json:"headers" yaml:"headers" ini:"-"` // 表头 → 字段名，优先于 Sink 的默认别名
+	Rules   []*validator.Rule                    `json:"rules" yaml:"rules" ini:"-"`     // 校验规则，字段名为映射后的字段名
+	Formats map[string]formatter.FormatRule[any] `json:"formats" yaml:"formats" ini:"-"` // 字段名 → 格式化规则，在校验通过后执行
+
+	BatchSize  int            `json:"batch_size" yaml:"batch_size" ini:"batch_size"` // 每批写入的行数，默认 500
+	DryRun     bool           `json:"dry_run" yaml:"dry_run" ini:"dry_run"`          // 只校验不写入
+	MaxErrors  int            `json:"max_errors" yaml:"max_errors" ini:"max_errors"` // 错误行数上限，超过后中止导入，0 不限制
+	OnProgress func(Progress) `json:"-" yaml:"-" ini:"-"`                            // 进度回调，每批处理完成后调用
+}
+
+func (o *Options) setDefaults() {
+	if o.FieldRow <= 0 {
+		o.FieldRow = 1
+	}
+	if o.DataRow <= o.FieldRow {
+		o.DataRow = o.FieldRow + 1
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = 500
+	}
+}
+
+// Progress 导入进度
+type Progress struct {
+	Total    int  `json:"total"`    // 已读取的数据行数
+	Imported int  `json:"imported"` // 已写入的行数，dry-run 时为校验通过的行数
+	Rejected int  `json:"rejected"` // 被拒绝的行数
+	Done     bool `json:"done"`     // 是否已完成
+}
+
+// RowError 被拒绝的行
+type RowError struct {
+	Row     int            `json:"row"`     // 在源文件中的行号，json 为第几个对象
+	Data    map[string]any `json:"data"`    // 原始数据，键为源文件的表头
+	Reasons []string       `json:"reasons"` // 拒绝原因
+}
@@ -102,7 +102,7 @@ type Net struct {
 	// 如果为零或正数，则启用保活。
 	// 如果为负数，则禁用保活。
 	KeepAlive time.Duration `yaml:"keep_alive" json:"keep_alive"`
-	Proxy     string        `yaml:"proxy" json:"proxy"`
+	Proxy     string        `yaml:"proxy" json:"proxy" valid:"url"`
 }
 
 // Metadata 是 Client 使用的元数据管理属性的命名空间，
@@ -345,7 +345,7 @@ type Config struct {
 	// 内部和外部通道中缓冲的事件数。这允许生产者和消费者
 	// 在用户代码工作时在后台继续处理一些消息，大大提高了吞吐量。
 	// 默认为 256。
-	ChannelBufferSize int `yaml:"channel_buffer_size" json:"channel_buffer_size"`
+	ChannelBufferSize int `yaml:"channel_buffer_size" json:"channel_buffer_size" valid:"min=0"`
 	// ApiVersionsRequest 决定 Sarama 是否应在其初始连接过程中
 	// 向每个代理发送 ApiVersionsRequest 消息。这默认为 `true` 以匹配官方 Java 客户端
 	// 和大多数第三方客户端。
@@ -2,120 +2,65 @@
 package localredis
 
 import (
-	"context"
-	"fmt"
 	"strconv"
+	"strings"
 	"time"
-
-	"github.com/redis/go-redis/v9"
 )
 
-// Get 实现，增加过期检查
-func (l *LocalCache) Get(ctx context.Context, key string) *redis.StringCmd {
-	cmd := redis.NewStringCmd(ctx)
-
-	// 检查是否过期
-	if expiry, ok := l.expiryMap.Load(key); ok {
-		if expiry.(time.Time).Before(time.Now()) {
-			l.kvData.Delete(key)
-			l.expiryMap.Delete(key)
-			cmd.SetErr(redis.Nil) // 键过期视为不存在
-			return cmd
-		}
-	}
-
-	if val, ok := l.kvData.Load(key); ok {
-		if str, ok := val.(string); ok {
-			cmd.SetVal(str)
-			return cmd
-		}
-	}
-
-	cmd.SetErr(redis.Nil) // 键不存在
-	return cmd
+// 连接和服务端命令
+var baseCommands = map[string]command{
+	"ping":     {fn: cmdPing, arity: -1},
+	"echo":     {fn: cmdEcho, arity: 2},
+	"select":   {fn: cmdOK, arity: 2}, // 只有一个库
+	"quit":     {fn: cmdOK, arity: 1},
+	"watch":    {fn: cmdOK, arity: -2}, // 由 LocalCache.Watch 串行化事务
+	"unwatch":  {fn: cmdOK, arity: 1},
+	"multi":    {fn: cmdOK, arity: 1},
+	"discard":  {fn: cmdOK, arity: 1},
+	"dbsize":   {fn: cmdDBSize, arity: 1},
+	"flushdb":  {fn: cmdFlush, arity: -1},
+	"flushall": {fn: cmdFlush, arity: -1},
+	"time":     {fn: cmdTime, arity: 1},
 }
 
-// Set 实现
-func (l *LocalCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd {
-	l.kvData.Store(key, value)
+func cmdOK(l *LocalCache, args []string) (any, error) {
+	return statusOK, nil
+}
 
-	if expiration > 0 {
-		// 存储过期时间
-		l.expiryMap.Store(key, time.Now().Add(expiration))
-	} else {
-		// 如果没有设置过期时间，确保删除可能存在的过期时间
-		l.expiryMap.Delete(key)
+func cmdPing(l *LocalCache, args []string) (any, error) {
+	switch len(args) {
+	case 1:
+		return status("PONG"), nil
+	case 2:
+		return args[1], nil
 	}
-
-	return redis.NewStatusCmd(ctx, "OK")
+	return nil, errWrongArgs(args[0])
 }
 
-// Del 实现
-func (l *LocalCache) Del(ctx context.Context, keys ...string) *redis.IntCmd {
-	cmd := redis.NewIntCmd(ctx)
-	count := int64(0)
-
-	for _, key := range keys {
-		// 删除普通键
-		if _, loaded := l.kvData.LoadAndDelete(key); loaded {
-			count++
-		}
-		l.expiryMap.Delete(key)
+func cmdEcho(l *LocalCache, args []string) (any, error) {
+	return args[1], nil
+}
 
-		// 删除哈希表
-		l.hMu.Lock()
-		if _, ok := l.hData[key]; ok {
-			delete(l.hData, key)
-			count++
+func cmdDBSize(l *LocalCache, args []string) (any, error) {
+	now := time.Now()
+	var n int64
+	for _, it := range l.data {
+		if !it.expired(now) {
+			n++
 		}
-		l.hMu.Unlock()
 	}
-
-	cmd.SetVal(count)
-	return cmd
+	return n, nil
 }
 
-// HIncrBy 对哈希表中指定字段的值进行整数增量操作
-func (l *LocalCache) HIncrBy(ctx context.Context, key, field string, incr int64) *redis.IntCmd {
-	cmd := redis.NewIntCmd(ctx)
-
-	l.hMu.Lock()
-	h, ok := l.hData[key]
-	if !ok {
-		h = &hMap{data: make(map[string]interface{})}
-		l.hData[key] = h
-	}
-	l.hMu.Unlock()
-
-	h.mu.Lock()
-	defer h.mu.Unlock()
-
-	var current int64
-	switch val := h.data[field].(type) {
-	case nil:
-		current = 0 // 字段不存在视为0
-	case int:
-		current = int64(val)
-	case int64:
-		current = val
-	case float64:
-		current = int64(val) // 浮点数截断
-	case string:
-		// 尝试解析字符串
-		if num, err := strconv.ParseInt(val, 10, 64); err == nil {
-			current = num
-		} else {
-			cmd.SetErr(fmt.Errorf("ERR hash value is not an integer"))
-			return cmd
-		}
-	default:
-		cmd.SetErr(fmt.Errorf("ERR hash value is not an integer"))
-		return cmd
+func cmdFlush(l *LocalCache, args []string) (any, error) {
+	if len(args) > 2 || (len(args) == 2 && !strings.EqualFold(args[1], "async") && !strings.EqualFold(args[1], "sync")) {
+		return nil, ErrSyntax
 	}
+	clear(l.data)
+	return statusOK, nil
+}
 
-	newVal := current + incr
-	h.data[field] = newVal // 存储为int64类型
-	cmd.SetVal(newVal)
-
-	return cmd
+func cmdTime(l *LocalCache, args []string) (any, error) {
+	now := time.Now()
+	return []any{strconv.FormatInt(now.Unix(), 10), strconv.FormatInt(int64(now.Nanosecond()/1000), 10)}, nil
 }
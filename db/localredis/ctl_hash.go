@@ -2,197 +2,245 @@
 package localredis
 
 import (
-	"context"
-	"encoding/json"
-	"fmt"
+	"math"
 	"strconv"
-
-	"github.com/redis/go-redis/v9"
+	"strings"
 )
 
-// HGet 实现
-func (l *LocalCache) HGet(ctx context.Context, key, field string) *redis.StringCmd {
-	cmd := redis.NewStringCmd(ctx)
-
-	l.hMu.RLock()
-	h, ok := l.hData[key]
-	l.hMu.RUnlock()
-
-	if !ok {
-		cmd.SetErr(redis.Nil)
-		return cmd
-	}
-
-	h.mu.RLock()
-	defer h.mu.RUnlock()
-
-	if val, ok := h.data[field]; ok {
-		// 自动转换为字符串
-		switch v := val.(type) {
-		case string:
-			cmd.SetVal(v)
-		case []byte:
-			cmd.SetVal(string(v))
-		case int, int64, float64:
-			cmd.SetVal(fmt.Sprintf("%v", v))
-		default:
-			cmd.SetVal(fmt.Sprintf("%v", v))
-		}
-	} else {
-		cmd.SetErr(redis.Nil)
-	}
-	return cmd
+// 哈希命令
+var hashCommands = map[string]command{
+	"hset":         {fn: cmdHSet, arity: -4},
+	"hmset":        {fn: cmdHSet, arity: -4},
+	"hsetnx":       {fn: cmdHSetNX, arity: 4},
+	"hget":         {fn: cmdHGet, arity: 3},
+	"hmget":        {fn: cmdHMGet, arity: -3},
+	"hgetall":      {fn: cmdHGetAll, arity: 2},
+	"hdel":         {fn: cmdHDel, arity: -3},
+	"hexists":      {fn: cmdHExists, arity: 3},
+	"hlen":         {fn: cmdHLen, arity: 2},
+	"hstrlen":      {fn: cmdHStrLen, arity: 3},
+	"hkeys":        {fn: cmdHKeys, arity: 2},
+	"hvals":        {fn: cmdHVals, arity: 2},
+	"hincrby":      {fn: cmdHIncrBy, arity: 4},
+	"hincrbyfloat": {fn: cmdHIncrByFloat, arity: 4},
+	"hscan":        {fn: cmdHScan, arity: -3},
 }
 
-// HSet 实现
-func (l *LocalCache) HSet(ctx context.Context, key string, values ...interface{}) *redis.IntCmd {
-	cmd := redis.NewIntCmd(ctx)
+func newHash() map[string]string {
+	return make(map[string]string)
+}
 
-	// 处理map参数
-	if len(values) == 1 {
-		if m, ok := values[0].(map[string]interface{}); ok {
-			values = make([]interface{}, 0, len(m)*2)
-			for k, v := range m {
-				values = append(values, k, v)
-			}
+// HSET key field value [field value ...]，HMSET 返回 OK
+func cmdHSet(l *LocalCache, args []string) (any, error) {
+	if len(args)%2 != 0 {
+		return nil, errWrongArgs(args[0])
+	}
+	h, err := lookupOrCreate(l, args[1], newHash)
+	if err != nil {
+		return nil, err
+	}
+	var n int64
+	for i := 2; i < len(args); i += 2 {
+		if _, ok := h[args[i]]; !ok {
+			n++
 		}
+		h[args[i]] = args[i+1]
 	}
-
-	if len(values)%2 != 0 {
-		cmd.SetErr(ErrInvalidArgNum)
-		return cmd
+	if strings.EqualFold(args[0], "hmset") {
+		return statusOK, nil
 	}
+	return n, nil
+}
 
-	l.hMu.Lock()
-	h, ok := l.hData[key]
-	if !ok {
-		h = &hMap{data: make(map[string]interface{})} // 修改初始化
-		l.hData[key] = h
+func cmdHSetNX(l *LocalCache, args []string) (any, error) {
+	h, err := lookupOrCreate(l, args[1], newHash)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := h[args[2]]; ok {
+		return int64(0), nil
 	}
-	l.hMu.Unlock()
+	h[args[2]] = args[3]
+	return int64(1), nil
+}
 
-	h.mu.Lock()
-	defer h.mu.Unlock()
+func cmdHGet(l *LocalCache, args []string) (any, error) {
+	h, ok, err := lookupAs[map[string]string](l, args[1])
+	if err != nil || !ok {
+		return nil, err
+	}
+	if v, ok := h[args[2]]; ok {
+		return v, nil
+	}
+	return nil, nil
+}
 
-	count := int64(0)
-	for i := 0; i < len(values); i += 2 {
-		if field, ok := values[i].(string); ok {
-			h.data[field] = values[i+1] // 直接存储原始值
-			count++
+func cmdHMGet(l *LocalCache, args []string) (any, error) {
+	h, _, err := lookupAs[map[string]string](l, args[1])
+	if err != nil {
+		return nil, err
+	}
+	out := make([]any, len(args)-2)
+	for i, field := range args[2:] {
+		if v, ok := h[field]; ok {
+			out[i] = v
 		}
 	}
+	return out, nil
+}
+
+func cmdHGetAll(l *LocalCache, args []string) (any, error) {
+	h, _, err := lookupAs[map[string]string](l, args[1])
+	if err != nil {
+		return nil, err
+	}
+	out := make([]any, 0, len(h)*2)
+	for field, v := range h {
+		out = append(out, field, v)
+	}
+	return out, nil
+}
 
-	cmd.SetVal(count)
-	return cmd
-}
-
-// HGetAll 实现
-func (l *LocalCache) HGetAll(ctx context.Context, key string) *redis.MapStringStringCmd {
-	cmd := redis.NewMapStringStringCmd(ctx)
-
-	l.hMu.RLock()
-	h, ok := l.hData[key]
-	l.hMu.RUnlock()
-
-	if !ok {
-		cmd.SetErr(redis.Nil) // 键不存在时返回 redis.Nil
-		return cmd
-	}
-
-	h.mu.RLock()
-	defer h.mu.RUnlock()
-
-	result := make(map[string]string, len(h.data))
-	for field, value := range h.data {
-		// 统一转换为字符串
-		switch v := value.(type) {
-		case string:
-			result[field] = v
-		case []byte:
-			result[field] = string(v)
-		case int, int8, int16, int32, int64,
-			uint, uint8, uint16, uint32, uint64:
-			result[field] = fmt.Sprintf("%d", v)
-		case float32, float64:
-			result[field] = strconv.FormatFloat(v.(float64), 'f', -1, 64)
-		case bool:
-			if v {
-				result[field] = "1"
-			} else {
-				result[field] = "0"
-			}
-		case nil:
-			result[field] = ""
-		default:
-			// 其他类型使用 JSON 序列化
-			jsonData, err := json.Marshal(v)
-			if err != nil {
-				result[field] = fmt.Sprintf("%v", v)
-			} else {
-				result[field] = string(jsonData)
-			}
+func cmdHDel(l *LocalCache, args []string) (any, error) {
+	h, ok, err := lookupAs[map[string]string](l, args[1])
+	if err != nil || !ok {
+		return int64(0), err
+	}
+	var n int64
+	for _, field := range args[2:] {
+		if _, ok := h[field]; ok {
+			delete(h, field)
+			n++
 		}
 	}
+	l.dropIfEmpty(args[1])
+	return n, nil
+}
+
+func cmdHExists(l *LocalCache, args []string) (any, error) {
+	h, _, err := lookupAs[map[string]string](l, args[1])
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := h[args[2]]; ok {
+		return int64(1), nil
+	}
+	return int64(0), nil
+}
+
+func cmdHLen(l *LocalCache, args []string) (any, error) {
+	h, _, err := lookupAs[map[string]string](l, args[1])
+	if err != nil {
+		return nil, err
+	}
+	return int64(len(h)), nil
+}
 
-	cmd.SetVal(result)
-	return cmd
+func cmdHStrLen(l *LocalCache, args []string) (any, error) {
+	h, _, err := lookupAs[map[string]string](l, args[1])
+	if err != nil {
+		return nil, err
+	}
+	return int64(len(h[args[2]])), nil
 }
 
-// HMGet 实现
-func (l *LocalCache) HMGet(ctx context.Context, key string, fields ...string) *redis.SliceCmd {
-	cmd := redis.NewSliceCmd(ctx)
-	result := make([]interface{}, len(fields))
+func cmdHKeys(l *LocalCache, args []string) (any, error) {
+	h, _, err := lookupAs[map[string]string](l, args[1])
+	if err != nil {
+		return nil, err
+	}
+	out := make([]any, 0, len(h))
+	for field := range h {
+		out = append(out, field)
+	}
+	return out, nil
+}
 
-	l.hMu.RLock()
-	h, ok := l.hData[key]
-	l.hMu.RUnlock()
+func cmdHVals(l *LocalCache, args []string) (any, error) {
+	h, _, err := lookupAs[map[string]string](l, args[1])
+	if err != nil {
+		return nil, err
+	}
+	out := make([]any, 0, len(h))
+	for _, v := range h {
+		out = append(out, v)
+	}
+	return out, nil
+}
 
-	if !ok {
-		// 键不存在时所有字段返回nil
-		for i := range result {
-			result[i] = nil
+// HIncrBy 对哈希表中指定字段的值进行整数增量操作
+func cmdHIncrBy(l *LocalCache, args []string) (any, error) {
+	incr, err := parseInt(args[3])
+	if err != nil {
+		return nil, err
+	}
+	h, err := lookupOrCreate(l, args[1], newHash)
+	if err != nil {
+		return nil, err
+	}
+	var current int64 // 字段不存在视为0
+	if v, ok := h[args[2]]; ok {
+		if current, err = strconv.ParseInt(v, 10, 64); err != nil {
+			l.dropIfEmpty(args[1])
+			return nil, ErrHashNotInteger
 		}
-		cmd.SetVal(result)
-		return cmd
-	}
-
-	h.mu.RLock()
-	defer h.mu.RUnlock()
-
-	for i, field := range fields {
-		if val, exists := h.data[field]; exists {
-			// 类型转换逻辑（与HGetAll保持一致）
-			switch v := val.(type) {
-			case string:
-				result[i] = v
-			case []byte:
-				result[i] = string(v)
-			case int, int8, int16, int32, int64,
-				uint, uint8, uint16, uint32, uint64:
-				result[i] = fmt.Sprintf("%d", v)
-			case float32, float64:
-				result[i] = strconv.FormatFloat(v.(float64), 'f', -1, 64)
-			case bool:
-				if v {
-					result[i] = "1"
-				} else {
-					result[i] = "0"
-				}
-			case nil:
-				result[i] = ""
-			default:
-				jsonData, err := json.Marshal(v)
-				if err != nil {
-					result[i] = fmt.Sprintf("%v", v)
-				} else {
-					result[i] = string(jsonData)
-				}
-			}
-		} else {
-			result[i] = nil
+	}
+	if (incr > 0 && current > math.MaxInt64-incr) || (incr < 0 && current < math.MinInt64-incr) {
+		l.dropIfEmpty(args[1])
+		return nil, ErrOverflow
+	}
+	current += incr
+	h[args[2]] = formatInt(current)
+	return current, nil
+}
+
+func cmdHIncrByFloat(l *LocalCache, args []string) (any, error) {
+	incr, err := parseFloat(args[3])
+	if err != nil {
+		return nil, err
+	}
+	h, err := lookupOrCreate(l, args[1], newHash)
+	if err != nil {
+		return nil, err
+	}
+	var current float64
+	if v, ok := h[args[2]]; ok {
+		if current, err = parseFloat(v); err != nil {
+			l.dropIfEmpty(args[1])
+			return nil, ErrHashNotFloat
 		}
 	}
+	current += incr
+	if math.IsInf(current, 0) || math.IsNaN(current) {
+		l.dropIfEmpty(args[1])
+		return nil, ErrOverflow
+	}
+	s := formatFloat(current)
+	h[args[2]] = s
+	return s, nil
+}
 
-	cmd.SetVal(result)
-	return cmd
+// HSCAN key cursor [MATCH pattern] [COUNT count] [NOVALUES]
+func cmdHScan(l *LocalCache, args []string) (any, error) {
+	so, err := parseScanArgs(args[2], args[3:])
+	if err != nil {
+		return nil, err
+	}
+	h, _, err := lookupAs[map[string]string](l, args[1])
+	if err != nil {
+		return nil, err
+	}
+	fields := make([]string, 0, len(h))
+	for field := range h {
+		fields = append(fields, field)
+	}
+	page, next := scanKeys(fields, so, nil)
+	out := make([]any, 0, len(page)*2)
+	for _, field := range page {
+		out = append(out, field)
+		if !so.noValue {
+			out = append(out, h[field])
+		}
+	}
+	return []any{strconv.FormatUint(next, 10), out}, nil
 }
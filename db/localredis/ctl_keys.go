@@ -0,0 +1,274 @@
+// noinspection all
+package localredis
+
+import (
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 键空间命令
+var keyCommands = map[string]command{
+	"del":         {fn: cmdDel, arity: -2},
+	"unlink":      {fn: cmdDel, arity: -2},
+	"exists":      {fn: cmdExists, arity: -2},
+	"touch":       {fn: cmdExists, arity: -2},
+	"type":        {fn: cmdType, arity: 2},
+	"expire":      {fn: cmdExpire, arity: -3},
+	"pexpire":     {fn: cmdExpire, arity: -3},
+	"expireat":    {fn: cmdExpire, arity: -3},
+	"pexpireat":   {fn: cmdExpire, arity: -3},
+	"ttl":         {fn: cmdTTL, arity: 2},
+	"pttl":        {fn: cmdTTL, arity: 2},
+	"expiretime":  {fn: cmdExpireTime, arity: 2},
+	"pexpiretime": {fn: cmdExpireTime, arity: 2},
+	"persist":     {fn: cmdPersist, arity: 2},
+	"keys":        {fn: cmdKeys, arity: 2},
+	"scan":        {fn: cmdScan, arity: -2},
+	"randomkey":   {fn: cmdRandomKey, arity: 1},
+	"rename":      {fn: cmdRename, arity: 3},
+	"renamenx":    {fn: cmdRename, arity: 3},
+	"copy":        {fn: cmdCopy, arity: -3},
+}
+
+func cmdDel(l *LocalCache, args []string) (any, error) {
+	var n int64
+	for _, key := range args[1:] {
+		if l.lookup(key) != nil {
+			delete(l.data, key)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// EXISTS 重复的 key 会重复计数，与redis一致
+func cmdExists(l *LocalCache, args []string) (any, error) {
+	var n int64
+	for _, key := range args[1:] {
+		if l.lookup(key) != nil {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func cmdType(l *LocalCache, args []string) (any, error) {
+	it := l.lookup(args[1])
+	if it == nil {
+		return status("none"), nil
+	}
+	return status(typeName(it.val)), nil
+}
+
+// EXPIRE key seconds [NX|XX|GT|LT]，PEXPIRE、EXPIREAT、PEXPIREAT 同理
+func cmdExpire(l *LocalCache, args []string) (any, error) {
+	name := strings.ToLower(args[0])
+	unit := map[string]string{"expire": "ex", "pexpire": "px", "expireat": "exat", "pexpireat": "pxat"}[name]
+	n, err := parseInt(args[2])
+	if err != nil {
+		return nil, err
+	}
+	var flag string
+	switch len(args) {
+	case 3:
+	case 4:
+		flag = strings.ToLower(args[3])
+		if flag != "nx" && flag != "xx" && flag != "gt" && flag != "lt" {
+			return nil, ErrSyntax
+		}
+	default:
+		return nil, ErrSyntax
+	}
+	it := l.lookup(args[1])
+	if it == nil {
+		return int64(0), nil
+	}
+	now := time.Now()
+	var expire time.Time
+	switch unit {
+	case "ex":
+		expire = now.Add(time.Duration(n) * time.Second)
+	case "px":
+		expire = now.Add(time.Duration(n) * time.Millisecond)
+	case "exat":
+		expire = time.Unix(n, 0)
+	case "pxat":
+		expire = time.UnixMilli(n)
+	}
+	// 没有过期时间视为无限大
+	switch flag {
+	case "nx":
+		if !it.expire.IsZero() {
+			return int64(0), nil
+		}
+	case "xx":
+		if it.expire.IsZero() {
+			return int64(0), nil
+		}
+	case "gt":
+		if it.expire.IsZero() || !expire.After(it.expire) {
+			return int64(0), nil
+		}
+	case "lt":
+		if !it.expire.IsZero() && !expire.Before(it.expire) {
+			return int64(0), nil
+		}
+	}
+	// 过期时间已经过去，直接删除
+	if !expire.After(now) {
+		delete(l.data, args[1])
+		return int64(1), nil
+	}
+	it.expire = expire
+	return int64(1), nil
+}
+
+// TTL 键不存在返回 -2，没有过期时间返回 -1
+func cmdTTL(l *LocalCache, args []string) (any, error) {
+	it := l.lookup(args[1])
+	if it == nil {
+		return int64(-2), nil
+	}
+	if it.expire.IsZero() {
+		return int64(-1), nil
+	}
+	remain := time.Until(it.expire)
+	if strings.EqualFold(args[0], "pttl") {
+		return remain.Milliseconds(), nil
+	}
+	return int64((remain + 500*time.Millisecond) / time.Second), nil
+}
+
+func cmdExpireTime(l *LocalCache, args []string) (any, error) {
+	it := l.lookup(args[1])
+	if it == nil {
+		return int64(-2), nil
+	}
+	if it.expire.IsZero() {
+		return int64(-1), nil
+	}
+	if strings.EqualFold(args[0], "pexpiretime") {
+		return it.expire.UnixMilli(), nil
+	}
+	return it.expire.Unix(), nil
+}
+
+func cmdPersist(l *LocalCache, args []string) (any, error) {
+	it := l.lookup(args[1])
+	if it == nil || it.expire.IsZero() {
+		return int64(0), nil
+	}
+	it.expire = time.Time{}
+	return int64(1), nil
+}
+
+// liveKeys 所有未过期的 key
+func (l *LocalCache) liveKeys() []string {
+	now := time.Now()
+	keys := make([]string, 0, len(l.data))
+	for key, it := range l.data {
+		if !it.expired(now) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func cmdKeys(l *LocalCache, args []string) (any, error) {
+	out := make([]any, 0)
+	for _, key := range l.liveKeys() {
+		if globMatch(args[1], key) {
+			out = append(out, key)
+		}
+	}
+	return out, nil
+}
+
+// SCAN cursor [MATCH pattern] [COUNT count] [TYPE type]
+func cmdScan(l *LocalCache, args []string) (any, error) {
+	so, err := parseScanArgs(args[1], args[2:])
+	if err != nil {
+		return nil, err
+	}
+	var filter func(string) bool
+	if so.typ != "" {
+		filter = func(key string) bool {
+			return typeName(l.data[key].val) == so.typ
+		}
+	}
+	page, next := scanKeys(l.liveKeys(), so, filter)
+	return []any{strconv.FormatUint(next, 10), stringsReply(page)}, nil
+}
+
+func cmdRandomKey(l *LocalCache, args []string) (any, error) {
+	keys := l.liveKeys()
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	return keys[rand.IntN(len(keys))], nil
+}
+
+// RENAME / RENAMENX 过期时间随键一起转移
+func cmdRename(l *LocalCache, args []string) (any, error) {
+	src, dst := args[1], args[2]
+	it := l.lookup(src)
+	if it == nil {
+		return nil, ErrNoSuchKey
+	}
+	nx := strings.EqualFold(args[0], "renamenx")
+	if nx && l.lookup(dst) != nil {
+		return int64(0), nil
+	}
+	delete(l.data, src)
+	l.data[dst] = it
+	if nx {
+		return int64(1), nil
+	}
+	return statusOK, nil
+}
+
+// COPY source destination [DB db] [REPLACE]
+func cmdCopy(l *LocalCache, args []string) (any, error) {
+	replace := false
+	for i := 3; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "replace":
+			replace = true
+		case "db":
+			// 只有一个库，只接受 0
+			if i++; i >= len(args) || args[i] != "0" {
+				return nil, ErrSyntax
+			}
+		default:
+			return nil, ErrSyntax
+		}
+	}
+	it := l.lookup(args[1])
+	if it == nil {
+		return int64(0), nil
+	}
+	if l.lookup(args[2]) != nil && !replace {
+		return int64(0), nil
+	}
+	l.data[args[2]] = &item{val: cloneValue(it.val), expire: it.expire}
+	return int64(1), nil
+}
+
+// cloneValue 深拷贝值
+func cloneValue(v any) any {
+	switch val := v.(type) {
+	case map[string]string:
+		return maps.Clone(val)
+	case *list:
+		return &list{items: slices.Clone(val.items)}
+	case map[string]struct{}:
+		return maps.Clone(val)
+	case *zset:
+		return val.clone()
+	}
+	return v
+}
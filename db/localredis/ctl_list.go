@@ -0,0 +1,377 @@
+// noinspection all
+package localredis
+
+import (
+	"slices"
+	"strings"
+)
+
+// 列表命令，不支持阻塞类命令（BLPOP 等）
+var listCommands = map[string]command{
+	"lpush":     {fn: cmdPush, arity: -3},
+	"rpush":     {fn: cmdPush, arity: -3},
+	"lpushx":    {fn: cmdPush, arity: -3},
+	"rpushx":    {fn: cmdPush, arity: -3},
+	"lpop":      {fn: cmdPop, arity: -2},
+	"rpop":      {fn: cmdPop, arity: -2},
+	"llen":      {fn: cmdLLen, arity: 2},
+	"lrange":    {fn: cmdLRange, arity: 4},
+	"lindex":    {fn: cmdLIndex, arity: 3},
+	"lset":      {fn: cmdLSet, arity: 4},
+	"lrem":      {fn: cmdLRem, arity: 4},
+	"ltrim":     {fn: cmdLTrim, arity: 4},
+	"linsert":   {fn: cmdLInsert, arity: 5},
+	"lpos":      {fn: cmdLPos, arity: -3},
+	"rpoplpush": {fn: cmdRPopLPush, arity: 3},
+	"lmove":     {fn: cmdLMove, arity: 5},
+}
+
+func newList() *list {
+	return &list{}
+}
+
+// LPUSH / RPUSH / LPUSHX / RPUSHX
+func cmdPush(l *LocalCache, args []string) (any, error) {
+	name := strings.ToLower(args[0])
+	var (
+		lst *list
+		err error
+	)
+	if strings.HasSuffix(name, "x") {
+		// 只在列表存在时写入
+		var ok bool
+		if lst, ok, err = lookupAs[*list](l, args[1]); err != nil || !ok {
+			return int64(0), err
+		}
+	} else if lst, err = lookupOrCreate(l, args[1], newList); err != nil {
+		return nil, err
+	}
+	for _, v := range args[2:] {
+		if name[0] == 'l' {
+			lst.items = slices.Insert(lst.items, 0, v)
+		} else {
+			lst.items = append(lst.items, v)
+		}
+	}
+	return int64(len(lst.items)), nil
+}
+
+// LPOP key [count] / RPOP key [count]
+func cmdPop(l *LocalCache, args []string) (any, error) {
+	if len(args) > 3 {
+		return nil, ErrSyntax
+	}
+	count := int64(-1)
+	if len(args) == 3 {
+		n, err := parseInt(args[2])
+		if err != nil || n < 0 {
+			return nil, ErrNotInteger
+		}
+		count = n
+	}
+	lst, ok, err := lookupAs[*list](l, args[1])
+	if err != nil || !ok {
+		return nil, err
+	}
+	left := strings.EqualFold(args[0], "lpop")
+	if count < 0 {
+		return l.popOne(args[1], lst, left), nil
+	}
+	out := make([]any, 0, min(int(count), len(lst.items)))
+	for i := int64(0); i < count && len(lst.items) > 0; i++ {
+		out = append(out, l.popOne(args[1], lst, left))
+	}
+	return out, nil
+}
+
+// popOne 弹出一个元素，列表为空时删除键
+func (l *LocalCache) popOne(key string, lst *list, left bool) string {
+	var v string
+	if left {
+		v, lst.items = lst.items[0], lst.items[1:]
+	} else {
+		n := len(lst.items) - 1
+		v, lst.items = lst.items[n], lst.items[:n]
+	}
+	l.dropIfEmpty(key)
+	return v
+}
+
+func cmdLLen(l *LocalCache, args []string) (any, error) {
+	lst, ok, err := lookupAs[*list](l, args[1])
+	if err != nil || !ok {
+		return int64(0), err
+	}
+	return int64(len(lst.items)), nil
+}
+
+func cmdLRange(l *LocalCache, args []string) (any, error) {
+	start, err := parseInt(args[2])
+	if err != nil {
+		return nil, err
+	}
+	stop, err := parseInt(args[3])
+	if err != nil {
+		return nil, err
+	}
+	lst, ok, err := lookupAs[*list](l, args[1])
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return []any{}, nil
+	}
+	from, to, ok := normRange(start, stop, len(lst.items))
+	if !ok {
+		return []any{}, nil
+	}
+	return stringsReply(lst.items[from:to]), nil
+}
+
+// listIndex 转换列表下标，越界返回 -1
+func listIndex(index int64, n int) int {
+	if index < 0 {
+		index += int64(n)
+	}
+	if index < 0 || index >= int64(n) {
+		return -1
+	}
+	return int(index)
+}
+
+func cmdLIndex(l *LocalCache, args []string) (any, error) {
+	index, err := parseInt(args[2])
+	if err != nil {
+		return nil, err
+	}
+	lst, ok, err := lookupAs[*list](l, args[1])
+	if err != nil || !ok {
+		return nil, err
+	}
+	i := listIndex(index, len(lst.items))
+	if i < 0 {
+		return nil, nil
+	}
+	return lst.items[i], nil
+}
+
+func cmdLSet(l *LocalCache, args []string) (any, error) {
+	index, err := parseInt(args[2])
+	if err != nil {
+		return nil, err
+	}
+	lst, ok, err := lookupAs[*list](l, args[1])
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNoSuchKey
+	}
+	i := listIndex(index, len(lst.items))
+	if i < 0 {
+		return nil, ErrIndexOutOfRange
+	}
+	lst.items[i] = args[3]
+	return statusOK, nil
+}
+
+// LREM key count element
+// count>0 从头开始删除 count 个，count<0 从尾开始删除，count=0 删除全部
+func cmdLRem(l *LocalCache, args []string) (any, error) {
+	count, err := parseInt(args[2])
+	if err != nil {
+		return nil, err
+	}
+	lst, ok, err := lookupAs[*list](l, args[1])
+	if err != nil || !ok {
+		return int64(0), err
+	}
+	var removed int64
+	limit := count
+	if limit < 0 {
+		limit = -limit
+	}
+	match := func(v string) bool {
+		if v == args[3] && (limit == 0 || removed < limit) {
+			removed++
+			return true
+		}
+		return false
+	}
+	if count < 0 {
+		slices.Reverse(lst.items)
+		lst.items = slices.DeleteFunc(lst.items, match)
+		slices.Reverse(lst.items)
+	} else {
+		lst.items = slices.DeleteFunc(lst.items, match)
+	}
+	l.dropIfEmpty(args[1])
+	return removed, nil
+}
+
+func cmdLTrim(l *LocalCache, args []string) (any, error) {
+	start, err := parseInt(args[2])
+	if err != nil {
+		return nil, err
+	}
+	stop, err := parseInt(args[3])
+	if err != nil {
+		return nil, err
+	}
+	lst, ok, err := lookupAs[*list](l, args[1])
+	if err != nil || !ok {
+		return statusOK, err
+	}
+	from, to, ok := normRange(start, stop, len(lst.items))
+	if !ok {
+		lst.items = nil
+	} else {
+		lst.items = slices.Clone(lst.items[from:to])
+	}
+	l.dropIfEmpty(args[1])
+	return statusOK, nil
+}
+
+// LINSERT key BEFORE|AFTER pivot element
+func cmdLInsert(l *LocalCache, args []string) (any, error) {
+	var after bool
+	switch strings.ToLower(args[2]) {
+	case "before":
+	case "after":
+		after = true
+	default:
+		return nil, ErrSyntax
+	}
+	lst, ok, err := lookupAs[*list](l, args[1])
+	if err != nil || !ok {
+		return int64(0), err
+	}
+	i := slices.Index(lst.items, args[3])
+	if i < 0 {
+		return int64(-1), nil
+	}
+	if after {
+		i++
+	}
+	lst.items = slices.Insert(lst.items, i, args[4])
+	return int64(len(lst.items)), nil
+}
+
+// LPOS key element [RANK rank] [COUNT num-matches] [MAXLEN len]
+func cmdLPos(l *LocalCache, args []string) (any, error) {
+	rank, count, maxLen := int64(1), int64(-1), int64(0)
+	for i := 3; i < len(args); i += 2 {
+		if i+1 >= len(args) {
+			return nil, ErrSyntax
+		}
+		n, err := parseInt(args[i+1])
+		if err != nil {
+			return nil, err
+		}
+		switch strings.ToLower(args[i]) {
+		case "rank":
+			if n == 0 {
+				return nil, ErrSyntax
+			}
+			rank = n
+		case "count":
+			if n < 0 {
+				return nil, ErrSyntax
+			}
+			count = n
+		case "maxlen":
+			if n < 0 {
+				return nil, ErrSyntax
+			}
+			maxLen = n
+		default:
+			return nil, ErrSyntax
+		}
+	}
+	lst, _, err := lookupAs[*list](l, args[1])
+	if err != nil {
+		return nil, err
+	}
+	var items []string
+	if lst != nil {
+		items = lst.items
+	}
+	var (
+		matches []any
+		skip    = max(rank, -rank) - 1
+		n       = len(items)
+	)
+	for step := 0; step < n && (maxLen == 0 || int64(step) < maxLen); step++ {
+		i := step
+		if rank < 0 {
+			i = n - 1 - step
+		}
+		if items[i] != args[2] {
+			continue
+		}
+		if skip > 0 {
+			skip--
+			continue
+		}
+		matches = append(matches, int64(i))
+		if count < 0 || (count > 0 && int64(len(matches)) >= count) {
+			break
+		}
+	}
+	if count < 0 {
+		if len(matches) == 0 {
+			return nil, nil
+		}
+		return matches[0], nil
+	}
+	if matches == nil {
+		matches = []any{}
+	}
+	return matches, nil
+}
+
+// move 从 src 弹出一个元素并写入 dst，src 不存在时返回空
+func (l *LocalCache) move(src, dst string, fromLeft, toLeft bool) (any, error) {
+	srcList, ok, err := lookupAs[*list](l, src)
+	if err != nil || !ok {
+		return nil, err
+	}
+	// 先检查目标类型，避免弹出后写入失败
+	if _, _, err = lookupAs[*list](l, dst); err != nil {
+		return nil, err
+	}
+	v := l.popOne(src, srcList, fromLeft)
+	dstList, _ := lookupOrCreate(l, dst, newList)
+	if toLeft {
+		dstList.items = slices.Insert(dstList.items, 0, v)
+	} else {
+		dstList.items = append(dstList.items, v)
+	}
+	return v, nil
+}
+
+func cmdRPopLPush(l *LocalCache, args []string) (any, error) {
+	return l.move(args[1], args[2], false, true)
+}
+
+// LMOVE source destination LEFT|RIGHT LEFT|RIGHT
+func cmdLMove(l *LocalCache, args []string) (any, error) {
+	side := func(s string) (bool, error) {
+		switch strings.ToLower(s) {
+		case "left":
+			return true, nil
+		case "right":
+			return false, nil
+		}
+		return false, ErrSyntax
+	}
+	from, err := side(args[3])
+	if err != nil {
+		return nil, err
+	}
+	to, err := side(args[4])
+	if err != nil {
+		return nil, err
+	}
+	return l.move(args[1], args[2], from, to)
+}
@@ -0,0 +1,326 @@
+// noinspection all
+package localredis
+
+import (
+	"math/rand/v2"
+	"strconv"
+	"strings"
+)
+
+// 集合命令
+var setCommands = map[string]command{
+	"sadd":        {fn: cmdSAdd, arity: -3},
+	"srem":        {fn: cmdSRem, arity: -3},
+	"smembers":    {fn: cmdSMembers, arity: 2},
+	"sismember":   {fn: cmdSIsMember, arity: 3},
+	"smismember":  {fn: cmdSMIsMember, arity: -3},
+	"scard":       {fn: cmdSCard, arity: 2},
+	"spop":        {fn: cmdSPop, arity: -2},
+	"srandmember": {fn: cmdSRandMember, arity: -2},
+	"smove":       {fn: cmdSMove, arity: 4},
+	"sinter":      {fn: cmdSetOp, arity: -2},
+	"sunion":      {fn: cmdSetOp, arity: -2},
+	"sdiff":       {fn: cmdSetOp, arity: -2},
+	"sinterstore": {fn: cmdSetOpStore, arity: -3},
+	"sunionstore": {fn: cmdSetOpStore, arity: -3},
+	"sdiffstore":  {fn: cmdSetOpStore, arity: -3},
+	"sintercard":  {fn: cmdSInterCard, arity: -3},
+	"sscan":       {fn: cmdSScan, arity: -3},
+}
+
+func newSet() map[string]struct{} {
+	return make(map[string]struct{})
+}
+
+func setReplyOf(set map[string]struct{}) []any {
+	out := make([]any, 0, len(set))
+	for m := range set {
+		out = append(out, m)
+	}
+	return out
+}
+
+func cmdSAdd(l *LocalCache, args []string) (any, error) {
+	set, err := lookupOrCreate(l, args[1], newSet)
+	if err != nil {
+		return nil, err
+	}
+	var n int64
+	for _, m := range args[2:] {
+		if _, ok := set[m]; !ok {
+			set[m] = struct{}{}
+			n++
+		}
+	}
+	return n, nil
+}
+
+func cmdSRem(l *LocalCache, args []string) (any, error) {
+	set, ok, err := lookupAs[map[string]struct{}](l, args[1])
+	if err != nil || !ok {
+		return int64(0), err
+	}
+	var n int64
+	for _, m := range args[2:] {
+		if _, ok := set[m]; ok {
+			delete(set, m)
+			n++
+		}
+	}
+	l.dropIfEmpty(args[1])
+	return n, nil
+}
+
+func cmdSMembers(l *LocalCache, args []string) (any, error) {
+	set, _, err := lookupAs[map[string]struct{}](l, args[1])
+	if err != nil {
+		return nil, err
+	}
+	return setReplyOf(set), nil
+}
+
+func cmdSIsMember(l *LocalCache, args []string) (any, error) {
+	set, _, err := lookupAs[map[string]struct{}](l, args[1])
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := set[args[2]]; ok {
+		return int64(1), nil
+	}
+	return int64(0), nil
+}
+
+func cmdSMIsMember(l *LocalCache, args []string) (any, error) {
+	set, _, err := lookupAs[map[string]struct{}](l, args[1])
+	if err != nil {
+		return nil, err
+	}
+	out := make([]any, len(args)-2)
+	for i, m := range args[2:] {
+		out[i] = int64(0)
+		if _, ok := set[m]; ok {
+			out[i] = int64(1)
+		}
+	}
+	return out, nil
+}
+
+func cmdSCard(l *LocalCache, args []string) (any, error) {
+	set, _, err := lookupAs[map[string]struct{}](l, args[1])
+	if err != nil {
+		return nil, err
+	}
+	return int64(len(set)), nil
+}
+
+// SPOP key [count]
+func cmdSPop(l *LocalCache, args []string) (any, error) {
+	if len(args) > 3 {
+		return nil, ErrSyntax
+	}
+	count := int64(-1)
+	if len(args) == 3 {
+		n, err := parseInt(args[2])
+		if err != nil || n < 0 {
+			return nil, ErrNotInteger
+		}
+		count = n
+	}
+	set, ok, err := lookupAs[map[string]struct{}](l, args[1])
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if count < 0 {
+			return nil, nil
+		}
+		return []any{}, nil
+	}
+	// map 的遍历顺序本身是随机的
+	out := make([]any, 0)
+	for m := range set {
+		if count >= 0 && int64(len(out)) >= count {
+			break
+		}
+		delete(set, m)
+		out = append(out, m)
+		if count < 0 {
+			break
+		}
+	}
+	l.dropIfEmpty(args[1])
+	if count < 0 {
+		return out[0], nil
+	}
+	return out, nil
+}
+
+// SRANDMEMBER key [count]，count 为负数时允许重复
+func cmdSRandMember(l *LocalCache, args []string) (any, error) {
+	if len(args) > 3 {
+		return nil, ErrSyntax
+	}
+	set, ok, err := lookupAs[map[string]struct{}](l, args[1])
+	if err != nil {
+		return nil, err
+	}
+	members := make([]string, 0, len(set))
+	for m := range set {
+		members = append(members, m)
+	}
+	if len(args) == 2 {
+		if !ok {
+			return nil, nil
+		}
+		return members[rand.IntN(len(members))], nil
+	}
+	count, err := parseInt(args[2])
+	if err != nil {
+		return nil, err
+	}
+	out := make([]any, 0)
+	if !ok || count == 0 {
+		return out, nil
+	}
+	if count < 0 {
+		for i := int64(0); i < -count; i++ {
+			out = append(out, members[rand.IntN(len(members))])
+		}
+		return out, nil
+	}
+	rand.Shuffle(len(members), func(i, j int) { members[i], members[j] = members[j], members[i] })
+	return stringsReply(members[:min(int(count), len(members))]), nil
+}
+
+func cmdSMove(l *LocalCache, args []string) (any, error) {
+	src, ok, err := lookupAs[map[string]struct{}](l, args[1])
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err = lookupAs[map[string]struct{}](l, args[2]); err != nil {
+		return nil, err
+	}
+	if !ok {
+		return int64(0), nil
+	}
+	if _, ok = src[args[3]]; !ok {
+		return int64(0), nil
+	}
+	delete(src, args[3])
+	l.dropIfEmpty(args[1])
+	dst, _ := lookupOrCreate(l, args[2], newSet)
+	dst[args[3]] = struct{}{}
+	return int64(1), nil
+}
+
+// setOp 计算集合的交集、并集、差集
+func (l *LocalCache) setOp(op string, keys []string) (map[string]struct{}, error) {
+	sets := make([]map[string]struct{}, len(keys))
+	for i, key := range keys {
+		set, _, err := lookupAs[map[string]struct{}](l, key)
+		if err != nil {
+			return nil, err
+		}
+		sets[i] = set
+	}
+	out := make(map[string]struct{})
+	switch op {
+	case "sinter":
+		for m := range sets[0] {
+			in := true
+			for _, s := range sets[1:] {
+				if _, ok := s[m]; !ok {
+					in = false
+					break
+				}
+			}
+			if in {
+				out[m] = struct{}{}
+			}
+		}
+	case "sunion":
+		for _, s := range sets {
+			for m := range s {
+				out[m] = struct{}{}
+			}
+		}
+	case "sdiff":
+		for m := range sets[0] {
+			out[m] = struct{}{}
+		}
+		for _, s := range sets[1:] {
+			for m := range s {
+				delete(out, m)
+			}
+		}
+	}
+	return out, nil
+}
+
+// SINTER / SUNION / SDIFF
+func cmdSetOp(l *LocalCache, args []string) (any, error) {
+	set, err := l.setOp(strings.ToLower(args[0]), args[1:])
+	if err != nil {
+		return nil, err
+	}
+	return setReplyOf(set), nil
+}
+
+// SINTERSTORE / SUNIONSTORE / SDIFFSTORE destination key [key ...]
+func cmdSetOpStore(l *LocalCache, args []string) (any, error) {
+	op := strings.TrimSuffix(strings.ToLower(args[0]), "store")
+	set, err := l.setOp(op, args[2:])
+	if err != nil {
+		return nil, err
+	}
+	delete(l.data, args[1])
+	if len(set) > 0 {
+		l.data[args[1]] = &item{val: set}
+	}
+	return int64(len(set)), nil
+}
+
+// SINTERCARD numkeys key [key ...] [LIMIT limit]
+func cmdSInterCard(l *LocalCache, args []string) (any, error) {
+	numKeys, err := parseInt(args[1])
+	if err != nil || numKeys < 1 || int(numKeys) > len(args)-2 {
+		return nil, ErrSyntax
+	}
+	keys := args[2 : 2+numKeys]
+	var limit int64
+	if rest := args[2+numKeys:]; len(rest) > 0 {
+		if len(rest) != 2 || !strings.EqualFold(rest[0], "limit") {
+			return nil, ErrSyntax
+		}
+		if limit, err = parseInt(rest[1]); err != nil || limit < 0 {
+			return nil, ErrSyntax
+		}
+	}
+	set, err := l.setOp("sinter", keys)
+	if err != nil {
+		return nil, err
+	}
+	n := int64(len(set))
+	if limit > 0 {
+		n = min(n, limit)
+	}
+	return n, nil
+}
+
+// SSCAN key cursor [MATCH pattern] [COUNT count]
+func cmdSScan(l *LocalCache, args []string) (any, error) {
+	so, err := parseScanArgs(args[2], args[3:])
+	if err != nil {
+		return nil, err
+	}
+	set, _, err := lookupAs[map[string]struct{}](l, args[1])
+	if err != nil {
+		return nil, err
+	}
+	members := make([]string, 0, len(set))
+	for m := range set {
+		members = append(members, m)
+	}
+	page, next := scanKeys(members, so, nil)
+	return []any{strconv.FormatUint(next, 10), stringsReply(page)}, nil
+}
@@ -0,0 +1,372 @@
+// noinspection all
+package localredis
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// 字符串命令
+var stringCommands = map[string]command{
+	"get":         {fn: cmdGet, arity: 2},
+	"set":         {fn: cmdSet, arity: -3},
+	"setnx":       {fn: cmdSetNX, arity: 3},
+	"setex":       {fn: cmdSetEX, arity: 4},
+	"psetex":      {fn: cmdSetEX, arity: 4},
+	"getset":      {fn: cmdGetSet, arity: 3},
+	"getdel":      {fn: cmdGetDel, arity: 2},
+	"getex":       {fn: cmdGetEX, arity: -2},
+	"mget":        {fn: cmdMGet, arity: -2},
+	"mset":        {fn: cmdMSet, arity: -3},
+	"msetnx":      {fn: cmdMSetNX, arity: -3},
+	"incr":        {fn: cmdIncr, arity: 2},
+	"decr":        {fn: cmdIncr, arity: 2},
+	"incrby":      {fn: cmdIncr, arity: 3},
+	"decrby":      {fn: cmdIncr, arity: 3},
+	"incrbyfloat": {fn: cmdIncrByFloat, arity: 3},
+	"append":      {fn: cmdAppend, arity: 3},
+	"strlen":      {fn: cmdStrLen, arity: 2},
+	"getrange":    {fn: cmdGetRange, arity: 4},
+	"setrange":    {fn: cmdSetRange, arity: 4},
+}
+
+// getString 读取字符串类型的值
+func (l *LocalCache) getString(key string) (any, error) {
+	v, ok, err := lookupAs[string](l, key)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return v, nil
+}
+
+// expireAt 解析过期参数，unit 为 EX、PX、EXAT、PXAT
+func expireAt(unit, value string, now time.Time) (time.Time, error) {
+	n, err := parseInt(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var t time.Time
+	switch strings.ToLower(unit) {
+	case "ex":
+		if n > math.MaxInt64/int64(time.Second) {
+			return time.Time{}, ErrInvalidExpire
+		}
+		t = now.Add(time.Duration(n) * time.Second)
+	case "px":
+		if n > math.MaxInt64/int64(time.Millisecond) {
+			return time.Time{}, ErrInvalidExpire
+		}
+		t = now.Add(time.Duration(n) * time.Millisecond)
+	case "exat":
+		t = time.Unix(n, 0)
+	case "pxat":
+		t = time.UnixMilli(n)
+	default:
+		return time.Time{}, ErrSyntax
+	}
+	if n <= 0 {
+		return time.Time{}, ErrInvalidExpire
+	}
+	return t, nil
+}
+
+// SET key value [NX|XX] [GET] [EX|PX|EXAT|PXAT time|KEEPTTL]
+func cmdSet(l *LocalCache, args []string) (any, error) {
+	key, val := args[1], args[2]
+	var (
+		nx, xx, get, keepTTL bool
+		expire               time.Time
+		now                  = time.Now()
+	)
+	for i := 3; i < len(args); i++ {
+		switch opt := strings.ToLower(args[i]); opt {
+		case "nx":
+			nx = true
+		case "xx":
+			xx = true
+		case "get":
+			get = true
+		case "keepttl":
+			keepTTL = true
+		case "ex", "px", "exat", "pxat":
+			if i++; i >= len(args) || !expire.IsZero() {
+				return nil, ErrSyntax
+			}
+			t, err := expireAt(opt, args[i], now)
+			if err != nil {
+				return nil, err
+			}
+			expire = t
+		default:
+			return nil, ErrSyntax
+		}
+	}
+	if (nx && xx) || (keepTTL && !expire.IsZero()) {
+		return nil, ErrSyntax
+	}
+	old, err := l.getString(key)
+	if err != nil && get {
+		return nil, err
+	}
+	exists := l.lookup(key) != nil
+	if (nx && exists) || (xx && !exists) {
+		if get {
+			return old, nil
+		}
+		return nil, nil
+	}
+	l.setValue(key, val, keepTTL)
+	if !expire.IsZero() {
+		l.data[key].expire = expire
+	}
+	if get {
+		return old, nil
+	}
+	return statusOK, nil
+}
+
+func cmdSetNX(l *LocalCache, args []string) (any, error) {
+	if l.lookup(args[1]) != nil {
+		return int64(0), nil
+	}
+	l.setValue(args[1], args[2], false)
+	return int64(1), nil
+}
+
+// SETEX key seconds value / PSETEX key milliseconds value
+func cmdSetEX(l *LocalCache, args []string) (any, error) {
+	unit := "ex"
+	if strings.EqualFold(args[0], "psetex") {
+		unit = "px"
+	}
+	expire, err := expireAt(unit, args[2], time.Now())
+	if err != nil {
+		return nil, err
+	}
+	l.setValue(args[1], args[3], false)
+	l.data[args[1]].expire = expire
+	return statusOK, nil
+}
+
+func cmdGet(l *LocalCache, args []string) (any, error) {
+	return l.getString(args[1])
+}
+
+func cmdGetSet(l *LocalCache, args []string) (any, error) {
+	old, err := l.getString(args[1])
+	if err != nil {
+		return nil, err
+	}
+	l.setValue(args[1], args[2], false)
+	return old, nil
+}
+
+func cmdGetDel(l *LocalCache, args []string) (any, error) {
+	old, err := l.getString(args[1])
+	if err != nil || old == nil {
+		return old, err
+	}
+	delete(l.data, args[1])
+	return old, nil
+}
+
+// GETEX key [EX|PX|EXAT|PXAT time|PERSIST]
+func cmdGetEX(l *LocalCache, args []string) (any, error) {
+	var (
+		expire  time.Time
+		persist bool
+	)
+	switch len(args) {
+	case 2:
+	case 3:
+		if !strings.EqualFold(args[2], "persist") {
+			return nil, ErrSyntax
+		}
+		persist = true
+	case 4:
+		t, err := expireAt(args[2], args[3], time.Now())
+		if err != nil {
+			return nil, err
+		}
+		expire = t
+	default:
+		return nil, ErrSyntax
+	}
+	v, err := l.getString(args[1])
+	if err != nil || v == nil {
+		return v, err
+	}
+	if persist || !expire.IsZero() {
+		l.data[args[1]].expire = expire
+	}
+	return v, nil
+}
+
+func cmdMGet(l *LocalCache, args []string) (any, error) {
+	out := make([]any, 0, len(args)-1)
+	for _, key := range args[1:] {
+		// 类型不一致时返回空值，与redis一致
+		v, _ := l.getString(key)
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+func cmdMSet(l *LocalCache, args []string) (any, error) {
+	if len(args)%2 != 1 {
+		return nil, errWrongArgs(args[0])
+	}
+	for i := 1; i < len(args); i += 2 {
+		l.setValue(args[i], args[i+1], false)
+	}
+	return statusOK, nil
+}
+
+func cmdMSetNX(l *LocalCache, args []string) (any, error) {
+	if len(args)%2 != 1 {
+		return nil, errWrongArgs(args[0])
+	}
+	for i := 1; i < len(args); i += 2 {
+		if l.lookup(args[i]) != nil {
+			return int64(0), nil
+		}
+	}
+	for i := 1; i < len(args); i += 2 {
+		l.setValue(args[i], args[i+1], false)
+	}
+	return int64(1), nil
+}
+
+// incrBy 整数自增，保留原有过期时间
+func (l *LocalCache) incrBy(key string, delta int64) (int64, error) {
+	v, err := l.getString(key)
+	if err != nil {
+		return 0, err
+	}
+	var cur int64
+	if v != nil {
+		if cur, err = parseInt(v.(string)); err != nil {
+			return 0, err
+		}
+	}
+	if (delta > 0 && cur > math.MaxInt64-delta) || (delta < 0 && cur < math.MinInt64-delta) {
+		return 0, ErrOverflow
+	}
+	cur += delta
+	l.setValue(key, formatInt(cur), true)
+	return cur, nil
+}
+
+// INCR / DECR / INCRBY / DECRBY
+func cmdIncr(l *LocalCache, args []string) (any, error) {
+	delta := int64(1)
+	if len(args) == 3 {
+		n, err := parseInt(args[2])
+		if err != nil {
+			return nil, err
+		}
+		delta = n
+	}
+	if strings.HasPrefix(strings.ToLower(args[0]), "decr") {
+		if delta == math.MinInt64 {
+			return nil, ErrOverflow
+		}
+		delta = -delta
+	}
+	n, err := l.incrBy(args[1], delta)
+	if err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+func cmdIncrByFloat(l *LocalCache, args []string) (any, error) {
+	delta, err := parseFloat(args[2])
+	if err != nil {
+		return nil, err
+	}
+	v, err := l.getString(args[1])
+	if err != nil {
+		return nil, err
+	}
+	var cur float64
+	if v != nil {
+		if cur, err = parseFloat(v.(string)); err != nil {
+			return nil, err
+		}
+	}
+	cur += delta
+	if math.IsInf(cur, 0) || math.IsNaN(cur) {
+		return nil, ErrOverflow
+	}
+	s := formatFloat(cur)
+	l.setValue(args[1], s, true)
+	return s, nil
+}
+
+func cmdAppend(l *LocalCache, args []string) (any, error) {
+	v, err := l.getString(args[1])
+	if err != nil {
+		return nil, err
+	}
+	s, _ := v.(string)
+	s += args[2]
+	l.setValue(args[1], s, true)
+	return int64(len(s)), nil
+}
+
+func cmdStrLen(l *LocalCache, args []string) (any, error) {
+	v, err := l.getString(args[1])
+	if err != nil {
+		return nil, err
+	}
+	s, _ := v.(string)
+	return int64(len(s)), nil
+}
+
+func cmdGetRange(l *LocalCache, args []string) (any, error) {
+	start, err := parseInt(args[2])
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseInt(args[3])
+	if err != nil {
+		return nil, err
+	}
+	v, err := l.getString(args[1])
+	if err != nil {
+		return nil, err
+	}
+	s, _ := v.(string)
+	from, to, ok := normRange(start, end, len(s))
+	if !ok {
+		return "", nil
+	}
+	return s[from:to], nil
+}
+
+func cmdSetRange(l *LocalCache, args []string) (any, error) {
+	offset, err := parseInt(args[2])
+	if err != nil {
+		return nil, err
+	}
+	if offset < 0 || offset > 512*1024*1024 {
+		return nil, ErrIndexOutOfRange
+	}
+	v, err := l.getString(args[1])
+	if err != nil {
+		return nil, err
+	}
+	s, _ := v.(string)
+	if args[3] == "" {
+		return int64(len(s)), nil
+	}
+	b := []byte(s)
+	if need := int(offset) + len(args[3]); need > len(b) {
+		b = append(b, make([]byte, need-len(b))...)
+	}
+	copy(b[offset:], args[3])
+	l.setValue(args[1], string(b), true)
+	return int64(len(b)), nil
+}
@@ -0,0 +1,599 @@
+// noinspection all
+package localredis
+
+import (
+	"cmp"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// 有序集合命令
+var zsetCommands = map[string]command{
+	"zadd":             {fn: cmdZAdd, arity: -4},
+	"zincrby":          {fn: cmdZIncrBy, arity: 4},
+	"zrem":             {fn: cmdZRem, arity: -3},
+	"zcard":            {fn: cmdZCard, arity: 2},
+	"zscore":           {fn: cmdZScore, arity: 3},
+	"zmscore":          {fn: cmdZMScore, arity: -3},
+	"zrank":            {fn: cmdZRank, arity: 3},
+	"zrevrank":         {fn: cmdZRank, arity: 3},
+	"zcount":           {fn: cmdZCount, arity: 4},
+	"zlexcount":        {fn: cmdZCount, arity: 4},
+	"zrange":           {fn: cmdZRange, arity: -4},
+	"zrevrange":        {fn: cmdZRange, arity: -4},
+	"zrangebyscore":    {fn: cmdZRange, arity: -4},
+	"zrevrangebyscore": {fn: cmdZRange, arity: -4},
+	"zrangebylex":      {fn: cmdZRange, arity: -4},
+	"zrevrangebylex":   {fn: cmdZRange, arity: -4},
+	"zremrangebyrank":  {fn: cmdZRemRange, arity: 4},
+	"zremrangebyscore": {fn: cmdZRemRange, arity: 4},
+	"zremrangebylex":   {fn: cmdZRemRange, arity: 4},
+	"zpopmin":          {fn: cmdZPop, arity: -2},
+	"zpopmax":          {fn: cmdZPop, arity: -2},
+	"zscan":            {fn: cmdZScan, arity: -3},
+}
+
+// zset 有序集合，dict 保存成员分数，sorted 按分数、成员排序
+type zset struct {
+	dict   map[string]float64
+	sorted []zentry
+}
+
+type zentry struct {
+	member string
+	score  float64
+}
+
+func newZSet() *zset {
+	return &zset{dict: make(map[string]float64)}
+}
+
+func compareEntry(a, b zentry) int {
+	if c := cmp.Compare(a.score, b.score); c != 0 {
+		return c
+	}
+	return strings.Compare(a.member, b.member)
+}
+
+// add 写入成员，已存在时更新分数
+func (z *zset) add(member string, score float64) {
+	z.remove(member)
+	e := zentry{member: member, score: score}
+	i, _ := slices.BinarySearchFunc(z.sorted, e, compareEntry)
+	z.sorted = slices.Insert(z.sorted, i, e)
+	z.dict[member] = score
+}
+
+func (z *zset) remove(member string) bool {
+	score, ok := z.dict[member]
+	if !ok {
+		return false
+	}
+	if i, found := slices.BinarySearchFunc(z.sorted, zentry{member: member, score: score}, compareEntry); found {
+		z.sorted = slices.Delete(z.sorted, i, i+1)
+	}
+	delete(z.dict, member)
+	return true
+}
+
+func (z *zset) rank(member string) int {
+	score, ok := z.dict[member]
+	if !ok {
+		return -1
+	}
+	i, _ := slices.BinarySearchFunc(z.sorted, zentry{member: member, score: score}, compareEntry)
+	return i
+}
+
+func (z *zset) clone() *zset {
+	return &zset{dict: maps.Clone(z.dict), sorted: slices.Clone(z.sorted)}
+}
+
+// scoreBound 分数区间边界，支持 -inf、+inf 和 ( 开区间
+type scoreBound struct {
+	value     float64
+	exclusive bool
+}
+
+func parseScoreBound(s string) (scoreBound, error) {
+	var b scoreBound
+	if strings.HasPrefix(s, "(") {
+		b.exclusive = true
+		s = s[1:]
+	}
+	v, err := parseFloat(s)
+	if err != nil {
+		return b, ErrMinMaxFloat
+	}
+	b.value = v
+	return b, nil
+}
+
+func (b scoreBound) lessEq(score float64) bool { // b <= score
+	if b.exclusive {
+		return b.value < score
+	}
+	return b.value <= score
+}
+
+func (b scoreBound) greaterEq(score float64) bool { // b >= score
+	if b.exclusive {
+		return b.value > score
+	}
+	return b.value >= score
+}
+
+// lexBound 字典序区间边界，- 最小、+ 最大、[ 闭区间、( 开区间
+type lexBound struct {
+	value     string
+	exclusive bool
+	min, max  bool
+}
+
+func parseLexBound(s string) (lexBound, error) {
+	switch {
+	case s == "-":
+		return lexBound{min: true}, nil
+	case s == "+":
+		return lexBound{max: true}, nil
+	case strings.HasPrefix(s, "["):
+		return lexBound{value: s[1:]}, nil
+	case strings.HasPrefix(s, "("):
+		return lexBound{value: s[1:], exclusive: true}, nil
+	}
+	return lexBound{}, ErrMinMaxLex
+}
+
+func (b lexBound) lessEq(member string) bool {
+	switch {
+	case b.min:
+		return true
+	case b.max:
+		return false
+	case b.exclusive:
+		return b.value < member
+	}
+	return b.value <= member
+}
+
+func (b lexBound) greaterEq(member string) bool {
+	switch {
+	case b.max:
+		return true
+	case b.min:
+		return false
+	case b.exclusive:
+		return b.value > member
+	}
+	return b.value >= member
+}
+
+// ZADD key [NX|XX] [GT|LT] [CH] [INCR] score member [score member ...]
+func cmdZAdd(l *LocalCache, args []string) (any, error) {
+	var nx, xx, gt, lt, ch, incr bool
+	i := 2
+loop:
+	for ; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "nx":
+			nx = true
+		case "xx":
+			xx = true
+		case "gt":
+			gt = true
+		case "lt":
+			lt = true
+		case "ch":
+			ch = true
+		case "incr":
+			incr = true
+		default:
+			break loop
+		}
+	}
+	pairs := args[i:]
+	if len(pairs) == 0 || len(pairs)%2 != 0 || (nx && xx) || (gt && lt) || (nx && (gt || lt)) || (incr && len(pairs) != 2) {
+		return nil, ErrSyntax
+	}
+	scores := make([]float64, len(pairs)/2)
+	for j := range scores {
+		s, err := parseFloat(pairs[j*2])
+		if err != nil {
+			return nil, err
+		}
+		scores[j] = s
+	}
+	z, err := lookupOrCreate(l, args[1], newZSet)
+	if err != nil {
+		return nil, err
+	}
+	defer l.dropIfEmpty(args[1])
+	var added, changed int64
+	for j, score := range scores {
+		member := pairs[j*2+1]
+		old, exists := z.dict[member]
+		if (nx && exists) || (xx && !exists) {
+			if incr {
+				return nil, nil
+			}
+			continue
+		}
+		if incr {
+			score += old
+			if math.IsNaN(score) {
+				return nil, ErrNotFloat
+			}
+		}
+		if exists && ((gt && score <= old) || (lt && score >= old)) {
+			if incr {
+				return nil, nil
+			}
+			continue
+		}
+		if !exists {
+			added++
+		} else if score != old {
+			changed++
+		}
+		z.add(member, score)
+		if incr {
+			return formatFloat(score), nil
+		}
+	}
+	if ch {
+		return added + changed, nil
+	}
+	return added, nil
+}
+
+func cmdZIncrBy(l *LocalCache, args []string) (any, error) {
+	delta, err := parseFloat(args[2])
+	if err != nil {
+		return nil, err
+	}
+	z, err := lookupOrCreate(l, args[1], newZSet)
+	if err != nil {
+		return nil, err
+	}
+	score := z.dict[args[3]] + delta
+	if math.IsNaN(score) {
+		l.dropIfEmpty(args[1])
+		return nil, ErrNotFloat
+	}
+	z.add(args[3], score)
+	return formatFloat(score), nil
+}
+
+func cmdZRem(l *LocalCache, args []string) (any, error) {
+	z, ok, err := lookupAs[*zset](l, args[1])
+	if err != nil || !ok {
+		return int64(0), err
+	}
+	var n int64
+	for _, m := range args[2:] {
+		if z.remove(m) {
+			n++
+		}
+	}
+	l.dropIfEmpty(args[1])
+	return n, nil
+}
+
+func cmdZCard(l *LocalCache, args []string) (any, error) {
+	z, ok, err := lookupAs[*zset](l, args[1])
+	if err != nil || !ok {
+		return int64(0), err
+	}
+	return int64(len(z.dict)), nil
+}
+
+func cmdZScore(l *LocalCache, args []string) (any, error) {
+	z, ok, err := lookupAs[*zset](l, args[1])
+	if err != nil || !ok {
+		return nil, err
+	}
+	if score, ok := z.dict[args[2]]; ok {
+		return formatFloat(score), nil
+	}
+	return nil, nil
+}
+
+func cmdZMScore(l *LocalCache, args []string) (any, error) {
+	z, ok, err := lookupAs[*zset](l, args[1])
+	if err != nil {
+		return nil, err
+	}
+	out := make([]any, len(args)-2)
+	if !ok {
+		return out, nil
+	}
+	for i, m := range args[2:] {
+		if score, ok := z.dict[m]; ok {
+			out[i] = formatFloat(score)
+		}
+	}
+	return out, nil
+}
+
+// ZRANK / ZREVRANK
+func cmdZRank(l *LocalCache, args []string) (any, error) {
+	z, ok, err := lookupAs[*zset](l, args[1])
+	if err != nil || !ok {
+		return nil, err
+	}
+	r := z.rank(args[2])
+	if r < 0 {
+		return nil, nil
+	}
+	if strings.EqualFold(args[0], "zrevrank") {
+		r = len(z.sorted) - 1 - r
+	}
+	return int64(r), nil
+}
+
+// zrangeQuery 统一描述 ZRANGE 系列命令的查询条件
+type zrangeQuery struct {
+	by         string // rank、score、lex
+	start, end string // 原始区间参数，rev 时为 max、min
+	rev        bool
+	withScores bool
+	offset     int64
+	count      int64 // <0 表示不限制
+}
+
+// selectRange 按查询条件返回符合条件的成员
+func (z *zset) selectRange(q *zrangeQuery) ([]zentry, error) {
+	var out []zentry
+	switch q.by {
+	case "rank":
+		start, err := parseInt(q.start)
+		if err != nil {
+			return nil, err
+		}
+		stop, err := parseInt(q.end)
+		if err != nil {
+			return nil, err
+		}
+		from, to, ok := normRange(start, stop, len(z.sorted))
+		if !ok {
+			return nil, nil
+		}
+		if q.rev {
+			n := len(z.sorted)
+			from, to = n-to, n-from
+		}
+		out = slices.Clone(z.sorted[from:to])
+	case "score":
+		lo, hi := q.start, q.end
+		if q.rev {
+			lo, hi = hi, lo
+		}
+		lower, err := parseScoreBound(lo)
+		if err != nil {
+			return nil, err
+		}
+		upper, err := parseScoreBound(hi)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range z.sorted {
+			if lower.lessEq(e.score) && upper.greaterEq(e.score) {
+				out = append(out, e)
+			}
+		}
+	case "lex":
+		lo, hi := q.start, q.end
+		if q.rev {
+			lo, hi = hi, lo
+		}
+		lower, err := parseLexBound(lo)
+		if err != nil {
+			return nil, err
+		}
+		upper, err := parseLexBound(hi)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range z.sorted {
+			if lower.lessEq(e.member) && upper.greaterEq(e.member) {
+				out = append(out, e)
+			}
+		}
+	}
+	if q.rev {
+		slices.Reverse(out)
+	}
+	if q.by != "rank" && (q.offset > 0 || q.count >= 0) {
+		if q.offset < 0 || q.offset >= int64(len(out)) {
+			return nil, nil
+		}
+		out = out[q.offset:]
+		if q.count >= 0 && q.count < int64(len(out)) {
+			out = out[:q.count]
+		}
+	}
+	return out, nil
+}
+
+// parseZRange 解析 ZRANGE 系列命令参数
+// ZRANGE key start stop [BYSCORE|BYLEX] [REV] [LIMIT offset count] [WITHSCORES]
+// ZREVRANGE、ZRANGEBYSCORE、ZREVRANGEBYSCORE、ZRANGEBYLEX、ZREVRANGEBYLEX 转换为等价的 ZRANGE
+func parseZRange(args []string) (*zrangeQuery, error) {
+	q := &zrangeQuery{by: "rank", start: args[2], end: args[3], count: -1}
+	switch strings.ToLower(args[0]) {
+	case "zrevrange":
+		q.rev = true
+	case "zrangebyscore":
+		q.by = "score"
+	case "zrevrangebyscore":
+		q.by, q.rev = "score", true
+	case "zrangebylex":
+		q.by = "lex"
+	case "zrevrangebylex":
+		q.by, q.rev = "lex", true
+	}
+	isZRange := strings.EqualFold(args[0], "zrange")
+	for i := 4; i < len(args); i++ {
+		switch opt := strings.ToLower(args[i]); {
+		case opt == "withscores" && q.by != "lex":
+			q.withScores = true
+		case opt == "limit" && i+2 < len(args):
+			offset, err := parseInt(args[i+1])
+			if err != nil {
+				return nil, err
+			}
+			count, err := parseInt(args[i+2])
+			if err != nil {
+				return nil, err
+			}
+			q.offset, q.count = offset, count
+			i += 2
+		case isZRange && opt == "byscore":
+			q.by = "score"
+		case isZRange && opt == "bylex":
+			q.by = "lex"
+		case isZRange && opt == "rev":
+			q.rev = true
+		default:
+			return nil, ErrSyntax
+		}
+	}
+	if q.by == "rank" && (q.offset != 0 || q.count != -1) {
+		return nil, ErrSyntax // LIMIT 只能和 BYSCORE、BYLEX 一起使用
+	}
+	if q.by == "lex" && q.withScores {
+		return nil, ErrSyntax
+	}
+	return q, nil
+}
+
+func cmdZRange(l *LocalCache, args []string) (any, error) {
+	q, err := parseZRange(args)
+	if err != nil {
+		return nil, err
+	}
+	z, ok, err := lookupAs[*zset](l, args[1])
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return []any{}, nil
+	}
+	entries, err := z.selectRange(q)
+	if err != nil {
+		return nil, err
+	}
+	return entriesReply(entries, q.withScores), nil
+}
+
+func entriesReply(entries []zentry, withScores bool) []any {
+	out := make([]any, 0, len(entries)*2)
+	for _, e := range entries {
+		out = append(out, e.member)
+		if withScores {
+			out = append(out, formatFloat(e.score))
+		}
+	}
+	return out
+}
+
+// ZCOUNT key min max / ZLEXCOUNT key min max
+func cmdZCount(l *LocalCache, args []string) (any, error) {
+	q := &zrangeQuery{by: "score", start: args[2], end: args[3], count: -1}
+	if strings.EqualFold(args[0], "zlexcount") {
+		q.by = "lex"
+	}
+	z, ok, err := lookupAs[*zset](l, args[1])
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		z = newZSet()
+	}
+	entries, err := z.selectRange(q)
+	if err != nil {
+		return nil, err
+	}
+	return int64(len(entries)), nil
+}
+
+// ZREMRANGEBYRANK / ZREMRANGEBYSCORE / ZREMRANGEBYLEX key start stop
+func cmdZRemRange(l *LocalCache, args []string) (any, error) {
+	q := &zrangeQuery{by: strings.TrimPrefix(strings.ToLower(args[0]), "zremrangeby"), start: args[2], end: args[3], count: -1}
+	z, ok, err := lookupAs[*zset](l, args[1])
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		z = newZSet()
+	}
+	entries, err := z.selectRange(q)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		z.remove(e.member)
+	}
+	l.dropIfEmpty(args[1])
+	return int64(len(entries)), nil
+}
+
+// ZPOPMIN / ZPOPMAX key [count]
+func cmdZPop(l *LocalCache, args []string) (any, error) {
+	if len(args) > 3 {
+		return nil, ErrSyntax
+	}
+	count := int64(1)
+	if len(args) == 3 {
+		n, err := parseInt(args[2])
+		if err != nil || n < 0 {
+			return nil, ErrNotInteger
+		}
+		count = n
+	}
+	z, ok, err := lookupAs[*zset](l, args[1])
+	if err != nil || !ok {
+		return []any{}, err
+	}
+	n := min(int(count), len(z.sorted))
+	var entries []zentry
+	if strings.EqualFold(args[0], "zpopmin") {
+		entries = slices.Clone(z.sorted[:n])
+	} else {
+		entries = slices.Clone(z.sorted[len(z.sorted)-n:])
+		slices.Reverse(entries)
+	}
+	for _, e := range entries {
+		z.remove(e.member)
+	}
+	l.dropIfEmpty(args[1])
+	return entriesReply(entries, true), nil
+}
+
+// ZSCAN key cursor [MATCH pattern] [COUNT count]
+func cmdZScan(l *LocalCache, args []string) (any, error) {
+	so, err := parseScanArgs(args[2], args[3:])
+	if err != nil {
+		return nil, err
+	}
+	z, ok, err := lookupAs[*zset](l, args[1])
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		z = newZSet()
+	}
+	members := make([]string, 0, len(z.dict))
+	for m := range z.dict {
+		members = append(members, m)
+	}
+	page, next := scanKeys(members, so, nil)
+	out := make([]any, 0, len(page)*2)
+	for _, m := range page {
+		out = append(out, m, formatFloat(z.dict[m]))
+	}
+	return []any{strconv.FormatUint(next, 10), out}, nil
+}
@@ -1,6 +1,36 @@
 // noinspection all
 package localredis
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var ErrInvalidArgNum = errors.New("invalid arg num")
+
+// noinspection all
+var (
+	ErrUnsupported = errors.New("localredis 未实现的命令")
+	ErrNoNetwork   = errors.New("localredis 为进程内实现，不支持建立网络连接")
+
+	ErrWrongType       = errors.New("WRONGTYPE Operation against a key holding the wrong kind of value")
+	ErrNotInteger      = errors.New("ERR value is not an integer or out of range")
+	ErrNotFloat        = errors.New("ERR value is not a valid float")
+	ErrSyntax          = errors.New("ERR syntax error")
+	ErrNoSuchKey       = errors.New("ERR no such key")
+	ErrIndexOutOfRange = errors.New("ERR index out of range")
+	ErrHashNotInteger  = errors.New("ERR hash value is not an integer")
+	ErrHashNotFloat    = errors.New("ERR hash value is not a float")
+	ErrOverflow        = errors.New("ERR increment or decrement would overflow")
+	ErrInvalidExpire   = errors.New("ERR invalid expire time")
+	ErrMinMaxFloat     = errors.New("ERR min or max is not a float")
+	ErrMinMaxLex       = errors.New("ERR min or max not valid string range item")
+)
+
+func errWrongArgs(name string) error {
+	return fmt.Errorf("%w: ERR wrong number of arguments for '%s' command", ErrInvalidArgNum, name)
+}
+
+func errUnknownCommand(name string) error {
+	return fmt.Errorf("%w '%s'", ErrUnsupported, name)
+}
@@ -2,39 +2,61 @@
 package localredis
 
 import (
+	"context"
+	"net"
 	"sync"
 	"time"
+
+	"github.com/redis/go-redis/v9"
 )
 
-// LocalCache 实现redis.UniversalClient接口的高效本地缓存
+var _ redis.UniversalClient = (*LocalCache)(nil)
+
+// LocalCache 实现redis.UniversalClient接口的进程内redis
+//
+// 内嵌的 *redis.Client 负责把各个方法组装成redis命令，
+// 通过hook拦截后在本地数据上执行，不会建立任何网络连接。
+// 因此 Pipeline、TxPipeline、Do 以及所有类型化的方法都走同一套命令实现，
+// 未实现的命令统一返回 ErrUnsupported。
 type LocalCache struct {
-	kvData    sync.Map         // 普通键值存储
-	hData     map[string]*hMap // 哈希表存储
-	hMu       sync.RWMutex     // 哈希表顶层锁
-	expiryMap sync.Map         // 存储键的过期时间
-	closeChan chan struct{}    // 用于关闭后台清理goroutine
-}
+	*redis.Client
 
-// hMap 哈希表结构
-type hMap struct {
-	data map[string]any
-	mu   sync.RWMutex
+	data      map[string]*item // 键空间
+	mu        sync.Mutex       // 键空间锁，命令串行执行，与redis单线程模型一致
+	watchMu   sync.Mutex       // Watch 事务锁
+	closeChan chan struct{}    // 用于关闭后台清理goroutine
+	closeOnce sync.Once
 }
 
 // noinspection all
 func NewLocalCache() *LocalCache {
 	lc := &LocalCache{
-		hData:     make(map[string]*hMap),
+		data:      make(map[string]*item),
 		closeChan: make(chan struct{}),
 	}
+	lc.Client = redis.NewClient(&redis.Options{Addr: "localredis", MaxRetries: -1})
+	lc.Client.AddHook(hook{lc: lc})
 	go lc.cleanupExpiredKeys() // 启动后台清理goroutine
 	return lc
 }
 
 // Close 关闭缓存，停止后台goroutine
 func (l *LocalCache) Close() error {
-	close(l.closeChan)
-	return nil
+	var err error
+	l.closeOnce.Do(func() {
+		close(l.closeChan)
+		err = l.Client.Close()
+	})
+	return err
+}
+
+// Watch 本地没有连接级别的 WATCH 状态，
+// 这里通过互斥锁让所有 Watch 事务串行执行，fn 内的读写和 TxPipelined 提交不会被其他 Watch 事务打断。
+// 注意：不在 Watch 内的普通写命令不会导致事务失败。
+func (l *LocalCache) Watch(ctx context.Context, fn func(*redis.Tx) error, keys ...string) error {
+	l.watchMu.Lock()
+	defer l.watchMu.Unlock()
+	return l.Client.Watch(ctx, fn, keys...)
 }
 
 // cleanupExpiredKeys 定期清理过期键
@@ -46,17 +68,90 @@ func (l *LocalCache) cleanupExpiredKeys() {
 		select {
 		case <-ticker.C:
 			now := time.Now()
-			l.kvData.Range(func(key, value interface{}) bool {
-				if expiry, ok := l.expiryMap.Load(key); ok {
-					if expiry.(time.Time).Before(now) {
-						l.kvData.Delete(key)
-						l.expiryMap.Delete(key)
-					}
+			l.mu.Lock()
+			for key, it := range l.data {
+				if it.expired(now) {
+					delete(l.data, key)
 				}
-				return true
-			})
+			}
+			l.mu.Unlock()
 		case <-l.closeChan:
 			return
 		}
 	}
 }
+
+// process 执行单条命令
+func (l *LocalCache) process(ctx context.Context, cmd redis.Cmder) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	reply, err := l.call(cmd)
+	l.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return setReply(cmd, reply)
+}
+
+// processPipeline 执行管道命令
+// 普通管道逐条执行；事务管道（MULTI ... EXEC）在一次加锁内执行，中间不会插入其他命令
+func (l *LocalCache) processPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if n := len(cmds); n >= 2 && cmds[0].Name() == "multi" && cmds[n-1].Name() == "exec" {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		for _, cmd := range cmds[1 : n-1] {
+			cmd.SetErr(l.apply(cmd))
+		}
+		return firstErr(cmds)
+	}
+	for _, cmd := range cmds {
+		l.mu.Lock()
+		err := l.apply(cmd)
+		l.mu.Unlock()
+		cmd.SetErr(err)
+	}
+	return firstErr(cmds)
+}
+
+// firstErr 与 go-redis 一致，管道返回第一条命令的错误
+func firstErr(cmds []redis.Cmder) error {
+	for _, cmd := range cmds {
+		if err := cmd.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apply 在已加锁的情况下执行命令并写入结果
+func (l *LocalCache) apply(cmd redis.Cmder) error {
+	reply, err := l.call(cmd)
+	if err != nil {
+		return err
+	}
+	return setReply(cmd, reply)
+}
+
+// hook 拦截 redis.Client 的所有请求
+type hook struct {
+	lc *LocalCache
+}
+
+func (h hook) DialHook(_ redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, ErrNoNetwork
+	}
+}
+
+func (h hook) ProcessHook(_ redis.ProcessHook) redis.ProcessHook {
+	return h.lc.process
+}
+
+func (h hook) ProcessPipelineHook(_ redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return h.lc.processPipeline
+}
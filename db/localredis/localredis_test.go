@@ -0,0 +1,274 @@
+package localredis
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestCache(t *testing.T) (*LocalCache, context.Context) {
+	t.Helper()
+	lc := NewLocalCache()
+	t.Cleanup(func() { _ = lc.Close() })
+	return lc, context.Background()
+}
+
+func TestString(t *testing.T) {
+	lc, ctx := newTestCache(t)
+
+	if err := lc.Set(ctx, "k", 10, 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := lc.Get(ctx, "k").Int(); err != nil || v != 10 {
+		t.Fatalf("get = %v, %v", v, err)
+	}
+	if v := lc.Incr(ctx, "k").Val(); v != 11 {
+		t.Fatalf("incr = %d", v)
+	}
+	if v := lc.DecrBy(ctx, "k", 5).Val(); v != 6 {
+		t.Fatalf("decrby = %d", v)
+	}
+	if v := lc.IncrByFloat(ctx, "k", 0.5).Val(); v != 6.5 {
+		t.Fatalf("incrbyfloat = %v", v)
+	}
+	if ok := lc.SetNX(ctx, "k", "x", 0).Val(); ok {
+		t.Fatal("setnx on existing key")
+	}
+	if ok := lc.SetNX(ctx, "n", "x", time.Minute).Val(); !ok {
+		t.Fatal("setnx on missing key")
+	}
+	if err := lc.Get(ctx, "missing").Err(); !errors.Is(err, redis.Nil) {
+		t.Fatalf("get missing = %v", err)
+	}
+	if vals := lc.MGet(ctx, "k", "missing").Val(); vals[0] != "6.5" || vals[1] != nil {
+		t.Fatalf("mget = %v", vals)
+	}
+	lc.HSet(ctx, "h", "f", "v")
+	if err := lc.Incr(ctx, "h").Err(); !errors.Is(err, ErrWrongType) {
+		t.Fatalf("incr hash = %v", err)
+	}
+}
+
+func TestExpire(t *testing.T) {
+	lc, ctx := newTestCache(t)
+
+	lc.Set(ctx, "k", "v", 0)
+	if ttl := lc.TTL(ctx, "k").Val(); ttl != -1 {
+		t.Fatalf("ttl without expire = %v", ttl)
+	}
+	if ttl := lc.TTL(ctx, "missing").Val(); ttl != -2 {
+		t.Fatalf("ttl missing = %v", ttl)
+	}
+	lc.Expire(ctx, "k", 10*time.Second)
+	if ttl := lc.TTL(ctx, "k").Val(); ttl != 10*time.Second {
+		t.Fatalf("ttl = %v", ttl)
+	}
+	if ok := lc.ExpireNX(ctx, "k", time.Hour).Val(); ok {
+		t.Fatal("expire nx on key with ttl")
+	}
+	lc.PExpire(ctx, "k", 20*time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+	if n := lc.Exists(ctx, "k").Val(); n != 0 {
+		t.Fatal("key not expired")
+	}
+	lc.Set(ctx, "k", "v", time.Minute)
+	lc.Set(ctx, "k", "v2", redis.KeepTTL)
+	if ttl := lc.PTTL(ctx, "k").Val(); ttl <= 0 {
+		t.Fatalf("keepttl lost ttl: %v", ttl)
+	}
+	lc.Persist(ctx, "k")
+	if ttl := lc.TTL(ctx, "k").Val(); ttl != -1 {
+		t.Fatalf("persist ttl = %v", ttl)
+	}
+}
+
+func TestList(t *testing.T) {
+	lc, ctx := newTestCache(t)
+
+	lc.RPush(ctx, "l", "a", "b", "c")
+	lc.LPush(ctx, "l", "z")
+	if v := lc.LRange(ctx, "l", 0, -1).Val(); !slices.Equal(v, []string{"z", "a", "b", "c"}) {
+		t.Fatalf("lrange = %v", v)
+	}
+	if v := lc.LPop(ctx, "l").Val(); v != "z" {
+		t.Fatalf("lpop = %v", v)
+	}
+	if v := lc.RPopCount(ctx, "l", 2).Val(); !slices.Equal(v, []string{"c", "b"}) {
+		t.Fatalf("rpop count = %v", v)
+	}
+	lc.RPush(ctx, "l", "x", "a")
+	if n := lc.LRem(ctx, "l", 0, "a").Val(); n != 2 {
+		t.Fatalf("lrem = %d", n)
+	}
+	if v := lc.LIndex(ctx, "l", -1).Val(); v != "x" {
+		t.Fatalf("lindex = %v", v)
+	}
+	lc.LPop(ctx, "l")
+	if n := lc.Exists(ctx, "l").Val(); n != 0 {
+		t.Fatal("empty list not deleted")
+	}
+}
+
+func TestSet(t *testing.T) {
+	lc, ctx := newTestCache(t)
+
+	lc.SAdd(ctx, "a", "1", "2", "3")
+	lc.SAdd(ctx, "b", "2", "3", "4")
+	if ok := lc.SIsMember(ctx, "a", "1").Val(); !ok {
+		t.Fatal("sismember")
+	}
+	inter := lc.SInter(ctx, "a", "b").Val()
+	slices.Sort(inter)
+	if !slices.Equal(inter, []string{"2", "3"}) {
+		t.Fatalf("sinter = %v", inter)
+	}
+	if n := lc.SUnionStore(ctx, "c", "a", "b").Val(); n != 4 {
+		t.Fatalf("sunionstore = %d", n)
+	}
+	if v := lc.SDiff(ctx, "a", "b").Val(); !slices.Equal(v, []string{"1"}) {
+		t.Fatalf("sdiff = %v", v)
+	}
+	if n := lc.SCard(ctx, "c").Val(); n != 4 {
+		t.Fatalf("scard = %d", n)
+	}
+}
+
+func TestZSet(t *testing.T) {
+	lc, ctx := newTestCache(t)
+
+	lc.ZAdd(ctx, "z", redis.Z{Score: 3, Member: "c"}, redis.Z{Score: 1, Member: "a"}, redis.Z{Score: 2, Member: "b"})
+	if v := lc.ZRange(ctx, "z", 0, -1).Val(); !slices.Equal(v, []string{"a", "b", "c"}) {
+		t.Fatalf("zrange = %v", v)
+	}
+	if v := lc.ZRevRange(ctx, "z", 0, 0).Val(); !slices.Equal(v, []string{"c"}) {
+		t.Fatalf("zrevrange = %v", v)
+	}
+	v := lc.ZRangeByScoreWithScores(ctx, "z", &redis.ZRangeBy{Min: "(1", Max: "+inf"}).Val()
+	if len(v) != 2 || v[0].Member != "b" || v[0].Score != 2 {
+		t.Fatalf("zrangebyscore = %v", v)
+	}
+	if r := lc.ZRank(ctx, "z", "c").Val(); r != 2 {
+		t.Fatalf("zrank = %d", r)
+	}
+	if s := lc.ZIncrBy(ctx, "z", 10, "a").Val(); s != 11 {
+		t.Fatalf("zincrby = %v", s)
+	}
+	if n := lc.ZCount(ctx, "z", "2", "11").Val(); n != 3 {
+		t.Fatalf("zcount = %d", n)
+	}
+	if n := lc.ZRemRangeByScore(ctx, "z", "-inf", "2").Val(); n != 1 {
+		t.Fatalf("zremrangebyscore = %d", n)
+	}
+	if p := lc.ZPopMax(ctx, "z").Val(); len(p) != 1 || p[0].Member != "a" {
+		t.Fatalf("zpopmax = %v", p)
+	}
+	if n := lc.ZAddGT(ctx, "z", redis.Z{Score: 1, Member: "c"}).Val(); n != 0 || lc.ZScore(ctx, "z", "c").Val() != 3 {
+		t.Fatal("zadd gt lowered score")
+	}
+}
+
+func TestScanKeys(t *testing.T) {
+	lc, ctx := newTestCache(t)
+
+	for i := 0; i < 100; i++ {
+		lc.Set(ctx, "user:"+string(rune('a'+i%26))+string(rune('0'+i/26)), i, 0)
+	}
+	lc.Set(ctx, "other", 1, 0)
+	lc.HSet(ctx, "user:hash", "f", "v")
+
+	keys := lc.Keys(ctx, "user:[a-c]?").Val()
+	if len(keys) != 12 {
+		t.Fatalf("keys = %d %v", len(keys), keys)
+	}
+	seen := map[string]bool{}
+	var cursor uint64
+	for {
+		page, next, err := lc.Scan(ctx, cursor, "user:*", 7).Result()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, k := range page {
+			seen[k] = true
+		}
+		if cursor = next; cursor == 0 {
+			break
+		}
+	}
+	if len(seen) != 101 {
+		t.Fatalf("scan returned %d keys", len(seen))
+	}
+	page, _, _ := lc.ScanType(ctx, 0, "*", 1000, "hash").Result()
+	if !slices.Equal(page, []string{"user:hash"}) {
+		t.Fatalf("scan type = %v", page)
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	lc, ctx := newTestCache(t)
+
+	pipe := lc.Pipeline()
+	set := pipe.Set(ctx, "k", "v", 0)
+	get := pipe.Get(ctx, "k")
+	incr := pipe.Incr(ctx, "counter")
+	if get.Val() != "" {
+		t.Fatal("pipeline executed before Exec")
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if set.Val() != "OK" || get.Val() != "v" || incr.Val() != 1 {
+		t.Fatalf("pipeline results %v %v %v", set.Val(), get.Val(), incr.Val())
+	}
+
+	cmds, err := lc.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.Incr(ctx, "counter")
+		p.Get(ctx, "missing")
+		return nil
+	})
+	if !errors.Is(err, redis.Nil) || len(cmds) != 2 {
+		t.Fatalf("tx pipeline = %v, %v", cmds, err)
+	}
+	if v := cmds[0].(*redis.IntCmd).Val(); v != 2 {
+		t.Fatalf("tx incr = %d", v)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	lc, ctx := newTestCache(t)
+
+	err := lc.Watch(ctx, func(tx *redis.Tx) error {
+		n, err := tx.Get(ctx, "k").Int()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(p redis.Pipeliner) error {
+			p.Set(ctx, "k", n+1, 0)
+			return nil
+		})
+		return err
+	}, "k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := lc.Get(ctx, "k").Val(); v != "1" {
+		t.Fatalf("watch result = %v", v)
+	}
+}
+
+func TestUnsupported(t *testing.T) {
+	lc, ctx := newTestCache(t)
+
+	if err := lc.XAdd(ctx, &redis.XAddArgs{Stream: "s", Values: []string{"a", "b"}}).Err(); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("xadd = %v", err)
+	}
+	if err := lc.Do(ctx, "nosuchcommand").Err(); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("do = %v", err)
+	}
+	if v, err := lc.Do(ctx, "incrby", "n", 3).Int(); err != nil || v != 3 {
+		t.Fatalf("do incrby = %v %v", v, err)
+	}
+}
@@ -0,0 +1,197 @@
+// noinspection all
+package localredis
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 命令的执行结果使用与RESP2一致的中间结构表示：
+// nil 空回复、status 简单字符串、string 批量字符串、int64 整数、[]any 数组。
+// setReply 按 go-redis 解析协议的规则，把中间结构写入具体的 Cmd 类型。
+
+func setReply(cmd redis.Cmder, reply any) error {
+	if reply == nil {
+		if c, ok := cmd.(*redis.BoolCmd); ok {
+			// SET NX 之类的命令返回空回复表示未执行
+			c.SetVal(false)
+			return nil
+		}
+		return redis.Nil
+	}
+	arr, isArray := reply.([]any)
+	switch cmd.(type) {
+	case *redis.StatusCmd, *redis.StringCmd, *redis.IntCmd, *redis.BoolCmd, *redis.FloatCmd, *redis.DurationCmd:
+		if isArray {
+			return fmt.Errorf("%w: %s 返回数组，与 %T 不匹配", ErrUnsupported, cmd.Name(), cmd)
+		}
+	case *redis.Cmd:
+	default:
+		if !isArray {
+			return fmt.Errorf("%w: %s 返回非数组，与 %T 不匹配", ErrUnsupported, cmd.Name(), cmd)
+		}
+	}
+	switch c := cmd.(type) {
+	case *redis.Cmd:
+		c.SetVal(rawValue(reply))
+	case *redis.StatusCmd:
+		c.SetVal(replyString(reply))
+	case *redis.StringCmd:
+		c.SetVal(replyString(reply))
+	case *redis.IntCmd:
+		n, err := replyInt(reply)
+		if err != nil {
+			return err
+		}
+		c.SetVal(n)
+	case *redis.BoolCmd:
+		switch v := reply.(type) {
+		case int64:
+			c.SetVal(v != 0)
+		case status:
+			c.SetVal(v == statusOK)
+		default:
+			c.SetVal(replyString(reply) != "")
+		}
+	case *redis.FloatCmd:
+		f, err := parseFloat(replyString(reply))
+		if err != nil {
+			return err
+		}
+		c.SetVal(f)
+	case *redis.DurationCmd:
+		n, err := replyInt(reply)
+		if err != nil {
+			return err
+		}
+		precision := time.Second
+		if len(cmd.Args()) > 0 && (cmd.Name() == "pttl" || cmd.Name() == "pexpiretime") {
+			precision = time.Millisecond
+		}
+		if n < 0 {
+			c.SetVal(time.Duration(n))
+		} else {
+			c.SetVal(time.Duration(n) * precision)
+		}
+	case *redis.TimeCmd:
+		sec, _ := strconv.ParseInt(replyString(arr[0]), 10, 64)
+		usec, _ := strconv.ParseInt(replyString(arr[1]), 10, 64)
+		c.SetVal(time.Unix(sec, usec*1000))
+	case *redis.StringSliceCmd:
+		val := make([]string, len(arr))
+		for i, v := range arr {
+			if v != nil {
+				val[i] = replyString(v)
+			}
+		}
+		c.SetVal(val)
+	case *redis.IntSliceCmd:
+		val := make([]int64, len(arr))
+		for i, v := range arr {
+			if v != nil {
+				val[i], _ = replyInt(v)
+			}
+		}
+		c.SetVal(val)
+	case *redis.BoolSliceCmd:
+		val := make([]bool, len(arr))
+		for i, v := range arr {
+			n, _ := replyInt(v)
+			val[i] = n == 1
+		}
+		c.SetVal(val)
+	case *redis.FloatSliceCmd:
+		val := make([]float64, len(arr))
+		for i, v := range arr {
+			if v != nil {
+				val[i], _ = parseFloat(replyString(v))
+			}
+		}
+		c.SetVal(val)
+	case *redis.SliceCmd:
+		val := make([]any, len(arr))
+		for i, v := range arr {
+			val[i] = rawValue(v)
+		}
+		c.SetVal(val)
+	case *redis.ZSliceCmd:
+		val := make([]redis.Z, 0, len(arr)/2)
+		for i := 0; i+1 < len(arr); i += 2 {
+			score, _ := parseFloat(replyString(arr[i+1]))
+			val = append(val, redis.Z{Member: replyString(arr[i]), Score: score})
+		}
+		c.SetVal(val)
+	case *redis.ScanCmd:
+		cursor, _ := strconv.ParseUint(replyString(arr[0]), 10, 64)
+		page := arr[1].([]any)
+		keys := make([]string, len(page))
+		for i, v := range page {
+			keys[i] = replyString(v)
+		}
+		c.SetVal(keys, cursor)
+	case *redis.MapStringStringCmd:
+		val := make(map[string]string, len(arr)/2)
+		for i := 0; i+1 < len(arr); i += 2 {
+			val[replyString(arr[i])] = replyString(arr[i+1])
+		}
+		c.SetVal(val)
+	case *redis.MapStringIntCmd:
+		val := make(map[string]int64, len(arr)/2)
+		for i := 0; i+1 < len(arr); i += 2 {
+			val[replyString(arr[i])], _ = replyInt(arr[i+1])
+		}
+		c.SetVal(val)
+	case *redis.KeyValuesCmd:
+		vals := arr[1].([]any)
+		val := make([]string, len(vals))
+		for i, v := range vals {
+			val[i] = replyString(v)
+		}
+		c.SetVal(replyString(arr[0]), val)
+	default:
+		return fmt.Errorf("%w: 不支持的返回类型 %T", ErrUnsupported, cmd)
+	}
+	return nil
+}
+
+// rawValue 转换为 go-redis 通用 Cmd 的返回值
+func rawValue(reply any) any {
+	switch v := reply.(type) {
+	case status:
+		return string(v)
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = rawValue(e)
+		}
+		return out
+	}
+	return reply
+}
+
+func replyString(reply any) string {
+	switch v := reply.(type) {
+	case string:
+		return v
+	case status:
+		return string(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	}
+	return fmt.Sprint(reply)
+}
+
+func replyInt(reply any) (int64, error) {
+	switch v := reply.(type) {
+	case int64:
+		return v, nil
+	case string:
+		return parseInt(v)
+	case status:
+		return parseInt(string(v))
+	}
+	return 0, ErrNotInteger
+}
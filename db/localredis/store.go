@@ -0,0 +1,152 @@
+// noinspection all
+package localredis
+
+import (
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// item 键空间中的一个键
+// val 的类型决定redis数据类型：
+// string 字符串、map[string]string 哈希、*list 列表、map[string]struct{} 集合、*zset 有序集合
+type item struct {
+	val    any
+	expire time.Time // 过期时间，零值表示不过期
+}
+
+func (it *item) expired(now time.Time) bool {
+	return !it.expire.IsZero() && !it.expire.After(now)
+}
+
+// list 列表
+type list struct {
+	items []string
+}
+
+// typeName 数据类型名称，与 TYPE 命令一致
+func typeName(v any) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case map[string]string:
+		return "hash"
+	case *list:
+		return "list"
+	case map[string]struct{}:
+		return "set"
+	case *zset:
+		return "zset"
+	}
+	return "none"
+}
+
+// lookup 查找键，过期的键会被删除
+func (l *LocalCache) lookup(key string) *item {
+	it, ok := l.data[key]
+	if !ok {
+		return nil
+	}
+	if it.expired(time.Now()) {
+		delete(l.data, key)
+		return nil
+	}
+	return it
+}
+
+// lookupAs 按类型查找键，类型不一致返回 ErrWrongType
+func lookupAs[T any](l *LocalCache, key string) (T, bool, error) {
+	var zero T
+	it := l.lookup(key)
+	if it == nil {
+		return zero, false, nil
+	}
+	v, ok := it.val.(T)
+	if !ok {
+		return zero, false, ErrWrongType
+	}
+	return v, true, nil
+}
+
+// lookupOrCreate 按类型查找键，不存在时创建
+func lookupOrCreate[T any](l *LocalCache, key string, create func() T) (T, error) {
+	v, ok, err := lookupAs[T](l, key)
+	if err != nil || ok {
+		return v, err
+	}
+	v = create()
+	l.data[key] = &item{val: v}
+	return v, nil
+}
+
+// setValue 写入键值，keepTTL 为 false 时清除原有过期时间
+func (l *LocalCache) setValue(key string, val any, keepTTL bool) {
+	if it := l.lookup(key); it != nil && keepTTL {
+		it.val = val
+		return
+	}
+	l.data[key] = &item{val: val}
+}
+
+// dropIfEmpty 容器类型的键在元素全部删除后需要一并删除
+func (l *LocalCache) dropIfEmpty(key string) {
+	it, ok := l.data[key]
+	if !ok {
+		return
+	}
+	var n int
+	switch v := it.val.(type) {
+	case map[string]string:
+		n = len(v)
+	case *list:
+		n = len(v.items)
+	case map[string]struct{}:
+		n = len(v)
+	case *zset:
+		n = len(v.dict)
+	default:
+		return
+	}
+	if n == 0 {
+		delete(l.data, key)
+	}
+}
+
+// command 命令定义
+// arity 与redis一致：正数表示参数个数（含命令名）必须相等，负数表示至少 -arity 个
+type command struct {
+	fn    func(l *LocalCache, args []string) (any, error)
+	arity int
+}
+
+var commands map[string]command
+
+func init() {
+	commands = make(map[string]command)
+	for _, group := range []map[string]command{baseCommands, stringCommands, keyCommands, hashCommands, listCommands, setCommands, zsetCommands} {
+		for name, c := range group {
+			commands[name] = c
+		}
+	}
+}
+
+// call 解析命令参数并执行，需要在持有 l.mu 的情况下调用
+func (l *LocalCache) call(cmd redis.Cmder) (any, error) {
+	args, err := toArgs(cmd.Args())
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		return nil, errUnknownCommand("")
+	}
+	name := strings.ToLower(args[0])
+	c, ok := commands[name]
+	if !ok {
+		return nil, errUnknownCommand(name)
+	}
+	if (c.arity > 0 && len(args) != c.arity) || (c.arity < 0 && len(args) < -c.arity) {
+		return nil, errWrongArgs(name)
+	}
+	return c.fn(l, args)
+}
@@ -1,6 +1,97 @@
 package localredis
 
-import "strconv"
+import (
+	"encoding"
+	"fmt"
+	"math"
+	"net"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// status 简单字符串回复，例如 OK、PONG
+type status string
+
+const statusOK status = "OK"
+
+// toArgs 将命令参数转换为字符串，转换规则与 go-redis 写入协议时保持一致
+func toArgs(raw []any) ([]string, error) {
+	args := make([]string, len(raw))
+	for i, v := range raw {
+		s, err := argString(v)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = s
+	}
+	return args, nil
+}
+
+// noinspection all
+func argString(v any) (string, error) {
+	switch val := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return val, nil
+	case []byte:
+		return string(val), nil
+	case int:
+		return strconv.FormatInt(int64(val), 10), nil
+	case int8:
+		return strconv.FormatInt(int64(val), 10), nil
+	case int16:
+		return strconv.FormatInt(int64(val), 10), nil
+	case int32:
+		return strconv.FormatInt(int64(val), 10), nil
+	case int64:
+		return strconv.FormatInt(val, 10), nil
+	case uint:
+		return strconv.FormatUint(uint64(val), 10), nil
+	case uint8:
+		return strconv.FormatUint(uint64(val), 10), nil
+	case uint16:
+		return strconv.FormatUint(uint64(val), 10), nil
+	case uint32:
+		return strconv.FormatUint(uint64(val), 10), nil
+	case uint64:
+		return strconv.FormatUint(val, 10), nil
+	case float32:
+		return strconv.FormatFloat(float64(val), 'f', -1, 64), nil
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64), nil
+	case bool:
+		if val {
+			return "1", nil
+		}
+		return "0", nil
+	case time.Time:
+		return val.Format(time.RFC3339Nano), nil
+	case time.Duration:
+		return strconv.FormatInt(val.Nanoseconds(), 10), nil
+	case encoding.BinaryMarshaler:
+		b, err := val.MarshalBinary()
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	case net.IP:
+		return string(val), nil
+	}
+	// 指针类型取值后再转换，空指针按零值处理
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return argString(reflect.Zero(rv.Type().Elem()).Interface())
+		}
+		return argString(rv.Elem().Interface())
+	}
+	return "", fmt.Errorf("redis: can't marshal %T (implement encoding.BinaryMarshaler)", v)
+}
 
 // noinspection all
 func toString(v interface{}) (string, bool) {
@@ -30,3 +121,239 @@ func toInt64(v interface{}) int64 {
 		return 0
 	}
 }
+
+func parseInt(s string) (int64, error) {
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, ErrNotInteger
+	}
+	return i, nil
+}
+
+func parseFloat(s string) (float64, error) {
+	switch strings.ToLower(s) {
+	case "+inf", "inf":
+		return math.Inf(1), nil
+	case "-inf":
+		return math.Inf(-1), nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) {
+		return 0, ErrNotFloat
+	}
+	return f, nil
+}
+
+func formatInt(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// normRange 将redis风格的起止下标（支持负数）转换为切片范围，范围为空时返回 false
+func normRange(start, stop int64, n int) (int, int, bool) {
+	size := int64(n)
+	if start < 0 {
+		start += size
+	}
+	if stop < 0 {
+		stop += size
+	}
+	start = max(start, 0)
+	if stop >= size {
+		stop = size - 1
+	}
+	if start > stop || start >= size {
+		return 0, 0, false
+	}
+	return int(start), int(stop) + 1, true
+}
+
+func stringsReply(items []string) []any {
+	out := make([]any, len(items))
+	for i, s := range items {
+		out[i] = s
+	}
+	return out
+}
+
+// globMatch redis风格的通配符匹配，支持 * ? [abc] [^a] [a-z] 以及 \ 转义
+func globMatch(pattern, s string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for len(pattern) > 1 && pattern[1] == '*' {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 1 {
+				return true
+			}
+			for i := 0; i <= len(s); i++ {
+				if globMatch(pattern[1:], s[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(s) == 0 {
+				return false
+			}
+			s = s[1:]
+			pattern = pattern[1:]
+		case '[':
+			if len(s) == 0 {
+				return false
+			}
+			p := pattern[1:]
+			not := len(p) > 0 && p[0] == '^'
+			if not {
+				p = p[1:]
+			}
+			match := false
+			for len(p) > 0 && p[0] != ']' {
+				switch {
+				case p[0] == '\\' && len(p) >= 2:
+					match = match || p[1] == s[0]
+					p = p[2:]
+				case len(p) >= 3 && p[1] == '-':
+					lo, hi := p[0], p[2]
+					if lo > hi {
+						lo, hi = hi, lo
+					}
+					match = match || (s[0] >= lo && s[0] <= hi)
+					p = p[3:]
+				default:
+					match = match || p[0] == s[0]
+					p = p[1:]
+				}
+			}
+			if not {
+				match = !match
+			}
+			if !match {
+				return false
+			}
+			if len(p) > 0 {
+				p = p[1:] // 跳过 ]
+			}
+			pattern = p
+			s = s[1:]
+		case '\\':
+			if len(pattern) >= 2 {
+				pattern = pattern[1:]
+			}
+			fallthrough
+		default:
+			if len(s) == 0 || pattern[0] != s[0] {
+				return false
+			}
+			s = s[1:]
+			pattern = pattern[1:]
+		}
+	}
+	return len(s) == 0
+}
+
+// scanOptions SCAN 系列命令的公共参数
+type scanOptions struct {
+	cursor  uint64
+	match   string
+	count   int
+	typ     string // 仅 SCAN 支持
+	noValue bool   // 仅 HSCAN 支持
+}
+
+func parseScanArgs(cursor string, opts []string) (*scanOptions, error) {
+	c, err := strconv.ParseUint(cursor, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("ERR invalid cursor")
+	}
+	so := &scanOptions{cursor: c, count: 10}
+	for i := 0; i < len(opts); i++ {
+		switch strings.ToLower(opts[i]) {
+		case "match":
+			if i++; i >= len(opts) {
+				return nil, ErrSyntax
+			}
+			so.match = opts[i]
+		case "count":
+			if i++; i >= len(opts) {
+				return nil, ErrSyntax
+			}
+			n, err := parseInt(opts[i])
+			if err != nil {
+				return nil, err
+			}
+			if n < 1 {
+				return nil, ErrSyntax
+			}
+			so.count = int(n)
+		case "type":
+			if i++; i >= len(opts) {
+				return nil, ErrSyntax
+			}
+			so.typ = strings.ToLower(opts[i])
+		case "novalues":
+			so.noValue = true
+		default:
+			return nil, ErrSyntax
+		}
+	}
+	return so, nil
+}
+
+// scanKeys 按 key 的哈希值排序遍历，游标为下一个待返回的哈希值。
+// 与redis一样，遍历期间一直存在的元素一定会被返回，遍历期间新增或删除的元素可能返回也可能不返回。
+// 匹配条件在分页后过滤，单页返回的数量可能少于 count。
+func scanKeys(keys []string, so *scanOptions, filter func(key string) bool) ([]string, uint64) {
+	type hashed struct {
+		h   uint64
+		key string
+	}
+	list := make([]hashed, 0, len(keys))
+	for _, k := range keys {
+		if h := xxhash.Sum64String(k); h >= so.cursor {
+			list = append(list, hashed{h: h, key: k})
+		}
+	}
+	slices.SortFunc(list, func(a, b hashed) int {
+		if a.h != b.h {
+			if a.h < b.h {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.key, b.key)
+	})
+	var next uint64
+	if len(list) > so.count {
+		// 哈希值相同的 key 必须在同一页返回，否则游标无法区分
+		end := so.count
+		for end < len(list) && list[end].h == list[end-1].h {
+			end++
+		}
+		if end < len(list) {
+			next = list[end].h
+		}
+		list = list[:end]
+	}
+	page := make([]string, 0, len(list))
+	for _, v := range list {
+		if so.match != "" && !globMatch(so.match, v.key) {
+			continue
+		}
+		if filter != nil && !filter(v.key) {
+			continue
+		}
+		page = append(page, v.key)
+	}
+	return page, next
+}
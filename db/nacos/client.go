@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/nacos-group/nacos-sdk-go/v2/clients"
+	"github.com/nacos-group/nacos-sdk-go/v2/clients/config_client"
 	"github.com/nacos-group/nacos-sdk-go/v2/clients/naming_client"
 	"github.com/nacos-group/nacos-sdk-go/v2/common/constant"
 	"github.com/nacos-group/nacos-sdk-go/v2/vo"
@@ -111,6 +112,15 @@ type RamConfig struct {
 }
 
 func (this *Config) NewClient() (naming_client.INamingClient, error) {
+	return clients.NewNamingClient(this.clientParam())
+}
+
+// NewConfigClient 创建配置中心客户端
+func (this *Config) NewConfigClient() (config_client.IConfigClient, error) {
+	return clients.NewConfigClient(this.clientParam())
+}
+
+func (this *Config) clientParam() vo.NacosClientParam {
 	var serverConfigs []constant.ServerConfig
 	for _, v := range this.ServerConfigs {
 		serverConfigs = append(serverConfigs,
@@ -128,10 +138,10 @@ func (this *Config) NewClient() (naming_client.INamingClient, error) {
 	this.setKMSConfig(clientConfig)
 	this.setLogSampling(clientConfig)
 	this.setLogRollingConfig(clientConfig)
-	return clients.NewNamingClient(vo.NacosClientParam{
+	return vo.NacosClientParam{
 		ClientConfig:  clientConfig,
 		ServerConfigs: serverConfigs,
-	})
+	}
 }
 
 // 设置 client 配置
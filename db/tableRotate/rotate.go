@@ -43,9 +43,9 @@ type CallbackParams struct {
 type TableRotate struct {
 	Enable                  bool          `json:"enable" yaml:"enable" ini:"enable"` // 是否启用自动轮转
 	Duration                time.Duration `json:"duration" yaml:"duration" ini:"duration"`
-	Retry                   int           `json:"retry" yaml:"retry" ini:"retry"`                                                                // 重试次数
+	Retry                   int           `json:"retry" yaml:"retry" ini:"retry" valid:"min=0"`                                                  // 重试次数
 	WaitTime                time.Duration `json:"wait_time" yaml:"wait_time" ini:"wait_time"`                                                    // 重试等待时间
-	Crontab                 string        `json:"crontab" yaml:"crontab" ini:"crontab"`                                                          // crontab 表达式 ,定时器和crontab二选一
+	Crontab                 string        `json:"crontab" yaml:"crontab" ini:"crontab"`                                                          // crontab 表达式 ,定时器和crontab二选一，同时配置时使用 crontab
 	SplitTable              bool          `json:"split_table" yaml:"split_table" ini:"split_table"`                                              // 是否开启按天切分日志 ，开启后，自动回收数据 只会看表的保留数量，不开启，就看数据保留时长
 	MaxTableRetention       int           `json:"max_table_retention" yaml:"max_table_retention" ini:"max_table_retention"`                      // 分表后，最大保留表的数量 -1 不限制
 	TimeFormat              string        `json:"time_format" yaml:"time_format" ini:"time_format"`                                              // 时间格式
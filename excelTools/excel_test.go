@@ -0,0 +1,99 @@
+package excelTools
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type TestBase struct {
+	ID int `excel:"编号;col:A"`
+}
+
+type testUser struct {
+	*TestBase
+	Name     string     `excel:"姓名;required"`
+	Gender   int        `excel:"性别;col:D;enum:1=男|2=女"`
+	Birthday time.Time  `excel:"生日;format:yyyy-mm-dd"`
+	Score    *float64   `excel:"分数"`
+	Active   bool       `excel:"启用"`
+	Login    *time.Time `excel:"最后登录"`
+	Ignored  string     `excel:"-"`
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	score := 92.5
+	in := []testUser{
+		{TestBase: &TestBase{ID: 1}, Name: "张三", Gender: 1, Birthday: time.Date(1990, 5, 6, 0, 0, 0, 0, time.Local), Score: &score, Active: true},
+		{TestBase: &TestBase{ID: 2}, Name: "李四", Gender: 2, Birthday: time.Date(2000, 1, 2, 0, 0, 0, 0, time.Local)},
+	}
+	var buf bytes.Buffer
+	if err := ExportToExcel(&buf, in); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := excelize.OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := f.GetCellValue("Sheet1", "D1"); v != "性别" {
+		t.Fatalf("D1 = %q, want col:D header", v)
+	}
+	if v, _ := f.GetCellValue("Sheet1", "D2"); v != "男" {
+		t.Fatalf("D2 = %q, want enum label", v)
+	}
+	CloseExcel(f)
+
+	out, err := ImportFromExcel[testUser](bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != len(in) {
+		t.Fatalf("got %d rows, want %d", len(out), len(in))
+	}
+	for i, want := range in {
+		got := out[i]
+		if got.TestBase == nil || got.ID != want.ID || got.Name != want.Name || got.Gender != want.Gender ||
+			!got.Birthday.Equal(want.Birthday) || got.Active != want.Active || got.Login != nil {
+			t.Fatalf("row %d = %+v, want %+v", i, got, want)
+		}
+		if (got.Score == nil) != (want.Score == nil) || (got.Score != nil && *got.Score != *want.Score) {
+			t.Fatalf("row %d score = %v, want %v", i, got.Score, want.Score)
+		}
+	}
+}
+
+func TestImportAllocatesEmbeddedPointer(t *testing.T) {
+	f := excelize.NewFile()
+	defer CloseExcel(f)
+	_ = f.SetSheetRow("Sheet1", "A1", &[]any{"编号", "姓名"})
+	_ = f.SetSheetRow("Sheet1", "A2", &[]any{7, "王五"})
+	out, err := ImportFromFile[testUser](f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 1 || out[0].TestBase == nil || out[0].ID != 7 {
+		t.Fatalf("got %+v", out)
+	}
+}
+
+func TestImportCellErrors(t *testing.T) {
+	f := excelize.NewFile()
+	defer CloseExcel(f)
+	_ = f.SetSheetRow("Sheet1", "A1", &[]any{"编号", "姓名", "生日", "性别"})
+	_ = f.SetSheetRow("Sheet1", "A2", &[]any{"x", "", "2020-01-02", "未知"})
+	_ = f.SetSheetRow("Sheet1", "A3", &[]any{3, "赵六", "2020-01-02", "女"})
+	out, err := ImportFromFile[testUser](f)
+	errs, ok := err.(ImportErrors)
+	if !ok || len(errs) != 3 {
+		t.Fatalf("err = %v", err)
+	}
+	if errs[0].Cell != "A2" || errs[1].Cell != "B2" || errs[2].Cell != "D2" {
+		t.Fatalf("cells = %s %s %s", errs[0].Cell, errs[1].Cell, errs[2].Cell)
+	}
+	if len(out) != 1 || out[0].Gender != 2 {
+		t.Fatalf("got %+v", out)
+	}
+}
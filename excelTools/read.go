@@ -0,0 +1,361 @@
+package excelTools
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/araddon/dateparse"
+	"github.com/xuri/excelize/v2"
+)
+
+// ImportOptions 结构体导入配置
+type ImportOptions struct {
+	SheetName string         // 读取的sheet，默认为模型 HasSheetName 指定的sheet，都没有时读取第一个sheet
+	FieldRow  int            // 表头所在行，从1开始，默认1
+	DataRow   int            // 数据开始行，默认表头的下一行
+	Location  *time.Location // 解析时间的时区，默认本地时区
+}
+
+// CellError 单元格错误
+type CellError struct {
+	Cell   string // 单元格坐标，如 B3
+	Row    int    // 行号，从1开始
+	Header string // 表头
+	Value  string // 单元格原始值
+	Err    error
+}
+
+func (e *CellError) Error() string {
+	return fmt.Sprintf("%s[%s] %s", e.Cell, e.Header, e.Err.Error())
+}
+
+func (e *CellError) Unwrap() error {
+	return e.Err
+}
+
+// ImportErrors 导入过程中全部的单元格错误，按行、列顺序排列
+type ImportErrors []*CellError
+
+func (e ImportErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Rows 出错的行号
+func (e ImportErrors) Rows() []int {
+	var rows []int
+	for i, err := range e {
+		if i == 0 || err.Row != e[i-1].Row {
+			rows = append(rows, err.Row)
+		}
+	}
+	return rows
+}
+
+var (
+	ErrCellRequired = errors.New("不能为空")
+	ErrCellEnum     = errors.New("不是可选值")
+)
+
+// ImportFromExcel 按结构体的 excel 标签流式读取 excel，是 ExportToExcel 的逆操作
+// 字段支持 string、整数、浮点数、bool、time.Time、实现了 encoding.TextUnmarshaler 的类型（如 decimal）以及它们的指针，
+// 空单元格保持零值。有单元格错误时，返回没有错误的行以及 ImportErrors，通过 errors.As 获取每个单元格的坐标和原因。
+func ImportFromExcel[T any](rd io.Reader, opts ...ImportOptions) ([]T, error) {
+	f, err := excelize.OpenReader(rd)
+	if err != nil {
+		return nil, fmt.Errorf("excel文件打开失败 %w", err)
+	}
+	defer CloseExcel(f)
+	return ImportFromFile[T](f, opts...)
+}
+
+// ImportFromFile 从已打开的 excel 文件按结构体导入，参见 ImportFromExcel
+func ImportFromFile[T any](f *excelize.File, opts ...ImportOptions) ([]T, error) {
+	var opt ImportOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	columns, err := excelColumns(typ)
+	if err != nil {
+		return nil, err
+	}
+	if opt.SheetName == "" {
+		opt.SheetName = sheetNameOf(typ)
+	}
+	if opt.SheetName == "" {
+		opt.SheetName = f.GetSheetName(0)
+	}
+	if opt.FieldRow <= 0 {
+		opt.FieldRow = 1
+	}
+	if opt.DataRow <= opt.FieldRow {
+		opt.DataRow = opt.FieldRow + 1
+	}
+	if opt.Location == nil {
+		opt.Location = time.Local
+	}
+	props, err := f.GetWorkbookProps()
+	if err != nil {
+		return nil, err
+	}
+	date1904 := props.Date1904 != nil && *props.Date1904
+
+	rows, err := f.Rows(opt.SheetName)
+	if err != nil {
+		return nil, fmt.Errorf("sheet读取失败 %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+	var (
+		data   []T
+		errs   ImportErrors
+		cols   []int // 每个字段所在的列号，从1开始，0表示文件中没有该列
+		rowNum int
+	)
+	for rows.Next() {
+		rowNum++
+		if rowNum < opt.FieldRow || (rowNum > opt.FieldRow && rowNum < opt.DataRow) {
+			continue
+		}
+		// 单元格的原始值，日期为序列号，数字不带格式
+		cells, e := rows.Columns(excelize.Options{RawCellValue: true})
+		if e != nil {
+			return nil, fmt.Errorf("第%d行读取失败 %w", rowNum, e)
+		}
+		if rowNum == opt.FieldRow {
+			if cols, err = columnIndex(columns, cells); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if isBlankRow(cells) {
+			continue
+		}
+		item := reflect.New(typ).Elem()
+		target := item
+		if typ.Kind() == reflect.Ptr {
+			item.Set(reflect.New(typ.Elem()))
+			target = item.Elem()
+		}
+		rowOK := true
+		for i, col := range columns {
+			if cols[i] == 0 {
+				continue
+			}
+			var raw string
+			if cols[i] <= len(cells) {
+				raw = strings.TrimSpace(cells[cols[i]-1])
+			}
+			fv, e := fieldByIndex(target, col.index)
+			if e != nil {
+				return nil, e
+			}
+			if e = col.set(fv, raw, opt.Location, date1904); e != nil {
+				cell, _ := excelize.CoordinatesToCellName(cols[i], rowNum)
+				errs = append(errs, &CellError{Cell: cell, Row: rowNum, Header: col.Header, Value: raw, Err: e})
+				rowOK = false
+			}
+		}
+		if rowOK {
+			data = append(data, item.Interface().(T))
+		}
+	}
+	if err = rows.Error(); err != nil {
+		return data, err
+	}
+	if rowNum < opt.FieldRow {
+		return nil, errors.New("无有效字段列")
+	}
+	if len(errs) > 0 {
+		return data, errs
+	}
+	return data, nil
+}
+
+// columnIndex 与 GetTplSheetFieldIndex 一样按表头文本匹配列，设置了 col 的字段使用固定列
+func columnIndex(columns []excelColumn, header []string) ([]int, error) {
+	positions := make(map[string]int, len(header))
+	for i, h := range header {
+		if h = strings.TrimSpace(h); h != "" {
+			if _, ok := positions[h]; !ok {
+				positions[h] = i + 1
+			}
+		}
+	}
+	cols := make([]int, len(columns))
+	var missing []string
+	for i, col := range columns {
+		if col.Col > 0 {
+			cols[i] = col.Col
+			continue
+		}
+		cols[i] = positions[col.Header]
+		if cols[i] == 0 && col.Required {
+			missing = append(missing, col.Header)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("缺少必填列 %s", strings.Join(missing, "、"))
+	}
+	return cols, nil
+}
+
+func isBlankRow(cells []string) bool {
+	for _, c := range cells {
+		if strings.TrimSpace(c) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// set 把单元格的值转换为字段类型
+func (c excelColumn) set(fv reflect.Value, raw string, loc *time.Location, date1904 bool) error {
+	if raw == "" {
+		if c.Required {
+			return ErrCellRequired
+		}
+		return nil
+	}
+	if len(c.Enum) > 0 {
+		value, ok := c.enumValue(raw)
+		if !ok {
+			return fmt.Errorf("%w %s", ErrCellEnum, strings.Join(c.enumLabels(), "、"))
+		}
+		raw = value
+	}
+	if fv.Kind() == reflect.Ptr {
+		v := reflect.New(fv.Type().Elem())
+		if err := c.set(v.Elem(), raw, loc, date1904); err != nil {
+			return err
+		}
+		fv.Set(v)
+		return nil
+	}
+	if fv.Type() == timeType {
+		t, err := parseCellTime(raw, loc, date1904)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(t))
+		return nil
+	}
+	if reflect.PointerTo(fv.Type()).Implements(textUnmarshalerType) {
+		if err := fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw)); err != nil {
+			return fmt.Errorf("格式错误 %w", err)
+		}
+		return nil
+	}
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(raw)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := parseCellInt(raw)
+		if err != nil {
+			return err
+		}
+		if fv.OverflowInt(n) {
+			return fmt.Errorf("%s 超出范围", raw)
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := parseCellInt(raw)
+		if err != nil {
+			return err
+		}
+		if n < 0 || fv.OverflowUint(uint64(n)) {
+			return fmt.Errorf("%s 超出范围", raw)
+		}
+		fv.SetUint(uint64(n))
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(strings.ReplaceAll(raw, ",", ""), 64)
+		if err != nil {
+			return fmt.Errorf("%s 不是数字", raw)
+		}
+		if fv.OverflowFloat(n) {
+			return fmt.Errorf("%s 超出范围", raw)
+		}
+		fv.SetFloat(n)
+	case reflect.Bool:
+		b, err := parseCellBool(raw)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	default:
+		return fmt.Errorf("不支持的字段类型 %s", fv.Type())
+	}
+	return nil
+}
+
+// enumValue 显示文本转换为值，单元格中直接填写值也可以
+func (c excelColumn) enumValue(raw string) (string, bool) {
+	for _, item := range c.Enum {
+		if item.Label == raw {
+			return item.Value, true
+		}
+	}
+	for _, item := range c.Enum {
+		if item.Value == raw {
+			return item.Value, true
+		}
+	}
+	return "", false
+}
+
+// parseCellTime 日期单元格为序列号，文本单元格按常见的日期格式解析
+func parseCellTime(raw string, loc *time.Location, date1904 bool) (time.Time, error) {
+	if serial, err := strconv.ParseFloat(raw, 64); err == nil {
+		t, err := excelize.ExcelDateToTime(serial, date1904)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%s 不是有效的日期", raw)
+		}
+		// 序列号没有时区，按 loc 解释
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc), nil
+	}
+	t, err := dateparse.ParseIn(raw, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s 不是有效的日期", raw)
+	}
+	return t, nil
+}
+
+// parseCellInt 数字单元格可能带有 .0，只要是整数都可以
+func parseCellInt(raw string) (int64, error) {
+	raw = strings.ReplaceAll(raw, ",", "")
+	if n, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return n, nil
+	}
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil || f != math.Trunc(f) || f > math.MaxInt64 || f < math.MinInt64 {
+		return 0, fmt.Errorf("%s 不是整数", raw)
+	}
+	return int64(f), nil
+}
+
+func parseCellBool(raw string) (bool, error) {
+	switch strings.ToLower(raw) {
+	case "1", "true", "yes", "y", "是", "对":
+		return true, nil
+	case "0", "false", "no", "n", "否", "错":
+		return false, nil
+	}
+	return false, fmt.Errorf("%s 不是有效的布尔值", raw)
+}
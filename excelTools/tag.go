@@ -0,0 +1,179 @@
+package excelTools
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// excelColumn 结构体字段的 excel 标签
+//
+// 格式为 `excel:"表头;col:B;format:yyyy-mm-dd;enum:1=男|2=女;required;note:说明"`，只有表头是必填的：
+//   - col 固定列，不设置时导入按表头匹配列，模板按字段顺序排列
+//   - format Excel 数字格式，用于模板的列格式，格式中不能包含分号
+//   - enum 枚举，值=显示文本，用 | 分隔，导入时显示文本转换为值，模板生成下拉框
+//   - required 必填
+//   - note 模板表头的批注
+type excelColumn struct {
+	index    []int // 字段索引
+	typ      reflect.Type
+	Header   string
+	Col      int // 固定列号，从1开始，0表示按表头匹配
+	Format   string
+	Enum     []enumItem
+	Required bool
+	Note     string
+}
+
+type enumItem struct {
+	Value string
+	Label string
+}
+
+// parseExcelTag 解析 excel 标签，表头为空或者为 - 时返回 false
+func parseExcelTag(tag string) (excelColumn, bool, error) {
+	parts := strings.Split(tag, ";")
+	col := excelColumn{Header: strings.TrimSpace(parts[0])}
+	if col.Header == "" || col.Header == "-" {
+		return col, false, nil
+	}
+	for _, part := range parts[1:] {
+		key, value, _ := strings.Cut(part, ":")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		switch key {
+		case "col":
+			n, err := excelize.ColumnNameToNumber(value)
+			if err != nil {
+				return col, false, fmt.Errorf("%s 列名 %s 错误 %w", col.Header, value, err)
+			}
+			col.Col = n
+		case "format":
+			col.Format = value
+		case "enum":
+			for _, item := range strings.Split(value, "|") {
+				v, label, ok := strings.Cut(item, "=")
+				if !ok {
+					label = v
+				}
+				col.Enum = append(col.Enum, enumItem{Value: strings.TrimSpace(v), Label: strings.TrimSpace(label)})
+			}
+		case "required":
+			col.Required = true
+		case "note":
+			col.Note = value
+		case "":
+		default:
+			return col, false, fmt.Errorf("%s 未知的标签选项 %s", col.Header, key)
+		}
+	}
+	return col, true, nil
+}
+
+// TagHeader 获取 excel 标签中的表头，表头为空或者为 - 时返回 false
+// 供按表头匹配列的调用方使用，与 ImportFromExcel 的匹配规则一致。
+func TagHeader(tag string) (string, bool) {
+	header, _, _ := strings.Cut(tag, ";")
+	header = strings.TrimSpace(header)
+	return header, header != "" && header != "-"
+}
+
+// excelColumns 解析结构体中带 excel 标签的字段，包括嵌入结构体的字段
+func excelColumns(t reflect.Type) ([]excelColumn, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s 不是结构体", t)
+	}
+	var columns []excelColumn
+	for _, field := range reflect.VisibleFields(t) {
+		if field.Anonymous || !field.IsExported() {
+			continue
+		}
+		col, ok, err := parseExcelTag(field.Tag.Get("excel"))
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		col.index = field.Index
+		col.typ = field.Type
+		columns = append(columns, col)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("%s 没有 excel 标签的字段", t)
+	}
+	return columns, nil
+}
+
+// fieldByIndex 按字段索引获取字段，经过为 nil 的嵌入结构体指针时分配新值
+// 未导出的嵌入结构体指针无法赋值，为 nil 时返回错误。
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf("嵌入的结构体指针 %s 未导出，无法赋值", v.Type())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
+
+// fieldByIndexRead 按字段索引读取字段，经过为 nil 的嵌入结构体指针时返回 false
+func fieldByIndexRead(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// enumLabel 值对应的显示文本，没有匹配的枚举时返回 false
+func (c excelColumn) enumLabel(value string) (string, bool) {
+	for _, item := range c.Enum {
+		if item.Value == value {
+			return item.Label, true
+		}
+	}
+	return "", false
+}
+
+// enumLabels 枚举的显示文本，bool 字段默认为 是、否
+func (c excelColumn) enumLabels() []string {
+	if len(c.Enum) == 0 && indirectType(c.typ).Kind() == reflect.Bool {
+		return []string{"是", "否"}
+	}
+	labels := make([]string, len(c.Enum))
+	for i, item := range c.Enum {
+		labels[i] = item.Label
+	}
+	return labels
+}
+
+// sheetNameOf 模型实现了 HasSheetName 时返回模型指定的sheet
+func sheetNameOf(t reflect.Type) string {
+	if h, ok := reflect.New(indirectType(t)).Interface().(HasSheetName); ok {
+		return h.SheetName()
+	}
+	return ""
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
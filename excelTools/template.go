@@ -0,0 +1,242 @@
+package excelTools
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+	"helay.net/go/utils/v3/tools"
+)
+
+// TemplateOptions 导入模板配置
+type TemplateOptions struct {
+	SheetName string // sheet名称，默认为模型 HasSheetName 指定的sheet，都没有时为 Sheet1
+	Rows      int    // 数据校验、下拉框作用的行数，默认到 sheet 的最后一行
+	Author    string // 批注作者
+}
+
+// optionsSheet 存放较长下拉选项的隐藏sheet
+const optionsSheet = "_options"
+
+// NewTemplate 按结构体的 excel 标签生成导入模板，与 ImportFromExcel 使用同一套标签
+// 表头行写入表头，必填列的表头为红色；enum 和 bool 字段生成下拉框；format 设置为列的数字格式；
+// note、必填、可选值和日期格式写入表头的批注。
+func NewTemplate[T any](opts ...TemplateOptions) (*excelize.File, error) {
+	var opt TemplateOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	columns, err := excelColumns(typ)
+	if err != nil {
+		return nil, err
+	}
+	if opt.SheetName == "" {
+		opt.SheetName = sheetNameOf(typ)
+	}
+	if opt.SheetName == "" {
+		opt.SheetName = "Sheet1"
+	}
+	lastRow := excelize.TotalRows
+	if opt.Rows > 0 && opt.Rows+1 < excelize.TotalRows {
+		lastRow = opt.Rows + 1
+	}
+
+	f := excelize.NewFile()
+	if err = f.SetSheetName(f.GetSheetName(0), opt.SheetName); err != nil {
+		f.Close()
+		return nil, err
+	}
+	t := &template{f: f, sheet: opt.SheetName, author: opt.Author, lastRow: lastRow}
+	if err = t.init(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	for i, colNum := range templateColumns(columns) {
+		if err = t.column(columns[i], colNum); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("%s 模板生成失败 %w", columns[i].Header, err)
+		}
+	}
+	if err = f.SetPanes(opt.SheetName, &excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// WriteTemplate 生成导入模板并写入 dst
+func WriteTemplate[T any](dst io.Writer, opts ...TemplateOptions) error {
+	f, err := NewTemplate[T](opts...)
+	if err != nil {
+		return err
+	}
+	defer CloseExcel(f)
+	return f.Write(dst)
+}
+
+// templateColumns 设置了 col 的字段使用固定列，其它字段按顺序使用剩余的列
+func templateColumns(columns []excelColumn) []int {
+	used := make(map[int]bool)
+	for _, col := range columns {
+		if col.Col > 0 {
+			used[col.Col] = true
+		}
+	}
+	nums := make([]int, len(columns))
+	next := 1
+	for i, col := range columns {
+		if col.Col > 0 {
+			nums[i] = col.Col
+			continue
+		}
+		for used[next] {
+			next++
+		}
+		nums[i] = next
+		used[next] = true
+	}
+	return nums
+}
+
+type template struct {
+	f             *excelize.File
+	sheet         string
+	author        string
+	lastRow       int
+	headerStyle   int
+	requiredStyle int
+	options       int // 隐藏sheet已使用的列数
+}
+
+func (t *template) init() error {
+	var err error
+	header := excelize.Style{
+		Font:      &excelize.Font{Bold: true},
+		Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"DDEBF7"}},
+		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center"},
+	}
+	if t.headerStyle, err = t.f.NewStyle(&header); err != nil {
+		return err
+	}
+	header.Font = &excelize.Font{Bold: true, Color: "C00000"}
+	t.requiredStyle, err = t.f.NewStyle(&header)
+	return err
+}
+
+func (t *template) column(col excelColumn, colNum int) error {
+	name, err := excelize.ColumnNumberToName(colNum)
+	if err != nil {
+		return err
+	}
+	cell := name + "1"
+	if err = t.f.SetCellStr(t.sheet, cell, col.Header); err != nil {
+		return err
+	}
+	if err = t.f.SetCellStyle(t.sheet, cell, cell, tools.Ternary(col.Required, t.requiredStyle, t.headerStyle)); err != nil {
+		return err
+	}
+	if err = t.f.SetColWidth(t.sheet, name, name, float64(max(12, len([]rune(col.Header))*2+4))); err != nil {
+		return err
+	}
+	if err = t.numFmt(col, name); err != nil {
+		return err
+	}
+	if labels := col.enumLabels(); len(labels) > 0 {
+		if err = t.dropList(col, name, labels); err != nil {
+			return err
+		}
+	}
+	if note := col.comment(); note != "" {
+		return t.f.AddComment(t.sheet, excelize.Comment{
+			Author:    t.author,
+			Cell:      cell,
+			Paragraph: []excelize.RichTextRun{{Text: note}},
+			Width:     200,
+			Height:    80,
+		})
+	}
+	return nil
+}
+
+// numFmt 设置列格式，字符串列设置为文本格式，避免编号、手机号等被转换为数字
+func (t *template) numFmt(col excelColumn, name string) error {
+	var style excelize.Style
+	switch typ := indirectType(col.typ); {
+	case col.Format != "":
+		style.CustomNumFmt = &col.Format
+	case typ == timeType:
+		layout := "yyyy-mm-dd"
+		style.CustomNumFmt = &layout
+	case typ.Kind() == reflect.String && len(col.Enum) == 0:
+		style.NumFmt = 49
+	default:
+		return nil
+	}
+	id, err := t.f.NewStyle(&style)
+	if err != nil {
+		return err
+	}
+	if err = t.f.SetColStyle(t.sheet, name, id); err != nil {
+		return err
+	}
+	// 列样式也会应用到表头，重新设置表头样式
+	return t.f.SetCellStyle(t.sheet, name+"1", name+"1", tools.Ternary(col.Required, t.requiredStyle, t.headerStyle))
+}
+
+// dropList 下拉框，选项超过 Excel 的 255 个字符限制时写入隐藏sheet并引用
+func (t *template) dropList(col excelColumn, name string, labels []string) error {
+	dv := excelize.NewDataValidation(!col.Required)
+	dv.SetSqref(fmt.Sprintf("%s2:%s%d", name, name, t.lastRow))
+	dv.SetError(excelize.DataValidationErrorStyleStop, col.Header, "请从下拉列表中选择")
+	if err := dv.SetDropList(labels); err != nil {
+		ref, e := t.writeOptions(labels)
+		if e != nil {
+			return e
+		}
+		dv.SetSqrefDropList(ref)
+	}
+	return t.f.AddDataValidation(t.sheet, dv)
+}
+
+func (t *template) writeOptions(labels []string) (string, error) {
+	if t.options == 0 {
+		if _, err := t.f.NewSheet(optionsSheet); err != nil {
+			return "", err
+		}
+		if err := t.f.SetSheetVisible(optionsSheet, false, true); err != nil {
+			return "", err
+		}
+	}
+	t.options++
+	name, err := excelize.ColumnNumberToName(t.options)
+	if err != nil {
+		return "", err
+	}
+	for i, label := range labels {
+		if err = t.f.SetCellStr(optionsSheet, fmt.Sprintf("%s%d", name, i+1), label); err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("%s!$%s$1:$%s$%d", optionsSheet, name, name, len(labels)), nil
+}
+
+// comment 表头批注
+func (c excelColumn) comment() string {
+	var lines []string
+	if c.Note != "" {
+		lines = append(lines, c.Note)
+	}
+	if c.Required {
+		lines = append(lines, "必填")
+	}
+	if labels := c.enumLabels(); len(labels) > 0 && len(labels) <= 20 {
+		lines = append(lines, "可选值："+strings.Join(labels, "、"))
+	}
+	if indirectType(c.typ) == timeType {
+		lines = append(lines, "日期格式："+tools.Ternary(c.Format == "", "yyyy-mm-dd", c.Format))
+	}
+	return strings.Join(lines, "\n")
+}
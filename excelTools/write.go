@@ -1,6 +1,7 @@
 package excelTools
 
 import (
+	"encoding"
 	"fmt"
 	"github.com/xuri/excelize/v2"
 	"io"
@@ -34,17 +35,23 @@ import (
 // Date: 2024/11/9 16:19
 //
 
-// ExportToExcel 将结构体切片导出为 Excel
+// ExportToExcel 将结构体切片导出为 Excel，是 ImportFromExcel 的逆操作
+// 与导入使用同一套 excel 标签：包括嵌入结构体的字段，设置了 col 的字段写入固定列，
+// enum 字段写入显示文本，format 设置为列的数字格式，nil 指针写入空单元格。
 func ExportToExcel[T any](dst io.Writer, data []T) error {
 	// 创建一个新的 Excel 文件。
 	f := excelize.NewFile()
+	defer CloseExcel(f)
 
 	// 获取第一个元素的类型。
 	if len(data) == 0 {
 		return fmt.Errorf("data slice is empty")
 	}
-	v := reflect.ValueOf(data[0])
-	t := v.Type()
+	columns, err := excelColumns(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return err
+	}
+	colNums := templateColumns(columns)
 
 	// 创建一个工作表。
 	sheetName := "Sheet1"
@@ -55,23 +62,23 @@ func ExportToExcel[T any](dst io.Writer, data []T) error {
 	f.SetActiveSheet(index)
 
 	// 写入表头。
-	headers := make([]string, 0)
-	fieldIndices := make([]int, 0)
-	for i := 0; i < v.NumField(); i++ {
-		field := t.Field(i)
-		_field := field.Tag.Get("excel")
-		if _field == "-" || _field == "" {
+	for i, col := range columns {
+		name, err := excelize.ColumnNumberToName(colNums[i])
+		if err != nil {
+			return err
+		}
+		if err = f.SetCellValue(sheetName, name+"1", col.Header); err != nil {
+			return fmt.Errorf("set cell value failed: %v", err)
+		}
+		if col.Format == "" {
 			continue
 		}
-		headers = append(headers, _field)
-		fieldIndices = append(fieldIndices, i)
-	}
-
-	for i, header := range headers {
-		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
-		err := f.SetCellValue(sheetName, cell, header)
+		style, err := f.NewStyle(&excelize.Style{CustomNumFmt: &col.Format})
 		if err != nil {
-			return fmt.Errorf("set cell value failed: %v", err)
+			return err
+		}
+		if err = f.SetColStyle(sheetName, name, style); err != nil {
+			return err
 		}
 	}
 
@@ -79,14 +86,52 @@ func ExportToExcel[T any](dst io.Writer, data []T) error {
 	for rowIndex, item := range data {
 		row := rowIndex + 2 // 跳过第一行的表头
 		vItem := reflect.ValueOf(item)
-		for i, fieldIndex := range fieldIndices {
-			value := vItem.Field(fieldIndex).Interface()
-			cell, _ := excelize.CoordinatesToCellName(i+1, row)
-			err := f.SetCellValue(sheetName, cell, value)
-			if err != nil {
+		for vItem.Kind() == reflect.Ptr && !vItem.IsNil() {
+			vItem = vItem.Elem()
+		}
+		if vItem.Kind() != reflect.Struct {
+			continue
+		}
+		for i, col := range columns {
+			fv, ok := fieldByIndexRead(vItem, col.index)
+			if !ok {
+				continue
+			}
+			value, ok := col.cellValue(fv)
+			if !ok {
+				continue
+			}
+			cell, _ := excelize.CoordinatesToCellName(colNums[i], row)
+			if err = f.SetCellValue(sheetName, cell, value); err != nil {
 				return fmt.Errorf("set cell value failed: %v", err)
 			}
 		}
 	}
 	return f.Write(dst)
 }
+
+// cellValue 字段写入单元格的值，nil 指针返回 false
+func (c excelColumn) cellValue(fv reflect.Value) (any, bool) {
+	for fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			return nil, false
+		}
+		fv = fv.Elem()
+	}
+	value := fv.Interface()
+	if len(c.Enum) > 0 {
+		if label, ok := c.enumLabel(fmt.Sprint(value)); ok {
+			return label, true
+		}
+		return value, true
+	}
+	if fv.Type() == timeType {
+		return value, true
+	}
+	if m, ok := value.(encoding.TextMarshaler); ok {
+		if text, err := m.MarshalText(); err == nil {
+			return string(text), true
+		}
+	}
+	return value, true
+}
@@ -33,7 +33,7 @@ const (
 
 // noinspection all
 type Config struct {
-	Driver Driver `json:"driver" yaml:"driver" ini:"driver"`
+	Driver Driver `json:"driver" yaml:"driver" ini:"driver" valid:"oneof=local sftp ftp hdfs minio ceph"`
 
 	Local localfile.Config `json:"local" yaml:"local" ini:"local"` // 本地文件系统
 	FTP   ftp.Config       `json:"ftp" yaml:"ftp" ini:"ftp"`       // ftp
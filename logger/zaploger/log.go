@@ -36,8 +36,9 @@ type Config struct {
 
 // Logger implements the gorm.Logger interface.
 type Logger struct {
-	logger *zap.Logger
-	level  zapcore.LevelEnabler
+	logger      *zap.Logger
+	level       zapcore.LevelEnabler
+	atomicLevel zap.AtomicLevel // New 创建时的级别，支持运行时修改
 }
 
 // customTimeEncoder formats time as "2006-01-02 15:04:05".
@@ -110,17 +111,31 @@ func New(cfg *Config) (*Logger, error) {
 		}
 	}
 	combinedCore := zapcore.NewTee(cores...)
+	atomicLevel := zap.NewAtomicLevelAt(defalutLevel) // Default to DebugLevel
 	return &Logger{
-		logger: zap.New(combinedCore, zap.AddCaller(), zap.AddStacktrace(zap.ErrorLevel)),
-		level:  defalutLevel, // Default to DebugLevel
+		logger:      zap.New(combinedCore, zap.AddCaller(), zap.AddStacktrace(zap.ErrorLevel)),
+		level:       atomicLevel,
+		atomicLevel: atomicLevel,
 	}, nil
 }
 
+// SetLevel 运行时修改日志级别，用于配置热更新。
+// 通过 LogMode 派生的 gorm 日志实例使用各自的级别，不受影响。
+func (l *Logger) SetLevel(level string) error {
+	lvl, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	l.atomicLevel.SetLevel(lvl)
+	return nil
+}
+
 func (l *Logger) LogMode(level logger.LogLevel) logger.Interface {
 	enabler := convertLogLevel(level)
 	return &Logger{
-		logger: l.logger,
-		level:  enabler,
+		logger:      l.logger,
+		level:       enabler,
+		atomicLevel: l.atomicLevel,
 	}
 }
 
@@ -0,0 +1,58 @@
+package outbox
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"helay.net/go/utils/v3/dataType"
+	"helay.net/go/utils/v3/db/userDb"
+	"helay.net/go/utils/v3/message/pubsub"
+)
+
+// 事务发件箱
+//
+// 业务数据和待发布的消息在同一个数据库事务中写入，事务提交后由 Relay 轮询发件箱表并发布，
+// 发布失败会按指数退避重试，保证消息至少投递一次。
+//
+//	err := db.Transaction(func(tx *gorm.DB) error {
+//		if err := userDb.Create(tx, &order); err != nil {
+//			return err
+//		}
+//		return outbox.Enqueue(tx, pubsub.Params{Topic: "order", Key: "create"}, order)
+//	})
+
+// AutoMigrate 创建发件箱表
+func AutoMigrate(tx *gorm.DB) {
+	userDb.AutoCreateTableWithStruct(tx.Session(&gorm.Session{NewDB: true}), Message{}, "创建发件箱表失败")
+}
+
+// Enqueue 在调用方的事务中写入待发布的消息，msg 使用 tools.Any2bytes 转换
+func Enqueue(tx *gorm.DB, param pubsub.Params, msg any) error {
+	m, err := pubsub.NewMessage(msg)
+	if err != nil {
+		return fmt.Errorf("发件箱消息转换失败 %w", err)
+	}
+	return EnqueueMessage(tx, param, m)
+}
+
+// EnqueueMessage 在调用方的事务中写入待发布的消息，消息头会一起保存
+// 载体支持 pubsub.HandlerV2 时，消息头会随消息一起发布。
+func EnqueueMessage(tx *gorm.DB, param pubsub.Params, msg *pubsub.Message) error {
+	row := &Message{
+		Topic:         param.Topic,
+		MsgKey:        param.Key,
+		Payload:       msg.Payload,
+		Status:        StatusPending,
+		NextRetryTime: dataType.NewCustomTimeNow(),
+	}
+	if len(msg.Headers) > 0 {
+		row.Headers = make(dataType.JSONMap, len(msg.Headers))
+		for k, v := range msg.Headers {
+			row.Headers[k] = v
+		}
+	}
+	if err := tx.Session(&gorm.Session{NewDB: true}).Create(row).Error; err != nil {
+		return fmt.Errorf("写入发件箱失败 %w", err)
+	}
+	return nil
+}
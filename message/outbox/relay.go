@@ -0,0 +1,221 @@
+package outbox
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"helay.net/go/utils/v3/cluster"
+	"helay.net/go/utils/v3/dataType"
+	"helay.net/go/utils/v3/logger/ulogs"
+	"helay.net/go/utils/v3/logger/zaploger"
+	"helay.net/go/utils/v3/message/pubsub"
+	"helay.net/go/utils/v3/tools/backoff"
+)
+
+// Config 投递配置
+type Config struct {
+	Interval    time.Duration `json:"interval" yaml:"interval" ini:"interval"`             // 轮询间隔，默认1s
+	BatchSize   int           `json:"batch_size" yaml:"batch_size" ini:"batch_size"`       // 每次轮询的最大消息数，默认100
+	MaxAttempts int           `json:"max_attempts" yaml:"max_attempts" ini:"max_attempts"` // 最大投递次数，超过后转为死信，0 表示不限制
+	MinBackoff  time.Duration `json:"min_backoff" yaml:"min_backoff" ini:"min_backoff"`    // 投递失败后的初始等待时间，默认1s
+	MaxBackoff  time.Duration `json:"max_backoff" yaml:"max_backoff" ini:"max_backoff"`    // 投递失败后的最大等待时间，默认10m
+	Retention   time.Duration `json:"retention" yaml:"retention" ini:"retention"`          // 已投递消息的保留时间，0 表示不清理
+}
+
+// Relay 发件箱投递
+// 多节点部署时只有主节点投递，主从切换的瞬间可能重复投递，消费方需要保证幂等。
+// 同一个 topic_key 的消息按 ID 顺序投递：某条消息投递失败后，在它重试成功或者转为死信之前，
+// 该 topic_key 后续的消息都不会投递。
+type Relay struct {
+	db      *gorm.DB
+	handler pubsub.Handler
+	cfg     Config
+	notify  chan struct{}
+	Loger   *zaploger.Logger
+}
+
+// NewRelay 创建发件箱投递
+func NewRelay(tx *gorm.DB, handler pubsub.Handler, cfg Config) *Relay {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = time.Second
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 10 * time.Minute
+	}
+	return &Relay{
+		db:      tx.Session(&gorm.Session{NewDB: true}),
+		handler: handler,
+		cfg:     cfg,
+		notify:  make(chan struct{}, 1),
+	}
+}
+
+// Notify 唤醒投递，事务提交后调用可以减少投递延迟
+func (r *Relay) Notify() {
+	select {
+	case r.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Run 开始投递，阻塞直到 ctx 结束
+// 开启多节点模式时，只有 cluster.IsLeader 为 true 的节点才会投递。
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+	// 数据库异常时按指数退避，避免频繁报错
+	b := backoff.NewBackoff(backoff.Exponential, r.cfg.Interval, time.Minute, 2.0)
+	var wait <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-wait:
+			wait = nil
+		case <-ticker.C:
+		case <-r.notify:
+		}
+		if wait != nil || !leader() {
+			continue
+		}
+		if err := r.Flush(ctx); err != nil {
+			r.error("发件箱投递失败", err)
+			wait = time.After(b.Next())
+			continue
+		}
+		b.Reset()
+	}
+}
+
+func leader() bool {
+	return !cluster.EnableCluster || cluster.IsLeader()
+}
+
+// Flush 投递所有到期的消息，直到没有到期消息或 ctx 结束
+func (r *Relay) Flush(ctx context.Context) error {
+	for ctx.Err() == nil {
+		n, err := r.flushBatch(ctx)
+		if err != nil {
+			return err
+		}
+		if n < r.cfg.BatchSize {
+			break
+		}
+	}
+	if r.cfg.Retention > 0 {
+		before := dataType.NewCustomTime(time.Now().Add(-r.cfg.Retention))
+		err := r.db.WithContext(ctx).Where("status = ? AND delivered_time < ?", StatusDelivered, before).Delete(&Message{}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flushBatch 投递一批消息，返回本批次读取的消息数
+// 同一个 topic_key 中有更早的消息在等待重试时，后续的消息不读取
+func (r *Relay) flushBatch(ctx context.Context) (int, error) {
+	var (
+		rows  []Message
+		now   = dataType.NewCustomTimeNow()
+		table = Message{}.TableName()
+	)
+	waiting := r.db.Table(table+" AS w").Select("1").
+		Where("w.topic = "+table+".topic AND w.msg_key = "+table+".msg_key AND w.id < "+table+".id").
+		Where("w.status = ? AND w.next_retry_time > ?", StatusPending, now)
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND next_retry_time <= ?", StatusPending, now).
+		Where("NOT EXISTS (?)", waiting).
+		Order("id").Limit(r.cfg.BatchSize).Find(&rows).Error
+	if err != nil {
+		return 0, err
+	}
+	blocked := make(map[string]bool)
+	for _, row := range rows {
+		if ctx.Err() != nil {
+			break
+		}
+		param := pubsub.Params{Topic: row.Topic, Key: row.MsgKey}
+		key := param.String(false)
+		if blocked[key] {
+			continue
+		}
+		if err = r.publish(ctx, param, &row); err != nil {
+			blocked[key] = true
+			if err = r.failed(ctx, &row, err); err != nil {
+				return len(rows), err
+			}
+			continue
+		}
+		now := dataType.NewCustomTimeNow()
+		err = r.db.WithContext(ctx).Model(&Message{}).Where("id = ?", row.Id).Updates(map[string]any{
+			"status":         StatusDelivered,
+			"attempts":       row.Attempts + 1,
+			"delivered_time": &now,
+			"last_error":     "",
+		}).Error
+		if err != nil {
+			return len(rows), err
+		}
+	}
+	return len(rows), nil
+}
+
+// publish 发布消息，载体支持 HandlerV2 时会发送消息头，消息ID为发件箱行ID
+func (r *Relay) publish(ctx context.Context, param pubsub.Params, row *Message) error {
+	if h, ok := r.handler.(pubsub.HandlerV2); ok {
+		msg := &pubsub.Message{Payload: row.Payload}
+		for k, v := range row.Headers {
+			if s, ok := v.(string); ok {
+				msg.SetHeader(k, s)
+			}
+		}
+		msg.Metadata.ID = strconv.FormatInt(row.Id, 10)
+		msg.Metadata.Timestamp = row.CreateTime.Time
+		return h.PublishV2(ctx, param, msg)
+	}
+	return r.handler.Publish(param, []byte(row.Payload))
+}
+
+// failed 记录投递失败，计算下次投递时间
+func (r *Relay) failed(ctx context.Context, row *Message, cause error) error {
+	attempts := row.Attempts + 1
+	updates := map[string]any{
+		"attempts":        attempts,
+		"last_error":      cause.Error(),
+		"next_retry_time": dataType.NewCustomTime(time.Now().Add(r.retryDelay(attempts))),
+	}
+	if r.cfg.MaxAttempts > 0 && attempts >= r.cfg.MaxAttempts {
+		updates["status"] = StatusDead
+		r.error("发件箱消息超过最大投递次数", cause, "id", row.Id, "topic", row.Topic, "key", row.MsgKey)
+	}
+	return r.db.WithContext(ctx).Model(&Message{}).Where("id = ?", row.Id).Updates(updates).Error
+}
+
+// retryDelay 第 attempts 次失败后的等待时间
+func (r *Relay) retryDelay(attempts int) time.Duration {
+	b := backoff.NewBackoff(backoff.Exponential, r.cfg.MinBackoff, r.cfg.MaxBackoff, 2.0)
+	var d time.Duration
+	for i := 0; i < attempts; i++ {
+		if d = b.Next(); d >= r.cfg.MaxBackoff {
+			break
+		}
+	}
+	return d
+}
+
+func (r *Relay) error(title string, err error, args ...any) {
+	if r.Loger == nil {
+		ulogs.Error(append([]any{title, err}, args...)...)
+	} else {
+		r.Loger.Error(context.Background(), title, zaploger.Auto2Field(append([]any{"错误信息", err}, args...)...))
+	}
+}
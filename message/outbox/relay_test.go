@@ -0,0 +1,81 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"helay.net/go/utils/v3/message/pubsub"
+)
+
+// recordHandler 记录发布的消息，payload 在 fail 中的消息发布失败
+type recordHandler struct {
+	fail      map[string]bool
+	published []string
+}
+
+func (h *recordHandler) Subscribe(pubsub.Params, *pubsub.Cbfunc) {}
+
+func (h *recordHandler) Publish(_ pubsub.Params, msg any) error {
+	s := string(msg.([]byte))
+	if h.fail[s] {
+		return errors.New("publish failed")
+	}
+	h.published = append(h.published, s)
+	return nil
+}
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1) // 每个连接都是独立的内存数据库
+	if err = db.AutoMigrate(&Message{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestRelayKeepsKeyOrder(t *testing.T) {
+	db := newTestDB(t)
+	for _, m := range []struct{ key, payload string }{{"a", "a1"}, {"a", "a2"}, {"b", "b1"}} {
+		if err := Enqueue(db, pubsub.Params{Topic: "t", Key: m.key}, m.payload); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h := &recordHandler{fail: map[string]bool{"a1": true}}
+	r := NewRelay(db, h, Config{})
+	ctx := context.Background()
+	if err := r.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(h.published, []string{"b1"}) {
+		t.Fatalf("published = %v", h.published)
+	}
+
+	// a1 等待重试期间，a2 已经到期也不能投递
+	h.fail = nil
+	if err := r.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(h.published, []string{"b1"}) {
+		t.Fatalf("published while a1 waits for retry = %v", h.published)
+	}
+
+	db.Model(&Message{}).Where("status = ?", StatusPending).Update("next_retry_time", "2000-01-01 00:00:00")
+	if err := r.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(h.published, []string{"b1", "a1", "a2"}) {
+		t.Fatalf("published = %v", h.published)
+	}
+}
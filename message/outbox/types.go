@@ -0,0 +1,34 @@
+package outbox
+
+import (
+	"helay.net/go/utils/v3/dataType"
+	"helay.net/go/utils/v3/db"
+)
+
+// Status 消息状态
+type Status int
+
+const (
+	StatusPending   Status = 0 // 待投递
+	StatusDelivered Status = 1 // 已投递
+	StatusDead      Status = 2 // 超过最大重试次数，不再投递
+)
+
+// Message 发件箱消息表
+type Message struct {
+	Id            int64                `json:"id" gorm:"primaryKey;not null;autoIncrement;comment:行ID"`
+	Topic         string               `json:"topic" gorm:"type:varchar(256);not null;index:idx_outbox_key,priority:1;comment:主题"`
+	MsgKey        string               `json:"msg_key" gorm:"type:varchar(256);not null;default:'';index:idx_outbox_key,priority:2;comment:消息key"`
+	Payload       dataType.Binary      `json:"payload" gorm:"comment:消息内容"`
+	Headers       dataType.JSONMap     `json:"headers" gorm:"comment:消息头"`
+	Status        Status               `json:"status" gorm:"type:int;not null;default:0;index:idx_outbox_status_retry,priority:1;comment:状态 0待投递 1已投递 2死信"`
+	Attempts      int                  `json:"attempts" gorm:"type:int;not null;default:0;comment:投递次数"`
+	NextRetryTime dataType.CustomTime  `json:"next_retry_time" gorm:"not null;index:idx_outbox_status_retry,priority:2;comment:下次投递时间"`
+	LastError     string               `json:"last_error" gorm:"type:text;comment:最后一次投递失败原因"`
+	DeliveredTime *dataType.CustomTime `json:"delivered_time" gorm:"index;comment:投递成功时间"`
+	db.TableDefaultTimeField
+}
+
+func (Message) TableName() string {
+	return "outbox_message"
+}
@@ -0,0 +1,52 @@
+package pubsub
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// redis、etcd 等载体没有消息头，消息头和元数据需要与消息内容一起编码。
+// 没有消息头和元数据时直接发送原始内容，旧的订阅方不受影响；
+// 否则使用带前缀的信封格式，旧的订阅方会收到信封原文。
+
+var envelopePrefix = []byte("\x00pubsub/v2\x00")
+
+type envelope struct {
+	Headers   map[string]string `json:"h,omitempty"`
+	ID        string            `json:"i,omitempty"`
+	Timestamp int64             `json:"t,omitempty"` // 毫秒时间戳
+	Payload   []byte            `json:"p"`
+}
+
+// EncodeEnvelope 编码消息，供不支持消息头的载体使用
+func EncodeEnvelope(msg *Message) ([]byte, error) {
+	if len(msg.Headers) == 0 && msg.Metadata.ID == "" && msg.Metadata.Timestamp.IsZero() {
+		return msg.Payload, nil
+	}
+	env := envelope{Headers: msg.Headers, ID: msg.Metadata.ID, Payload: msg.Payload}
+	if !msg.Metadata.Timestamp.IsZero() {
+		env.Timestamp = msg.Metadata.Timestamp.UnixMilli()
+	}
+	byt, err := json.Marshal(env)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte(nil), envelopePrefix...), byt...), nil
+}
+
+// DecodeEnvelope 解码消息，不是信封格式时整体作为消息内容
+func DecodeEnvelope(data []byte) *Message {
+	if rest, ok := bytes.CutPrefix(data, envelopePrefix); ok {
+		var env envelope
+		if json.Unmarshal(rest, &env) == nil {
+			msg := &Message{Payload: env.Payload, Headers: env.Headers}
+			msg.Metadata.ID = env.ID
+			if env.Timestamp > 0 {
+				msg.Metadata.Timestamp = time.UnixMilli(env.Timestamp)
+			}
+			return msg
+		}
+	}
+	return &Message{Payload: data}
+}
@@ -8,6 +8,7 @@ import (
 	"helay.net/go/utils/v3/logger/zaploger"
 	"helay.net/go/utils/v3/message/pubsub"
 	"helay.net/go/utils/v3/tools"
+	"time"
 )
 
 type Instance struct {
@@ -24,22 +25,7 @@ func New(etcd *clientv3.Client, opts *pubsub.Options) *Instance {
 
 // Subscribe 订阅消息
 func (this *Instance) Subscribe(param pubsub.Params, cbs *pubsub.Cbfunc) {
-	topic := param.String(true)
-	rch := this.etcdClient.Watch(this.opts.Ctx, topic)
-	for wresp := range rch {
-		for _, ev := range wresp.Events {
-			switch ev.Type {
-			case clientv3.EventTypePut:
-				if cbs.CbString != nil {
-					cbs.CbString(string(ev.Kv.Value))
-				} else if cbs.CbByte != nil {
-					cbs.CbByte(ev.Kv.Value)
-				} else if cbs.CbAny != nil {
-					cbs.CbAny(ev.Kv.Value)
-				}
-			}
-		}
-	}
+	pubsub.Adapt(this.opts.Ctx, this).Subscribe(param, cbs)
 }
 
 func (this *Instance) Publish(param pubsub.Params, msg any) error {
@@ -53,6 +39,60 @@ func (this *Instance) Publish(param pubsub.Params, msg any) error {
 	return nil
 }
 
+// SubscribeV2 订阅消息，只处理写入事件，元数据中的 Offset 为修订版本
+// etcd 没有确认机制，回调最终失败的消息只会记录日志。
+func (this *Instance) SubscribeV2(ctx context.Context, param pubsub.Params, h pubsub.MessageHandler, opts ...pubsub.SubscribeOption) (*pubsub.Subscription, error) {
+	topic := param.String(true)
+	o := pubsub.NewSubscribeOptions(opts...)
+	sub, subCtx := pubsub.NewSubscription(ctx)
+	rch := this.etcdClient.Watch(clientv3.WithRequireLeader(subCtx), topic)
+	go func() {
+		var err error
+		defer func() { sub.Finish(err) }()
+		for wresp := range rch {
+			if _err := wresp.Err(); _err != nil {
+				if subCtx.Err() != nil {
+					return
+				}
+				err = fmt.Errorf("etcdHander 订阅消息失败: %w", _err)
+				return
+			}
+			for _, ev := range wresp.Events {
+				if ev.Type != clientv3.EventTypePut {
+					continue
+				}
+				msg := pubsub.DecodeEnvelope(ev.Kv.Value)
+				msg.Metadata.Carrier = pubsub.CarrierEtcd
+				msg.Metadata.Topic = param.Topic
+				msg.Metadata.Key = param.Key
+				msg.Metadata.Offset = ev.Kv.ModRevision
+				if msg.Metadata.Timestamp.IsZero() {
+					msg.Metadata.Timestamp = time.Now()
+				}
+				if _err := o.Deliver(subCtx, h, msg); _err != nil {
+					this.error("etcdHander 消息处理失败", "主题", topic, "版本", ev.Kv.ModRevision, "错误信息", _err)
+				}
+			}
+		}
+	}()
+	return sub, nil
+}
+
+// PublishV2 发布消息，消息头和元数据通过信封格式发送
+func (this *Instance) PublishV2(ctx context.Context, param pubsub.Params, msg *pubsub.Message) error {
+	topic := param.String(true)
+	payload, err := pubsub.EncodeEnvelope(msg)
+	if err != nil {
+		return fmt.Errorf("etcdHander 发布消息失败: %w", err)
+	}
+	resp, err := this.etcdClient.Put(ctx, topic, string(payload))
+	if err != nil {
+		return fmt.Errorf("etcdHander 发布消息失败: %w", err)
+	}
+	this.debug("etcdHander 发布消息成功", "主题", topic, "版本", resp.Header.GetRevision())
+	return nil
+}
+
 func (this *Instance) log(title string, args ...any) {
 	if this.opts.Loger == nil {
 		ulogs.Log(append([]any{title}, args...)...)
@@ -6,7 +6,7 @@ import (
 )
 
 type consumerGroupHander struct {
-	msg chan *sarama.ConsumerMessage
+	msg chan *delivery
 }
 
 func (this *consumerGroupHander) Setup(session sarama.ConsumerGroupSession) error {
@@ -18,6 +18,9 @@ func (h *consumerGroupHander) Cleanup(session sarama.ConsumerGroupSession) error
 	return nil
 }
 
+// ConsumeClaim 逐条投递分区消息，所有订阅者处理完成后才提交偏移量
+// 消息被拒绝时 OnError 已经执行（记录或转入死信），同样提交偏移量继续消费，避免一条消息反复投递阻塞整个分区。
+// 会话结束时未处理完的消息不提交偏移量，重新加入消费组后从已提交的偏移量开始重新投递。
 func (h *consumerGroupHander) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
 	for {
 		select {
@@ -25,8 +28,22 @@ func (h *consumerGroupHander) ConsumeClaim(session sarama.ConsumerGroupSession,
 			if !ok {
 				return fmt.Errorf("kafka状态失败:topic:%s,partition:%d", claim.Topic(), claim.Partition())
 			}
-			h.msg <- message
-			session.MarkMessage(message, "")
+			d := &delivery{msg: message, done: make(chan error, 1)}
+			select {
+			case h.msg <- d:
+			case <-session.Context().Done():
+				return nil
+			}
+			select {
+			case err := <-d.done:
+				if err != nil && session.Context().Err() != nil {
+					// 会话结束导致的失败，不提交偏移量
+					return nil
+				}
+				session.MarkMessage(message, "")
+			case <-session.Context().Done():
+				return nil
+			}
 		case <-session.Context().Done():
 			return nil
 		}
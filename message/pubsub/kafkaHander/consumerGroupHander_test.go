@@ -0,0 +1,138 @@
+package kafkaHander
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"helay.net/go/utils/v3/message/pubsub"
+)
+
+type fakeSession struct {
+	sarama.ConsumerGroupSession
+	ctx    context.Context
+	mu     sync.Mutex
+	marked []int64
+}
+
+func (s *fakeSession) Context() context.Context { return s.ctx }
+
+func (s *fakeSession) MarkMessage(msg *sarama.ConsumerMessage, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.marked = append(s.marked, msg.Offset)
+}
+
+type fakeClaim struct {
+	sarama.ConsumerGroupClaim
+	msgs chan *sarama.ConsumerMessage
+}
+
+func (c *fakeClaim) Topic() string                            { return "topic" }
+func (c *fakeClaim) Partition() int32                         { return 0 }
+func (c *fakeClaim) Messages() <-chan *sarama.ConsumerMessage { return c.msgs }
+
+// newGroupInstance 不连接 kafka 的消费组实例，消息由测试直接交给 consumerGroupHander
+func newGroupInstance(ctx context.Context) *Instance {
+	ins := &Instance{
+		opts:    &pubsub.Options{Ctx: ctx},
+		isGroup: true,
+		message: make(chan *delivery, 100),
+		subs:    make(map[string]map[*subscriber]struct{}),
+	}
+	ins.topics.Store("topic", true)
+	go ins.msgHander()
+	return ins
+}
+
+func TestConsumeClaimRejectedDoesNotStall(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ins := newGroupInstance(ctx)
+
+	var (
+		mu       sync.Mutex
+		handled  []int64
+		rejected []int64
+	)
+	_, err := ins.SubscribeV2(ctx, pubsub.Params{Topic: "topic", Key: "k"}, func(_ context.Context, msg *pubsub.Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+		handled = append(handled, msg.Metadata.Offset)
+		if msg.Metadata.Offset == 0 {
+			return pubsub.Permanent(errors.New("bad message"))
+		}
+		return nil
+	}, pubsub.WithErrorHandler(func(msg *pubsub.Message, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		rejected = append(rejected, msg.Metadata.Offset)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session := &fakeSession{ctx: ctx}
+	claim := &fakeClaim{msgs: make(chan *sarama.ConsumerMessage, 2)}
+	for offset := range int64(2) {
+		claim.msgs <- &sarama.ConsumerMessage{Topic: "topic", Key: []byte("k"), Offset: offset}
+	}
+	close(claim.msgs)
+
+	done := make(chan error, 1)
+	go func() { done <- (&consumerGroupHander{msg: ins.message}).ConsumeClaim(session, claim) }()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("ConsumeClaim stalled")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !slices.Equal(handled, []int64{0, 1}) || !slices.Equal(rejected, []int64{0}) {
+		t.Fatalf("handled = %v, rejected = %v", handled, rejected)
+	}
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if !slices.Equal(session.marked, []int64{0, 1}) {
+		t.Fatalf("marked = %v, want [0 1]", session.marked)
+	}
+}
+
+func TestConsumeClaimSessionEndNotMarked(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ins := newGroupInstance(ctx)
+
+	started := make(chan struct{})
+	_, err := ins.SubscribeV2(ctx, pubsub.Params{Topic: "topic", Key: "k"}, func(ctx context.Context, msg *pubsub.Message) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessCtx, endSession := context.WithCancel(ctx)
+	session := &fakeSession{ctx: sessCtx}
+	claim := &fakeClaim{msgs: make(chan *sarama.ConsumerMessage, 1)}
+	claim.msgs <- &sarama.ConsumerMessage{Topic: "topic", Key: []byte("k")}
+
+	done := make(chan error, 1)
+	go func() { done <- (&consumerGroupHander{msg: ins.message}).ConsumeClaim(session, claim) }()
+	<-started
+	endSession()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if len(session.marked) != 0 {
+		t.Fatalf("marked = %v, want none", session.marked)
+	}
+}
@@ -10,15 +10,15 @@ import (
 	"time"
 )
 
-func (this *Instance) single(param pubsub.Params) {
+func (this *Instance) single(param pubsub.Params) error {
 	partitionList, err := this.consumer.Partitions(param.Topic)
 	if err != nil {
-		this.error("订阅发布组件订阅失败", "kafka consumer", fmt.Errorf("%s：%s", param.Topic, err.Error()))
-		return
+		return fmt.Errorf("kafka consumer %s：%w", param.Topic, err)
 	}
 	for _, partition := range partitionList {
 		go this.partition(param.Topic, partition, sarama.OffsetNewest) // 默认从最新的offset开始消费
 	}
+	return nil
 }
 
 // 分区消费
@@ -39,7 +39,7 @@ func (this *Instance) partition(topic string, partition int32, offset int64) {
 				this.error("订阅发布组件订阅失败", "kafka consumer", topic, "消息为空", "分区", partition)
 				continue
 			}
-			this.message <- msg
+			this.message <- &delivery{msg: msg}
 		case <-this.opts.Ctx.Done(): // 监听退出信号
 			this.log("订阅发布组件", "kafka载体", "普通消费者", topic, "退出消费", "分区", partition)
 			b.Reset()
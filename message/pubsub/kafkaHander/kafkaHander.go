@@ -7,7 +7,6 @@ import (
 	"helay.net/go/utils/v3/logger/ulogs"
 	"helay.net/go/utils/v3/logger/zaploger"
 	"helay.net/go/utils/v3/message/pubsub"
-	"helay.net/go/utils/v3/tools"
 	"sync"
 )
 
@@ -17,9 +16,12 @@ type Instance struct {
 	isGroup       bool                 // 是否消费组模式
 	consumer      sarama.Consumer      // kafka consumer 消费者
 	consumerGroup sarama.ConsumerGroup // kafka consumer 消费者组
-	message       chan *sarama.ConsumerMessage
+	message       chan *delivery
 	topics        sync.Map // 用于注册 topics监听的安全集合
-	pubHandler    sync.Map // 用于注册 topic_key 的消息处理函数
+	topicMu       sync.Mutex
+	subMu         sync.RWMutex
+	subs          map[string]map[*subscriber]struct{} // topic_key 对应的订阅者
+	legacy        sync.Map                            // 旧接口 topic_key 对应的订阅，重复订阅时替换
 	ctx           context.Context
 	cancel        context.CancelFunc
 }
@@ -52,22 +54,32 @@ func New(opts *pubsub.Options, k ...any) (*Instance, error) {
 	if ins.consumer == nil && ins.consumerGroup == nil {
 		return nil, fmt.Errorf("kafkaHander 参数错误：缺失消费客户端")
 	}
-	ins.message = make(chan *sarama.ConsumerMessage, 100) // 创建消息队列
+	ins.message = make(chan *delivery, 100) // 创建消息队列
+	ins.subs = make(map[string]map[*subscriber]struct{})
 	return &ins, nil
 }
 
 // Publish 发布消息
 func (this *Instance) Publish(param pubsub.Params, msg any) error {
-	// 这里生成消息
-	byt, err := tools.Any2bytes(msg)
+	m, err := pubsub.NewMessage(msg)
 	if err != nil {
 		return err
 	}
-	partition, offset, err := this.producer.SendMessage(&sarama.ProducerMessage{
-		Topic: param.Topic,
-		Key:   sarama.StringEncoder(param.Key),
-		Value: sarama.ByteEncoder(byt),
-	})
+	return this.PublishV2(this.opts.Ctx, param, m)
+}
+
+// PublishV2 发布消息，消息头使用kafka的消息头发送，Metadata.ID 不会发送
+func (this *Instance) PublishV2(_ context.Context, param pubsub.Params, msg *pubsub.Message) error {
+	pm := &sarama.ProducerMessage{
+		Topic:     param.Topic,
+		Key:       sarama.StringEncoder(param.Key),
+		Value:     sarama.ByteEncoder(msg.Payload),
+		Timestamp: msg.Metadata.Timestamp,
+	}
+	for k, v := range msg.Headers {
+		pm.Headers = append(pm.Headers, sarama.RecordHeader{Key: []byte(k), Value: []byte(v)})
+	}
+	partition, offset, err := this.producer.SendMessage(pm)
 	if err != nil {
 		return fmt.Errorf("kafkaHander 发布消息失败: %v", err)
 	}
@@ -78,20 +90,141 @@ func (this *Instance) Publish(param pubsub.Params, msg any) error {
 // Subscribe 订阅消息
 // 关于kafka订阅，如果topic一样，需要进行合并，通过key来判断是否是同一个消息
 // 如果topic不一样，就分开消费
+// 同一个 topic_key 重复订阅时，会替换之前的回调
+// 消费组模式下首次订阅 topic 时阻塞直到订阅结束，与之前的版本一致
 func (this *Instance) Subscribe(param pubsub.Params, cbs *pubsub.Cbfunc) {
-	this.pubHandler.Store(fmt.Sprintf("%s_%s", param.Topic, param.Key), cbs) // 注册消息处理函数
-	_, ok := this.topics.Load(param.Topic)
-	if ok {
-		// 如果topic已经监听，就不继续了
+	_, listened := this.topics.Load(param.Topic)
+	sub, err := this.SubscribeV2(this.opts.Ctx, param, cbs.Handler())
+	if err != nil {
+		this.error("订阅发布组件订阅失败", "kafka consumer", err)
 		return
 	}
-	this.topics.Store(param.Topic, true) // 初始化当前 topic
+	if old, ok := this.legacy.Swap(param.String(false), sub); ok {
+		old.(*pubsub.Subscription).Unsubscribe()
+	}
+	if this.isGroup && !listened {
+		<-sub.Done()
+	}
+}
 
-	go this.msgHander()
+// subscriber 订阅者，每个订阅者拥有独立的消息队列，回调重试不会阻塞其他订阅者
+type subscriber struct {
+	ctx   context.Context
+	sub   *pubsub.Subscription
+	h     pubsub.MessageHandler
+	opts  *pubsub.SubscribeOptions
+	queue chan *queued
+}
+
+// delivery 待分发的kafka消息
+// 消费组模式下 done 不为空，所有订阅者处理完成后写入结果，nil 表示全部确认。
+type delivery struct {
+	msg  *sarama.ConsumerMessage
+	done chan error
+}
+
+// queued 订阅者队列中的消息，ack 在回调结束后调用
+type queued struct {
+	msg *pubsub.Message
+	ack func(err error)
+}
+
+// newAck 创建 n 个订阅者共享的确认函数，最后一个订阅者确认后写入第一个错误
+func (d *delivery) newAck(n int) func(err error) {
+	if d.done == nil {
+		return func(error) {}
+	}
+	var (
+		mu    sync.Mutex
+		left  = n
+		first error
+	)
+	return func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil && first == nil {
+			first = err
+		}
+		left--
+		if left == 0 {
+			d.done <- first
+		}
+	}
+}
+
+// SubscribeV2 订阅消息
+// 普通消费者不提交偏移量，确认不做任何处理，回调最终失败的消息只会记录日志。
+// 消费组模式下所有订阅者处理完成后才提交偏移量，被拒绝的消息在 OnError 执行后同样提交偏移量，不会重新投递。
+func (this *Instance) SubscribeV2(ctx context.Context, param pubsub.Params, h pubsub.MessageHandler, opts ...pubsub.SubscribeOption) (*pubsub.Subscription, error) {
+	if err := this.listen(param); err != nil {
+		return nil, err
+	}
+	key := param.String(false)
+	s := &subscriber{
+		h:     h,
+		opts:  pubsub.NewSubscribeOptions(opts...),
+		queue: make(chan *queued, 100),
+	}
+	s.sub, s.ctx = pubsub.NewSubscription(ctx)
+	this.subMu.Lock()
+	if this.subs[key] == nil {
+		this.subs[key] = make(map[*subscriber]struct{})
+	}
+	this.subs[key][s] = struct{}{}
+	this.subMu.Unlock()
+	go this.run(key, s)
+	return s.sub, nil
+}
+
+// listen 开始监听 topic，每个 topic 只监听一次
+func (this *Instance) listen(param pubsub.Params) error {
+	this.topicMu.Lock()
+	defer this.topicMu.Unlock()
+	if _, ok := this.topics.Load(param.Topic); ok {
+		// 如果topic已经监听，就不继续了
+		return nil
+	}
 	if this.isGroup {
-		this.group(param)
-	} else {
-		this.single(param)
+		go this.group(param)
+	} else if err := this.single(param); err != nil {
+		return err
+	}
+	this.topics.Store(param.Topic, true) // 初始化当前 topic
+	go this.msgHander()
+	return nil
+}
+
+// run 订阅者消费队列
+func (this *Instance) run(key string, s *subscriber) {
+	defer func() {
+		this.subMu.Lock()
+		delete(this.subs[key], s)
+		if len(this.subs[key]) == 0 {
+			delete(this.subs, key)
+		}
+		this.subMu.Unlock()
+		for drained := false; !drained; { // 订阅已取消，队列中未处理的消息不再等待该订阅者
+			select {
+			case q := <-s.queue:
+				q.ack(nil)
+			default:
+				drained = true
+			}
+		}
+		s.sub.Finish(nil)
+	}()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case q := <-s.queue:
+			msg := q.msg
+			err := s.opts.Deliver(s.ctx, s.h, msg)
+			if err != nil {
+				this.error("订阅发布组件消息处理失败", "kafka载体", "topic", msg.Metadata.Topic, "key", msg.Metadata.Key, "offset", msg.Metadata.Offset, err)
+			}
+			q.ack(err)
+		}
 	}
 }
 
@@ -100,26 +233,60 @@ func (this *Instance) msgHander() {
 		select {
 		case <-this.opts.Ctx.Done():
 			return
-		case msg := <-this.message:
+		case d := <-this.message:
+			msg := d.msg
 			topic := msg.Topic
-			key := sarama.StringEncoder(msg.Key)
+			key := string(msg.Key)
 			this.log("订阅发布组件", "kafka载体", "topic", topic, "key", key, "offset", msg.Offset, msg.Timestamp)
-			_t, ok := this.pubHandler.Load(fmt.Sprintf("%s_%s", topic, key))
-			if !ok {
+			this.subMu.RLock()
+			subs := this.subs[fmt.Sprintf("%s_%s", topic, key)]
+			targets := make([]*subscriber, 0, len(subs))
+			for s := range subs {
+				targets = append(targets, s)
+			}
+			this.subMu.RUnlock()
+			if len(targets) == 0 {
+				d.newAck(1)(nil) // 没有订阅者，直接确认
 				continue
 			}
-			cbs := _t.(*pubsub.Cbfunc)
-			if cbs.CbString != nil {
-				cbs.CbString(string(msg.Value))
-			} else if cbs.CbByte != nil {
-				cbs.CbByte(msg.Value)
-			} else if cbs.CbAny != nil {
-				cbs.CbAny(msg.Value)
+			ack := d.newAck(len(targets))
+			for i, s := range targets {
+				m := newMessage(msg)
+				if i > 0 {
+					m = m.Clone()
+				}
+				select {
+				case s.queue <- &queued{msg: m, ack: ack}:
+				case <-s.ctx.Done():
+					ack(nil) // 订阅已取消，不再等待该订阅者
+				case <-this.opts.Ctx.Done():
+					return
+				}
 			}
 		}
 	}
 }
 
+// newMessage 转换kafka消息，ID 为 分区-偏移量
+func newMessage(msg *sarama.ConsumerMessage) *pubsub.Message {
+	m := &pubsub.Message{Payload: msg.Value}
+	for _, h := range msg.Headers {
+		if h != nil {
+			m.SetHeader(string(h.Key), string(h.Value))
+		}
+	}
+	m.Metadata = pubsub.Metadata{
+		Carrier:   pubsub.CarrierKafka,
+		Topic:     msg.Topic,
+		Key:       string(msg.Key),
+		ID:        fmt.Sprintf("%d-%d", msg.Partition, msg.Offset),
+		Timestamp: msg.Timestamp,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+	}
+	return m
+}
+
 func (this *Instance) log(title string, args ...any) {
 	if this.opts.Loger == nil {
 		ulogs.Log(append([]any{title}, args...)...)
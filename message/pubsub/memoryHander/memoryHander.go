@@ -0,0 +1,239 @@
+package memoryHander
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"helay.net/go/utils/v3/logger/ulogs"
+	"helay.net/go/utils/v3/logger/zaploger"
+	"helay.net/go/utils/v3/message/pubsub"
+	"helay.net/go/utils/v3/tools"
+)
+
+// 进程内订阅发布，不依赖任何消息中间件，适用于单元测试和单体部署。
+//
+// 主题由 Params.String(true) 生成，即 topic/key，按 / 分段匹配：
+//   - 订阅主题的每一段支持 path.Match 通配符，例如 * 匹配任意一段，user_* 匹配以 user_ 开头的段
+//   - # 匹配零个或多个段，例如 order/# 可以订阅 order、order/create、order/create/1
+//
+// 每个订阅者拥有独立的消息队列和goroutine，一条消息会分发给所有匹配的订阅者，
+// 同一个订阅者按发布顺序串行回调。队列满时 Publish 会阻塞，直到订阅者消费或 Options.Ctx 结束。
+
+const queueSize = 100 // 每个订阅者的消息队列长度
+
+type Instance struct {
+	opts *pubsub.Options
+	mu   sync.RWMutex
+	subs map[*subscriber]struct{}
+}
+
+// subscriber 订阅者
+type subscriber struct {
+	pattern []string
+	msg     chan delivery
+	done    chan struct{} // 订阅结束后关闭，避免发布方阻塞在已退出的订阅者上
+}
+
+// delivery 投递的消息，旧接口发布时只有 raw，新接口发布时只有 msg，由订阅方按需转换
+type delivery struct {
+	raw any
+	msg *pubsub.Message
+}
+
+// New 创建进程内订阅发布实例
+func New(opts *pubsub.Options) *Instance {
+	if opts.Ctx == nil {
+		opts.Ctx = context.Background()
+	}
+	return &Instance{
+		opts: opts,
+		subs: make(map[*subscriber]struct{}),
+	}
+}
+
+// Subscribe 订阅消息，阻塞直到 Options.Ctx 结束
+// 通过 Publish 发布时 CbAny 收到的是原始值，通过 PublishV2 发布时为 Payload
+func (this *Instance) Subscribe(param pubsub.Params, cbs *pubsub.Cbfunc) {
+	topic := param.String(true)
+	sub := this.register(topic)
+	defer this.unregister(sub)
+	this.debug("memoryHander 开始订阅", "主题", topic)
+	for {
+		select {
+		case <-this.opts.Ctx.Done():
+			this.debug("memoryHander 退出订阅", "主题", topic)
+			return
+		case d := <-sub.msg:
+			if d.msg != nil {
+				d.raw = d.msg.Payload
+			}
+			this.dispatch(topic, cbs, d.raw)
+		}
+	}
+}
+
+// Publish 发布消息，发布主题不能包含通配符
+func (this *Instance) Publish(param pubsub.Params, msg any) error {
+	return this.publish(this.opts.Ctx, param, delivery{raw: msg})
+}
+
+// SubscribeV2 订阅消息
+// 进程内没有重新投递，回调最终失败的消息只会记录日志。
+func (this *Instance) SubscribeV2(ctx context.Context, param pubsub.Params, h pubsub.MessageHandler, opts ...pubsub.SubscribeOption) (*pubsub.Subscription, error) {
+	topic := param.String(true)
+	o := pubsub.NewSubscribeOptions(opts...)
+	sub := this.register(topic)
+	handle, subCtx := pubsub.NewSubscription(ctx)
+	go func() {
+		defer func() {
+			this.unregister(sub)
+			handle.Finish(nil)
+		}()
+		for {
+			select {
+			case <-subCtx.Done():
+				return
+			case d := <-sub.msg:
+				msg := d.msg
+				if msg == nil {
+					var err error
+					if msg, err = pubsub.NewMessage(d.raw); err != nil {
+						this.error("memoryHander 消息转换失败", "主题", topic, "错误信息", err)
+						continue
+					}
+				}
+				if err := o.Deliver(subCtx, h, msg); err != nil {
+					this.error("memoryHander 消息处理失败", "主题", topic, "错误信息", err)
+				}
+			}
+		}
+	}()
+	return handle, nil
+}
+
+// PublishV2 发布消息，每个订阅者收到的是消息的副本
+func (this *Instance) PublishV2(ctx context.Context, param pubsub.Params, msg *pubsub.Message) error {
+	msg = msg.Clone()
+	msg.Metadata.Carrier = pubsub.CarrierMemory
+	msg.Metadata.Topic = param.Topic
+	msg.Metadata.Key = param.Key
+	if msg.Metadata.Timestamp.IsZero() {
+		msg.Metadata.Timestamp = time.Now()
+	}
+	return this.publish(ctx, param, delivery{msg: msg})
+}
+
+func (this *Instance) register(topic string) *subscriber {
+	sub := &subscriber{
+		pattern: strings.Split(topic, "/"),
+		msg:     make(chan delivery, queueSize),
+		done:    make(chan struct{}),
+	}
+	this.mu.Lock()
+	this.subs[sub] = struct{}{}
+	this.mu.Unlock()
+	return sub
+}
+
+func (this *Instance) unregister(sub *subscriber) {
+	this.mu.Lock()
+	delete(this.subs, sub)
+	this.mu.Unlock()
+	close(sub.done)
+}
+
+func (this *Instance) publish(ctx context.Context, param pubsub.Params, d delivery) error {
+	topic := param.String(true)
+	if strings.ContainsAny(topic, "*?[#") {
+		return fmt.Errorf("memoryHander 发布消息失败: 主题 %s 不能包含通配符", topic)
+	}
+	segments := strings.Split(topic, "/")
+	this.mu.RLock()
+	targets := make([]*subscriber, 0, len(this.subs))
+	for sub := range this.subs {
+		if match(sub.pattern, segments) {
+			targets = append(targets, sub)
+		}
+	}
+	this.mu.RUnlock()
+	for i, sub := range targets {
+		_d := d
+		if d.msg != nil && i < len(targets)-1 {
+			_d.msg = d.msg.Clone() // 最后一个订阅者使用原消息，其余使用副本
+		}
+		select {
+		case sub.msg <- _d:
+		case <-sub.done:
+		case <-ctx.Done():
+			return fmt.Errorf("memoryHander 发布消息失败: %w", ctx.Err())
+		}
+	}
+	return nil
+}
+
+// dispatch 执行回调，回调中的panic不会导致订阅退出
+func (this *Instance) dispatch(topic string, cbs *pubsub.Cbfunc, msg any) {
+	defer func() {
+		if r := recover(); r != nil {
+			this.error("memoryHander 回调执行异常", "主题", topic, "错误信息", r)
+		}
+	}()
+	if cbs.CbString != nil {
+		cbs.CbString(tools.Any2string(msg))
+	} else if cbs.CbByte != nil {
+		byt, err := tools.Any2bytes(msg)
+		if err != nil {
+			this.error("memoryHander 消息转换失败", "主题", topic, "错误信息", err)
+			return
+		}
+		if b, ok := msg.([]byte); ok {
+			byt = bytes.Clone(b) // 多个订阅者共享同一条消息，避免相互修改
+		}
+		cbs.CbByte(byt)
+	} else if cbs.CbAny != nil {
+		cbs.CbAny(msg)
+	}
+}
+
+// match 按段匹配订阅主题
+func match(pattern, segments []string) bool {
+	for i, p := range pattern {
+		if p == "#" {
+			rest := pattern[i+1:]
+			for j := i; j <= len(segments); j++ {
+				if match(rest, segments[j:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if i >= len(segments) {
+			return false
+		}
+		if ok, err := path.Match(p, segments[i]); err != nil || !ok {
+			return false
+		}
+	}
+	return len(pattern) == len(segments)
+}
+
+func (this *Instance) error(title string, args ...any) {
+	if this.opts.Loger == nil {
+		ulogs.Error(append([]any{title}, args...)...)
+	} else {
+		this.opts.Loger.Error(context.Background(), title, zaploger.Auto2Field(args...))
+	}
+}
+
+func (this *Instance) debug(title string, args ...any) {
+	if this.opts.Loger == nil {
+		ulogs.Debug(append([]any{title}, args...)...)
+	} else {
+		this.opts.Loger.Debug(context.Background(), title, zaploger.Auto2Field(args...))
+	}
+}
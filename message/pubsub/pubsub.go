@@ -13,6 +13,7 @@ const (
 	CarrierRabbitMQ = "rabbitmq" // rabbitmq 载体
 	CarrierRocketMQ = "rocketmq" // rocketmq 载体
 	CarrierEtcd     = "etcd"     // etcd 载体
+	CarrierMemory   = "memory"   // 进程内载体
 )
 
 const (
@@ -0,0 +1,247 @@
+package rabbitmqHander
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"helay.net/go/utils/v3/logger/ulogs"
+	"helay.net/go/utils/v3/logger/zaploger"
+	"helay.net/go/utils/v3/message/pubsub"
+	"helay.net/go/utils/v3/tools/backoff"
+)
+
+// 基于 AMQP 0-9-1 的订阅发布，适用于 RabbitMQ。
+//
+// Params.Topic 对应 topic 类型的交换机，Params.Key 对应路由键。
+// 订阅时 Key 支持 AMQP 通配符：* 匹配一个单词，# 匹配零个或多个单词，Key 为空时订阅交换机下的全部消息。
+// 每次订阅都会创建一个独占、自动删除的队列绑定到交换机，与 redis 一样是广播语义。
+
+const (
+	exchangeKind      = "topic"
+	headerContentType = "Content-Type" // 对应amqp消息的 ContentType
+)
+
+type Instance struct {
+	conn *amqp.Connection
+	opts *pubsub.Options
+
+	pubMu     sync.Mutex
+	pubCh     *amqp.Channel // 发布使用的channel，关闭后会重新创建
+	exchanges sync.Map      // 已经声明过的交换机
+}
+
+// New 创建rabbitmq实例
+func New(conn *amqp.Connection, opts *pubsub.Options) *Instance {
+	if opts.Ctx == nil {
+		opts.Ctx = context.Background()
+	}
+	return &Instance{
+		conn: conn,
+		opts: opts,
+	}
+}
+
+// Subscribe 订阅消息，阻塞直到 Options.Ctx 结束
+func (this *Instance) Subscribe(param pubsub.Params, cbs *pubsub.Cbfunc) {
+	pubsub.Adapt(this.opts.Ctx, this).Subscribe(param, cbs)
+}
+
+// Publish 发布消息
+func (this *Instance) Publish(param pubsub.Params, msg any) error {
+	m, err := pubsub.NewMessage(msg)
+	if err != nil {
+		return err
+	}
+	if _, ok := msg.(string); ok {
+		m.SetHeader(headerContentType, "text/plain")
+	}
+	return this.PublishV2(this.opts.Ctx, param, m)
+}
+
+// SubscribeV2 订阅消息
+// 回调成功时确认消息，最终失败时拒绝消息且不重新入队，交换机配置了死信时会转入死信。
+// channel 异常关闭后会按指数退避重新订阅，直到 ctx 结束或取消订阅。
+func (this *Instance) SubscribeV2(ctx context.Context, param pubsub.Params, h pubsub.MessageHandler, opts ...pubsub.SubscribeOption) (*pubsub.Subscription, error) {
+	key := param.Key
+	if key == "" {
+		key = "#"
+	}
+	o := pubsub.NewSubscribeOptions(opts...)
+	sub, subCtx := pubsub.NewSubscription(ctx)
+	ch, deliveries, err := this.setup(subCtx, param.Topic, key)
+	if err != nil {
+		sub.Finish(err)
+		return nil, fmt.Errorf("rabbitmqHander 订阅消息失败: %w", err)
+	}
+	go func() {
+		defer sub.Finish(nil)
+		b := backoff.NewBackoff(backoff.Exponential, 100*time.Millisecond, 10*time.Second, 2.0)
+		for {
+			err := this.consume(subCtx, ch, deliveries, param, h, o)
+			_ = ch.Close()
+			if subCtx.Err() != nil {
+				this.debug("rabbitmqHander 退出订阅", "交换机", param.Topic, "路由键", key)
+				return
+			}
+			this.error("rabbitmqHander 订阅消息失败", "交换机", param.Topic, "路由键", key, "错误信息", err)
+			for {
+				select {
+				case <-time.After(b.Next()):
+				case <-subCtx.Done():
+					return
+				}
+				if ch, deliveries, err = this.setup(subCtx, param.Topic, key); err == nil {
+					b.Reset()
+					break
+				}
+				this.error("rabbitmqHander 重新订阅失败", "交换机", param.Topic, "路由键", key, "错误信息", err)
+			}
+		}
+	}()
+	return sub, nil
+}
+
+// setup 声明交换机和队列，并开始消费
+func (this *Instance) setup(ctx context.Context, exchange, key string) (*amqp.Channel, <-chan amqp.Delivery, error) {
+	ch, err := this.conn.Channel()
+	if err != nil {
+		return nil, nil, fmt.Errorf("创建channel失败 %w", err)
+	}
+	deliveries, err := func() (<-chan amqp.Delivery, error) {
+		if err := ch.ExchangeDeclare(exchange, exchangeKind, true, false, false, false, nil); err != nil {
+			return nil, fmt.Errorf("声明交换机失败 %w", err)
+		}
+		q, err := ch.QueueDeclare("", false, true, true, false, nil)
+		if err != nil {
+			return nil, fmt.Errorf("声明队列失败 %w", err)
+		}
+		if err = ch.QueueBind(q.Name, key, exchange, false, nil); err != nil {
+			return nil, fmt.Errorf("绑定队列失败 %w", err)
+		}
+		deliveries, err := ch.ConsumeWithContext(ctx, q.Name, "", false, true, false, false, nil)
+		if err != nil {
+			return nil, fmt.Errorf("消费队列失败 %w", err)
+		}
+		this.debug("rabbitmqHander 开始订阅", "交换机", exchange, "路由键", key, "队列", q.Name)
+		return deliveries, nil
+	}()
+	if err != nil {
+		_ = ch.Close()
+		return nil, nil, err
+	}
+	return ch, deliveries, nil
+}
+
+// consume 消费消息，直到channel关闭或 ctx 结束
+func (this *Instance) consume(ctx context.Context, ch *amqp.Channel, deliveries <-chan amqp.Delivery, param pubsub.Params, h pubsub.MessageHandler, o *pubsub.SubscribeOptions) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case d, ok := <-deliveries:
+			if !ok {
+				return fmt.Errorf("channel已关闭")
+			}
+			msg := newMessage(param.Topic, d)
+			if err := o.Deliver(ctx, h, msg); err != nil {
+				this.error("rabbitmqHander 消息处理失败", "交换机", param.Topic, "路由键", d.RoutingKey, "错误信息", err)
+				_ = d.Nack(false, false)
+				continue
+			}
+			_ = d.Ack(false)
+		}
+	}
+}
+
+// newMessage 转换amqp消息，字符串类型的消息头会放入 Headers，Content-Type 使用 headerContentType
+func newMessage(exchange string, d amqp.Delivery) *pubsub.Message {
+	msg := &pubsub.Message{Payload: d.Body}
+	for k, v := range d.Headers {
+		if str, ok := v.(string); ok {
+			msg.SetHeader(k, str)
+		}
+	}
+	if d.ContentType != "" {
+		msg.SetHeader(headerContentType, d.ContentType)
+	}
+	msg.Metadata = pubsub.Metadata{
+		Carrier:   pubsub.CarrierRabbitMQ,
+		Topic:     exchange,
+		Key:       d.RoutingKey,
+		ID:        d.MessageId,
+		Timestamp: d.Timestamp,
+	}
+	return msg
+}
+
+// PublishV2 发布消息
+// 消息头放入amqp消息头，Headers 中的 headerContentType 作为 ContentType 发送。
+func (this *Instance) PublishV2(ctx context.Context, param pubsub.Params, msg *pubsub.Message) error {
+	p := amqp.Publishing{
+		ContentType: "application/octet-stream",
+		MessageId:   msg.Metadata.ID,
+		Timestamp:   msg.Metadata.Timestamp,
+		Body:        msg.Payload,
+	}
+	if p.Timestamp.IsZero() {
+		p.Timestamp = time.Now()
+	}
+	for k, v := range msg.Headers {
+		if k == headerContentType {
+			p.ContentType = v
+			continue
+		}
+		if p.Headers == nil {
+			p.Headers = amqp.Table{}
+		}
+		p.Headers[k] = v
+	}
+	this.pubMu.Lock()
+	defer this.pubMu.Unlock()
+	ch, err := this.publishChannel(param.Topic)
+	if err != nil {
+		return fmt.Errorf("rabbitmqHander 发布消息失败: %w", err)
+	}
+	if err = ch.PublishWithContext(ctx, param.Topic, param.Key, false, false, p); err != nil {
+		return fmt.Errorf("rabbitmqHander 发布消息失败: %w", err)
+	}
+	this.debug("rabbitmqHander 发布消息成功", "交换机", param.Topic, "路由键", param.Key)
+	return nil
+}
+
+// publishChannel 获取发布channel，并确保交换机已声明，需在 pubMu 内调用
+func (this *Instance) publishChannel(exchange string) (*amqp.Channel, error) {
+	if this.pubCh == nil || this.pubCh.IsClosed() {
+		ch, err := this.conn.Channel()
+		if err != nil {
+			return nil, fmt.Errorf("创建channel失败 %w", err)
+		}
+		this.pubCh = ch
+	}
+	if _, ok := this.exchanges.Load(exchange); !ok {
+		if err := this.pubCh.ExchangeDeclare(exchange, exchangeKind, true, false, false, false, nil); err != nil {
+			return nil, fmt.Errorf("声明交换机失败 %w", err)
+		}
+		this.exchanges.Store(exchange, true)
+	}
+	return this.pubCh, nil
+}
+
+func (this *Instance) error(title string, args ...any) {
+	if this.opts.Loger == nil {
+		ulogs.Error(append([]any{title}, args...)...)
+	} else {
+		this.opts.Loger.Error(context.Background(), title, zaploger.Auto2Field(args...))
+	}
+}
+
+func (this *Instance) debug(title string, args ...any) {
+	if this.opts.Loger == nil {
+		ulogs.Debug(append([]any{title}, args...)...)
+	} else {
+		this.opts.Loger.Debug(context.Background(), title, zaploger.Auto2Field(args...))
+	}
+}
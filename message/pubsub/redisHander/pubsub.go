@@ -2,6 +2,9 @@ package redisHander
 
 import (
 	"context"
+	"fmt"
+	"time"
+
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 	"helay.net/go/utils/v3/logger/ulogs"
@@ -24,31 +27,7 @@ func New(rdb *redis.UniversalClient, opts *pubsub.Options) *Instance {
 
 // Subscribe 订阅消息
 func (this *Instance) Subscribe(param pubsub.Params, cbs *pubsub.Cbfunc) {
-	topic := param.String(false)
-	subIde := this.rdb.Subscribe(this.opts.Ctx, topic)
-	for {
-		select {
-		case <-this.opts.Ctx.Done():
-			return
-		default:
-			msg, err := subIde.ReceiveMessage(this.opts.Ctx)
-			if err != nil {
-				if this.opts.Loger != nil {
-					this.opts.Loger.Error(context.Background(), "redis订阅消息失败", zap.String("topic", topic), zap.String("错误信息", err.Error()))
-				} else {
-					ulogs.Error("redis订阅消息失败", topic, err)
-				}
-				continue
-			}
-			if cbs.CbString != nil {
-				cbs.CbString(msg.Payload)
-			} else if cbs.CbByte != nil {
-				cbs.CbByte([]byte(msg.Payload))
-			} else if cbs.CbAny != nil {
-				cbs.CbAny(msg.Payload)
-			}
-		}
-	}
+	pubsub.Adapt(this.opts.Ctx, this).Subscribe(param, cbs)
 }
 
 // Publish 发布消息
@@ -57,3 +36,63 @@ func (this *Instance) Publish(param pubsub.Params, msg any) error {
 
 	return this.rdb.Publish(this.opts.Ctx, topic, msg).Err()
 }
+
+// SubscribeV2 订阅消息
+// redis 的订阅发布没有确认机制，回调最终失败的消息只会记录日志。
+func (this *Instance) SubscribeV2(ctx context.Context, param pubsub.Params, h pubsub.MessageHandler, opts ...pubsub.SubscribeOption) (*pubsub.Subscription, error) {
+	topic := param.String(false)
+	ps := this.rdb.Subscribe(ctx, topic)
+	if _, err := ps.Receive(ctx); err != nil {
+		_ = ps.Close()
+		return nil, fmt.Errorf("redis订阅消息失败 %w", err)
+	}
+	o := pubsub.NewSubscribeOptions(opts...)
+	sub, subCtx := pubsub.NewSubscription(ctx)
+	go func() {
+		var err error
+		defer func() {
+			_ = ps.Close()
+			sub.Finish(err)
+		}()
+		ch := ps.Channel()
+		for {
+			select {
+			case <-subCtx.Done():
+				return
+			case m, ok := <-ch:
+				if !ok {
+					err = fmt.Errorf("redis订阅通道已关闭")
+					return
+				}
+				msg := pubsub.DecodeEnvelope([]byte(m.Payload))
+				msg.Metadata.Carrier = pubsub.CarrierRedis
+				msg.Metadata.Topic = param.Topic
+				msg.Metadata.Key = param.Key
+				if msg.Metadata.Timestamp.IsZero() {
+					msg.Metadata.Timestamp = time.Now()
+				}
+				if _err := o.Deliver(subCtx, h, msg); _err != nil {
+					this.error("redis订阅消息处理失败", topic, _err)
+				}
+			}
+		}
+	}()
+	return sub, nil
+}
+
+// PublishV2 发布消息，消息头和元数据通过信封格式发送
+func (this *Instance) PublishV2(ctx context.Context, param pubsub.Params, msg *pubsub.Message) error {
+	payload, err := pubsub.EncodeEnvelope(msg)
+	if err != nil {
+		return fmt.Errorf("redis发布消息失败 %w", err)
+	}
+	return this.rdb.Publish(ctx, param.String(false), payload).Err()
+}
+
+func (this *Instance) error(title, topic string, err error) {
+	if this.opts.Loger != nil {
+		this.opts.Loger.Error(context.Background(), title, zap.String("topic", topic), zap.String("错误信息", err.Error()))
+	} else {
+		ulogs.Error(title, topic, err)
+	}
+}
@@ -0,0 +1,281 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"helay.net/go/utils/v3/logger/ulogs"
+	"helay.net/go/utils/v3/tools"
+	"helay.net/go/utils/v3/tools/backoff"
+)
+
+// HandlerV2 订阅发布组件
+// 与 Handler 相比，订阅返回可取消的句柄，回调通过返回错误驱动确认、重试和拒绝，消息支持消息头和元数据。
+type HandlerV2 interface {
+	SubscribeV2(ctx context.Context, param Params, h MessageHandler, opts ...SubscribeOption) (*Subscription, error) // 订阅消息，不阻塞
+	PublishV2(ctx context.Context, param Params, msg *Message) error                                                 // 发布消息
+}
+
+// MessageHandler 消息回调
+// 返回 nil 表示处理成功，消息会被确认；返回错误时按 SubscribeOptions 重试，
+// 重试耗尽或返回 Permanent 包装的错误后，消息会被拒绝，具体行为由载体决定。
+type MessageHandler func(ctx context.Context, msg *Message) error
+
+// Message 消息
+type Message struct {
+	Payload  []byte            // 消息内容
+	Headers  map[string]string // 消息头，随消息一起发布
+	Metadata Metadata          // 元数据，订阅时由载体填充
+}
+
+// Metadata 消息元数据
+// 发布时只有 ID 和 Timestamp 会随消息发送，其余字段在订阅时由载体填充，载体不支持的字段为零值。
+type Metadata struct {
+	Carrier   string    // 载体
+	Topic     string    // 主题
+	Key       string    // key
+	ID        string    // 消息ID
+	Timestamp time.Time // 发布时间
+	Partition int32     // kafka 分区
+	Offset    int64     // kafka 偏移量，etcd 为修订版本
+	Attempt   int       // 当前投递次数，从1开始
+}
+
+// NewMessage 创建消息，payload 使用 tools.Any2bytes 转换
+func NewMessage(payload any) (*Message, error) {
+	byt, err := tools.Any2bytes(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &Message{Payload: byt}, nil
+}
+
+// SetHeader 设置消息头
+func (m *Message) SetHeader(key, value string) *Message {
+	if m.Headers == nil {
+		m.Headers = make(map[string]string)
+	}
+	m.Headers[key] = value
+	return m
+}
+
+// Header 获取消息头
+func (m *Message) Header(key string) string {
+	return m.Headers[key]
+}
+
+// Clone 复制消息，多个订阅者共享消息时使用
+func (m *Message) Clone() *Message {
+	c := *m
+	c.Payload = append([]byte(nil), m.Payload...)
+	c.Headers = maps.Clone(m.Headers)
+	return &c
+}
+
+// SubscribeOptions 订阅配置
+type SubscribeOptions struct {
+	MaxAttempts int                           // 回调失败时的最大尝试次数，默认为1，即不重试
+	MinBackoff  time.Duration                 // 重试的初始等待时间，默认100ms
+	MaxBackoff  time.Duration                 // 重试的最大等待时间，默认10s
+	OnError     func(msg *Message, err error) // 消息最终处理失败时的回调，可用于记录或转入死信
+}
+
+type SubscribeOption func(*SubscribeOptions)
+
+// WithRetry 设置回调失败时的重试次数和等待时间，等待时间按指数递增
+func WithRetry(maxAttempts int, minBackoff, maxBackoff time.Duration) SubscribeOption {
+	return func(o *SubscribeOptions) {
+		o.MaxAttempts = maxAttempts
+		o.MinBackoff = minBackoff
+		o.MaxBackoff = maxBackoff
+	}
+}
+
+// WithErrorHandler 设置消息最终处理失败时的回调
+func WithErrorHandler(fn func(msg *Message, err error)) SubscribeOption {
+	return func(o *SubscribeOptions) {
+		o.OnError = fn
+	}
+}
+
+// NewSubscribeOptions 合并订阅配置，供载体实现使用
+func NewSubscribeOptions(opts ...SubscribeOption) *SubscribeOptions {
+	o := &SubscribeOptions{
+		MaxAttempts: 1,
+		MinBackoff:  100 * time.Millisecond,
+		MaxBackoff:  10 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	o.MaxAttempts = max(o.MaxAttempts, 1)
+	return o
+}
+
+// Deliver 执行回调，失败时按配置重试，返回最后一次的错误，供载体实现使用
+// 回调中的panic会转换为错误，ctx 结束时停止重试。
+func (o *SubscribeOptions) Deliver(ctx context.Context, h MessageHandler, msg *Message) error {
+	var b *backoff.Backoff
+	for attempt := 1; ; attempt++ {
+		msg.Metadata.Attempt = attempt
+		err := call(ctx, h, msg)
+		if err == nil {
+			return nil
+		}
+		if attempt >= o.MaxAttempts || IsPermanent(err) || ctx.Err() != nil {
+			if o.OnError != nil {
+				o.OnError(msg, err)
+			}
+			return err
+		}
+		if b == nil {
+			b = backoff.NewBackoff(backoff.Exponential, o.MinBackoff, o.MaxBackoff, 2.0)
+		}
+		select {
+		case <-time.After(b.Next()):
+		case <-ctx.Done():
+			if o.OnError != nil {
+				o.OnError(msg, err)
+			}
+			return err
+		}
+	}
+}
+
+func call(ctx context.Context, h MessageHandler, msg *Message) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = Permanent(fmt.Errorf("回调执行异常 %v", r))
+		}
+	}()
+	return h(ctx, msg)
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent 包装不需要重试的错误，消息会直接被拒绝
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent 判断是否为不需要重试的错误
+func IsPermanent(err error) bool {
+	var pe *permanentError
+	return errors.As(err, &pe)
+}
+
+// Subscription 订阅句柄
+type Subscription struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+	err    error
+}
+
+// NewSubscription 创建订阅句柄，返回的 ctx 在取消订阅或父 ctx 结束时取消，供载体实现使用
+// 载体在订阅goroutine退出时必须调用 Finish。
+func NewSubscription(ctx context.Context) (*Subscription, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Subscription{cancel: cancel, done: make(chan struct{})}, ctx
+}
+
+// Finish 标记订阅结束，err 为异常退出的原因，正常取消时传 nil，供载体实现使用
+func (s *Subscription) Finish(err error) {
+	s.once.Do(func() {
+		s.err = err
+		s.cancel()
+		close(s.done)
+	})
+}
+
+// Unsubscribe 取消订阅，阻塞直到订阅goroutine退出
+func (s *Subscription) Unsubscribe() {
+	s.cancel()
+	<-s.done
+}
+
+// Done 订阅结束后关闭
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err 订阅异常退出的原因，订阅未结束或正常取消时返回 nil
+func (s *Subscription) Err() error {
+	select {
+	case <-s.done:
+		return s.err
+	default:
+		return nil
+	}
+}
+
+// Handler 将旧的回调函数转换为 MessageHandler，回调始终视为处理成功
+// CbAny 收到的类型与旧版载体一致，redis 为 string，其余为 []byte。
+func (this *Cbfunc) Handler() MessageHandler {
+	return func(_ context.Context, msg *Message) error {
+		if this.CbString != nil {
+			this.CbString(string(msg.Payload))
+		} else if this.CbByte != nil {
+			this.CbByte(msg.Payload)
+		} else if this.CbAny != nil {
+			if msg.Metadata.Carrier == CarrierRedis {
+				this.CbAny(string(msg.Payload)) // 旧版 redis 载体传递的是字符串
+			} else {
+				this.CbAny(msg.Payload)
+			}
+		}
+		return nil
+	}
+}
+
+// Adapt 将 HandlerV2 适配为 Handler，供只支持旧接口的调用方使用
+// Subscribe 阻塞直到 ctx 结束。
+func Adapt(ctx context.Context, h HandlerV2) Handler {
+	return &adapter{ctx: ctx, h: h}
+}
+
+type adapter struct {
+	ctx context.Context
+	h   HandlerV2
+}
+
+// Subscribe 订阅失败或异常退出时按指数退避重新订阅
+func (a *adapter) Subscribe(param Params, cbs *Cbfunc) {
+	b := backoff.NewBackoff(backoff.Exponential, 100*time.Millisecond, 10*time.Second, 2.0)
+	for {
+		sub, err := a.h.SubscribeV2(a.ctx, param, cbs.Handler())
+		if err == nil {
+			<-sub.Done()
+			err = sub.Err()
+		}
+		if err == nil || a.ctx.Err() != nil {
+			return
+		}
+		ulogs.Error("订阅发布组件订阅失败", param.String(false), err)
+		select {
+		case <-time.After(b.Next()):
+		case <-a.ctx.Done():
+			return
+		}
+	}
+}
+
+func (a *adapter) Publish(param Params, msg any) error {
+	m, err := NewMessage(msg)
+	if err != nil {
+		return err
+	}
+	return a.h.PublishV2(a.ctx, param, m)
+}
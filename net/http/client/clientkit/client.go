@@ -0,0 +1,94 @@
+// Package clientkit 链式调用的 http 客户端
+// 负责组装请求、编码请求体、解码响应和统一的错误处理，重试、熔断等传输层能力由传入的 http.Client 提供，
+// 通常配合 transportkit.NewClient 使用。
+//
+//	c := clientkit.New(hc, clientkit.Options{BaseURL: "https://api.example.com"})
+//	user, err := clientkit.Do[User](c.Get("/users/1").Query(q))
+package clientkit
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Options 客户端配置
+type Options struct {
+	BaseURL      string            `json:"base_url" yaml:"base_url" ini:"base_url"`                // 相对路径请求的前缀
+	Header       map[string]string `json:"header" yaml:"header" ini:"header"`                      // 每个请求都携带的请求头
+	UserAgent    string            `json:"user_agent" yaml:"user_agent" ini:"user_agent"`          // 默认使用 go 标准库的 User-Agent
+	MaxBodySize  int64             `json:"max_body_size" yaml:"max_body_size" ini:"max_body_size"` // 读取响应体的最大字节数，默认10M，超过时返回 ErrBodyTooLarge
+	ErrorSnippet int               `json:"error_snippet" yaml:"error_snippet" ini:"error_snippet"` // StatusError 中保留的响应体字节数，默认512
+}
+
+// Client 链式调用的 http 客户端，创建后可以并发使用
+type Client struct {
+	hc          *http.Client
+	opt         Options
+	middlewares []Middleware
+}
+
+// New 创建客户端，hc 为空时使用 http.DefaultClient
+func New(hc *http.Client, opt Options) *Client {
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	if opt.MaxBodySize <= 0 {
+		opt.MaxBodySize = 10 << 20
+	}
+	if opt.ErrorSnippet <= 0 {
+		opt.ErrorSnippet = 512
+	}
+	opt.BaseURL = strings.TrimRight(opt.BaseURL, "/")
+	return &Client{hc: hc, opt: opt}
+}
+
+// Use 添加中间件，先添加的在外层，需要在发出请求前设置
+func (c *Client) Use(mw ...Middleware) *Client {
+	c.middlewares = append(c.middlewares, mw...)
+	return c
+}
+
+// NewRequest 创建请求，path 为完整 url 时忽略 BaseURL
+func (c *Client) NewRequest(method, path string) *Request {
+	r := &Request{
+		client:  c,
+		ctx:     context.Background(),
+		method:  method,
+		url:     c.resolve(path),
+		query:   make(map[string][]string),
+		header:  make(http.Header),
+		maxBody: c.opt.MaxBodySize,
+	}
+	for k, v := range c.opt.Header {
+		r.header.Set(k, v)
+	}
+	if c.opt.UserAgent != "" {
+		r.header.Set("User-Agent", c.opt.UserAgent)
+	}
+	return r
+}
+
+func (c *Client) Get(path string) *Request    { return c.NewRequest(http.MethodGet, path) }
+func (c *Client) Head(path string) *Request   { return c.NewRequest(http.MethodHead, path) }
+func (c *Client) Post(path string) *Request   { return c.NewRequest(http.MethodPost, path) }
+func (c *Client) Put(path string) *Request    { return c.NewRequest(http.MethodPut, path) }
+func (c *Client) Patch(path string) *Request  { return c.NewRequest(http.MethodPatch, path) }
+func (c *Client) Delete(path string) *Request { return c.NewRequest(http.MethodDelete, path) }
+
+// resolve 拼接 BaseURL 和相对路径
+func (c *Client) resolve(path string) string {
+	if c.opt.BaseURL == "" || strings.Contains(path, "://") {
+		return path
+	}
+	return c.opt.BaseURL + "/" + strings.TrimLeft(path, "/")
+}
+
+// handler 按中间件包装最终的请求处理
+func (c *Client) handler() Handler {
+	h := Handler(c.hc.Do)
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		h = c.middlewares[i](h)
+	}
+	return h
+}
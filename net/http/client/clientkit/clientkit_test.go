@@ -0,0 +1,207 @@
+package clientkit
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, h http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	return New(srv.Client(), Options{BaseURL: srv.URL + "/api/", ErrorSnippet: 8})
+}
+
+func TestQueryAndForm(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		_, _ = io.WriteString(w, r.URL.Path+"?"+r.URL.RawQuery+"|"+r.Header.Get("Content-Type")+"|"+r.PostForm.Encode())
+	})
+	type query struct {
+		Name string `query:"name"`
+		Page int    `query:"page,omitempty"`
+	}
+	got, err := Do[string](c.Get("/users?sort=id").Query(query{Name: "a b"}).Param("tag", "x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "/api/users?name=a+b&sort=id&tag=x||" {
+		t.Fatalf("query = %q", got)
+	}
+
+	type form struct {
+		Name string `form:"name"`
+		Age  int    `form:"age"`
+	}
+	got, err = Do[string](c.Post("users").Form(form{Name: "张三", Age: 20}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "/api/users?|application/x-www-form-urlencoded|age=20&name=%E5%BC%A0%E4%B8%89" {
+		t.Fatalf("form = %q", got)
+	}
+}
+
+func TestJSONDecode(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	})
+	type user struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	got, err := Do[user](c.Put("/users/1").JSON(user{ID: 1, Name: "a"}))
+	if err != nil || got != (user{ID: 1, Name: "a"}) {
+		t.Fatalf("Do = %+v, %v", got, err)
+	}
+}
+
+func TestMultipart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "b.txt")
+	if err := os.WriteFile(path, []byte("file b"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var retry bool
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var parts []string
+		parts = append(parts, r.FormValue("name"))
+		for _, field := range []string{"a", "b"} {
+			f, h, err := r.FormFile(field)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			b, _ := io.ReadAll(f)
+			_ = f.Close()
+			parts = append(parts, h.Filename+"="+string(b))
+		}
+		_, _ = io.WriteString(w, strings.Join(parts, ","))
+	})
+	c.Use(func(next Handler) Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			retry = req.GetBody != nil
+			return next(req)
+		}
+	})
+
+	got, err := Do[string](c.Post("/upload").FormField("name", "x").File("a", "a.txt", strings.NewReader("file a")).FilePath("b", path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "x,a.txt=file a,b.txt=file b" || retry {
+		t.Fatalf("multipart = %q, retry = %v", got, retry)
+	}
+	// 全部文件来自路径时可以重试
+	if _, err = Do[string](c.Post("/upload").FilePath("a", path).FilePath("b", path)); err != nil || !retry {
+		t.Fatalf("retry = %v, err = %v", retry, err)
+	}
+}
+
+func TestMultipartMiddlewareError(t *testing.T) {
+	c := New(nil, Options{})
+	errSign := errors.New("sign failed")
+	c.Use(func(next Handler) Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			return nil, errSign
+		}
+	})
+	before := runtime.NumGoroutine()
+	for range 10 {
+		_, err := c.Post("http://127.0.0.1:1/upload").File("a", "a.txt", strings.NewReader("a")).Send()
+		if !errors.Is(err, errSign) {
+			t.Fatalf("err = %v", err)
+		}
+	}
+	// 请求没有发出，写入请求体的协程不能一直阻塞
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("goroutines = %d, want %d", runtime.NumGoroutine(), before)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCharset(t *testing.T) {
+	gbk := []byte{0xD6, 0xD0, 0xCE, 0xC4} // “中文”
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/header":
+			w.Header().Set("Content-Type", "text/plain; charset=GBK")
+			_, _ = w.Write(gbk)
+		case "/api/meta":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write(append([]byte(`<html><head><meta charset="gbk"></head><body>`), gbk...))
+		case "/api/binary":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			_, _ = w.Write(gbk)
+		}
+	})
+	if got, err := Do[string](c.Get("/header")); err != nil || got != "中文" {
+		t.Fatalf("header charset = %q, %v", got, err)
+	}
+	if got, err := Do[string](c.Get("/meta")); err != nil || !strings.HasSuffix(got, "中文") {
+		t.Fatalf("meta charset = %q, %v", got, err)
+	}
+	if got, err := Do[[]byte](c.Get("/binary")); err != nil || string(got) != string(gbk) {
+		t.Fatalf("binary = %x, %v", got, err)
+	}
+}
+
+func TestMaxBodySize(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("chunked") != "" {
+			w.(http.Flusher).Flush() // 不设置 Content-Length
+		}
+		_, _ = io.WriteString(w, "0123456789")
+	})
+	for _, chunked := range []string{"", "1"} {
+		_, err := c.Get("/big").Param("chunked", chunked).MaxBodySize(4).Send()
+		if !errors.Is(err, ErrBodyTooLarge) {
+			t.Fatalf("chunked=%q err = %v", chunked, err)
+		}
+	}
+	if got, err := Do[string](c.Get("/big").MaxBodySize(10)); err != nil || got != "0123456789" {
+		t.Fatalf("Do = %q, %v", got, err)
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, "user not found")
+	})
+	resp, err := c.Get("/users/1").Send()
+	if !IsStatus(err, http.StatusNotFound) {
+		t.Fatalf("err = %v", err)
+	}
+	se, _ := AsStatusError(err)
+	// 只保留 ErrorSnippet 字节
+	if string(se.Body) != "user not" || se.Method != http.MethodGet || !strings.HasSuffix(se.URL, "/api/users/1") {
+		t.Fatalf("StatusError = %+v", se)
+	}
+	if resp == nil || resp.StatusCode != http.StatusNotFound || string(resp.Body) != "user not" {
+		t.Fatalf("resp = %+v", resp)
+	}
+	if _, err = c.Get("/users/1").ExpectStatus(http.StatusNotFound).Send(); err != nil {
+		t.Fatalf("expected status err = %v", err)
+	}
+	if _, err = c.Get("/users/1").Stream(); !IsStatus(err, http.StatusNotFound) {
+		t.Fatalf("Stream err = %v", err)
+	}
+}
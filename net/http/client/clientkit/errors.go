@@ -0,0 +1,38 @@
+package clientkit
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var ErrBodyTooLarge = errors.New("响应体超过大小限制")
+
+// StatusError 响应状态码不符合预期，保留状态码和响应体开头部分用于排查
+type StatusError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Header     http.Header
+	Body       []byte // 响应体开头部分，已转换为 utf-8
+}
+
+func (e *StatusError) Error() string {
+	if len(e.Body) == 0 {
+		return fmt.Sprintf("%s %s 响应状态 %d", e.Method, e.URL, e.StatusCode)
+	}
+	return fmt.Sprintf("%s %s 响应状态 %d: %s", e.Method, e.URL, e.StatusCode, e.Body)
+}
+
+// AsStatusError 获取错误链中的 StatusError
+func AsStatusError(err error) (*StatusError, bool) {
+	var se *StatusError
+	ok := errors.As(err, &se)
+	return se, ok
+}
+
+// IsStatus 错误是否为指定状态码的 StatusError
+func IsStatus(err error, code int) bool {
+	se, ok := AsStatusError(err)
+	return ok && se.StatusCode == code
+}
@@ -0,0 +1,106 @@
+package clientkit
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"helay.net/go/utils/v3/logger/ulogs"
+)
+
+// Handler 发出请求
+type Handler func(req *http.Request) (*http.Response, error)
+
+// Middleware 请求中间件，用于签名、日志等横切逻辑
+// 中间件可以修改请求头，但不能读取请求体，需要请求体时使用 req.GetBody。
+type Middleware func(next Handler) Handler
+
+// BearerToken 设置 Authorization: Bearer 请求头，token 每次请求时获取，便于令牌刷新
+func BearerToken(token func(ctx context.Context) (string, error)) Middleware {
+	return func(next Handler) Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			t, err := token(req.Context())
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("Authorization", "Bearer "+t)
+			return next(req)
+		}
+	}
+}
+
+// BasicAuth 设置 Authorization: Basic 请求头
+func BasicAuth(username, password string) Middleware {
+	return func(next Handler) Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			req.SetBasicAuth(username, password)
+			return next(req)
+		}
+	}
+}
+
+// HMACSign HMAC-SHA256 请求签名
+// 签名内容为 method、path?query、时间戳、请求体 sha256 按换行拼接，结果写入 X-Key-Id、X-Timestamp、X-Signature 请求头。
+// 请求体不能重复读取时使用 UNSIGNED-PAYLOAD 代替请求体摘要。
+func HMACSign(keyID string, secret []byte) Middleware {
+	return func(next Handler) Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			payload, err := payloadHash(req)
+			if err != nil {
+				return nil, err
+			}
+			ts := strconv.FormatInt(time.Now().Unix(), 10)
+			mac := hmac.New(sha256.New, secret)
+			mac.Write([]byte(req.Method + "\n" + req.URL.RequestURI() + "\n" + ts + "\n" + payload))
+			req.Header.Set("X-Key-Id", keyID)
+			req.Header.Set("X-Timestamp", ts)
+			req.Header.Set("X-Signature", hex.EncodeToString(mac.Sum(nil)))
+			return next(req)
+		}
+	}
+}
+
+// payloadHash 请求体的 sha256，通过 GetBody 读取副本，不影响请求本身
+func payloadHash(req *http.Request) (string, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return hex.EncodeToString(sha256.New().Sum(nil)), nil
+	}
+	if req.GetBody == nil {
+		return "UNSIGNED-PAYLOAD", nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+	h := sha256.New()
+	if _, err = io.Copy(h, body); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Logger 记录请求日志，成功的请求记为 debug，出错或 5xx 记为 warn
+func Logger() Middleware {
+	return func(next Handler) Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next(req)
+			cost := time.Since(start)
+			switch {
+			case err != nil:
+				ulogs.Warnf("%s %s 请求失败 %s %v", req.Method, req.URL.Redacted(), cost, err)
+			case resp.StatusCode >= http.StatusInternalServerError:
+				ulogs.Warnf("%s %s %d %s", req.Method, req.URL.Redacted(), resp.StatusCode, cost)
+			default:
+				ulogs.Debugf("%s %s %d %s", req.Method, req.URL.Redacted(), resp.StatusCode, cost)
+			}
+			return resp, err
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package clientkit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"helay.net/go/utils/v3/net/http/request"
+)
+
+// Request 链式构造的请求，构造过程中的错误在发出请求时返回
+// Request 不能并发使用，也不能重复发出。
+type Request struct {
+	client      *Client
+	ctx         context.Context
+	method      string
+	url         string
+	query       url.Values
+	header      http.Header
+	body        io.Reader
+	contentType string
+	parts       *multipartBody
+	maxBody     int64
+	expect      []int
+	err         error
+}
+
+// WithContext 设置请求的 context
+func (r *Request) WithContext(ctx context.Context) *Request {
+	r.ctx = ctx
+	return r
+}
+
+// Header 设置请求头
+func (r *Request) Header(key, value string) *Request {
+	r.header.Set(key, value)
+	return r
+}
+
+// Param 添加一个 query 参数
+func (r *Request) Param(key, value string) *Request {
+	r.query.Add(key, value)
+	return r
+}
+
+// Query 添加 query 参数，支持 url.Values、map[string]string 和带 query 标签的结构体
+// 结构体的编码规则与 request.QueryDecode 一致。
+func (r *Request) Query(v any) *Request {
+	values, err := toValues(v, request.QueryEncode)
+	if err != nil {
+		return r.fail(fmt.Errorf("query参数编码失败 %w", err))
+	}
+	for k, vs := range values {
+		r.query[k] = append(r.query[k], vs...)
+	}
+	return r
+}
+
+// JSON 设置 json 请求体
+func (r *Request) JSON(v any) *Request {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return r.fail(fmt.Errorf("json请求体编码失败 %w", err))
+	}
+	return r.Body(bytes.NewReader(b), "application/json; charset=utf-8")
+}
+
+// Form 设置 application/x-www-form-urlencoded 请求体，支持 url.Values、map[string]string 和带 form 标签的结构体
+func (r *Request) Form(v any) *Request {
+	values, err := toValues(v, request.FormEncode)
+	if err != nil {
+		return r.fail(fmt.Errorf("form请求体编码失败 %w", err))
+	}
+	return r.Body(strings.NewReader(values.Encode()), "application/x-www-form-urlencoded")
+}
+
+// Body 设置请求体，bytes.Reader、bytes.Buffer、strings.Reader 以外的 reader 按流式发送，不能重试
+func (r *Request) Body(body io.Reader, contentType string) *Request {
+	r.body = body
+	r.contentType = contentType
+	r.parts = nil
+	return r
+}
+
+// FormField 添加 multipart 表单字段
+func (r *Request) FormField(name, value string) *Request {
+	r.multipart().fields = append(r.multipart().fields, [2]string{name, value})
+	return r
+}
+
+// File 添加 multipart 文件，内容从 reader 流式读取，请求不能重试
+func (r *Request) File(field, filename string, content io.Reader) *Request {
+	r.multipart().files = append(r.multipart().files, multipartFile{field: field, filename: filename, reader: content})
+	return r
+}
+
+// FilePath 添加 multipart 文件，发送时打开文件；全部文件都来自路径时请求可以重试
+func (r *Request) FilePath(field, path string) *Request {
+	r.multipart().files = append(r.multipart().files, multipartFile{field: field, filename: filepath.Base(path), path: path})
+	return r
+}
+
+// MaxBodySize 设置本次请求读取响应体的最大字节数
+func (r *Request) MaxBodySize(n int64) *Request {
+	if n > 0 {
+		r.maxBody = n
+	}
+	return r
+}
+
+// ExpectStatus 设置视为成功的状态码，默认 2xx
+func (r *Request) ExpectStatus(codes ...int) *Request {
+	r.expect = codes
+	return r
+}
+
+func (r *Request) fail(err error) *Request {
+	if r.err == nil {
+		r.err = err
+	}
+	return r
+}
+
+func (r *Request) multipart() *multipartBody {
+	if r.parts == nil {
+		r.parts = &multipartBody{boundary: multipart.NewWriter(io.Discard).Boundary()}
+		r.body = nil
+	}
+	return r.parts
+}
+
+// success 状态码是否符合预期
+func (r *Request) success(code int) bool {
+	if len(r.expect) > 0 {
+		return slices.Contains(r.expect, code)
+	}
+	return code >= 200 && code < 300
+}
+
+// build 生成 http.Request
+func (r *Request) build() (*http.Request, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	u, err := url.Parse(r.url)
+	if err != nil {
+		return nil, fmt.Errorf("请求地址解析失败 %w", err)
+	}
+	if len(r.query) > 0 {
+		q := u.Query()
+		for k, vs := range r.query {
+			q[k] = append(q[k], vs...)
+		}
+		u.RawQuery = q.Encode()
+	}
+	body := r.body
+	if r.parts != nil {
+		body = r.parts.reader()
+	}
+	req, err := http.NewRequestWithContext(r.ctx, r.method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败 %w", err)
+	}
+	req.Header = r.header.Clone()
+	if r.parts != nil {
+		req.Header.Set("Content-Type", "multipart/form-data; boundary="+r.parts.boundary)
+		if r.parts.replayable() {
+			req.GetBody = func() (io.ReadCloser, error) { return r.parts.reader(), nil }
+		}
+	} else if r.contentType != "" {
+		req.Header.Set("Content-Type", r.contentType)
+	}
+	return req, nil
+}
+
+// toValues 将参数转换为 url.Values，结构体使用 encode 编码
+func toValues(v any, encode func(any) (url.Values, error)) (url.Values, error) {
+	switch val := v.(type) {
+	case url.Values:
+		return val, nil
+	case map[string][]string:
+		return val, nil
+	case map[string]string:
+		values := make(url.Values, len(val))
+		for k, s := range val {
+			values.Set(k, s)
+		}
+		return values, nil
+	}
+	return encode(v)
+}
+
+// multipartBody multipart 请求体，发送时通过 io.Pipe 流式写入，不在内存中缓存文件
+type multipartBody struct {
+	boundary string
+	fields   [][2]string
+	files    []multipartFile
+}
+
+type multipartFile struct {
+	field    string
+	filename string
+	reader   io.Reader
+	path     string
+}
+
+func (m *multipartBody) replayable() bool {
+	for _, f := range m.files {
+		if f.path == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// reader 返回请求体，第一次读取时才启动写入协程、打开文件，
+// 请求没有发出（如中间件返回错误）时不会泄漏协程和文件句柄
+func (m *multipartBody) reader() io.ReadCloser {
+	return &lazyBody{m: m}
+}
+
+func (m *multipartBody) pipe() io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(m.write(pw))
+	}()
+	return pr
+}
+
+// lazyBody 第一次读取时创建管道的 multipart 请求体，Transport 可能在其他协程中关闭请求体
+type lazyBody struct {
+	m      *multipartBody
+	mu     sync.Mutex
+	rc     io.ReadCloser
+	closed bool
+}
+
+func (b *lazyBody) Read(p []byte) (int, error) {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return 0, io.ErrClosedPipe
+	}
+	if b.rc == nil {
+		b.rc = b.m.pipe()
+	}
+	rc := b.rc
+	b.mu.Unlock()
+	return rc.Read(p)
+}
+
+func (b *lazyBody) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	if b.rc == nil {
+		return nil
+	}
+	return b.rc.Close()
+}
+
+func (m *multipartBody) write(w io.Writer) error {
+	mw := multipart.NewWriter(w)
+	if err := mw.SetBoundary(m.boundary); err != nil {
+		return err
+	}
+	for _, f := range m.fields {
+		if err := mw.WriteField(f[0], f[1]); err != nil {
+			return err
+		}
+	}
+	for _, f := range m.files {
+		if err := f.write(mw); err != nil {
+			return err
+		}
+	}
+	return mw.Close()
+}
+
+func (f multipartFile) write(mw *multipart.Writer) error {
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", multipart.FileContentDisposition(f.field, f.filename))
+	h.Set("Content-Type", "application/octet-stream")
+	part, err := mw.CreatePart(h)
+	if err != nil {
+		return err
+	}
+	src := f.reader
+	if f.path != "" {
+		file, err := os.Open(f.path)
+		if err != nil {
+			return fmt.Errorf("打开上传文件失败 %w", err)
+		}
+		defer file.Close()
+		src = file
+	}
+	_, err = io.Copy(part, src)
+	return err
+}
@@ -0,0 +1,193 @@
+package clientkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"helay.net/go/utils/v3/close/httpClose"
+	"helay.net/go/utils/v3/net/http/httpkit"
+	"helay.net/go/utils/v3/tools/encodinghelper"
+)
+
+// Response 已读取完成的响应
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Request    *http.Request
+	Body       []byte // 文本类响应已按 charset 转换为 utf-8
+}
+
+// Do 发出请求并把响应解码为 T
+// T 为 []byte、string 时返回响应体，为 *Response 时返回完整响应；其他类型按 Content-Type 使用 xml 或 json 解码。
+// 状态码不符合预期时返回 StatusError。
+func Do[T any](r *Request) (T, error) {
+	var dst T
+	resp, err := r.Send()
+	if err != nil {
+		return dst, err
+	}
+	err = resp.Decode(&dst)
+	return dst, err
+}
+
+// Send 发出请求并读取响应体
+// 状态码不符合预期时同时返回响应和 StatusError，此时响应体只包含开头部分。
+func (r *Request) Send() (*Response, error) {
+	resp, err := r.do()
+	if err != nil {
+		return nil, err
+	}
+	defer httpClose.CloseResp(resp)
+	if !r.success(resp.StatusCode) {
+		se := r.statusError(resp)
+		return &Response{StatusCode: resp.StatusCode, Header: resp.Header, Request: resp.Request, Body: se.Body}, se
+	}
+	body, err := readBody(resp, r.maxBody)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s 读取响应失败 %w", resp.Request.Method, resp.Request.URL.Redacted(), err)
+	}
+	return &Response{StatusCode: resp.StatusCode, Header: resp.Header, Request: resp.Request, Body: body}, nil
+}
+
+// Stream 发出请求并返回未读取的响应，用于下载等大响应体，调用方负责关闭 Body
+// 状态码不符合预期时关闭响应并返回 StatusError。
+func (r *Request) Stream() (*http.Response, error) {
+	resp, err := r.do()
+	if err != nil {
+		return nil, err
+	}
+	if !r.success(resp.StatusCode) {
+		defer httpClose.CloseResp(resp)
+		return nil, r.statusError(resp)
+	}
+	return resp, nil
+}
+
+func (r *Request) do() (*http.Response, error) {
+	req, err := r.build()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.client.handler()(req)
+	// http.Client 出错时会关闭请求体，中间件在此之前返回错误时由这里关闭
+	if err != nil && req.Body != nil {
+		_ = req.Body.Close()
+	}
+	return resp, err
+}
+
+// statusError 读取响应体开头部分生成 StatusError，状态码异常时的响应 Send 也只返回这部分
+func (r *Request) statusError(resp *http.Response) *StatusError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, int64(r.client.opt.ErrorSnippet)))
+	if b, err := toUTF8(resp.Header.Get("Content-Type"), body); err == nil {
+		body = bytes.ToValidUTF8(b, nil)
+	}
+	return &StatusError{
+		Method:     resp.Request.Method,
+		URL:        resp.Request.URL.Redacted(),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+	}
+}
+
+// Decode 按 Content-Type 解码响应体
+func (res *Response) Decode(dst any) error {
+	switch d := dst.(type) {
+	case *[]byte:
+		*d = res.Body
+		return nil
+	case *string:
+		*d = string(res.Body)
+		return nil
+	case **Response:
+		*d = res
+		return nil
+	}
+	if len(bytes.TrimSpace(res.Body)) == 0 {
+		return nil
+	}
+	var err error
+	if strings.Contains(res.Header.Get("Content-Type"), "xml") {
+		err = xml.Unmarshal(res.Body, dst)
+	} else {
+		err = json.Unmarshal(res.Body, dst)
+	}
+	if err != nil {
+		return fmt.Errorf("%s %s 响应解码失败 %w", res.Request.Method, res.Request.URL.Redacted(), err)
+	}
+	return nil
+}
+
+// String 响应体文本
+func (res *Response) String() string {
+	return string(res.Body)
+}
+
+// readBody 读取响应体，超过 limit 时返回 ErrBodyTooLarge；文本类响应按 charset 转换为 utf-8
+func readBody(resp *http.Response, limit int64) ([]byte, error) {
+	if resp.ContentLength > limit {
+		return nil, fmt.Errorf("%w %d > %d", ErrBodyTooLarge, resp.ContentLength, limit)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > limit {
+		return nil, fmt.Errorf("%w %d", ErrBodyTooLarge, limit)
+	}
+	return toUTF8(resp.Header.Get("Content-Type"), body)
+}
+
+// toUTF8 按 Content-Type 的 charset 转码，未声明时 html 页面从 meta 标签识别
+func toUTF8(contentType string, body []byte) ([]byte, error) {
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+	if !textual(mediaType) {
+		return body, nil
+	}
+	charset := strings.ToLower(params["charset"])
+	if charset == "" {
+		if utf8.Valid(body) {
+			return body, nil
+		}
+		if m := httpkit.PageCharacterSetPreg.FindSubmatch(body); len(m) > 1 {
+			charset = strings.ToLower(string(m[1]))
+		}
+	}
+	switch charset {
+	case "", "utf8", "utf-8", "us-ascii":
+		return body, nil
+	case "gbk", "gb2312":
+		charset = "gb18030"
+	}
+	// encodinghelper 使用 SHIFTJIS、EUCJP 这样不带分隔符的名称，utf-16 除外
+	name := charset
+	if !strings.HasPrefix(name, "utf-16") {
+		name = strings.NewReplacer("-", "", "_", "").Replace(name)
+	}
+	out, err := encodinghelper.ToUTF8(body, name)
+	if err != nil {
+		return nil, fmt.Errorf("响应体从 %s 转码失败 %w", charset, err)
+	}
+	return out, nil
+}
+
+// textual 是否为文本类响应
+func textual(mediaType string) bool {
+	if mediaType == "" || strings.HasPrefix(mediaType, "text/") {
+		return true
+	}
+	for _, s := range []string{"json", "xml", "javascript", "x-www-form-urlencoded"} {
+		if strings.Contains(mediaType, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+package transportkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"helay.net/go/utils/v3/safe"
+	"helay.net/go/utils/v3/tools"
+)
+
+var ErrCircuitOpen = errors.New("熔断中，请求被拒绝")
+
+// maxHosts 熔断、限流最多保留的主机数，超过时淘汰最久未访问的主机，避免请求大量不同主机时无限增长
+const maxHosts = 4096
+
+// BreakerState 熔断状态
+type BreakerState int
+
+const (
+	StateClosed   BreakerState = iota // 正常
+	StateOpen                         // 熔断
+	StateHalfOpen                     // 半开，放行探测请求
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// Breaker 按主机熔断的 RoundTripper
+type Breaker struct {
+	next     http.RoundTripper
+	cfg      BreakerConfig
+	hosts    *safe.Map[string, *hostBreaker]
+	onChange func(host string, from, to BreakerState)
+}
+
+// hostBreaker 单个主机的熔断状态
+// generation 在每次状态变化时递增，旧状态下放行的请求结果不会影响新状态。
+type hostBreaker struct {
+	mu         sync.Mutex
+	state      BreakerState
+	generation uint64
+	failures   int       // closed 状态下的连续失败次数
+	openedAt   time.Time // 进入 open 状态的时间
+	probes     int       // half-open 状态已放行的探测请求数
+	successes  int       // half-open 状态成功的探测请求数
+}
+
+// NewBreaker 创建熔断层
+func NewBreaker(next http.RoundTripper, cfg BreakerConfig) *Breaker {
+	cfg.FailureThreshold = tools.Ternary(cfg.FailureThreshold > 0, cfg.FailureThreshold, 5)
+	cfg.OpenTimeout = tools.AutoTimeDuration(cfg.OpenTimeout, time.Second, 30*time.Second)
+	cfg.HalfOpenRequests = tools.Ternary(cfg.HalfOpenRequests > 0, cfg.HalfOpenRequests, 1)
+	return &Breaker{
+		next:  next,
+		cfg:   cfg,
+		hosts: safe.NewMap[string, *hostBreaker](context.Background(), safe.StringHasher{}, safe.CacheConfig{MaxEntries: maxHosts}),
+	}
+}
+
+// SetOnStateChange 设置状态变化回调，回调在持有锁时同步调用，不能阻塞
+func (b *Breaker) SetOnStateChange(fn func(host string, from, to BreakerState)) {
+	b.onChange = fn
+}
+
+// State 主机当前的熔断状态，open 状态超过 OpenTimeout 后显示为 half-open
+func (b *Breaker) State(host string) BreakerState {
+	h, ok := b.hosts.Load(host)
+	if !ok {
+		return StateClosed
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.state == StateOpen && time.Since(h.openedAt) >= b.cfg.OpenTimeout {
+		return StateHalfOpen
+	}
+	return h.state
+}
+
+func (b *Breaker) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	h, _, _ := b.hosts.LoadOrStoreFunc(host, func(string) (*hostBreaker, error) { return &hostBreaker{}, nil })
+	gen, ok := b.allow(host, h)
+	if !ok {
+		return nil, fmt.Errorf("%w %s", ErrCircuitOpen, host)
+	}
+	resp, err := b.next.RoundTrip(req)
+	// 请求被调用方取消不代表服务端异常
+	if err != nil && req.Context().Err() != nil {
+		b.release(h, gen)
+		return resp, err
+	}
+	b.record(host, h, gen, err == nil && resp.StatusCode < http.StatusInternalServerError)
+	return resp, err
+}
+
+// allow 是否放行请求，返回放行时的 generation
+func (b *Breaker) allow(host string, h *hostBreaker) (uint64, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	switch h.state {
+	case StateOpen:
+		if time.Since(h.openedAt) < b.cfg.OpenTimeout {
+			return 0, false
+		}
+		b.transition(host, h, StateHalfOpen)
+		fallthrough
+	case StateHalfOpen:
+		if h.probes >= b.cfg.HalfOpenRequests {
+			return 0, false
+		}
+		h.probes++
+	}
+	return h.generation, true
+}
+
+// release 归还未产生结果的探测名额
+func (b *Breaker) release(h *hostBreaker, gen uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.generation == gen && h.state == StateHalfOpen && h.probes > 0 {
+		h.probes--
+	}
+}
+
+// record 记录请求结果
+func (b *Breaker) record(host string, h *hostBreaker, gen uint64, success bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.generation != gen {
+		return
+	}
+	switch h.state {
+	case StateClosed:
+		if success {
+			h.failures = 0
+			return
+		}
+		h.failures++
+		if h.failures >= b.cfg.FailureThreshold {
+			b.transition(host, h, StateOpen)
+		}
+	case StateHalfOpen:
+		if !success {
+			b.transition(host, h, StateOpen)
+			return
+		}
+		h.successes++
+		if h.successes >= b.cfg.HalfOpenRequests {
+			b.transition(host, h, StateClosed)
+		}
+	}
+}
+
+// transition 切换状态并重置计数，调用方持有锁
+func (b *Breaker) transition(host string, h *hostBreaker, to BreakerState) {
+	from := h.state
+	h.state = to
+	h.generation++
+	h.failures, h.probes, h.successes = 0, 0, 0
+	if to == StateOpen {
+		h.openedAt = time.Now()
+	}
+	if b.onChange != nil {
+		b.onChange(host, from, to)
+	}
+}
@@ -0,0 +1,118 @@
+package transportkit
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"time"
+
+	"golang.org/x/net/proxy"
+	cfg_proxy "helay.net/go/utils/v3/config/cfg-proxy"
+	"helay.net/go/utils/v3/tools"
+)
+
+// NewClient 按配置创建 http.Client
+func NewClient(cfg Config) (*http.Client, error) {
+	rt, err := NewTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: rt, Timeout: tools.AutoTimeDuration(cfg.Timeout, time.Second)}, nil
+}
+
+// NewTransport 按配置创建基础 http.Transport，并包装重试、对冲、熔断、限流
+func NewTransport(cfg Config) (http.RoundTripper, error) {
+	base, err := NewBaseTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return Wrap(base, cfg), nil
+}
+
+// Wrap 在已有的 RoundTripper 外包装重试、对冲、熔断、限流，未启用的层会被跳过
+// 重试在最外层，每次重试都会重新经过熔断和限流；熔断在限流之外，熔断期间的请求不消耗令牌。
+func Wrap(base http.RoundTripper, cfg Config) http.RoundTripper {
+	rt := base
+	if cfg.RateLimit.RPS > 0 || len(cfg.RateLimit.Hosts) > 0 {
+		rt = NewRateLimit(rt, cfg.RateLimit)
+	}
+	if cfg.Breaker.Enable {
+		rt = NewBreaker(rt, cfg.Breaker)
+	}
+	if cfg.Hedge.Delay > 0 {
+		rt = NewHedge(rt, cfg.Hedge)
+	}
+	if cfg.Retry.MaxAttempts > 1 {
+		rt = NewRetry(rt, cfg.Retry)
+	}
+	return rt
+}
+
+// NewBaseTransport 创建基础 http.Transport
+// tls 未启用时按系统证书校验服务端，不会跳过证书验证。
+func NewBaseTransport(cfg Config) (*http.Transport, error) {
+	dialer := &net.Dialer{
+		Timeout:   tools.AutoTimeDuration(cfg.DialTimeout, time.Second, 10*time.Second),
+		KeepAlive: 30 * time.Second,
+	}
+	trans := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   tools.AutoTimeDuration(cfg.TLSHandshakeTimeout, time.Second, 10*time.Second),
+		ResponseHeaderTimeout: tools.AutoTimeDuration(cfg.ResponseHeaderTimeout, time.Second),
+		IdleConnTimeout:       tools.AutoTimeDuration(cfg.IdleConnTimeout, time.Second, 90*time.Second),
+		ExpectContinueTimeout: time.Second,
+		MaxIdleConns:          tools.Ternary(cfg.MaxIdleConns > 0, cfg.MaxIdleConns, 100),
+		MaxIdleConnsPerHost:   tools.Ternary(cfg.MaxIdleConnsPerHost > 0, cfg.MaxIdleConnsPerHost, 10),
+		MaxConnsPerHost:       cfg.MaxConnsPerHost,
+		ForceAttemptHTTP2:     true,
+	}
+	tlsConfig, err := cfg.TLS.ToTLSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("tls配置错误 %w", err)
+	}
+	if tlsConfig != nil {
+		trans.TLSClientConfig = tlsConfig
+		// 自定义 NextProtos 不包含 h2 时，按配置只使用 http/1.1
+		trans.ForceAttemptHTTP2 = len(tlsConfig.NextProtos) == 0 || slices.Contains(tlsConfig.NextProtos, "h2")
+	}
+	if err = setProxy(trans, dialer, cfg.Proxy); err != nil {
+		return nil, err
+	}
+	return trans, nil
+}
+
+// setProxy 设置代理，http、https 代理使用 Proxy 函数，socks5 代理替换拨号器
+// socks5 通过 dialer 连接代理服务器，和直连使用相同的连接超时。
+func setProxy(trans *http.Transport, dialer *net.Dialer, p cfg_proxy.Proxy) error {
+	if err := p.Valid(); err != nil {
+		return err
+	}
+	switch p.ProxyType() {
+	case cfg_proxy.ProxyNone:
+	case cfg_proxy.ProxyHttp, cfg_proxy.ProxyHttps:
+		trans.Proxy = p.HttpProxy()
+	case cfg_proxy.ProxySocks5:
+		var auth *proxy.Auth
+		if p.Account != "" && p.Password != "" {
+			auth = &proxy.Auth{User: p.Account, Password: p.Password}
+		}
+		d, err := proxy.SOCKS5("tcp", p.GetProxyUrl().Host, auth, dialer)
+		if err != nil {
+			return fmt.Errorf("socks5拨号器初始化失败 %w", err)
+		}
+		trans.Proxy = nil
+		if cd, ok := d.(proxy.ContextDialer); ok {
+			trans.DialContext = cd.DialContext
+		} else {
+			trans.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+				return d.Dial(network, addr)
+			}
+		}
+	default:
+		return fmt.Errorf("不支持的代理类型 %s", p.Addr)
+	}
+	return nil
+}
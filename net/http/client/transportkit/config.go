@@ -0,0 +1,73 @@
+// Package transportkit 基于 http.RoundTripper 的客户端中间层
+// 从外到内依次为：重试、对冲请求、按主机熔断、按主机限流，最内层是标准的 http.Transport。
+// 每一层都可以单独使用，也可以通过 NewClient 按配置组合。
+package transportkit
+
+import (
+	"time"
+
+	cfg_proxy "helay.net/go/utils/v3/config/cfg-proxy"
+	"helay.net/go/utils/v3/net/tlsconfig"
+	"helay.net/go/utils/v3/tools/backoff"
+)
+
+// Config 客户端配置
+type Config struct {
+	Timeout               time.Duration `json:"timeout" yaml:"timeout" ini:"timeout"`                                           // 包括重试在内的总超时时间，0 表示不限制
+	DialTimeout           time.Duration `json:"dial_timeout" yaml:"dial_timeout" ini:"dial_timeout"`                            // 建立连接的超时时间，默认10秒
+	TLSHandshakeTimeout   time.Duration `json:"tls_handshake_timeout" yaml:"tls_handshake_timeout" ini:"tls_handshake_timeout"` // tls 握手超时时间，默认10秒
+	ResponseHeaderTimeout time.Duration `json:"response_header_timeout" yaml:"response_header_timeout" ini:"response_header_timeout"`
+	IdleConnTimeout       time.Duration `json:"idle_conn_timeout" yaml:"idle_conn_timeout" ini:"idle_conn_timeout"`                   // 空闲连接关闭时间，默认90秒
+	MaxIdleConns          int           `json:"max_idle_conns" yaml:"max_idle_conns" ini:"max_idle_conns"`                            // 最大空闲连接数，默认100
+	MaxIdleConnsPerHost   int           `json:"max_idle_conns_per_host" yaml:"max_idle_conns_per_host" ini:"max_idle_conns_per_host"` // 每个主机的最大空闲连接数，默认10
+	MaxConnsPerHost       int           `json:"max_conns_per_host" yaml:"max_conns_per_host" ini:"max_conns_per_host"`                // 每个主机的最大连接数，0 表示不限制
+
+	TLS   tlsconfig.TLSConfig `json:"tls" yaml:"tls" ini:"tls"`       // 未启用时使用系统证书校验服务端
+	Proxy cfg_proxy.Proxy     `json:"proxy" yaml:"proxy" ini:"proxy"` // 代理，支持 http、https、socks5
+
+	Retry     RetryConfig     `json:"retry" yaml:"retry" ini:"retry"`
+	Breaker   BreakerConfig   `json:"breaker" yaml:"breaker" ini:"breaker"`
+	RateLimit RateLimitConfig `json:"rate_limit" yaml:"rate_limit" ini:"rate_limit"`
+	Hedge     HedgeConfig     `json:"hedge" yaml:"hedge" ini:"hedge"`
+}
+
+// RetryConfig 重试配置
+// 默认只重试幂等请求：GET、HEAD、OPTIONS、TRACE、PUT、DELETE，以及带有 Idempotency-Key 请求头的请求。
+// 带请求体的请求需要设置 GetBody 才能重试，http.NewRequest 对常见的 body 类型会自动设置。
+type RetryConfig struct {
+	MaxAttempts   int             `json:"max_attempts" yaml:"max_attempts" ini:"max_attempts"`          // 最多尝试次数，包括第一次，<=1 表示不重试
+	Backoff       backoff.Backoff `json:"backoff" yaml:"backoff" ini:"backoff"`                         // 重试等待时间，默认从100ms开始指数递增，最大10秒
+	RetryStatus   []int           `json:"retry_status" yaml:"retry_status" ini:"retry_status"`          // 需要重试的状态码，默认 429、502、503、504
+	NonIdempotent bool            `json:"non_idempotent" yaml:"non_idempotent" ini:"non_idempotent"`    // 是否重试非幂等请求
+	MaxRetryAfter time.Duration   `json:"max_retry_after" yaml:"max_retry_after" ini:"max_retry_after"` // Retry-After 的最大等待时间，超过时直接返回响应，默认1分钟
+}
+
+// BreakerConfig 熔断配置，按主机独立统计
+// 请求出错或者返回 5xx 记为失败，连续失败达到阈值后熔断，熔断期间的请求直接返回 ErrCircuitOpen，
+// 超过 OpenTimeout 后进入半开状态，放行 HalfOpenRequests 个探测请求，全部成功后恢复，任一失败重新熔断。
+type BreakerConfig struct {
+	Enable           bool          `json:"enable" yaml:"enable" ini:"enable"`
+	FailureThreshold int           `json:"failure_threshold" yaml:"failure_threshold" ini:"failure_threshold"`    // 连续失败次数，默认5
+	OpenTimeout      time.Duration `json:"open_timeout" yaml:"open_timeout" ini:"open_timeout"`                   // 熔断持续时间，默认30秒
+	HalfOpenRequests int           `json:"half_open_requests" yaml:"half_open_requests" ini:"half_open_requests"` // 半开状态的探测请求数，默认1
+}
+
+// RateLimitConfig 限流配置，每个主机一个令牌桶，令牌不足时等待
+type RateLimitConfig struct {
+	RPS   float64              `json:"rps" yaml:"rps" ini:"rps"`       // 每个主机每秒请求数，0 表示不限制
+	Burst int                  `json:"burst" yaml:"burst" ini:"burst"` // 令牌桶容量，默认为 RPS 向上取整
+	Hosts map[string]HostLimit `json:"hosts" yaml:"hosts" ini:"hosts"` // 按主机单独设置，key 为 host 或 host:port
+}
+
+// HostLimit 单个主机的限流
+type HostLimit struct {
+	RPS   float64 `json:"rps" yaml:"rps" ini:"rps"`
+	Burst int     `json:"burst" yaml:"burst" ini:"burst"`
+}
+
+// HedgeConfig 对冲请求配置，只对可以重放的幂等请求生效
+// 请求超过 Delay 没有响应时再发出一个相同的请求，采用最先返回的响应，其余请求被取消。
+type HedgeConfig struct {
+	Delay     time.Duration `json:"delay" yaml:"delay" ini:"delay"`                // 发出对冲请求前的等待时间，0 表示不启用
+	MaxHedges int           `json:"max_hedges" yaml:"max_hedges" ini:"max_hedges"` // 最多额外发出的请求数，默认1
+}
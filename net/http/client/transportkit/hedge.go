@@ -0,0 +1,117 @@
+package transportkit
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"helay.net/go/utils/v3/tools"
+)
+
+// Hedge 对冲请求的 RoundTripper，用额外的请求降低长尾延迟
+// 只对幂等且请求体可以重放的请求生效，每个请求使用独立的 context，采用第一个成功返回的响应，其余请求被取消。
+type Hedge struct {
+	next http.RoundTripper
+	cfg  HedgeConfig
+}
+
+// NewHedge 创建对冲层
+func NewHedge(next http.RoundTripper, cfg HedgeConfig) *Hedge {
+	cfg.Delay = tools.AutoTimeDuration(cfg.Delay, time.Millisecond)
+	cfg.MaxHedges = tools.Ternary(cfg.MaxHedges > 0, cfg.MaxHedges, 1)
+	return &Hedge{next: next, cfg: cfg}
+}
+
+type hedgeResult struct {
+	idx  int
+	resp *http.Response
+	err  error
+}
+
+func (t *Hedge) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.cfg.Delay <= 0 || !idempotent(req) || !replayable(req) {
+		return t.next.RoundTrip(req)
+	}
+	results := make(chan hedgeResult, t.cfg.MaxHedges+1)
+	var (
+		cancels  []context.CancelFunc
+		inflight int
+		launched int
+	)
+	launch := func() error {
+		ctx, cancel := context.WithCancel(req.Context())
+		r, err := cloneRequest(ctx, req)
+		if err != nil {
+			cancel()
+			return err
+		}
+		idx := len(cancels)
+		cancels = append(cancels, cancel)
+		inflight++
+		launched++
+		go func() {
+			resp, err := t.next.RoundTrip(r)
+			results <- hedgeResult{idx: idx, resp: resp, err: err}
+		}()
+		return nil
+	}
+	if err := launch(); err != nil {
+		return nil, err
+	}
+	timer := time.NewTimer(t.cfg.Delay)
+	defer timer.Stop()
+	var lastErr error
+	for {
+		select {
+		case <-timer.C:
+			if launched <= t.cfg.MaxHedges {
+				if err := launch(); err == nil && launched <= t.cfg.MaxHedges {
+					timer.Reset(t.cfg.Delay)
+				}
+			}
+		case res := <-results:
+			inflight--
+			if res.err == nil {
+				// 取消其他请求，胜出请求的 context 在响应体关闭时取消
+				for i, cancel := range cancels {
+					if i != res.idx {
+						cancel()
+					}
+				}
+				t.discard(results, inflight)
+				res.resp.Body = &cancelBody{ReadCloser: res.resp.Body, cancel: cancels[res.idx]}
+				return res.resp, nil
+			}
+			cancels[res.idx]()
+			lastErr = res.err
+			if req.Context().Err() != nil {
+				t.discard(results, inflight)
+				return nil, req.Context().Err()
+			}
+			// 已发出的请求都失败时，立即发出下一个对冲请求
+			if inflight == 0 {
+				if launched > t.cfg.MaxHedges {
+					return nil, lastErr
+				}
+				if err := launch(); err != nil {
+					return nil, lastErr
+				}
+			}
+		}
+	}
+}
+
+// discard 在后台关闭落选请求的响应体
+func (t *Hedge) discard(results <-chan hedgeResult, n int) {
+	if n == 0 {
+		return
+	}
+	go func() {
+		for range n {
+			res := <-results
+			if res.resp != nil {
+				drainBody(res.resp)
+			}
+		}
+	}()
+}
@@ -0,0 +1,66 @@
+package transportkit
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/time/rate"
+	"helay.net/go/utils/v3/safe"
+)
+
+// RateLimit 按主机限流的 RoundTripper，令牌不足时等待，请求被取消时返回错误
+type RateLimit struct {
+	next     http.RoundTripper
+	cfg      RateLimitConfig
+	limiters *safe.Map[string, *rate.Limiter]
+}
+
+// NewRateLimit 创建限流层
+func NewRateLimit(next http.RoundTripper, cfg RateLimitConfig) *RateLimit {
+	return &RateLimit{
+		next:     next,
+		cfg:      cfg,
+		limiters: safe.NewMap[string, *rate.Limiter](context.Background(), safe.StringHasher{}, safe.CacheConfig{MaxEntries: maxHosts}),
+	}
+}
+
+func (t *RateLimit) RoundTrip(req *http.Request) (*http.Response, error) {
+	if l := t.limiter(req.URL); l != nil {
+		if err := l.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+	return t.next.RoundTrip(req)
+}
+
+// limiter 获取主机的令牌桶，先按 host:port 再按 host 匹配单独配置，未配置且 RPS 为 0 时不限流
+func (t *RateLimit) limiter(u *url.URL) *rate.Limiter {
+	l, _, _ := t.limiters.LoadOrStoreFunc(u.Host, func(host string) (*rate.Limiter, error) {
+		return newLimiter(t.hostLimit(host, u.Hostname())), nil
+	})
+	return l
+}
+
+// hostLimit 主机的限流配置
+func (t *RateLimit) hostLimit(host, hostname string) HostLimit {
+	if l, ok := t.cfg.Hosts[host]; ok {
+		return l
+	}
+	if l, ok := t.cfg.Hosts[hostname]; ok {
+		return l
+	}
+	return HostLimit{RPS: t.cfg.RPS, Burst: t.cfg.Burst}
+}
+
+func newLimiter(l HostLimit) *rate.Limiter {
+	if l.RPS <= 0 {
+		return nil
+	}
+	burst := l.Burst
+	if burst <= 0 {
+		burst = int(math.Ceil(l.RPS))
+	}
+	return rate.NewLimiter(rate.Limit(l.RPS), burst)
+}
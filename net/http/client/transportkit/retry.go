@@ -0,0 +1,99 @@
+package transportkit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"helay.net/go/utils/v3/tools"
+)
+
+// Retry 按策略重试的 RoundTripper
+// 请求出错或者返回需要重试的状态码时，按 Backoff 等待后重试；响应带有 Retry-After 时按服务端要求等待。
+type Retry struct {
+	next http.RoundTripper
+	cfg  RetryConfig
+}
+
+// NewRetry 创建重试层
+func NewRetry(next http.RoundTripper, cfg RetryConfig) *Retry {
+	if cfg.Backoff.InitialSleep <= 0 {
+		cfg.Backoff.InitialSleep = 100 * time.Millisecond
+	}
+	if cfg.Backoff.MaxSleep <= 0 {
+		cfg.Backoff.MaxSleep = 10 * time.Second
+	}
+	if cfg.Backoff.Base <= 0 {
+		cfg.Backoff.Base = 2
+	}
+	if len(cfg.RetryStatus) == 0 {
+		cfg.RetryStatus = []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+	}
+	cfg.MaxRetryAfter = tools.AutoTimeDuration(cfg.MaxRetryAfter, time.Second, time.Minute)
+	return &Retry{next: next, cfg: cfg}
+}
+
+func (t *Retry) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.cfg.MaxAttempts <= 1 || !replayable(req) || (!t.cfg.NonIdempotent && !idempotent(req)) {
+		return t.next.RoundTrip(req)
+	}
+	ctx := req.Context()
+	b := t.cfg.Backoff
+	b.Reset()
+	for attempt := 1; ; attempt++ {
+		r := req
+		if attempt > 1 {
+			var err error
+			if r, err = cloneRequest(ctx, req); err != nil {
+				return nil, err
+			}
+		}
+		resp, err := t.next.RoundTrip(r)
+		if attempt >= t.cfg.MaxAttempts || !t.shouldRetry(ctx, resp, err) {
+			return resp, err
+		}
+		wait := b.Next()
+		if resp != nil {
+			if after, ok := retryAfter(resp); ok {
+				if after > t.cfg.MaxRetryAfter {
+					return resp, nil
+				}
+				wait = after
+			}
+			drainBody(resp)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// shouldRetry 是否需要重试，请求被取消和熔断时不重试
+func (t *Retry) shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil && !errors.Is(err, ErrCircuitOpen)
+	}
+	return slices.Contains(t.cfg.RetryStatus, resp.StatusCode)
+}
+
+// retryAfter 解析 Retry-After 响应头，支持秒数和 http 日期两种格式
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if sec, err := strconv.Atoi(v); err == nil {
+		return time.Duration(max(sec, 0)) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
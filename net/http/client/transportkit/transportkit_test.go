@@ -0,0 +1,237 @@
+package transportkit
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"helay.net/go/utils/v3/tools/backoff"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func newResponse(req *http.Request, status int) *http.Response {
+	return &http.Response{StatusCode: status, Header: http.Header{}, Body: io.NopCloser(strings.NewReader("")), Request: req}
+}
+
+func newRequest(t *testing.T, method string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(method, "http://example.com/", strings.NewReader("body"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+func testRetryConfig() RetryConfig {
+	return RetryConfig{MaxAttempts: 3, Backoff: backoff.Backoff{InitialSleep: time.Millisecond, MaxSleep: time.Millisecond}}
+}
+
+func TestRetry(t *testing.T) {
+	var calls atomic.Int32
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if b, _ := io.ReadAll(req.Body); string(b) != "body" {
+			t.Errorf("attempt %d body = %q", calls.Load()+1, b)
+		}
+		if calls.Add(1) < 3 {
+			return newResponse(req, http.StatusServiceUnavailable), nil
+		}
+		return newResponse(req, http.StatusOK), nil
+	})
+	resp, err := NewRetry(next, testRetryConfig()).RoundTrip(newRequest(t, http.MethodPut))
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("RoundTrip = %v, %v", resp, err)
+	}
+	if n := calls.Load(); n != 3 {
+		t.Fatalf("calls = %d, want 3", n)
+	}
+}
+
+func TestRetrySkip(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		key    bool // 是否带 Idempotency-Key 请求头
+		header http.Header
+		err    error
+		calls  int32
+	}{
+		{name: "non idempotent", method: http.MethodPost, calls: 1},
+		{name: "circuit open", method: http.MethodGet, err: ErrCircuitOpen, calls: 1},
+		{name: "retry after too long", method: http.MethodGet, header: http.Header{"Retry-After": {"3600"}}, calls: 1},
+		{name: "idempotency key", method: http.MethodPost, key: true, header: http.Header{"Retry-After": {"0"}}, calls: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				calls.Add(1)
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				resp := newResponse(req, http.StatusServiceUnavailable)
+				resp.Header = tt.header.Clone()
+				return resp, nil
+			})
+			req := newRequest(t, tt.method)
+			if tt.key {
+				req.Header.Set("Idempotency-Key", "1")
+			}
+			_, _ = NewRetry(next, testRetryConfig()).RoundTrip(req)
+			if n := calls.Load(); n != tt.calls {
+				t.Fatalf("calls = %d, want %d", n, tt.calls)
+			}
+		})
+	}
+}
+
+func TestHedge(t *testing.T) {
+	var (
+		calls    atomic.Int32
+		canceled = make(chan struct{})
+	)
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if calls.Add(1) == 1 {
+			// 第一个请求一直不返回，直到被取消
+			<-req.Context().Done()
+			close(canceled)
+			return nil, req.Context().Err()
+		}
+		resp := newResponse(req, http.StatusOK)
+		resp.Header.Set("X-Attempt", "2")
+		return resp, nil
+	})
+	resp, err := NewHedge(next, HedgeConfig{Delay: 5 * time.Millisecond}).RoundTrip(newRequest(t, http.MethodGet))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.Header.Get("X-Attempt") != "2" {
+		t.Fatalf("response from attempt %q, want hedged request", resp.Header.Get("X-Attempt"))
+	}
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("slow request not canceled")
+	}
+}
+
+func TestHedgeAllFailed(t *testing.T) {
+	var calls atomic.Int32
+	errFail := errors.New("fail")
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls.Add(1)
+		return nil, errFail
+	})
+	_, err := NewHedge(next, HedgeConfig{Delay: time.Hour, MaxHedges: 2}).RoundTrip(newRequest(t, http.MethodGet))
+	if !errors.Is(err, errFail) {
+		t.Fatalf("err = %v", err)
+	}
+	// 失败后立即发出下一个对冲请求，不等待 Delay
+	if n := calls.Load(); n != 3 {
+		t.Fatalf("calls = %d, want 3", n)
+	}
+}
+
+func TestBreakerTransitions(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusInternalServerError)
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return newResponse(req, int(status.Load())), nil
+	})
+	b := NewBreaker(next, BreakerConfig{Enable: true, FailureThreshold: 2, OpenTimeout: 20 * time.Millisecond})
+	var (
+		mu      sync.Mutex
+		changes []string
+	)
+	b.SetOnStateChange(func(host string, from, to BreakerState) {
+		mu.Lock()
+		defer mu.Unlock()
+		changes = append(changes, from.String()+"->"+to.String())
+	})
+	do := func() error {
+		_, err := b.RoundTrip(newRequest(t, http.MethodGet))
+		return err
+	}
+
+	for range 2 {
+		if err := do(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if s := b.State("example.com"); s != StateOpen {
+		t.Fatalf("state = %s, want open", s)
+	}
+	if err := do(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("err = %v, want ErrCircuitOpen", err)
+	}
+
+	// 半开后探测失败重新熔断
+	time.Sleep(25 * time.Millisecond)
+	if s := b.State("example.com"); s != StateHalfOpen {
+		t.Fatalf("state = %s, want half-open", s)
+	}
+	if err := do(); err != nil {
+		t.Fatal(err)
+	}
+	if s := b.State("example.com"); s != StateOpen {
+		t.Fatalf("state = %s, want open", s)
+	}
+
+	// 探测成功后恢复
+	time.Sleep(25 * time.Millisecond)
+	status.Store(http.StatusOK)
+	if err := do(); err != nil {
+		t.Fatal(err)
+	}
+	if s := b.State("example.com"); s != StateClosed {
+		t.Fatalf("state = %s, want closed", s)
+	}
+
+	want := []string{"closed->open", "open->half-open", "half-open->open", "open->half-open", "half-open->closed"}
+	mu.Lock()
+	defer mu.Unlock()
+	if strings.Join(changes, ",") != strings.Join(want, ",") {
+		t.Fatalf("changes = %v, want %v", changes, want)
+	}
+}
+
+func TestBreakerHalfOpenLimit(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if calls.Add(1) > 1 {
+			<-release
+		}
+		return newResponse(req, http.StatusBadGateway), nil
+	})
+	b := NewBreaker(next, BreakerConfig{Enable: true, FailureThreshold: 1, OpenTimeout: 10 * time.Millisecond})
+	if _, err := b.RoundTrip(newRequest(t, http.MethodGet)); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(15 * time.Millisecond)
+
+	// 只放行一个探测请求
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = b.RoundTrip(newRequest(t, http.MethodGet))
+	}()
+	for calls.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := b.RoundTrip(newRequest(t, http.MethodGet)); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("second probe err = %v, want ErrCircuitOpen", err)
+	}
+	close(release)
+	<-done
+}
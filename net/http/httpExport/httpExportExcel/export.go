@@ -1,12 +1,12 @@
 package httpExportExcel
 
 import (
+	"fmt"
 	"net/http"
 
 	"gorm.io/gorm"
 	"helay.net/go/utils/v3/db/exportkit"
 	"helay.net/go/utils/v3/db/exportkit/gormkit"
-	"helay.net/go/utils/v3/net/http/httpkit"
 	"helay.net/go/utils/v3/tools"
 )
 
@@ -54,24 +54,10 @@ func (e *RowsExport) Response(w http.ResponseWriter) error {
 	if e.FileType == "" {
 		e.FileType = exportkit.ExportTypeCsv // 默认为csv
 	}
+	format, ok := exportkit.Lookup(e.FileType)
+	if !ok {
+		return fmt.Errorf("%w %s", exportkit.ErrExportTypeNotSupport, e.FileType)
+	}
 	e.FileName = tools.Ternary(e.FileName == "", "export", e.FileName)
-	w.Header().Del("Accept-Ranges")
-	// 设置csv的响应头
-	export.OnInitColumn(func(et exportkit.ExportType) error {
-		if et == exportkit.ExportTypeCsv {
-			w.Header().Set("Content-Type", "text/csv")
-			httpkit.SetDisposition(w, e.FileName+".csv")
-		}
-		return nil
-	})
-	// 设置excel的响应头
-	export.BeforeFinalize(func(et exportkit.ExportType) error {
-		if et == exportkit.ExportTypeExcel {
-			w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
-			httpkit.SetDisposition(w, e.FileName+".xlsx")
-		}
-		return nil
-	})
-
-	return export.Execute(e.FileType, w)
+	return export.Execute(e.FileType, newExportWriter(w, format, e.FileName))
 }
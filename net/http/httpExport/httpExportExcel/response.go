@@ -0,0 +1,31 @@
+package httpExportExcel
+
+import (
+	"net/http"
+
+	"helay.net/go/utils/v3/db/exportkit"
+	"helay.net/go/utils/v3/net/http/httpkit"
+)
+
+// exportWriter 在第一次写入时设置下载响应头
+// xlsx、parquet 在全部数据写完后才输出内容，导出过程中出错时调用方仍然可以返回错误响应。
+type exportWriter struct {
+	w        http.ResponseWriter
+	format   exportkit.Format
+	fileName string
+	wrote    bool
+}
+
+func newExportWriter(w http.ResponseWriter, format exportkit.Format, fileName string) *exportWriter {
+	w.Header().Del("Accept-Ranges")
+	return &exportWriter{w: w, format: format, fileName: fileName}
+}
+
+func (e *exportWriter) Write(p []byte) (int, error) {
+	if !e.wrote {
+		e.wrote = true
+		e.w.Header().Set("Content-Type", e.format.ContentType)
+		httpkit.SetDisposition(e.w, e.fileName+"."+e.format.Extension)
+	}
+	return e.w.Write(p)
+}
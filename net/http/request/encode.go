@@ -0,0 +1,27 @@
+package request
+
+import (
+	"net/url"
+
+	"github.com/gorilla/schema"
+)
+
+// QueryEncode 将结构体编码为query数据，与 QueryDecode 使用相同的 query 标签，支持 omitempty
+func QueryEncode(src any) (url.Values, error) {
+	return schemaEncode(src, "query")
+}
+
+// FormEncode 将结构体编码为 application/x-www-form-urlencoded 数据，与 FormDecode 使用相同的 form 标签
+func FormEncode(src any) (url.Values, error) {
+	return schemaEncode(src, "form")
+}
+
+func schemaEncode(src any, tag string) (url.Values, error) {
+	encoder := schema.NewEncoder()
+	encoder.SetAliasTag(tag)
+	dst := url.Values{}
+	if err := encoder.Encode(src, dst); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
@@ -0,0 +1,163 @@
+package captcha
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"time"
+
+	"helay.net/go/utils/v3/logger/ulogs"
+	"helay.net/go/utils/v3/net/http/request"
+	"helay.net/go/utils/v3/net/http/response"
+	"helay.net/go/utils/v3/net/http/session"
+)
+
+// 行为验证码（拖拽、点选、滑动）的公共流程：
+// 1. 获取验证码：生成图片，答案通过 StoreFunc 保存，图片以 data uri 的形式返回给前端；
+// 2. 校验验证码：通过 LoadFunc 取出答案（取出即失效），与前端提交的坐标按容错像素比对；
+// 3. 校验通过后写入 CaptchaVerifiedKey 标记，业务接口（如登录）调用 Verified 确认并消费该标记。
+
+// StoreFunc 保存行为验证码答案
+type StoreFunc func(w http.ResponseWriter, r *http.Request, key, value string) error
+
+// LoadFunc 读取行为验证码答案，读取后必须删除，保证验证码只能使用一次
+type LoadFunc func(w http.ResponseWriter, r *http.Request, key string) (string, error)
+
+var ErrCaptchaNotFound = errors.New("验证码不存在或已过期")
+
+// Point 坐标
+type Point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// SessionStore 基于session的答案存储
+func SessionStore(ttl time.Duration) StoreFunc {
+	return func(w http.ResponseWriter, r *http.Request, key, value string) error {
+		sv := session.Value{Field: key, Value: value, TTL: ttl}
+		return session.GetSession().Set(w, r, &sv)
+	}
+}
+
+// SessionLoad 基于session的答案读取，读取后删除
+func SessionLoad(w http.ResponseWriter, r *http.Request, key string) (string, error) {
+	var value string
+	if err := session.GetSession().Flashes(w, r, key, &value); err != nil {
+		if errors.Is(err, session.ErrNotFound) {
+			return "", ErrCaptchaNotFound
+		}
+		return "", err
+	}
+	return value, nil
+}
+
+// Verified 业务接口调用，确认行为验证码已经校验通过，标记只能使用一次
+func (c *Captcha) Verified(w http.ResponseWriter, r *http.Request) bool {
+	return c.VerifiedWithLoad(w, r, SessionLoad)
+}
+
+func (c *Captcha) VerifiedWithLoad(w http.ResponseWriter, r *http.Request, load LoadFunc) bool {
+	value, err := load(w, r, CaptchaVerifiedKey)
+	if err != nil {
+		if !errors.Is(err, ErrCaptchaNotFound) {
+			ulogs.Error(err)
+		}
+		return false
+	}
+	return value != ""
+}
+
+// challenge 生成验证码，保存答案并返回图片数据
+func (c *Captcha) challenge(w http.ResponseWriter, r *http.Request, key string, store StoreFunc, generate func() (any, any, error)) {
+	setNoCache(w)
+	data, answer, err := generate()
+	if err != nil {
+		ulogs.Errorf("验证码生成失败 %v", err)
+		response.InternalServerError(w)
+		return
+	}
+	b, err := json.Marshal(answer)
+	if err != nil {
+		ulogs.Errorf("验证码答案序列化失败 %v", err)
+		response.InternalServerError(w)
+		return
+	}
+	if err = store(w, r, key, string(b)); err != nil {
+		ulogs.Error(err)
+		response.InternalServerError(w)
+		return
+	}
+	response.SetReturnData(w, 0, "成功", data)
+}
+
+// verify 读取答案并校验，校验通过后写入通过标记
+func verify[A, S any](w http.ResponseWriter, r *http.Request, key string, store StoreFunc, load LoadFunc, check func(answer A, submit S) bool) {
+	setNoCache(w)
+	submit, err := request.JsonDecode[S](r)
+	if err != nil {
+		response.SetReturnErrorDisableLog(w, err, http.StatusBadRequest, "验证码参数错误")
+		return
+	}
+	raw, err := load(w, r, key)
+	if err != nil {
+		if errors.Is(err, ErrCaptchaNotFound) {
+			response.SetReturnData(w, http.StatusForbidden, err.Error())
+			return
+		}
+		ulogs.Error(err)
+		response.InternalServerError(w)
+		return
+	}
+	var answer A
+	if err = json.Unmarshal([]byte(raw), &answer); err != nil {
+		ulogs.Errorf("验证码答案解析失败 %v", err)
+		response.InternalServerError(w)
+		return
+	}
+	if !check(answer, submit) {
+		response.SetReturnData(w, http.StatusForbidden, "验证码错误")
+		return
+	}
+	if err = store(w, r, CaptchaVerifiedKey, key); err != nil {
+		ulogs.Error(err)
+		response.InternalServerError(w)
+		return
+	}
+	response.SetReturnData(w, 0, "成功")
+}
+
+func setNoCache(w http.ResponseWriter) {
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.Header().Set("Pragma", "no-cache")
+	w.Header().Set("Expires", "0")
+}
+
+// within 判断两个坐标的偏差是否在容错范围内
+func within(a, b, tolerance int) bool {
+	d := a - b
+	return d >= -tolerance && d <= tolerance
+}
+
+// pngDataURI 透明图片使用png编码
+func pngDataURI(img image.Image) (string, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", fmt.Errorf("png编码失败 %w", err)
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// jpegDataURI 背景图使用jpeg编码，体积更小，同时有损压缩也能增加像素比对的难度
+func jpegDataURI(img image.Image) (string, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+		return "", fmt.Errorf("jpeg编码失败 %w", err)
+	}
+	return "data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
@@ -1,13 +1,19 @@
 package captcha
 
 import (
+	"sync"
 	"time"
 
+	"golang.org/x/image/font/opentype"
 	"helay.net/go/utils/v3/tools"
 )
 
 type Captcha struct {
 	opt *Config
+
+	fontOnce sync.Once
+	font     *opentype.Font
+	fontErr  error
 }
 
 func New(opt *Config) *Captcha {
@@ -21,4 +27,30 @@ func (c *Captcha) setDefault() {
 	c.opt.Text.ExpireTime = tools.AutoTimeDuration(c.opt.Text.ExpireTime, time.Second, 4*time.Minute)
 	c.opt.Text.Width = tools.Ternary(c.opt.Text.Width < 1, 106, c.opt.Text.Width)
 	c.opt.Text.Height = tools.Ternary(c.opt.Text.Height < 1, 40, c.opt.Text.Height)
+
+	c.opt.Drag.BackgroundWidth = tools.Ternary(c.opt.Drag.BackgroundWidth < 1, 300, c.opt.Drag.BackgroundWidth)
+	c.opt.Drag.BackgroundHeight = tools.Ternary(c.opt.Drag.BackgroundHeight < 1, 160, c.opt.Drag.BackgroundHeight)
+	c.opt.Drag.TemplateWidth = tools.Ternary(c.opt.Drag.TemplateWidth < 1, 50, c.opt.Drag.TemplateWidth)
+	c.opt.Drag.TemplateHeight = tools.Ternary(c.opt.Drag.TemplateHeight < 1, 50, c.opt.Drag.TemplateHeight)
+	c.opt.Drag.Tolerance = tools.Ternary(c.opt.Drag.Tolerance < 1, 5, c.opt.Drag.Tolerance)
+	c.opt.Drag.ExpireTime = tools.AutoTimeDuration(c.opt.Drag.ExpireTime, time.Second, 2*time.Minute)
+
+	c.opt.Click.Width = tools.Ternary(c.opt.Click.Width < 1, 300, c.opt.Click.Width)
+	c.opt.Click.Height = tools.Ternary(c.opt.Click.Height < 1, 200, c.opt.Click.Height)
+	c.opt.Click.WordCount = tools.Ternary(c.opt.Click.WordCount < 1, 3, c.opt.Click.WordCount)
+	c.opt.Click.TotalWords = tools.Ternary(c.opt.Click.TotalWords < c.opt.Click.WordCount, c.opt.Click.WordCount+2, c.opt.Click.TotalWords)
+	c.opt.Click.FontSize = tools.Ternary(c.opt.Click.FontSize < 1, 30, c.opt.Click.FontSize)
+	c.opt.Click.Tolerance = tools.Ternary(c.opt.Click.Tolerance < 0, 0, c.opt.Click.Tolerance)
+	c.opt.Click.ExpireTime = tools.AutoTimeDuration(c.opt.Click.ExpireTime, time.Second, 2*time.Minute)
+	if len(c.opt.Click.WordList) < 1 {
+		// 默认字体只包含西文字符，配置了字体文件时才使用中文字表
+		c.opt.Click.WordList = tools.Ternary(c.opt.Click.FontPath == "", defaultClickWords, defaultClickChineseWords)
+	}
+
+	c.opt.Slide.Width = tools.Ternary(c.opt.Slide.Width < 1, 300, c.opt.Slide.Width)
+	c.opt.Slide.Height = tools.Ternary(c.opt.Slide.Height < 1, 160, c.opt.Slide.Height)
+	c.opt.Slide.TemplateWidth = tools.Ternary(c.opt.Slide.TemplateWidth < 1, 50, c.opt.Slide.TemplateWidth)
+	c.opt.Slide.TemplateHeight = tools.Ternary(c.opt.Slide.TemplateHeight < 1, 50, c.opt.Slide.TemplateHeight)
+	c.opt.Slide.Tolerance = tools.Ternary(c.opt.Slide.Tolerance < 1, 5, c.opt.Slide.Tolerance)
+	c.opt.Slide.ExpireTime = tools.AutoTimeDuration(c.opt.Slide.ExpireTime, time.Second, 2*time.Minute)
 }
@@ -1 +1,218 @@
 package captcha
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"os"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// 默认字体只支持西文，去掉了容易混淆的字符
+var defaultClickWords = []string{
+	"A", "B", "C", "D", "E", "F", "G", "H", "J", "K", "L", "M", "N", "P", "Q", "R", "S", "T", "U", "V", "W", "X", "Y", "Z",
+	"2", "3", "4", "5", "6", "7", "8", "9",
+}
+
+var defaultClickChineseWords = []string{
+	"天", "地", "人", "和", "春", "夏", "秋", "冬", "山", "水", "风", "云", "花", "草", "鸟", "鱼",
+	"日", "月", "星", "辰", "东", "南", "西", "北", "金", "木", "火", "土", "龙", "虎", "马", "牛",
+}
+
+// ClickData 点选验证码数据，前端按 Words 的顺序依次点击图片中的文字
+type ClickData struct {
+	Image  string   `json:"image"`  // 验证码图片，data uri
+	Width  int      `json:"width"`  // 图片宽度
+	Height int      `json:"height"` // 图片高度
+	Words  []string `json:"words"`  // 需要依次点击的文字
+}
+
+// ClickSubmit 点选验证码提交数据
+type ClickSubmit struct {
+	Points []Point `json:"points"` // 按顺序点击的坐标
+}
+
+// clickTarget 点选目标，中心坐标和命中半径
+type clickTarget struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	R int `json:"r"`
+}
+
+// Click 点选验证码
+func (c *Captcha) Click() http.HandlerFunc {
+	return c.ClickWithStore(SessionStore(c.opt.Click.ExpireTime))
+}
+
+func (c *Captcha) ClickWithStore(store StoreFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c.ClickHandFunc(w, r, store)
+	}
+}
+
+func (c *Captcha) ClickHandFunc(w http.ResponseWriter, r *http.Request, store StoreFunc) {
+	c.challenge(w, r, CaptchaClickKey, store, c.generateClick)
+}
+
+// ClickVerify 点选验证码校验，提交按顺序点击的坐标 {"points":[{"x":0,"y":0}]}
+func (c *Captcha) ClickVerify() http.HandlerFunc {
+	return c.ClickVerifyWithStore(SessionStore(c.opt.Click.ExpireTime), SessionLoad)
+}
+
+func (c *Captcha) ClickVerifyWithStore(store StoreFunc, load LoadFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c.ClickVerifyHandFunc(w, r, store, load)
+	}
+}
+
+func (c *Captcha) ClickVerifyHandFunc(w http.ResponseWriter, r *http.Request, store StoreFunc, load LoadFunc) {
+	verify(w, r, CaptchaClickKey, store, load, c.checkClick)
+}
+
+// loadFont 加载字体，只加载一次
+func (c *Captcha) loadFont() (*opentype.Font, error) {
+	c.fontOnce.Do(func() {
+		data := gobold.TTF
+		if c.opt.Click.FontPath != "" {
+			if data, c.fontErr = os.ReadFile(c.opt.Click.FontPath); c.fontErr != nil {
+				c.fontErr = fmt.Errorf("字体文件读取失败 %w", c.fontErr)
+				return
+			}
+		}
+		if c.font, c.fontErr = opentype.Parse(data); c.fontErr != nil {
+			c.fontErr = fmt.Errorf("字体文件解析失败 %w", c.fontErr)
+		}
+	})
+	return c.font, c.fontErr
+}
+
+func (c *Captcha) generateClick() (any, any, error) {
+	cfg := c.opt.Click
+	f, err := c.loadFont()
+	if err != nil {
+		return nil, nil, err
+	}
+	// face 不是并发安全的，每次生成单独创建
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: float64(cfg.FontSize), DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, nil, fmt.Errorf("字体创建失败 %w", err)
+	}
+	defer face.Close()
+
+	total := min(cfg.TotalWords, len(cfg.WordList))
+	if total < cfg.WordCount {
+		return nil, nil, fmt.Errorf("点选验证码文字列表数量[%d]少于需要点击的文字数量[%d]", len(cfg.WordList), cfg.WordCount)
+	}
+	words := make([]string, 0, total)
+	for _, i := range rand.Perm(len(cfg.WordList))[:total] {
+		words = append(words, cfg.WordList[i])
+	}
+
+	img := randomBackground(cfg.Width, cfg.Height, true)
+	targets := make([]clickTarget, 0, total)
+	for _, word := range words {
+		width := font.MeasureString(face, word).Ceil()
+		radius := max(width, cfg.FontSize) / 2
+		t := placeTarget(targets, cfg.Width, cfg.Height, radius)
+		drawWord(img, face, word, t, cfg.RandomRotation)
+		targets = append(targets, t)
+	}
+
+	background, err := jpegDataURI(img)
+	if err != nil {
+		return nil, nil, err
+	}
+	data := ClickData{Image: background, Width: cfg.Width, Height: cfg.Height, Words: words[:cfg.WordCount]}
+	return data, targets[:cfg.WordCount], nil
+}
+
+func (c *Captcha) checkClick(answer []clickTarget, submit ClickSubmit) bool {
+	if len(answer) == 0 || len(answer) != len(submit.Points) {
+		return false
+	}
+	for i, t := range answer {
+		p := submit.Points[i]
+		limit := float64(t.R + c.opt.Click.Tolerance)
+		if math.Hypot(float64(p.X-t.X), float64(p.Y-t.Y)) > limit {
+			return false
+		}
+	}
+	return true
+}
+
+// placeTarget 随机选择文字位置，尽量避免与已有文字重叠
+func placeTarget(placed []clickTarget, width, height, radius int) clickTarget {
+	var t clickTarget
+	for try := 0; try < 100; try++ {
+		t = clickTarget{
+			X: radius + rand.IntN(max(width-2*radius, 1)),
+			Y: radius + rand.IntN(max(height-2*radius, 1)),
+			R: radius,
+		}
+		overlap := false
+		for _, p := range placed {
+			if math.Hypot(float64(p.X-t.X), float64(p.Y-t.Y)) < float64(p.R+t.R) {
+				overlap = true
+				break
+			}
+		}
+		if !overlap {
+			break
+		}
+	}
+	return t
+}
+
+// drawWord 以 t 为中心绘制文字，rotate 为 true 时随机旋转 ±45 度
+func drawWord(img *image.RGBA, face font.Face, word string, t clickTarget, rotate bool) {
+	// 先把文字绘制到透明底的方形画布上，再旋转贴到背景图
+	size := int(float64(t.R) * 2 * math.Sqrt2)
+	tile := image.NewAlpha(image.Rect(0, 0, size, size))
+	metrics := face.Metrics()
+	width := font.MeasureString(face, word)
+	d := &font.Drawer{
+		Dst:  tile,
+		Src:  image.Opaque,
+		Face: face,
+		Dot: fixed.Point26_6{
+			X: fixed.I(size/2) - width/2,
+			Y: fixed.I(size/2) + (metrics.Ascent-metrics.Descent)/2,
+		},
+	}
+	d.DrawString(word)
+
+	var angle float64
+	if rotate {
+		angle = (rand.Float64()*90 - 45) * math.Pi / 180
+	}
+	sin, cos := math.Sincos(angle)
+	c := randomColor(0, 255)
+	// 文字颜色与背景拉开对比度
+	if int(c.R)+int(c.G)+int(c.B) > 380 {
+		c = color.RGBA{R: c.R / 3, G: c.G / 3, B: c.B / 3, A: 255}
+	}
+	half := float64(size) / 2
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			// 反向旋转找到画布上对应的像素
+			dx, dy := float64(x)-half, float64(y)-half
+			sx, sy := int(dx*cos+dy*sin+half), int(-dx*sin+dy*cos+half)
+			if sx < 0 || sy < 0 || sx >= size || sy >= size {
+				continue
+			}
+			a := tile.AlphaAt(sx, sy).A
+			if a == 0 {
+				continue
+			}
+			c.A = a
+			blend(img, t.X-size/2+x, t.Y-size/2+y, c)
+		}
+	}
+}
@@ -1 +1,72 @@
 package captcha
+
+import (
+	"net/http"
+)
+
+// DragData 拖拽验证码数据，前端将拼图块拖动到背景图缺口位置
+type DragData struct {
+	Background     string `json:"background"`      // 带缺口的背景图，data uri
+	Template       string `json:"template"`        // 拼图块，data uri
+	Width          int    `json:"width"`           // 背景图宽度
+	Height         int    `json:"height"`          // 背景图高度
+	TemplateWidth  int    `json:"template_width"`  // 拼图块宽度
+	TemplateHeight int    `json:"template_height"` // 拼图块高度
+}
+
+// Drag 拖拽验证码
+func (c *Captcha) Drag() http.HandlerFunc {
+	return c.DragWithStore(SessionStore(c.opt.Drag.ExpireTime))
+}
+
+func (c *Captcha) DragWithStore(store StoreFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c.DragHandFunc(w, r, store)
+	}
+}
+
+func (c *Captcha) DragHandFunc(w http.ResponseWriter, r *http.Request, store StoreFunc) {
+	c.challenge(w, r, CaptchaDragKey, store, c.generateDrag)
+}
+
+// DragVerify 拖拽验证码校验，提交拼图块左上角在背景图中的坐标 {"x":0,"y":0}
+func (c *Captcha) DragVerify() http.HandlerFunc {
+	return c.DragVerifyWithStore(SessionStore(c.opt.Drag.ExpireTime), SessionLoad)
+}
+
+func (c *Captcha) DragVerifyWithStore(store StoreFunc, load LoadFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c.DragVerifyHandFunc(w, r, store, load)
+	}
+}
+
+func (c *Captcha) DragVerifyHandFunc(w http.ResponseWriter, r *http.Request, store StoreFunc, load LoadFunc) {
+	verify(w, r, CaptchaDragKey, store, load, c.checkDrag)
+}
+
+func (c *Captcha) generateDrag() (any, any, error) {
+	cfg := c.opt.Drag
+	bg := randomBackground(cfg.BackgroundWidth, cfg.BackgroundHeight, false)
+	p := newPuzzle(bg, cfg.TemplateWidth, cfg.TemplateHeight, 0, cfg.ShowShadow)
+	background, err := jpegDataURI(p.background)
+	if err != nil {
+		return nil, nil, err
+	}
+	template, err := pngDataURI(p.template)
+	if err != nil {
+		return nil, nil, err
+	}
+	data := DragData{
+		Background:     background,
+		Template:       template,
+		Width:          cfg.BackgroundWidth,
+		Height:         cfg.BackgroundHeight,
+		TemplateWidth:  cfg.TemplateWidth,
+		TemplateHeight: cfg.TemplateHeight,
+	}
+	return data, Point{X: p.x, Y: p.y}, nil
+}
+
+func (c *Captcha) checkDrag(answer, submit Point) bool {
+	return within(answer.X, submit.X, c.opt.Drag.Tolerance) && within(answer.Y, submit.Y, c.opt.Drag.Tolerance)
+}
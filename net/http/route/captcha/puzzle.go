@@ -0,0 +1,193 @@
+package captcha
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"math/rand/v2"
+)
+
+// 拖拽和滑动验证码共用的拼图生成逻辑。
+// 背景图随机生成，拼图块从背景图中抠出，原位置留下缺口。
+
+// puzzle 拼图生成结果
+type puzzle struct {
+	background *image.RGBA // 带缺口的背景图
+	template   *image.RGBA // 拼图块，透明底
+	x, y       int         // 拼图块在背景图中的左上角坐标
+}
+
+// randomBackground 随机生成背景图：渐变底色 + 半透明色块，noise 为 true 时额外叠加干扰线和噪点
+func randomBackground(width, height int, noise bool) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	from, to := randomColor(60, 200), randomColor(60, 200)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			t := float64(x+y) / float64(width+height)
+			img.SetRGBA(x, y, color.RGBA{
+				R: mix(from.R, to.R, t),
+				G: mix(from.G, to.G, t),
+				B: mix(from.B, to.B, t),
+				A: 255,
+			})
+		}
+	}
+	// 色块让背景有足够的纹理，避免拼图缺口被轻易识别
+	shapes := 12 + rand.IntN(8)
+	for i := 0; i < shapes; i++ {
+		c := randomColor(30, 230)
+		c.A = uint8(80 + rand.IntN(100))
+		cx, cy := rand.IntN(width), rand.IntN(height)
+		r := 8 + rand.IntN(max(height/4, 9))
+		if rand.IntN(2) == 0 {
+			fillCircle(img, cx, cy, r, c)
+		} else {
+			fillRect(img, image.Rect(cx-r, cy-r/2, cx+r, cy+r/2), c)
+		}
+	}
+	if noise {
+		addNoise(img)
+	}
+	return img
+}
+
+// addNoise 叠加干扰线和噪点
+func addNoise(img *image.RGBA) {
+	b := img.Bounds()
+	for i := 0; i < 6; i++ {
+		c := randomColor(0, 255)
+		c.A = 160
+		x0, y0 := float64(rand.IntN(b.Dx())), float64(rand.IntN(b.Dy()))
+		x1, y1 := float64(rand.IntN(b.Dx())), float64(rand.IntN(b.Dy()))
+		steps := int(math.Hypot(x1-x0, y1-y0))
+		for s := 0; s <= steps; s++ {
+			t := float64(s) / float64(max(steps, 1))
+			blend(img, int(x0+(x1-x0)*t), int(y0+(y1-y0)*t), c)
+		}
+	}
+	dots := b.Dx() * b.Dy() / 40
+	for i := 0; i < dots; i++ {
+		c := randomColor(0, 255)
+		c.A = 180
+		blend(img, rand.IntN(b.Dx()), rand.IntN(b.Dy()), c)
+	}
+}
+
+// newPuzzle 在背景图上随机选择位置抠出拼图块
+// minX 用于滑动验证码，保证缺口离起点有足够距离
+func newPuzzle(bg *image.RGBA, tw, th, minX int, shadow bool) *puzzle {
+	b := bg.Bounds()
+	const margin = 4
+	maxX, maxY := b.Dx()-tw-margin, b.Dy()-th-margin
+	minX = max(min(minX, maxX), margin)
+	p := &puzzle{
+		background: bg,
+		template:   image.NewRGBA(image.Rect(0, 0, tw, th)),
+		x:          minX + rand.IntN(max(maxX-minX, 0)+1),
+		y:          margin + rand.IntN(max(maxY-margin, 0)+1),
+	}
+	mask := puzzleMask(tw, th)
+	border := color.RGBA{R: 255, G: 255, B: 255, A: 200}
+	for y := 0; y < th; y++ {
+		for x := 0; x < tw; x++ {
+			if !mask[y][x] {
+				continue
+			}
+			bx, by := p.x+x, p.y+y
+			src := bg.RGBAAt(bx, by)
+			p.template.SetRGBA(x, y, src)
+			edge := isEdge(mask, x, y)
+			if edge {
+				blend(p.template, x, y, border)
+			}
+			// 缺口：阴影模式压暗，否则提亮，边缘再描一圈便于辨认
+			if shadow {
+				bg.SetRGBA(bx, by, color.RGBA{R: src.R / 3, G: src.G / 3, B: src.B / 3, A: 255})
+			} else {
+				blend(bg, bx, by, color.RGBA{R: 255, G: 255, B: 255, A: 140})
+			}
+			if edge {
+				blend(bg, bx, by, border)
+			}
+		}
+	}
+	return p
+}
+
+// puzzleMask 生成拼图形状：主体矩形，上方和右侧各有一个凸起，左侧一个凹槽
+func puzzleMask(tw, th int) [][]bool {
+	r := float64(min(tw, th)) / 6
+	left, top := 0.0, r
+	right, bottom := float64(tw)-r, float64(th)
+	midX, midY := (left+right)/2, (top+bottom)/2
+	mask := make([][]bool, th)
+	for y := 0; y < th; y++ {
+		mask[y] = make([]bool, tw)
+		for x := 0; x < tw; x++ {
+			px, py := float64(x)+0.5, float64(y)+0.5
+			in := px >= left && px < right && py >= top && py < bottom
+			if math.Hypot(px-midX, py-top) <= r || math.Hypot(px-right, py-midY) <= r {
+				in = true
+			}
+			if math.Hypot(px-left, py-midY) <= r*0.8 {
+				in = false
+			}
+			mask[y][x] = in
+		}
+	}
+	return mask
+}
+
+func isEdge(mask [][]bool, x, y int) bool {
+	h, w := len(mask), len(mask[0])
+	for _, d := range [4][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+		nx, ny := x+d[0], y+d[1]
+		if nx < 0 || ny < 0 || nx >= w || ny >= h || !mask[ny][nx] {
+			return true
+		}
+	}
+	return false
+}
+
+func randomColor(lo, hi int) color.RGBA {
+	n := hi - lo + 1
+	return color.RGBA{R: uint8(lo + rand.IntN(n)), G: uint8(lo + rand.IntN(n)), B: uint8(lo + rand.IntN(n)), A: 255}
+}
+
+func mix(a, b uint8, t float64) uint8 {
+	return uint8(float64(a)*(1-t) + float64(b)*t)
+}
+
+// blend 按 c 的透明度叠加颜色
+func blend(img *image.RGBA, x, y int, c color.RGBA) {
+	if !(image.Point{X: x, Y: y}.In(img.Bounds())) {
+		return
+	}
+	dst := img.RGBAAt(x, y)
+	a := float64(c.A) / 255
+	img.SetRGBA(x, y, color.RGBA{
+		R: mix(dst.R, c.R, a),
+		G: mix(dst.G, c.G, a),
+		B: mix(dst.B, c.B, a),
+		A: max(dst.A, c.A),
+	})
+}
+
+func fillCircle(img *image.RGBA, cx, cy, r int, c color.RGBA) {
+	for y := cy - r; y <= cy+r; y++ {
+		for x := cx - r; x <= cx+r; x++ {
+			if (x-cx)*(x-cx)+(y-cy)*(y-cy) <= r*r {
+				blend(img, x, y, c)
+			}
+		}
+	}
+}
+
+func fillRect(img *image.RGBA, rect image.Rectangle, c color.RGBA) {
+	rect = rect.Intersect(img.Bounds())
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			blend(img, x, y, c)
+		}
+	}
+}
@@ -1 +1,82 @@
 package captcha
+
+import (
+	"net/http"
+)
+
+// SlideData 滑动验证码数据，拼图块固定在 Y 坐标，前端只能水平滑动
+type SlideData struct {
+	Background     string `json:"background"`      // 带缺口的背景图，data uri
+	Template       string `json:"template"`        // 滑块，data uri
+	Width          int    `json:"width"`           // 背景图宽度
+	Height         int    `json:"height"`          // 背景图高度
+	TemplateWidth  int    `json:"template_width"`  // 滑块宽度
+	TemplateHeight int    `json:"template_height"` // 滑块高度
+	Y              int    `json:"y"`               // 滑块的纵坐标
+	ShowTrajectory bool   `json:"show_trajectory"` // 前端是否显示滑动轨迹
+}
+
+// SlideSubmit 滑动验证码提交数据
+type SlideSubmit struct {
+	X int `json:"x"` // 滑块左上角的横坐标
+}
+
+// Slide 滑动验证码
+func (c *Captcha) Slide() http.HandlerFunc {
+	return c.SlideWithStore(SessionStore(c.opt.Slide.ExpireTime))
+}
+
+func (c *Captcha) SlideWithStore(store StoreFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c.SlideHandFunc(w, r, store)
+	}
+}
+
+func (c *Captcha) SlideHandFunc(w http.ResponseWriter, r *http.Request, store StoreFunc) {
+	c.challenge(w, r, CaptchaSlideKey, store, c.generateSlide)
+}
+
+// SlideVerify 滑动验证码校验，提交滑块的横坐标 {"x":0}
+func (c *Captcha) SlideVerify() http.HandlerFunc {
+	return c.SlideVerifyWithStore(SessionStore(c.opt.Slide.ExpireTime), SessionLoad)
+}
+
+func (c *Captcha) SlideVerifyWithStore(store StoreFunc, load LoadFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c.SlideVerifyHandFunc(w, r, store, load)
+	}
+}
+
+func (c *Captcha) SlideVerifyHandFunc(w http.ResponseWriter, r *http.Request, store StoreFunc, load LoadFunc) {
+	verify(w, r, CaptchaSlideKey, store, load, c.checkSlide)
+}
+
+func (c *Captcha) generateSlide() (any, any, error) {
+	cfg := c.opt.Slide
+	bg := randomBackground(cfg.Width, cfg.Height, cfg.BackgroundNoise)
+	// 滑块从最左侧出发，缺口至少离开起点一个滑块宽度
+	p := newPuzzle(bg, cfg.TemplateWidth, cfg.TemplateHeight, cfg.TemplateWidth+cfg.Tolerance*2, true)
+	background, err := jpegDataURI(p.background)
+	if err != nil {
+		return nil, nil, err
+	}
+	template, err := pngDataURI(p.template)
+	if err != nil {
+		return nil, nil, err
+	}
+	data := SlideData{
+		Background:     background,
+		Template:       template,
+		Width:          cfg.Width,
+		Height:         cfg.Height,
+		TemplateWidth:  cfg.TemplateWidth,
+		TemplateHeight: cfg.TemplateHeight,
+		Y:              p.y,
+		ShowTrajectory: cfg.ShowTrajectory,
+	}
+	return data, SlideSubmit{X: p.x}, nil
+}
+
+func (c *Captcha) checkSlide(answer, submit SlideSubmit) bool {
+	return within(answer.X, submit.X, c.opt.Slide.Tolerance)
+}
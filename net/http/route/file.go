@@ -6,6 +6,7 @@ import (
 	"io/fs"
 	"net/http"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"helay.net/go/utils/v3/config"
 	"helay.net/go/utils/v3/net/http/httpkit"
 	"helay.net/go/utils/v3/net/http/mime"
+	"helay.net/go/utils/v3/net/http/route/middleware"
 	"helay.net/go/utils/v3/tools"
 )
 
@@ -130,9 +132,48 @@ func (ro *Route) singleFile(w http.ResponseWriter, r *http.Request, path string)
 			modTime = time.Date(1994, time.January, 1, 0, 0, 0, 0, time.UTC)
 		}
 	}
+	if ro.opt.Precompressed && ro.precompressed(w, r, hfs, path, d.Name(), modTime, enableEmbed) {
+		return
+	}
 	http.ServeContent(w, r, d.Name(), modTime, f)
 }
 
+// 预压缩文件的后缀
+var precompressedExt = map[middleware.CompressionAlgorithm]string{
+	middleware.Brotli: ".br",
+	middleware.Zstd:   ".zst",
+	middleware.Gzip:   ".gz",
+}
+
+// precompressed 按 Accept-Encoding 协商查找预压缩文件，找到后以原文件名输出，Content-Type 仍按原文件判断
+func (ro *Route) precompressed(w http.ResponseWriter, r *http.Request, hfs http.FileSystem, path, name string, modTime time.Time, enableEmbed bool) bool {
+	h := w.Header()
+	if !slices.Contains(h.Values("Vary"), "Accept-Encoding") {
+		h.Add("Vary", "Accept-Encoding")
+	}
+	header := r.Header.Get("Accept-Encoding")
+	offers := []middleware.CompressionAlgorithm{middleware.Brotli, middleware.Zstd, middleware.Gzip}
+	for len(offers) > 0 {
+		enc := middleware.NegotiateEncoding(header, offers...)
+		if enc == "" {
+			return false
+		}
+		offers = slices.DeleteFunc(offers, func(a middleware.CompressionAlgorithm) bool { return a == enc })
+		f, d, err := HttpFS(hfs, path+precompressedExt[enc])
+		if err != nil {
+			continue
+		}
+		h.Set("Content-Encoding", enc.String())
+		if !enableEmbed {
+			modTime = d.ModTime()
+		}
+		http.ServeContent(w, r, name, modTime, f)
+		vclose.Close(f)
+		return true
+	}
+	return false
+}
+
 func (ro *Route) multipleFiles(w http.ResponseWriter, r *http.Request, path string, files []string) {
 	if len(files) == 0 {
 		RenderErrorText(w, &ErrorResp{Code: http.StatusBadRequest, Msg: "invalid URL path"})
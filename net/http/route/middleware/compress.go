@@ -2,27 +2,30 @@ package middleware
 
 import (
 	"bufio"
-	"compress/flate"
 	"compress/gzip"
 	"errors"
 	"io"
 	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
-	"helay.net/go/utils/v3/close/vclose"
 	"helay.net/go/utils/v3/logger/zaploger"
 )
 
 type CompressionConfig struct {
 	Enabled             bool     `json:"enabled" yaml:"enabled" ini:"enabled"`                                           // 是否启用压缩
-	Level               int      `json:"level" yaml:"level" ini:"level"`                                                 // 压缩级别
+	Level               int      `json:"level" yaml:"level" ini:"level"`                                                 // 压缩级别，按 gzip 的 -1~9，br、zstd 会换算成对应级别
+	MinSize             int      `json:"min_size" yaml:"min_size" ini:"min_size"`                                        // 最小压缩字节数，小于该值的响应不压缩，0 表示不限制
+	Algorithms          []string `json:"algorithms" yaml:"algorithms" ini:"algorithms"`                                  // 启用的压缩算法及优先顺序，默认 br、zstd、gzip、deflate
 	ExcludeContentTypes []string `json:"exclude_content_types" yaml:"exclude_content_types" ini:"exclude_content_types"` // 不需要压缩的 MIME 类型
 }
 
 type ResponseProcessor struct {
 	compressOpt CompressionConfig
+	algorithms  []CompressionAlgorithm // 启用的压缩算法
+	encoders    *encoderPools          // 压缩写入器池
 	loggerOpt   zaploger.Config
 	logEvent    []Logger // 日志事件
 }
@@ -42,6 +45,17 @@ func (c *ResponseProcessor) SetCompressionConfig(opt CompressionConfig) {
 		for _, contentType := range c.compressOpt.ExcludeContentTypes {
 			excludeContentTypes[strings.ToLower(contentType)] = struct{}{}
 		}
+		c.algorithms = nil
+		for _, alg := range c.compressOpt.Algorithms {
+			alg := CompressionAlgorithm(strings.ToLower(strings.TrimSpace(alg)))
+			if _, ok := supportedCompressionAlgorithms[alg]; ok {
+				c.algorithms = append(c.algorithms, alg)
+			}
+		}
+		if len(c.algorithms) == 0 {
+			c.algorithms = defaultCompressionOrder
+		}
+		c.encoders = newEncoderPools(c.compressOpt.Level)
 	}
 }
 
@@ -68,22 +82,17 @@ func (c *ResponseProcessor) Handler(next http.Handler) http.Handler {
 			next.ServeHTTP(w, r)
 			return
 		}
-		encoding := parseAcceptEncoding(r.Header.Get(acceptEncoding))
-		var encWriter io.WriteCloser
-		switch encoding {
-		case Gzip:
-			encWriter, _ = gzip.NewWriterLevel(w, c.compressOpt.Level)
-		case Deflate:
-			encWriter, _ = flate.NewWriter(w, c.compressOpt.Level)
-		default:
+		encoding := NegotiateEncoding(r.Header.Get(acceptEncoding), c.algorithms...)
+		if encoding == "" {
 			next.ServeHTTP(enhancedWriter, r)
 			return
 		}
-		defer vclose.Close(encWriter)
-		w.Header().Set("Transfer-Encoding", "chunked")
-		w.Header().Set("Content-Encoding", encoding.String())
-		r.Header.Del(acceptEncoding)
-		enhancedWriter.compressor = encWriter
+		// 压缩器延迟到确定需要压缩时才创建，响应结束后归还到池中。
+		// Accept-Encoding 保留给后续处理器（如预压缩静态文件），已设置 Content-Encoding 的响应不会重复压缩。
+		enhancedWriter.encoding = encoding
+		enhancedWriter.encoders = c.encoders
+		enhancedWriter.minSize = c.compressOpt.MinSize
+		defer enhancedWriter.finish()
 		next.ServeHTTP(enhancedWriter, r)
 	})
 }
@@ -91,25 +100,72 @@ func (c *ResponseProcessor) Handler(next http.Handler) http.Handler {
 type writer struct {
 	w            http.ResponseWriter
 	r            *http.Request
-	compressor   io.Writer
 	status       int       // 状态码（如200、404）
 	bytesWritten int64     // 响应体字节数
 	createAt     time.Time // 创建时间
+
+	encoding    CompressionAlgorithm // 协商出的压缩算法，为空表示不压缩
+	encoders    *encoderPools
+	compressor  encoder // 已启用的压缩器
+	minSize     int     // 最小压缩字节数
+	buf         []byte  // 未达到最小压缩字节数前缓存的响应内容
+	pending     bool    // 响应头已由业务设置，但在等待是否压缩的判断，尚未下发
+	wroteHeader bool    // 响应头是否已下发
 }
 
 func (c *writer) WriteHeader(status int) {
+	if c.wroteHeader || c.pending {
+		return
+	}
 	c.status = status
+	if c.encoding == "" {
+		c.writeHeader()
+		return
+	}
+	h := c.w.Header()
+	if !c.compressible(status) {
+		c.encoding = ""
+		c.writeHeader()
+		return
+	}
+	if cl, err := strconv.Atoi(h.Get("Content-Length")); err == nil && cl < c.minSize {
+		c.encoding = ""
+		c.writeHeader()
+		return
+	}
+	if c.minSize <= 0 {
+		c.startCompress()
+		return
+	}
+	c.pending = true
+}
 
+// compressible 判断当前响应是否可以压缩，已经编码过的内容（如预压缩静态文件）、分段响应和无响应体的状态码不再压缩
+func (c *writer) compressible(status int) bool {
 	h := c.w.Header()
-	if c.compressor != nil {
-		if !shouldCompress(h.Get("Content-Type")) {
-			c.closeCompressor()
-		} else {
-			c.Header().Del("Content-Length")
-		}
+	if h.Get("Content-Encoding") != "" || !shouldCompress(h.Get("Content-Type")) {
+		return false
+	}
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified || status == http.StatusPartialContent {
+		return false
 	}
+	return c.r.Method != http.MethodHead
+}
+
+func (c *writer) writeHeader() {
+	c.wroteHeader = true
+	c.w.WriteHeader(c.status)
+}
 
-	c.w.WriteHeader(status)
+// startCompress 启用压缩器并下发响应头
+func (c *writer) startCompress() {
+	h := c.w.Header()
+	h.Del("Content-Length")
+	h.Del("Accept-Ranges")
+	h.Set("Content-Encoding", c.encoding.String())
+	c.compressor = c.encoders.get(c.encoding, c.w)
+	c.pending = false
+	c.writeHeader()
 }
 
 func (c *writer) Header() http.Header {
@@ -117,16 +173,61 @@ func (c *writer) Header() http.Header {
 }
 
 func (c *writer) Write(b []byte) (n int, err error) {
-	h := c.w.Header()
-	if c.compressor == nil || !shouldCompress(h.Get("Content-Type")) {
-		c.closeCompressor()
-		n, err = c.w.Write(b)
+	if !c.wroteHeader && !c.pending {
+		// 未设置 Content-Type 时先嗅探，避免标准库对压缩后的内容进行嗅探
+		if c.encoding != "" && c.w.Header().Get("Content-Type") == "" {
+			c.w.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		c.WriteHeader(http.StatusOK)
+	}
+	c.bytesWritten += int64(len(b))
+	switch {
+	case c.compressor != nil:
+		return c.compressor.Write(b)
+	case c.pending:
+		c.buf = append(c.buf, b...)
+		if len(c.buf) >= c.minSize {
+			return len(b), c.flushBuffer(true)
+		}
+		return len(b), nil
+	default:
+		return c.w.Write(b)
+	}
+}
+
+// flushBuffer 结束缓存，compress 为 true 时开启压缩写入缓存内容，否则原样输出
+func (c *writer) flushBuffer(compress bool) error {
+	if compress {
+		c.startCompress()
 	} else {
-		h.Del("Content-Length")
-		n, err = c.compressor.Write(b)
+		c.pending = false
+		c.encoding = ""
+		c.writeHeader()
+	}
+	buf := c.buf
+	c.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if c.compressor != nil {
+		_, err = c.compressor.Write(buf)
+	} else {
+		_, err = c.w.Write(buf)
+	}
+	return err
+}
+
+// finish 请求处理结束时调用，输出缓存内容并归还压缩器
+func (c *writer) finish() {
+	if c.pending {
+		_ = c.flushBuffer(false)
+	}
+	if c.compressor != nil {
+		_ = c.compressor.Close()
+		c.encoders.put(c.encoding, c.compressor)
+		c.compressor = nil
 	}
-	c.bytesWritten += int64(n)
-	return n, err
 }
 
 func (c *writer) Hijack() (net.Conn, *bufio.ReadWriter, error) {
@@ -134,14 +235,8 @@ func (c *writer) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 	if !ok {
 		return nil, nil, errors.New("ResponseWriter 不支持 Hijacker 接口")
 	}
-	// 重要：关闭并清理压缩器
-	if c.compressor != nil {
-		// 如果有压缩器，需要关闭它
-		if closer, ok := c.compressor.(io.Closer); ok {
-			vclose.Close(closer)
-		}
-		c.closeCompressor()
-	}
+	// 重要：关闭并清理压缩器，连接被接管后不再输出缓存内容
+	c.closeCompressor()
 
 	// 移除压缩相关的头部，避免客户端误解
 	c.w.Header().Del("Content-Encoding")
@@ -150,24 +245,32 @@ func (c *writer) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 	return hijacker.Hijack()
 }
 
-func (c *writer) ReadFrom(r io.Reader) (n int64, err error) {
-	h := c.w.Header()
-	if c.compressor == nil || !shouldCompress(h.Get("Content-Type")) {
-		c.closeCompressor()
-		return io.Copy(c.w, r)
-	}
-	h.Del("Content-Length")
-	return io.Copy(c.compressor, r)
+// writerOnly 屏蔽 ReadFrom，避免 io.Copy 递归调用
+type writerOnly struct {
+	io.Writer
 }
 
-type flusher interface {
-	Flush() error
+func (c *writer) ReadFrom(r io.Reader) (n int64, err error) {
+	if c.encoding == "" && c.wroteHeader {
+		// 不压缩时交给底层 ResponseWriter，以便使用 sendfile 等优化
+		n, err = io.Copy(c.w, r)
+		c.bytesWritten += n
+		return n, err
+	}
+	return io.Copy(writerOnly{c}, r)
 }
 
 func (c *writer) Flush() {
+	if !c.wroteHeader && !c.pending {
+		c.WriteHeader(http.StatusOK)
+	}
+	// 流式输出时不再等待最小压缩字节数
+	if c.pending {
+		_ = c.flushBuffer(true)
+	}
 	// Flush compressed data if compressor supports it.
-	if f, ok := c.compressor.(flusher); ok {
-		_ = f.Flush()
+	if c.compressor != nil {
+		_ = c.compressor.Flush()
 	}
 	// Flush HTTP response.
 	if f, ok := c.w.(http.Flusher); ok {
@@ -176,7 +279,11 @@ func (c *writer) Flush() {
 }
 
 func (c *writer) closeCompressor() {
-	c.w.Header().Del("Content-Encoding")
-	c.w.Header().Del("Vary")
-	c.compressor = nil
+	if c.compressor != nil {
+		c.encoders.put(c.encoding, c.compressor)
+		c.compressor = nil
+	}
+	c.encoding = ""
+	c.pending = false
+	c.buf = nil
 }
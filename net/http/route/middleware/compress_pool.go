@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// encoder 各压缩算法写入器的公共方法，Reset 后可以复用
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// encoderPools 按算法复用压缩写入器，避免每个请求都分配压缩字典和窗口
+type encoderPools struct {
+	pools map[CompressionAlgorithm]*sync.Pool
+}
+
+// newEncoderPools level 为 gzip 级别（-1~9），brotli 和 zstd 按比例换算成各自的级别
+func newEncoderPools(level int) *encoderPools {
+	p := &encoderPools{pools: make(map[CompressionAlgorithm]*sync.Pool)}
+	p.pools[Gzip] = &sync.Pool{New: func() any {
+		w, _ := gzip.NewWriterLevel(nil, level)
+		return w
+	}}
+	p.pools[Deflate] = &sync.Pool{New: func() any {
+		w, _ := flate.NewWriter(nil, level)
+		return w
+	}}
+	p.pools[Brotli] = &sync.Pool{New: func() any {
+		return brotli.NewWriterLevel(nil, brotliLevel(level))
+	}}
+	p.pools[Zstd] = &sync.Pool{New: func() any {
+		w, _ := zstd.NewWriter(nil,
+			zstd.WithEncoderLevel(zstdLevel(level)),
+			zstd.WithEncoderConcurrency(1),
+			zstd.WithLowerEncoderMem(true),
+		)
+		return w
+	}}
+	return p
+}
+
+func (p *encoderPools) get(alg CompressionAlgorithm, w io.Writer) encoder {
+	pool, ok := p.pools[alg]
+	if !ok {
+		return nil
+	}
+	enc := pool.Get().(encoder)
+	enc.Reset(w)
+	return enc
+}
+
+func (p *encoderPools) put(alg CompressionAlgorithm, enc encoder) {
+	if pool, ok := p.pools[alg]; ok {
+		enc.Reset(nil)
+		pool.Put(enc)
+	}
+}
+
+// brotliLevel 把 gzip 级别（0~9）按比例换算为 brotli 级别（0~11），9 对应 11
+// 动态响应不适合过高的 brotli 级别，默认级别使用 5；HuffmanOnly 等负数级别按 0 处理
+func brotliLevel(level int) int {
+	if level == gzip.DefaultCompression {
+		return 5
+	}
+	level = min(max(level, gzip.NoCompression), gzip.BestCompression)
+	return (level*brotli.BestCompression + gzip.BestCompression/2) / gzip.BestCompression
+}
+
+func zstdLevel(level int) zstd.EncoderLevel {
+	switch {
+	case level == gzip.DefaultCompression:
+		return zstd.SpeedDefault
+	case level <= gzip.BestSpeed:
+		return zstd.SpeedFastest
+	case level >= gzip.BestCompression:
+		return zstd.SpeedBestCompression
+	case level >= 7:
+		return zstd.SpeedBetterCompression
+	default:
+		return zstd.SpeedDefault
+	}
+}
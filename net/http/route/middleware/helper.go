@@ -1,64 +1,62 @@
 package middleware
 
 import (
-	"sort"
 	"strconv"
 	"strings"
 )
 
-type encodingWithWeight struct {
-	encoding CompressionAlgorithm
-	weight   float64
-}
-
-// parseAcceptEncoding 解析 Accept-Encoding 头
-func parseAcceptEncoding(header string) CompressionAlgorithm {
+// NegotiateEncoding 根据 Accept-Encoding 头从 offers 中选出客户端可接受且权重最高的压缩算法，
+// 权重相同时按 offers 的顺序优先。支持 q 值、q=0 表示拒绝、* 通配符，大小写不敏感。
+// offers 为空时使用默认顺序 br、zstd、gzip、deflate，没有可用算法时返回空字符串。
+func NegotiateEncoding(header string, offers ...CompressionAlgorithm) CompressionAlgorithm {
 	if header == "" {
 		return ""
 	}
-	var encodings []encodingWithWeight
-	parts := strings.Split(header, ",")
-
-	for _, part := range parts {
-		part = strings.TrimSpace(part)
-		encodingParts := strings.Split(part, ";")
-		encodingStr := strings.TrimSpace(encodingParts[0])
-		var encoding CompressionAlgorithm
-		weight := 1.0 // 默认权重
-		if encodingStr == "*" {
-			encoding = Gzip
-			weight = 0
-		} else {
-			encoding = CompressionAlgorithm(encodingStr)
-			if _, ok := supportedCompressionAlgorithms[encoding]; !ok {
-				continue
-			}
+	if len(offers) == 0 {
+		offers = defaultCompressionOrder
+	}
+	weights := make(map[CompressionAlgorithm]float64)
+	wildcard := -1.0
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
 		}
-
-		// 解析 q 值
-		for i := 1; i < len(encodingParts); i++ {
-			param := strings.TrimSpace(encodingParts[i])
-			if strings.HasPrefix(param, "q=") {
-				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+		weight := 1.0 // 默认权重
+		for _, param := range strings.Split(params, ";") {
+			param = strings.TrimSpace(param)
+			if len(param) > 2 && (param[0] == 'q' || param[0] == 'Q') && param[1] == '=' {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil && q >= 0 && q <= 1 {
 					weight = q
 				}
 			}
 		}
-
-		encodings = append(encodings, encodingWithWeight{
-			encoding: encoding,
-			weight:   weight,
-		})
+		if name == "*" {
+			wildcard = weight
+			continue
+		}
+		weights[CompressionAlgorithm(name)] = weight
 	}
 
-	// 按权重排序（从高到低）
-	sort.Slice(encodings, func(i, j int) bool {
-		return encodings[i].weight > encodings[j].weight
-	})
-	if len(encodings) > 0 {
-		return encodings[0].encoding
+	var (
+		best       CompressionAlgorithm
+		bestWeight float64
+	)
+	for _, offer := range offers {
+		weight, ok := weights[offer]
+		if !ok {
+			// 未显式列出的算法使用 * 的权重
+			if wildcard < 0 {
+				continue
+			}
+			weight = wildcard
+		}
+		if weight > bestWeight {
+			best, bestWeight = offer, weight
+		}
 	}
-	return ""
+	return best
 }
 
 func shouldCompress(contentType string) bool {
@@ -16,13 +16,19 @@ const (
 	Gzip    CompressionAlgorithm = "gzip"    // 最广泛兼容
 	Deflate CompressionAlgorithm = "deflate" // 基本支持
 	Brotli  CompressionAlgorithm = "br"      // 最佳压缩比和性能平衡
+	Zstd    CompressionAlgorithm = "zstd"    // 压缩速度快，压缩比接近 br
 )
 
 var supportedCompressionAlgorithms = map[CompressionAlgorithm]struct{}{
 	Gzip:    struct{}{},
 	Deflate: struct{}{},
+	Brotli:  struct{}{},
+	Zstd:    struct{}{},
 }
 
+// 默认的服务端优先顺序，客户端权重相同时靠前的优先
+var defaultCompressionOrder = []CompressionAlgorithm{Brotli, Zstd, Gzip, Deflate}
+
 const acceptEncoding string = "Accept-Encoding"
 
 // 需要排除的压缩类型
@@ -44,6 +44,9 @@ type Config struct {
 	// 就可以通过这个参数来去掉请求 path部分中的前面这部分内容。
 	// 用最后部分去匹配文件系统。
 	URLPrefix string `json:"url_prefix" yaml:"url_prefix"` // 路由前缀
+
+	// 启用后优先查找同目录下预压缩的 .br、.zst、.gz 文件，按 Accept-Encoding 协商后直接输出
+	Precompressed bool `json:"precompressed" yaml:"precompressed"`
 }
 
 type ErrorResp struct {
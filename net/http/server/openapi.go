@@ -0,0 +1,440 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"helay.net/go/utils/v3/logger/ulogs"
+	"helay.net/go/utils/v3/net/http/response"
+	"helay.net/go/utils/v3/tools"
+)
+
+const openAPIVersion = "3.1.0"
+
+type OpenAPIConfig struct {
+	Enable      bool     `ini:"enable" json:"enable" yaml:"enable"`                      // 是否启用文档路由
+	Path        string   `ini:"path" json:"path" yaml:"path"`                            // 文档 json 路径，默认 /openapi.json
+	UIPath      string   `ini:"ui_path" json:"ui_path" yaml:"ui_path"`                   // 文档页面路径，默认 /docs
+	UI          string   `ini:"ui" json:"ui" yaml:"ui" valid:"oneof=swagger redoc none"` // 文档页面类型 swagger、redoc、none，默认 swagger
+	UIAssets    string   `ini:"ui_assets" json:"ui_assets" yaml:"ui_assets"`             // 页面静态资源地址，导入 openapi_ui 包后默认使用内置的资源（UIPath/assets），否则使用 CDN
+	Title       string   `ini:"title" json:"title" yaml:"title"`                         // 文档标题
+	Version     string   `ini:"version" json:"version" yaml:"version"`                   // 接口版本
+	Description string   `ini:"description" json:"description" yaml:"description"`       // 文档说明
+	Servers     []string `ini:"servers" json:"servers" yaml:"servers"`                   // 服务地址
+}
+
+const (
+	OpenAPIUISwagger = "swagger"
+	OpenAPIUIRedoc   = "redoc"
+	OpenAPIUINone    = "none"
+)
+
+// OpenAPIDocument OpenAPI 3.1 文档
+type OpenAPIDocument struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       OpenAPIInfo                      `json:"info"`
+	Servers    []OpenAPIServer                  `json:"servers,omitempty"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components OpenAPIComponents                `json:"components,omitempty"`
+}
+
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type OpenAPIServer struct {
+	URL string `json:"url"`
+}
+
+type OpenAPIComponents struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty"`
+}
+
+type Operation struct {
+	OperationID string               `json:"operationId,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Deprecated  bool                 `json:"deprecated,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"` // path、query
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// OpenAPI 根据已注册的路由生成文档，websocket 路由不会输出。
+// 请求结构来自 Typed 包装的处理器，或者 Description 的 Query、Request、Response 字段，Description 中的字段优先。
+func (s *Server[T]) OpenAPI() *OpenAPIDocument {
+	cfg := s.opt.OpenAPI
+	doc := &OpenAPIDocument{
+		OpenAPI: openAPIVersion,
+		Info: OpenAPIInfo{
+			Title:       tools.Ternary(cfg.Title == "", "API", cfg.Title),
+			Version:     tools.Ternary(cfg.Version == "", "1.0.0", cfg.Version),
+			Description: cfg.Description,
+		},
+		Paths: make(map[string]map[string]*Operation),
+	}
+	for _, u := range cfg.Servers {
+		doc.Servers = append(doc.Servers, OpenAPIServer{URL: u})
+	}
+	b := newSchemaBuilder()
+	opIds := make(map[string]struct{})
+	for _, route := range s.routes {
+		if route.routeType != RouteTypeHTTP || route.method == "" {
+			continue
+		}
+		p, params := openAPIPath(route.path)
+		if p == "" {
+			continue
+		}
+		op := buildOperation(b, route.method, p, params, route.handle, route.description)
+		op.OperationID = uniqueOperationID(opIds, route.method, p, route.description.Code)
+		if doc.Paths[p] == nil {
+			doc.Paths[p] = make(map[string]*Operation)
+		}
+		doc.Paths[p][strings.ToLower(route.method)] = op
+	}
+	if len(b.components) > 0 {
+		doc.Components.Schemas = b.components
+	}
+	return doc
+}
+
+var pathParamRe = regexp.MustCompile(`\{([^}]*)\}`)
+
+// openAPIPath 将路由转换为 OpenAPI 路径格式，{*name}、{name...} 统一为 {name}，{$} 去掉
+func openAPIPath(p string) (string, []string) {
+	p, err := tools.ToBraceSyntax(p)
+	if err != nil {
+		return "", nil
+	}
+	// 路由可能带有 host 部分，如 example.com/users
+	if i := strings.Index(p, "/"); i > 0 {
+		p = p[i:]
+	}
+	var params []string
+	p = pathParamRe.ReplaceAllStringFunc(p, func(m string) string {
+		name := strings.TrimSuffix(strings.TrimPrefix(m[1:len(m)-1], "*"), "...")
+		if name == "$" || name == "" {
+			return ""
+		}
+		params = append(params, name)
+		return "{" + name + "}"
+	})
+	return p, params
+}
+
+func uniqueOperationID(used map[string]struct{}, method, p, code string) string {
+	id := code
+	if id == "" {
+		id = strings.ToLower(method) + "_" + strings.Trim(nameRe.ReplaceAllString(p, "_"), "_")
+	}
+	if _, ok := used[id]; ok {
+		id = id + "_" + strings.ToLower(method)
+	}
+	used[id] = struct{}{}
+	return id
+}
+
+func buildOperation[T any](b *schemaBuilder, method, p string, params []string, handle http.Handler, desc Description[T]) *Operation {
+	op := &Operation{
+		Summary:    tools.Ternary(desc.Summary == "", desc.Name, desc.Summary),
+		Tags:       desc.Tags,
+		Deprecated: desc.Deprecated,
+		Responses:  make(map[string]*Response),
+	}
+	if len(op.Tags) < 1 {
+		if seg := strings.Split(strings.Trim(p, "/"), "/")[0]; seg != "" && !strings.HasPrefix(seg, "{") {
+			op.Tags = []string{seg}
+		}
+	}
+
+	var reqType, respType reflect.Type
+	wrapped := false
+	if typed, ok := handle.(typedOperation); ok {
+		reqType, respType = typed.openAPITypes()
+		wrapped = true
+	}
+	queryType := typeOf(desc.Query)
+	bodyType := typeOf(desc.Request)
+	if reqType != nil {
+		if bodyMethod(method) {
+			bodyType = tools.Ternary(bodyType == nil, reqType, bodyType)
+		} else {
+			queryType = tools.Ternary(queryType == nil, reqType, queryType)
+		}
+	}
+	if desc.Response != nil {
+		respType = typeOf(desc.Response)
+		wrapped = false
+	}
+
+	// 路径参数，优先使用结构体中 path tag 字段的类型
+	pathTypes := pathFieldTypes(tools.Ternary(reqType != nil, reqType, bodyType))
+	for _, name := range params {
+		ps := &Schema{Type: "string"}
+		if t, ok := pathTypes[name]; ok {
+			ps = b.schema(t)
+		}
+		op.Parameters = append(op.Parameters, &Parameter{Name: name, In: "path", Required: true, Schema: ps})
+	}
+	op.Parameters = append(op.Parameters, queryParameters(b, queryType)...)
+
+	if bodyType != nil && bodyMethod(method) {
+		contentType := tools.Ternary(desc.ContentType == "", "application/json", desc.ContentType)
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{contentType: {Schema: b.schema(bodyType)}},
+		}
+	}
+
+	ok := &Response{Description: "成功"}
+	if respType != nil {
+		rs := b.schema(respType)
+		if wrapped {
+			rs = envelopeSchema(rs)
+		}
+		ok.Content = map[string]*MediaType{"application/json": {Schema: rs}}
+	}
+	op.Responses["200"] = ok
+	if wrapped {
+		op.Responses["default"] = &Response{
+			Description: "失败",
+			Content:     map[string]*MediaType{"application/json": {Schema: envelopeSchema(nil)}},
+		}
+	}
+	return op
+}
+
+// envelopeSchema 通用响应结构 response.SetReturnData 的 schema
+func envelopeSchema(data *Schema) *Schema {
+	s := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"code": {Type: "integer", Description: "0 表示成功"},
+			"msg":  {Type: "string"},
+		},
+		Required: []string{"code"},
+	}
+	if data != nil {
+		s.Properties["data"] = data
+	}
+	return s
+}
+
+func pathFieldTypes(t reflect.Type) map[string]reflect.Type {
+	t = derefType(t)
+	res := make(map[string]reflect.Type)
+	if t == nil || t.Kind() != reflect.Struct {
+		return res
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if name := t.Field(i).Tag.Get("path"); name != "" {
+			res[name] = t.Field(i).Type
+		}
+	}
+	return res
+}
+
+// queryParameters 按 request.QueryDecode 的规则生成 query 参数，跳过带 path tag 的字段
+func queryParameters(b *schemaBuilder, t reflect.Type) []*Parameter {
+	t = derefType(t)
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	var params []*Parameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("path") != "" {
+			continue
+		}
+		tag := field.Tag.Get("query")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && derefType(field.Type).Kind() == reflect.Struct {
+			params = append(params, queryParameters(b, field.Type)...)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		params = append(params, &Parameter{
+			Name:        name,
+			In:          "query",
+			Description: field.Tag.Get("description"),
+			Required:    strings.Contains(","+opts+",", ",required,"),
+			Schema:      b.schema(field.Type),
+		})
+	}
+	return params
+}
+
+// setOpenAPIRoutes 注册文档路由，文档在此时生成一次，之后注册的路由不会出现在文档中
+func (s *Server[T]) setOpenAPIRoutes() {
+	cfg := s.opt.OpenAPI
+	if !cfg.Enable {
+		return
+	}
+	specPath := tools.Ternary(cfg.Path == "", "/openapi.json", cfg.Path)
+	spec, err := json.Marshal(s.OpenAPI())
+	if err != nil {
+		ulogs.Error("OpenAPI 文档生成失败", err)
+		return
+	}
+	s.AddRoute(http.MethodGet, specPath, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response.RespJson(w)
+		_, _ = w.Write(spec)
+	}))
+
+	ui := tools.Ternary(cfg.UI == "", OpenAPIUISwagger, cfg.UI)
+	if ui == OpenAPIUINone {
+		return
+	}
+	uiPath := tools.Ternary(cfg.UIPath == "", "/docs", cfg.UIPath)
+	page, err := renderOpenAPIUI(ui, cfg, specPath, uiPath)
+	if err != nil {
+		ulogs.Error("OpenAPI 文档页面生成失败", err)
+		return
+	}
+	s.AddRoute(http.MethodGet, uiPath, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response.RespHtml(w)
+		_, _ = w.Write(page)
+	}))
+	if cfg.UIAssets == "" && openAPIAssets != nil {
+		s.setOpenAPIAssets(ui, uiPath)
+	}
+}
+
+// openAPIAssets 内置的页面静态资源，按页面类型分目录存放，由 openapi_ui 包注册
+var openAPIAssets fs.FS
+
+// RegisterOpenAPIAssets 注册内置的页面静态资源，fsys 中按页面类型分目录存放，如 swagger/swagger-ui.css。
+// 资源体积较大，默认不编译进程序，导入 openapi_ui 包时自动注册：
+//
+//	import _ "helay.net/go/utils/v3/net/http/server/openapi_ui"
+func RegisterOpenAPIAssets(fsys fs.FS) {
+	openAPIAssets = fsys
+}
+
+// setOpenAPIAssets 注册内置的页面静态资源，离线和内网环境也可以使用
+func (s *Server[T]) setOpenAPIAssets(ui, uiPath string) {
+	entries, err := fs.ReadDir(openAPIAssets, ui)
+	if err != nil {
+		ulogs.Error("OpenAPI 文档页面资源载入失败", err)
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		byt, err := fs.ReadFile(openAPIAssets, path.Join(ui, entry.Name()))
+		if err != nil {
+			ulogs.Error("OpenAPI 文档页面资源载入失败", entry.Name(), err)
+			continue
+		}
+		contentType := mime.TypeByExtension(path.Ext(entry.Name()))
+		s.AddRoute(http.MethodGet, openAPIAssetsPath(uiPath)+"/"+entry.Name(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", contentType)
+			w.Header().Set("Cache-Control", "public, max-age=86400")
+			_, _ = w.Write(byt)
+		}))
+	}
+}
+
+// openAPIAssetsPath 内置页面静态资源的路径
+func openAPIAssetsPath(uiPath string) string {
+	return strings.TrimRight(uiPath, "/") + "/assets"
+}
+
+// openAPIUICDN 未注册内置资源、也没有配置 UIAssets 时使用的 CDN 地址
+var openAPIUICDN = map[string]string{
+	OpenAPIUISwagger: "https://cdn.jsdelivr.net/npm/swagger-ui-dist@5",
+	OpenAPIUIRedoc:   "https://cdn.jsdelivr.net/npm/redoc@2/bundles",
+}
+
+var openAPIUITemplates = map[string]*template.Template{
+	OpenAPIUISwagger: template.Must(template.New(OpenAPIUISwagger).Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<link rel="stylesheet" href="{{.Assets}}/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="{{.Assets}}/swagger-ui-bundle.js"></script>
+<script>
+window.onload = function () {
+  window.ui = SwaggerUIBundle({url: {{.Spec}}, dom_id: "#swagger-ui", deepLinking: true});
+};
+</script>
+</body>
+</html>`)),
+	OpenAPIUIRedoc: template.Must(template.New(OpenAPIUIRedoc).Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+</head>
+<body>
+<redoc spec-url="{{.Spec}}"></redoc>
+<script src="{{.Assets}}/redoc.standalone.js"></script>
+</body>
+</html>`)),
+}
+
+func renderOpenAPIUI(ui string, cfg OpenAPIConfig, specPath, uiPath string) ([]byte, error) {
+	t, ok := openAPIUITemplates[ui]
+	if !ok {
+		return nil, fmt.Errorf("不支持的文档页面类型 %s", ui)
+	}
+	assets := cfg.UIAssets
+	if assets == "" {
+		assets = tools.Ternary(openAPIAssets == nil, openAPIUICDN[ui], openAPIAssetsPath(uiPath))
+	}
+	var buf strings.Builder
+	err := t.Execute(&buf, map[string]string{
+		"Title":  tools.Ternary(cfg.Title == "", "API", cfg.Title),
+		"Assets": strings.TrimRight(assets, "/"),
+		"Spec":   specPath,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []byte(buf.String()), nil
+}
@@ -0,0 +1,249 @@
+package server
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"helay.net/go/utils/v3/dataType"
+)
+
+// Schema OpenAPI 3.1 schema 对象，只保留常用字段
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Example              any                `json:"example,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+}
+
+var (
+	customSchemaMu sync.RWMutex
+	customSchemas  = map[reflect.Type]*Schema{
+		reflect.TypeOf(time.Time{}):            {Type: "string", Format: "date-time"},
+		reflect.TypeOf(time.Duration(0)):       {Type: "integer", Format: "int64"},
+		reflect.TypeOf(dataType.CustomTime{}):  {Type: "string", Example: time.DateTime},
+		reflect.TypeOf(dataType.CustomDate{}):  {Type: "string", Format: "date"},
+		reflect.TypeOf(dataType.JSONMap{}):     {Type: "object"},
+		reflect.TypeOf(json.RawMessage{}):      {},
+		reflect.TypeOf(dataType.DynamicTime{}): {Type: "string"},
+		reflect.TypeOf(json.Number("")):        {Type: "number"},
+		reflect.TypeOf((*any)(nil)).Elem():     {},
+		reflect.TypeOf((*error)(nil)).Elem():   {Type: "string"},
+		reflect.TypeOf(struct{}{}):             {Type: "object"},
+		reflect.TypeOf(map[string]any{}):       {Type: "object"},
+		reflect.TypeOf([]byte{}):               {Type: "string", Format: "byte"},
+		reflect.TypeOf(dataType.Binary{}):      {Type: "string", Format: "byte"},
+	}
+)
+
+// RegisterSchema 为自定义序列化的类型注册固定 schema，v 传入该类型的零值即可。
+// 例如实现了 json.Marshaler 的时间类型，反射得到的结构与实际输出不一致时使用。
+func RegisterSchema(v any, schema Schema) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil {
+		return
+	}
+	customSchemaMu.Lock()
+	defer customSchemaMu.Unlock()
+	customSchemas[t] = &schema
+}
+
+func lookupCustomSchema(t reflect.Type) (*Schema, bool) {
+	customSchemaMu.RLock()
+	defer customSchemaMu.RUnlock()
+	s, ok := customSchemas[t]
+	if !ok {
+		return nil, false
+	}
+	cp := *s
+	return &cp, true
+}
+
+// schemaBuilder 通过反射生成 schema，结构体统一放入 components 中以 $ref 引用
+type schemaBuilder struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+	used       map[string]reflect.Type
+}
+
+func newSchemaBuilder() *schemaBuilder {
+	return &schemaBuilder{
+		components: make(map[string]*Schema),
+		names:      make(map[reflect.Type]string),
+		used:       make(map[string]reflect.Type),
+	}
+}
+
+// typeOf 支持传入值或者 reflect.Type
+func typeOf(v any) reflect.Type {
+	if v == nil {
+		return nil
+	}
+	if t, ok := v.(reflect.Type); ok {
+		return t
+	}
+	return reflect.TypeOf(v)
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+func (b *schemaBuilder) schema(t reflect.Type) *Schema {
+	t = derefType(t)
+	if t == nil {
+		return &Schema{}
+	}
+	if s, ok := lookupCustomSchema(t); ok {
+		return s
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		zero := 0.0
+		return &Schema{Type: "integer", Minimum: &zero}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: b.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.schema(t.Elem())}
+	case reflect.Struct:
+		return b.structRef(t)
+	default:
+		// interface、func、chan 等无法描述的类型
+		return &Schema{}
+	}
+}
+
+// structRef 注册结构体到 components 并返回引用，先占位再填充字段以支持递归结构
+func (b *schemaBuilder) structRef(t reflect.Type) *Schema {
+	if t.Name() == "" {
+		return b.structSchema(t)
+	}
+	if name, ok := b.names[t]; ok {
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+	name := b.componentName(t)
+	b.names[t] = name
+	b.used[name] = t
+	b.components[name] = &Schema{Type: "object"}
+	b.components[name] = b.structSchema(t)
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+var pkgPathRe = regexp.MustCompile(`[\w.\-]*/`)
+var nameRe = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// componentName 生成组件名称，泛型参数中的包路径去掉，同名不同包时加上包名前缀
+func (b *schemaBuilder) componentName(t reflect.Type) string {
+	name := pkgPathRe.ReplaceAllString(t.Name(), "")
+	name = strings.Trim(nameRe.ReplaceAllString(name, "_"), "_")
+	if exist, ok := b.used[name]; !ok || exist == t {
+		return name
+	}
+	pkg := t.PkgPath()
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+	base := nameRe.ReplaceAllString(pkg, "_") + "_" + name
+	name = base
+	for i := 2; ; i++ {
+		if _, ok := b.used[name]; !ok {
+			return name
+		}
+		name = base + "_" + strconv.Itoa(i)
+	}
+}
+
+func (b *schemaBuilder) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	b.collectFields(t, s)
+	return s
+}
+
+// collectFields 按 encoding/json 的规则收集字段，匿名嵌入且未指定名称的结构体会被展开
+func (b *schemaBuilder) collectFields(t reflect.Type, s *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		ft := derefType(field.Type)
+		if field.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			if _, ok := lookupCustomSchema(ft); !ok {
+				b.collectFields(ft, s)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		var fs *Schema
+		if strings.Contains(","+opts+",", ",string,") {
+			fs = &Schema{Type: "string"}
+		} else {
+			fs = b.schema(field.Type)
+		}
+		fs = applyFieldTags(fs, field)
+		s.Properties[name] = fs
+		if !strings.Contains(","+opts+",", ",omitempty,") && !strings.Contains(","+opts+",", ",omitzero,") && field.Type.Kind() != reflect.Pointer {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// applyFieldTags 读取 description、example、enum tag 补充字段说明
+func applyFieldTags(fs *Schema, field reflect.StructField) *Schema {
+	desc := field.Tag.Get("description")
+	example := field.Tag.Get("example")
+	enum := field.Tag.Get("enum")
+	if desc == "" && example == "" && enum == "" {
+		return fs
+	}
+	// OpenAPI 3.1 允许 $ref 与 description 同级
+	fs.Description = desc
+	if example != "" {
+		fs.Example = example
+	}
+	if enum != "" {
+		for _, e := range strings.Split(enum, ",") {
+			fs.Enum = append(fs.Enum, strings.TrimSpace(e))
+		}
+	}
+	return fs
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+type testUserReq struct {
+	ID   int    `path:"id" json:"-"`
+	Name string `json:"name" description:"姓名"`
+}
+
+type testUserResp struct {
+	ID   int    `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+type testSearchQuery struct {
+	Keyword string `query:"keyword,required"`
+	Page    int    `query:"page"`
+}
+
+func newTestServer() *Server[any] {
+	return &Server[any]{opt: &Config{}, routesMap: make(map[string]struct{})}
+}
+
+func TestOpenAPITypedRoute(t *testing.T) {
+	s := newTestServer()
+	s.AddRoute(http.MethodPut, "/users/{id}", Typed(func(r *http.Request, req testUserReq) (testUserResp, error) {
+		return testUserResp{ID: req.ID, Name: req.Name}, nil
+	}))
+	s.AddRoute(http.MethodGet, "/search", Typed(func(r *http.Request, q testSearchQuery) ([]testUserResp, error) {
+		return nil, nil
+	}))
+	doc := s.OpenAPI()
+
+	put := doc.Paths["/users/{id}"]["put"]
+	if put == nil {
+		t.Fatalf("paths = %v", doc.Paths)
+	}
+	if put.OperationID != "put_users_id" || !slices.Equal(put.Tags, []string{"users"}) {
+		t.Fatalf("operationId = %q, tags = %v", put.OperationID, put.Tags)
+	}
+	if len(put.Parameters) != 1 {
+		t.Fatalf("parameters = %v", put.Parameters)
+	}
+	if p := put.Parameters[0]; p.Name != "id" || p.In != "path" || !p.Required || p.Schema.Type != "integer" {
+		t.Fatalf("path parameter = %+v", p)
+	}
+	if put.RequestBody == nil || put.RequestBody.Content["application/json"].Schema.Ref != "#/components/schemas/testUserReq" {
+		t.Fatalf("request body = %+v", put.RequestBody)
+	}
+	ok := put.Responses["200"].Content["application/json"].Schema
+	if ok.Properties["data"].Ref != "#/components/schemas/testUserResp" || put.Responses["default"] == nil {
+		t.Fatalf("responses = %+v", put.Responses)
+	}
+
+	req := doc.Components.Schemas["testUserReq"]
+	if _, exist := req.Properties["ID"]; exist || req.Properties["name"].Description != "姓名" {
+		t.Fatalf("testUserReq = %+v", req)
+	}
+	resp := doc.Components.Schemas["testUserResp"]
+	if !slices.Equal(resp.Required, []string{"id"}) {
+		t.Fatalf("testUserResp required = %v", resp.Required)
+	}
+
+	get := doc.Paths["/search"]["get"]
+	if get == nil || get.RequestBody != nil || len(get.Parameters) != 2 {
+		t.Fatalf("search = %+v", get)
+	}
+	if p := get.Parameters[0]; p.Name != "keyword" || p.In != "query" || !p.Required {
+		t.Fatalf("query parameter = %+v", p)
+	}
+	if data := get.Responses["200"].Content["application/json"].Schema.Properties["data"]; data.Type != "array" || data.Items.Ref != "#/components/schemas/testUserResp" {
+		t.Fatalf("search response data = %+v", data)
+	}
+}
+
+func TestOpenAPIUIAssets(t *testing.T) {
+	defer RegisterOpenAPIAssets(nil)
+	cfg := OpenAPIConfig{Enable: true}
+
+	// 未注册内置资源时使用 CDN
+	s := newTestServer()
+	s.opt.OpenAPI = cfg
+	s.setOpenAPIRoutes()
+	if len(s.routes) != 2 {
+		t.Fatalf("routes = %d, want spec and page only", len(s.routes))
+	}
+	page, _ := renderOpenAPIUI(OpenAPIUISwagger, cfg, "/openapi.json", "/docs")
+	if !strings.Contains(string(page), openAPIUICDN[OpenAPIUISwagger]+"/swagger-ui.css") {
+		t.Fatalf("page without assets = %s", page)
+	}
+
+	RegisterOpenAPIAssets(fstest.MapFS{
+		"swagger/swagger-ui.css":       {Data: []byte("css")},
+		"swagger/swagger-ui-bundle.js": {Data: []byte("js")},
+	})
+	s = newTestServer()
+	s.opt.OpenAPI = cfg
+	s.setOpenAPIRoutes()
+	var paths []string
+	for _, r := range s.routes {
+		paths = append(paths, r.path)
+	}
+	if !slices.Contains(paths, "/docs/assets/swagger-ui.css") || !slices.Contains(paths, "/docs/assets/swagger-ui-bundle.js") {
+		t.Fatalf("routes = %v", paths)
+	}
+	page, _ = renderOpenAPIUI(OpenAPIUISwagger, cfg, "/openapi.json", "/docs")
+	if !strings.Contains(string(page), `href="/docs/assets/swagger-ui.css"`) {
+		t.Fatalf("page with assets = %s", page)
+	}
+}
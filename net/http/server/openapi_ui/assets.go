@@ -0,0 +1,20 @@
+// Package openapi_ui 内置的 OpenAPI 文档页面静态资源（swagger-ui、redoc），约 2.5MB。
+// 离线和内网环境需要文档页面时导入该包，文档页面改为使用内置的资源：
+//
+//	import _ "helay.net/go/utils/v3/net/http/server/openapi_ui"
+//
+// 未导入时文档页面从 CDN 加载资源，也可以通过 OpenAPIConfig.UIAssets 指定自建的地址。
+package openapi_ui
+
+import (
+	"embed"
+
+	"helay.net/go/utils/v3/net/http/server"
+)
+
+//go:embed swagger redoc
+var assets embed.FS
+
+func init() {
+	server.RegisterOpenAPIAssets(assets)
+}
@@ -17,6 +17,7 @@ func (s *Server[T]) setRoutes() {
 	now := time.Now()
 	ulogs.Infof("开始http router初始化 ")
 	defer ulogs.Infof("完成http router初始化，耗时:%v", time.Since(now))
+	s.setOpenAPIRoutes()
 	s.setDebugRoutes()
 	for _, route := range s.routes {
 		switch route.routeType {
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"helay.net/go/utils/v3/net/http/request"
+	"helay.net/go/utils/v3/net/http/response"
+	"helay.net/go/utils/v3/tools/decode/json_decode_tee"
+)
+
+// StatusCoder 业务错误可以实现该接口指定响应状态码，否则统一按 500 处理
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// TypedFunc 类型化的处理函数，请求参数已解析完成，返回值按通用响应结构输出
+type TypedFunc[Req, Resp any] func(r *http.Request, req Req) (Resp, error)
+
+// typedOperation 类型化处理器向 OpenAPI 生成器暴露的请求和响应类型
+type typedOperation interface {
+	openAPITypes() (req, resp reflect.Type)
+}
+
+type typedHandler[Req, Resp any] struct {
+	fn TypedFunc[Req, Resp]
+}
+
+// Typed 将类型化函数包装为 http.Handler，注册后会自动推导 OpenAPI 的请求和响应结构。
+// GET、HEAD、DELETE 请求从 query 解析（query tag），其余请求按 Content-Type 解析 json 或 form 表单（form tag）。
+// 带有 path tag 的字段会从路由参数中填充。
+// 成功时响应 {"code":0,"msg":"成功","data":Resp}。
+func Typed[Req, Resp any](fn TypedFunc[Req, Resp]) http.Handler {
+	return &typedHandler[Req, Resp]{fn: fn}
+}
+
+func (h *typedHandler[Req, Resp]) openAPITypes() (req, resp reflect.Type) {
+	return reflect.TypeFor[Req](), reflect.TypeFor[Resp]()
+}
+
+func (h *typedHandler[Req, Resp]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req Req
+	if err := decodeTyped(r, &req); err != nil {
+		response.SetReturnError(w, r, err, http.StatusBadRequest, "参数解析失败")
+		return
+	}
+	resp, err := h.fn(r, req)
+	if err != nil {
+		code := http.StatusInternalServerError
+		var sc StatusCoder
+		if errors.As(err, &sc) {
+			code = sc.StatusCode()
+		}
+		response.SetReturnError(w, r, err, code)
+		return
+	}
+	response.SetReturnData(w, 0, "成功", resp)
+}
+
+func decodeTyped[Req any](r *http.Request, dst *Req) error {
+	if bodyMethod(r.Method) && !strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		if err := json_decode_tee.JsonDecode(r.Body, dst); err != nil {
+			return err
+		}
+	}
+	// Req 为指针类型时需要先初始化
+	rv := reflect.ValueOf(dst).Elem()
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	if !bodyMethod(r.Method) {
+		if err := request.QueryDecode(r.URL.Query(), rv.Addr().Interface()); err != nil {
+			return err
+		}
+	} else if strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		if err := r.ParseForm(); err != nil {
+			return err
+		}
+		if err := request.FormDecode(r, rv.Addr().Interface()); err != nil {
+			return err
+		}
+	}
+	return setPathValues(r, rv)
+}
+
+func bodyMethod(method string) bool {
+	return method != http.MethodGet && method != http.MethodHead && method != http.MethodDelete
+}
+
+// setPathValues 根据 path tag 填充路由参数，仅支持字符串、整数、浮点和布尔类型
+func setPathValues(r *http.Request, rv reflect.Value) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name := field.Tag.Get("path")
+		if name == "" || !field.IsExported() {
+			continue
+		}
+		val := r.PathValue(name)
+		if val == "" {
+			continue
+		}
+		fv := rv.Field(i)
+		switch fv.Kind() {
+		case reflect.String:
+			fv.SetString(val)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n, err := strconv.ParseInt(val, 10, 64)
+			if err != nil {
+				return err
+			}
+			fv.SetInt(n)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			n, err := strconv.ParseUint(val, 10, 64)
+			if err != nil {
+				return err
+			}
+			fv.SetUint(n)
+		case reflect.Float32, reflect.Float64:
+			n, err := strconv.ParseFloat(val, 64)
+			if err != nil {
+				return err
+			}
+			fv.SetFloat(n)
+		case reflect.Bool:
+			b, err := strconv.ParseBool(val)
+			if err != nil {
+				return err
+			}
+			fv.SetBool(b)
+		}
+	}
+	return nil
+}
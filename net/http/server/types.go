@@ -17,8 +17,8 @@ import (
 const Version = "vs/2.1"
 
 type Config struct {
-	Addr                         string        `json:"addr" yaml:"addr"`                                                       // 监听地址
-	Port                         int           `json:"port" yaml:"port"`                                                       //用于 Alt-Svc 响应头中的端口,用于防火墙重定向等场景，允许客户端使用与服务器监听端口不同的端口
+	Addr                         string        `json:"addr" yaml:"addr" valid:"hostport"`                                      // 监听地址
+	Port                         int           `json:"port" yaml:"port" valid:"max=65535"`                                     //用于 Alt-Svc 响应头中的端口,用于防火墙重定向等场景，允许客户端使用与服务器监听端口不同的端口
 	DisableGeneralOptionsHandler bool          `json:"disable_general_options_handler" yaml:"disable_general_options_handler"` // 如果为 true，将 "OPTIONS *" 请求传递给 Handler；否则自动响应 200 O
 	ReadTimeout                  time.Duration `json:"read_timeout" yaml:"read_timeout"`                                       // 读取超时，零或负值表示无超时
 	ReadHeaderTimeout            time.Duration `json:"read_header_timeout" yaml:"read_header_timeout"`                         // 读取请求头超时，零或负值表示无超时
@@ -39,6 +39,8 @@ type Config struct {
 	Logger      zaploger.Config              `json:"logger" yaml:"logger"`                    // 日志配置
 
 	Route route.Config `json:"route" yaml:"route"` // 路由配置
+
+	OpenAPI OpenAPIConfig `json:"openapi" yaml:"openapi"` // OpenAPI 文档配置
 }
 
 type SecurityConfig struct {
@@ -97,6 +99,15 @@ type Description[T any] struct {
 	Path     string
 	Code     string // 路由编码
 	Metadata *T     // 自定义描述结构
+
+	// 以下字段用于生成 OpenAPI 文档
+	Summary     string   // 接口摘要，为空时使用 Name
+	Tags        []string // 接口分组，为空时使用路径第一段
+	Deprecated  bool     // 是否已废弃
+	Query       any      // query 参数结构体，如 UserQuery{}，使用 query tag
+	Request     any      // 请求体结构体，如 UserReq{}，使用 json tag
+	Response    any      // 响应体结构体，按原样生成 200 响应
+	ContentType string   // 请求体类型，默认 application/json
 }
 
 func NewDesc[T any](key, code string, meta *T) Description[T] {
@@ -62,11 +62,29 @@ func NewIPMatcher(ctx context.Context, config *Config) (*IPMatcher, error) {
 	return m, nil
 }
 
-func (m *IPMatcher) LoadRule() error {
-	if m.config.Dynamic {
-		m.mu.Lock()
-		defer m.mu.Unlock()
+// Reload 使用新配置重新构建规则，构建成功后整体替换，失败时保留原有规则。
+// 热更新需要读写锁保护，所以新旧配置都必须开启 Dynamic；只替换规则，缓存时间等参数仍使用创建时的配置。
+func (m *IPMatcher) Reload(config *Config) error {
+	if !m.config.Dynamic || !config.Dynamic {
+		return fmt.Errorf("IP匹配器热更新需要开启 dynamic")
+	}
+	nm := &IPMatcher{config: config, temp: newIPTemp()}
+	if err := nm.LoadRule(); err != nil {
+		return err
 	}
+	nm.Build()
+
+	m.mu.Lock()
+	m.storage = nm.storage
+	m.enable = nm.enable
+	m.mu.Unlock()
+	m.ipv4Cache.DeleteAll()
+	m.ipv6Cache.DeleteAll()
+	return nil
+}
+
+// LoadRule 加载配置中的规则，动态模式下由 AddIPv4Rule、AddIPv6Rule 各自加锁，这里不能重复加锁
+func (m *IPMatcher) LoadRule() error {
 	ipv4RuleSet := m.config.IPv4RuleSet
 	ipv6RuleSet := m.config.IPv6RuleSet
 
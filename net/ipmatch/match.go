@@ -7,13 +7,13 @@ import (
 )
 
 func (m *IPMatcher) Contains(ip string) bool {
-	if !m.enable {
-		return true
-	}
 	if m.config.Dynamic {
 		m.mu.RLock()
 		defer m.mu.RUnlock()
 	}
+	if !m.enable {
+		return true
+	}
 	// 使用 netip 解析IP地址
 	addr, err := netip.ParseAddr(ip)
 	if err != nil {
@@ -0,0 +1,70 @@
+package validator_test
+
+import (
+	"testing"
+
+	"helay.net/go/utils/v3/rule-engine/validator"
+	"helay.net/go/utils/v3/rule-engine/validator/types"
+)
+
+func TestAdvancedOperators(t *testing.T) {
+	tests := []struct {
+		name     string
+		operator types.Operator
+		expr     any
+		data     map[string]any
+		want     bool
+	}{
+		{"eval pass", types.ExprEval, `age >= 18 && value == "tom"`, map[string]any{"name": "tom", "age": 20}, true},
+		{"eval fail", types.ExprEval, `age >= 18`, map[string]any{"name": "tom", "age": 16}, false},
+		{"golang pass", types.ExprGolang, `age >= 18 && len(name) == 3 && hasPrefix(value, "t")`, map[string]any{"name": "tom", "age": uint8(20)}, true},
+		{"golang nested", types.ExprGolang, `profile.level > 2 || data["name"] == "admin"`, map[string]any{"name": "tom", "profile": map[string]any{"level": 3}}, true},
+		{"golang matches", types.ExprGolang, `matches(value, "^t[a-z]+$") && !matches(value, "^a")`, map[string]any{"name": "tom"}, true},
+		{"golang bad regexp", types.ExprGolang, `matches(value, "(")`, map[string]any{"name": "tom"}, false},
+		{"golang fail", types.ExprGolang, `age * 2 < 30`, map[string]any{"name": "tom", "age": 20.5}, false},
+		{"golang reserved value", types.ExprGolang, `value == "tom" && data["value"] == 1`, map[string]any{"name": "tom", "value": 1}, true},
+		{"eval reserved value", types.ExprEval, `value == "tom" && data["value"] == 1`, map[string]any{"name": "tom", "value": 1}, true},
+		{"cel pass", types.ExprCEL, `data.age >= 18 && value.startsWith("t")`, map[string]any{"name": "tom", "age": 20}, true},
+		{"cel fail", types.ExprCEL, `data.age >= 18`, map[string]any{"name": "tom", "age": 10}, false},
+		{"jsonlogic pass", types.ExprJSONLogic, `{">=":[{"var":"age"},18]}`, map[string]any{"name": "tom", "age": 20}, true},
+		{"jsonlogic object", types.ExprJSONLogic, map[string]any{"==": []any{map[string]any{"var": "value"}, "tom"}}, map[string]any{"name": "tom"}, true},
+		{"jsonlogic fail", types.ExprJSONLogic, `{"<":[{"var":"age"},18]}`, map[string]any{"name": "tom", "age": 20}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &validator.Rule{
+				Field:    "name",
+				Category: types.CategoryAdvanced,
+				Operator: tt.operator,
+				Value:    []any{tt.expr},
+			}
+			if err := rule.CheckRule(map[string]string{"name": "string"}); err != nil {
+				t.Fatalf("CheckRule() error = %v", err)
+			}
+			msg, ok := rule.Validate(tt.data)
+			if ok != tt.want {
+				t.Fatalf("Validate() = %v, want %v, msg %v", ok, tt.want, msg)
+			}
+		})
+	}
+}
+
+func TestAdvancedCompileError(t *testing.T) {
+	exprs := map[types.Operator]string{
+		types.ExprEval:      `age >=`,
+		types.ExprGolang:    `func() {}`,
+		types.ExprCEL:       `data.age >`,
+		types.ExprJSONLogic: `{"unknown_op":[1,2]}`,
+	}
+	for operator, expr := range exprs {
+		rule := &validator.Rule{
+			Field:    "name",
+			Category: types.CategoryAdvanced,
+			Operator: operator,
+			Value:    []any{expr},
+		}
+		if err := rule.CheckRule(map[string]string{"name": "string"}); err == nil {
+			t.Errorf("%s: CheckRule() expected compile error for %q", operator, expr)
+		}
+	}
+}
@@ -1,19 +1,108 @@
 package operators
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+
 	"helay.net/go/utils/v3/rule-engine/validator/types"
+	"helay.net/go/utils/v3/tools"
 )
 
-func ValidateAdvanced(operator types.Operator, value any, rule []any) (string, bool) {
+// program 编译后的高级表达式，需要支持并发执行
+type program interface {
+	run(value any, data map[string]any) (bool, error)
+}
+
+// ValidateAdvanced 高级表达式校验
+// value 为当前字段的值，data 为 Rule.Validate 收到的完整数据
+func ValidateAdvanced(operator types.Operator, value any, data map[string]any, rule []any) (string, bool) {
+	src, err := exprSource(operator, rule)
+	if err != nil {
+		return err.Error(), false
+	}
+	prg, err := globalExprCache.Get(operator, src)
+	if err != nil {
+		return fmt.Sprintf("表达式编译失败：%v", err), false
+	}
+	ok, err := prg.run(value, data)
+	if err != nil {
+		return fmt.Sprintf("表达式执行失败：%v", err), false
+	}
+	if !ok {
+		return fmt.Sprintf(types.AdvancedChineseMap[operator], src), false
+	}
+	return "", true
+}
+
+// CompileAdvanced 预编译高级表达式，编译结果会进入全局缓存
+func CompileAdvanced(operator types.Operator, rule []any) error {
+	src, err := exprSource(operator, rule)
+	if err != nil {
+		return err
+	}
+	if _, err = globalExprCache.Get(operator, src); err != nil {
+		return fmt.Errorf("%s表达式【%s】编译失败：%w", operator, src, err)
+	}
+	return nil
+}
+
+// exprSource 从规则参数中提取表达式源码
+func exprSource(operator types.Operator, rule []any) (string, error) {
+	if len(rule) < 1 || rule[0] == nil {
+		return "", errors.New("表达式不能为空")
+	}
+	var src string
+	switch v := rule[0].(type) {
+	case string:
+		src = v
+	case []byte:
+		src = string(v)
+	default:
+		if operator != types.ExprJSONLogic {
+			src = tools.Any2string(v)
+			break
+		}
+		// JSONLogic 规则允许直接配置为对象
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("JSONLogic规则序列化失败：%w", err)
+		}
+		src = string(b)
+	}
+	src = strings.TrimSpace(src)
+	if src == "" {
+		return "", errors.New("表达式不能为空")
+	}
+	return src, nil
+}
+
+// compileProgram 按操作符编译表达式
+func compileProgram(operator types.Operator, src string) (program, error) {
 	switch operator {
 	case types.ExprEval:
+		return compileEval(src)
 	case types.ExprGolang:
+		return compileGolang(src)
 	case types.ExprCEL:
+		return compileCEL(src)
 	case types.ExprJSONLogic:
+		return compileJSONLogic(src)
 	default:
-		return fmt.Sprintf("未知类型校验操作符：%s", operator), false
+		return nil, fmt.Errorf("未知高级校验操作符：%s", operator)
+	}
+}
 
+// exprEnv 构建表达式执行环境
+// 数据中的字段可以直接按名称引用，value 表示当前字段的值，data 表示完整数据；
+// value、data 优先于同名的数据字段，同名字段通过 data["value"] 引用
+func exprEnv(value any, data map[string]any) map[string]any {
+	env := make(map[string]any, len(data)+2)
+	for k, v := range data {
+		env[k] = v
 	}
-	return "", true
+	env["value"] = value
+	env["data"] = data
+	return env
 }
@@ -0,0 +1,89 @@
+package operators
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"helay.net/go/utils/v3/rule-engine/validator/types"
+)
+
+// ExprCache 封装高级表达式编译结果缓存
+type ExprCache struct {
+	cache   sync.Map   // 存储编译后的表达式程序
+	count   int32      // 当前缓存数量
+	maxSize int32      // 最大缓存数量
+	mutex   sync.Mutex // 保护缓存操作
+}
+
+var globalExprCache = NewExprCache(10000) // 全局缓存实例
+
+// NewExprCache 创建新的缓存实例
+func NewExprCache(maxSize int32) *ExprCache {
+	return &ExprCache{
+		maxSize: maxSize,
+	}
+}
+
+// Get 获取或编译表达式
+func (ec *ExprCache) Get(operator types.Operator, source string) (program, error) {
+	key := string(operator) + ":" + source
+
+	// 第一层缓存检查（无锁）
+	if cached, ok := ec.cache.Load(key); ok {
+		return cached.(program), nil
+	}
+
+	// 缓存未命中，进入加锁流程
+	ec.mutex.Lock()
+	defer ec.mutex.Unlock()
+
+	// 第二层缓存检查（双检查锁定）
+	if cached, ok := ec.cache.Load(key); ok {
+		return cached.(program), nil
+	}
+
+	// 编译失败的表达式不进入缓存
+	prg, err := compileProgram(operator, source)
+	if err != nil {
+		return nil, err
+	}
+
+	// 执行缓存淘汰（如果超过最大大小）
+	if atomic.LoadInt32(&ec.count) >= ec.maxSize {
+		ec.evict(ec.maxSize / 2) // 淘汰一半缓存
+	}
+
+	ec.cache.Store(key, prg)
+	atomic.AddInt32(&ec.count, 1)
+	return prg, nil
+}
+
+// evict 执行缓存淘汰（LRU简化实现）
+func (ec *ExprCache) evict(toRemove int32) {
+	removed := int32(0)
+	ec.cache.Range(func(key, value interface{}) bool {
+		if removed < toRemove {
+			ec.cache.Delete(key)
+			atomic.AddInt32(&ec.count, -1)
+			removed++
+			return true // 继续迭代
+		}
+		return false // 停止迭代
+	})
+}
+
+// Clear 清空缓存（释放资源）
+func (ec *ExprCache) Clear() {
+	ec.mutex.Lock()
+	defer ec.mutex.Unlock()
+	ec.cache.Range(func(key, value interface{}) bool {
+		ec.cache.Delete(key)
+		return true
+	})
+	atomic.StoreInt32(&ec.count, 0)
+}
+
+// GetGlobalExprCache 获取全局表达式缓存实例
+func GetGlobalExprCache() *ExprCache {
+	return globalExprCache
+}
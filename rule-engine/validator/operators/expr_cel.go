@@ -0,0 +1,55 @@
+package operators
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+)
+
+// celProgram Google CEL 表达式
+// 可用变量：value 当前字段的值，data 完整数据
+type celProgram struct {
+	prg cel.Program
+}
+
+var celEnv = sync.OnceValues(func() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("value", cel.DynType),
+		cel.Variable("data", cel.MapType(cel.StringType, cel.DynType)),
+	)
+})
+
+func compileCEL(src string) (program, error) {
+	env, err := celEnv()
+	if err != nil {
+		return nil, err
+	}
+	ast, iss := env.Compile(src)
+	if iss != nil && iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	if t := ast.OutputType(); t != cel.BoolType && t != cel.DynType {
+		return nil, fmt.Errorf("表达式结果类型必须是布尔值，实际为%s", t)
+	}
+	prg, err := env.Program(ast)
+	if err != nil {
+		return nil, err
+	}
+	return &celProgram{prg: prg}, nil
+}
+
+func (p *celProgram) run(value any, data map[string]any) (bool, error) {
+	if data == nil {
+		data = map[string]any{}
+	}
+	out, _, err := p.prg.Eval(map[string]any{"value": value, "data": data})
+	if err != nil {
+		return false, err
+	}
+	ok, isBool := out.Value().(bool)
+	if !isBool {
+		return false, fmt.Errorf("表达式结果不是布尔值：%v", out.Value())
+	}
+	return ok, nil
+}
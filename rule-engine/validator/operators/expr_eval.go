@@ -0,0 +1,33 @@
+package operators
+
+import (
+	"fmt"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+)
+
+// evalProgram 逻辑表达式，基于 expr-lang 实现
+type evalProgram struct {
+	prg *vm.Program
+}
+
+func compileEval(src string) (program, error) {
+	prg, err := expr.Compile(src, expr.AllowUndefinedVariables())
+	if err != nil {
+		return nil, err
+	}
+	return &evalProgram{prg: prg}, nil
+}
+
+func (p *evalProgram) run(value any, data map[string]any) (bool, error) {
+	out, err := expr.Run(p.prg, exprEnv(value, data))
+	if err != nil {
+		return false, err
+	}
+	ok, isBool := out.(bool)
+	if !isBool {
+		return false, fmt.Errorf("表达式结果不是布尔值：%v", out)
+	}
+	return ok, nil
+}
@@ -0,0 +1,517 @@
+package operators
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// golangProgram Go语法表达式，基于 go/ast 解释执行
+// 支持字面量、算术/比较/逻辑运算、map 字段访问（a.b、a["b"]）、切片下标以及内置函数
+type golangProgram struct {
+	expr ast.Expr
+}
+
+// golangBuiltins Go表达式支持的内置函数及参数数量
+var golangBuiltins = map[string]int{
+	"len":       1,
+	"contains":  2,
+	"hasPrefix": 2,
+	"hasSuffix": 2,
+	"matches":   2,
+	"lower":     1,
+	"upper":     1,
+	"trim":      1,
+	"int":       1,
+	"float":     1,
+	"string":    1,
+}
+
+func compileGolang(src string) (program, error) {
+	expr, err := parser.ParseExpr(src)
+	if err != nil {
+		return nil, err
+	}
+	var checkErr error
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if checkErr != nil || n == nil {
+			return false
+		}
+		switch t := n.(type) {
+		case *ast.BasicLit:
+			if t.Kind == token.IMAG {
+				checkErr = fmt.Errorf("不支持复数字面量：%s", t.Value)
+			}
+		case *ast.Ident, *ast.ParenExpr, *ast.SelectorExpr, *ast.IndexExpr:
+		case *ast.UnaryExpr:
+			if t.Op != token.NOT && t.Op != token.SUB && t.Op != token.ADD {
+				checkErr = fmt.Errorf("不支持的一元运算符：%s", t.Op)
+			}
+		case *ast.BinaryExpr:
+			switch t.Op {
+			case token.ADD, token.SUB, token.MUL, token.QUO, token.REM,
+				token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ,
+				token.LAND, token.LOR:
+			default:
+				checkErr = fmt.Errorf("不支持的运算符：%s", t.Op)
+			}
+		case *ast.CallExpr:
+			ident, ok := t.Fun.(*ast.Ident)
+			if !ok {
+				checkErr = fmt.Errorf("仅支持调用内置函数")
+				return false
+			}
+			argNum, ok := golangBuiltins[ident.Name]
+			if !ok {
+				checkErr = fmt.Errorf("未知函数：%s", ident.Name)
+			} else if argNum != len(t.Args) || t.Ellipsis.IsValid() {
+				checkErr = fmt.Errorf("函数%s参数数量错误，需要%d个", ident.Name, argNum)
+			}
+		default:
+			checkErr = fmt.Errorf("不支持的表达式：%T", n)
+		}
+		return checkErr == nil
+	})
+	if checkErr != nil {
+		return nil, checkErr
+	}
+	return &golangProgram{expr: expr}, nil
+}
+
+func (p *golangProgram) run(value any, data map[string]any) (bool, error) {
+	out, err := (&golangEvaluator{value: value, data: data}).eval(p.expr)
+	if err != nil {
+		return false, err
+	}
+	ok, isBool := out.(bool)
+	if !isBool {
+		return false, fmt.Errorf("表达式结果不是布尔值：%v", out)
+	}
+	return ok, nil
+}
+
+type golangEvaluator struct {
+	value any
+	data  map[string]any
+}
+
+func (e *golangEvaluator) eval(n ast.Expr) (any, error) {
+	switch t := n.(type) {
+	case *ast.BasicLit:
+		return golangLiteral(t)
+	case *ast.Ident:
+		return e.ident(t.Name), nil
+	case *ast.ParenExpr:
+		return e.eval(t.X)
+	case *ast.UnaryExpr:
+		x, err := e.eval(t.X)
+		if err != nil {
+			return nil, err
+		}
+		return golangUnary(t.Op, x)
+	case *ast.BinaryExpr:
+		return e.binary(t)
+	case *ast.SelectorExpr:
+		x, err := e.eval(t.X)
+		if err != nil {
+			return nil, err
+		}
+		return golangIndex(x, t.Sel.Name)
+	case *ast.IndexExpr:
+		x, err := e.eval(t.X)
+		if err != nil {
+			return nil, err
+		}
+		idx, err := e.eval(t.Index)
+		if err != nil {
+			return nil, err
+		}
+		return golangIndex(x, idx)
+	case *ast.CallExpr:
+		args := make([]any, len(t.Args))
+		for i, arg := range t.Args {
+			v, err := e.eval(arg)
+			if err != nil {
+				return nil, err
+			}
+			args[i] = v
+		}
+		return golangCall(t.Fun.(*ast.Ident).Name, args)
+	default:
+		return nil, fmt.Errorf("不支持的表达式：%T", n)
+	}
+}
+
+func (e *golangEvaluator) ident(name string) any {
+	switch name {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "nil":
+		return nil
+	}
+	// value/data 为保留变量，与 exprEnv 一致优先于同名的数据字段，同名字段通过 data["value"] 引用
+	switch name {
+	case "value":
+		return golangNormalize(e.value)
+	case "data":
+		return e.data
+	}
+	if v, ok := e.data[name]; ok {
+		return golangNormalize(v)
+	}
+	return nil
+}
+
+func (e *golangEvaluator) binary(t *ast.BinaryExpr) (any, error) {
+	x, err := e.eval(t.X)
+	if err != nil {
+		return nil, err
+	}
+	// 逻辑运算短路
+	if t.Op == token.LAND || t.Op == token.LOR {
+		xb, ok := x.(bool)
+		if !ok {
+			return nil, fmt.Errorf("运算符%s的左值不是布尔值：%v", t.Op, x)
+		}
+		if (t.Op == token.LAND && !xb) || (t.Op == token.LOR && xb) {
+			return xb, nil
+		}
+		y, err := e.eval(t.Y)
+		if err != nil {
+			return nil, err
+		}
+		yb, ok := y.(bool)
+		if !ok {
+			return nil, fmt.Errorf("运算符%s的右值不是布尔值：%v", t.Op, y)
+		}
+		return yb, nil
+	}
+	y, err := e.eval(t.Y)
+	if err != nil {
+		return nil, err
+	}
+	return golangBinary(t.Op, x, y)
+}
+
+func golangLiteral(lit *ast.BasicLit) (any, error) {
+	switch lit.Kind {
+	case token.INT:
+		return strconv.ParseInt(lit.Value, 0, 64)
+	case token.FLOAT:
+		return strconv.ParseFloat(lit.Value, 64)
+	case token.STRING:
+		return strconv.Unquote(lit.Value)
+	case token.CHAR:
+		s, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return nil, err
+		}
+		return int64([]rune(s)[0]), nil
+	default:
+		return nil, fmt.Errorf("不支持的字面量：%s", lit.Value)
+	}
+}
+
+// golangNormalize 将数值统一为 int64/float64，便于运算和比较
+func golangNormalize(v any) any {
+	switch t := v.(type) {
+	case nil, bool, string, int64, float64, map[string]any, []any:
+		return v
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+		if f, err := t.Float64(); err == nil {
+			return f
+		}
+		return t.String()
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := rv.Uint()
+		if u > math.MaxInt64 {
+			return float64(u)
+		}
+		return int64(u)
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return rv.Bool()
+	}
+	return v
+}
+
+func golangUnary(op token.Token, x any) (any, error) {
+	switch op {
+	case token.NOT:
+		b, ok := x.(bool)
+		if !ok {
+			return nil, fmt.Errorf("运算符!的操作数不是布尔值：%v", x)
+		}
+		return !b, nil
+	case token.SUB:
+		switch t := x.(type) {
+		case int64:
+			return -t, nil
+		case float64:
+			return -t, nil
+		}
+	case token.ADD:
+		switch x.(type) {
+		case int64, float64:
+			return x, nil
+		}
+	}
+	return nil, fmt.Errorf("运算符%s的操作数不是数字：%v", op, x)
+}
+
+func golangBinary(op token.Token, x, y any) (any, error) {
+	switch op {
+	case token.EQL:
+		return golangEqual(x, y), nil
+	case token.NEQ:
+		return !golangEqual(x, y), nil
+	}
+
+	// 字符串运算
+	if xs, ok := x.(string); ok {
+		ys, ok := y.(string)
+		if !ok {
+			return nil, fmt.Errorf("类型不匹配：%v %s %v", x, op, y)
+		}
+		switch op {
+		case token.ADD:
+			return xs + ys, nil
+		case token.LSS:
+			return xs < ys, nil
+		case token.LEQ:
+			return xs <= ys, nil
+		case token.GTR:
+			return xs > ys, nil
+		case token.GEQ:
+			return xs >= ys, nil
+		}
+		return nil, fmt.Errorf("字符串不支持运算符%s", op)
+	}
+
+	// 整数运算
+	xi, xIsInt := x.(int64)
+	yi, yIsInt := y.(int64)
+	if xIsInt && yIsInt {
+		switch op {
+		case token.ADD:
+			return xi + yi, nil
+		case token.SUB:
+			return xi - yi, nil
+		case token.MUL:
+			return xi * yi, nil
+		case token.QUO:
+			if yi == 0 {
+				return nil, fmt.Errorf("除数不能为0")
+			}
+			return xi / yi, nil
+		case token.REM:
+			if yi == 0 {
+				return nil, fmt.Errorf("除数不能为0")
+			}
+			return xi % yi, nil
+		}
+	}
+
+	xf, ok1 := golangFloat(x)
+	yf, ok2 := golangFloat(y)
+	if !ok1 || !ok2 {
+		return nil, fmt.Errorf("类型不匹配：%v %s %v", x, op, y)
+	}
+	switch op {
+	case token.ADD:
+		return xf + yf, nil
+	case token.SUB:
+		return xf - yf, nil
+	case token.MUL:
+		return xf * yf, nil
+	case token.QUO:
+		if yf == 0 {
+			return nil, fmt.Errorf("除数不能为0")
+		}
+		return xf / yf, nil
+	case token.REM:
+		if yf == 0 {
+			return nil, fmt.Errorf("除数不能为0")
+		}
+		return math.Mod(xf, yf), nil
+	case token.LSS:
+		return xf < yf, nil
+	case token.LEQ:
+		return xf <= yf, nil
+	case token.GTR:
+		return xf > yf, nil
+	case token.GEQ:
+		return xf >= yf, nil
+	}
+	return nil, fmt.Errorf("不支持的运算符：%s", op)
+}
+
+func golangFloat(v any) (float64, bool) {
+	switch t := v.(type) {
+	case int64:
+		return float64(t), true
+	case float64:
+		return t, true
+	}
+	return 0, false
+}
+
+func golangEqual(x, y any) bool {
+	if xf, ok := golangFloat(x); ok {
+		yf, ok := golangFloat(y)
+		return ok && xf == yf
+	}
+	if x == nil || y == nil {
+		return x == nil && y == nil
+	}
+	xv, yv := reflect.ValueOf(x), reflect.ValueOf(y)
+	if xv.Type() != yv.Type() {
+		return false
+	}
+	if !xv.Comparable() {
+		return reflect.DeepEqual(x, y)
+	}
+	return x == y
+}
+
+// golangIndex 支持 map 按键取值与切片按下标取值，键不存在时返回 nil
+func golangIndex(x, idx any) (any, error) {
+	if x == nil {
+		return nil, nil
+	}
+	rv := reflect.ValueOf(x)
+	switch rv.Kind() {
+	case reflect.Map:
+		key, ok := idx.(string)
+		if !ok || rv.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("map键类型不匹配：%v", idx)
+		}
+		v := rv.MapIndex(reflect.ValueOf(key).Convert(rv.Type().Key()))
+		if !v.IsValid() {
+			return nil, nil
+		}
+		return golangNormalize(v.Interface()), nil
+	case reflect.Slice, reflect.Array, reflect.String:
+		i, ok := idx.(int64)
+		if !ok {
+			return nil, fmt.Errorf("下标必须是整数：%v", idx)
+		}
+		if i < 0 || i >= int64(rv.Len()) {
+			return nil, fmt.Errorf("下标越界：%d", i)
+		}
+		return golangNormalize(rv.Index(int(i)).Interface()), nil
+	}
+	return nil, fmt.Errorf("类型%T不支持取值操作", x)
+}
+
+func golangCall(name string, args []any) (any, error) {
+	switch name {
+	case "len":
+		if args[0] == nil {
+			return int64(0), nil
+		}
+		rv := reflect.ValueOf(args[0])
+		switch rv.Kind() {
+		case reflect.String:
+			return int64(len([]rune(rv.String()))), nil
+		case reflect.Map, reflect.Slice, reflect.Array:
+			return int64(rv.Len()), nil
+		}
+		return nil, fmt.Errorf("len不支持类型%T", args[0])
+	case "contains":
+		if s, ok := args[0].(string); ok {
+			sub, ok := args[1].(string)
+			if !ok {
+				return nil, fmt.Errorf("contains参数类型不匹配")
+			}
+			return strings.Contains(s, sub), nil
+		}
+		if args[0] == nil {
+			return false, nil
+		}
+		rv := reflect.ValueOf(args[0])
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return nil, fmt.Errorf("contains不支持类型%T", args[0])
+		}
+		for i := 0; i < rv.Len(); i++ {
+			if golangEqual(golangNormalize(rv.Index(i).Interface()), args[1]) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "hasPrefix", "hasSuffix", "matches":
+		s, ok1 := args[0].(string)
+		p, ok2 := args[1].(string)
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("%s参数必须是字符串", name)
+		}
+		switch name {
+		case "hasPrefix":
+			return strings.HasPrefix(s, p), nil
+		case "hasSuffix":
+			return strings.HasSuffix(s, p), nil
+		}
+		re, err := globalRegexCache.Compile(p)
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString(s), nil
+	case "lower", "upper", "trim":
+		s, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("%s参数必须是字符串", name)
+		}
+		switch name {
+		case "lower":
+			return strings.ToLower(s), nil
+		case "upper":
+			return strings.ToUpper(s), nil
+		}
+		return strings.TrimSpace(s), nil
+	case "int":
+		switch t := args[0].(type) {
+		case int64:
+			return t, nil
+		case float64:
+			return int64(t), nil
+		case string:
+			return strconv.ParseInt(strings.TrimSpace(t), 10, 64)
+		}
+		return nil, fmt.Errorf("无法转换为整数：%v", args[0])
+	case "float":
+		switch t := args[0].(type) {
+		case int64:
+			return float64(t), nil
+		case float64:
+			return t, nil
+		case string:
+			return strconv.ParseFloat(strings.TrimSpace(t), 64)
+		}
+		return nil, fmt.Errorf("无法转换为浮点数：%v", args[0])
+	case "string":
+		if args[0] == nil {
+			return "", nil
+		}
+		return fmt.Sprint(args[0]), nil
+	}
+	return nil, fmt.Errorf("未知函数：%s", name)
+}
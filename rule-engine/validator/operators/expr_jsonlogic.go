@@ -0,0 +1,65 @@
+package operators
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/diegoholiveira/jsonlogic/v3"
+)
+
+// jsonLogicProgram JSONLogic 规则
+// var 可以直接引用数据中的字段，value 表示当前字段的值
+type jsonLogicProgram struct {
+	rule json.RawMessage
+}
+
+func compileJSONLogic(src string) (program, error) {
+	if !json.Valid([]byte(src)) {
+		return nil, errors.New("JSONLogic规则不是有效的JSON")
+	}
+	if !jsonlogic.IsValid(strings.NewReader(src)) {
+		return nil, errors.New("JSONLogic规则包含无效的操作符")
+	}
+	return &jsonLogicProgram{rule: json.RawMessage(src)}, nil
+}
+
+func (p *jsonLogicProgram) run(value any, data map[string]any) (bool, error) {
+	env := make(map[string]any, len(data)+1)
+	for k, v := range data {
+		env[k] = v
+	}
+	env["value"] = value
+	// 通过 JSON 序列化统一数据类型，jsonlogic 仅识别 JSON 原生类型
+	b, err := json.Marshal(env)
+	if err != nil {
+		return false, err
+	}
+	out, err := jsonlogic.ApplyRaw(p.rule, b)
+	if err != nil {
+		return false, err
+	}
+	var result any
+	if err = json.Unmarshal(out, &result); err != nil {
+		return false, err
+	}
+	return jsonLogicTruthy(result), nil
+}
+
+// jsonLogicTruthy 按 JSONLogic 规范判断真值
+func jsonLogicTruthy(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return t
+	case float64:
+		return t != 0
+	case string:
+		return t != ""
+	case []any:
+		return len(t) > 0
+	default:
+		return true
+	}
+}
@@ -146,11 +146,11 @@ func (r *Rule) validateCondition(rule *Rule, data map[string]any) (*types.Valida
 	if strings.Contains(rule.Field, "*") {
 		return r.validateWildcard(rule, data)
 	}
-	return r.validateSimple(rule, data[rule.Field])
+	return r.validateSimple(rule, data[rule.Field], data)
 }
 
 // 简单条件校验
-func (r *Rule) validateSimple(rule *Rule, value any) (*types.ValidationError, bool) {
+func (r *Rule) validateSimple(rule *Rule, value any, data map[string]any) (*types.ValidationError, bool) {
 	var (
 		msg string
 		ok  bool
@@ -193,7 +193,7 @@ func (r *Rule) validateSimple(rule *Rule, value any) (*types.ValidationError, bo
 
 	case types.CategoryAdvanced: // 高级校验
 		if msg, ok = validParams(rule, types.AdvancedAttributes); ok {
-			if msg, ok = operators.ValidateAdvanced(rule.Operator, value, rule.Value); ok {
+			if msg, ok = operators.ValidateAdvanced(rule.Operator, value, data, rule.Value); ok {
 				return nil, ok
 			}
 		}
@@ -226,7 +226,7 @@ func (r *Rule) validateWildcard(rule *Rule, data map[string]any) (*types.Validat
 	for key, value := range data {
 		if regex.MatchString(key) {
 			matched = true
-			msg, ok := r.validateSimple(rule, value)
+			msg, ok := r.validateSimple(rule, value, data)
 			if !ok {
 				errorMsgs = append(errorMsgs, msg)
 			}
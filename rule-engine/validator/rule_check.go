@@ -2,6 +2,7 @@ package validator
 
 import (
 	"fmt"
+	"helay.net/go/utils/v3/rule-engine/validator/operators"
 	"helay.net/go/utils/v3/rule-engine/validator/types"
 )
 
@@ -33,6 +34,12 @@ func (r *Rule) CheckRule(dataTypeMap map[string]string) error {
 			}
 			// 补充FieldDataType
 			current.FieldDataType = dataType
+			// 高级校验需要预编译表达式，提前暴露语法错误
+			if current.Category == types.CategoryAdvanced {
+				if err := operators.CompileAdvanced(current.Operator, current.Value); err != nil {
+					return fmt.Errorf("字段'%s'%w", current.Field, err)
+				}
+			}
 		} else {
 			// 将子节点压入栈中（逆序以保证原顺序）
 			for i := len(current.Conditions) - 1; i >= 0; i-- {
@@ -162,9 +162,16 @@ const (
 	ExprJSONLogic Operator = "expr_jsonlogic" // JSONLogic规则
 )
 
+var AdvancedChineseMap = map[Operator]string{
+	ExprEval:      "不满足逻辑表达式：%s",
+	ExprGolang:    "不满足Go表达式：%s",
+	ExprCEL:       "不满足CEL表达式：%s",
+	ExprJSONLogic: "不满足JSONLogic规则：%s",
+}
+
 var AdvancedAttributes = map[Operator]Attribute{
-	ExprEval:      {Title: "逻辑表达式", ValueNum: 1, DataType: "string"},
-	ExprGolang:    {Title: "Go表达式", ValueNum: 1, DataType: "string"},
-	ExprCEL:       {Title: "CEL表达式", ValueNum: 1, DataType: "string"},
-	ExprJSONLogic: {Title: "JSONLogic规则", ValueNum: 1, DataType: "string"},
+	ExprEval:      {Title: "逻辑表达式", ValueNum: 1, DataType: "string", Description: "expr语法，可直接引用字段名，value为当前字段值，data为完整数据"},
+	ExprGolang:    {Title: "Go表达式", ValueNum: 1, DataType: "string", Description: "Go语法，可直接引用字段名，value为当前字段值，data为完整数据"},
+	ExprCEL:       {Title: "CEL表达式", ValueNum: 1, DataType: "string", Description: "value为当前字段值，data为完整数据，如 data.age >= 18"},
+	ExprJSONLogic: {Title: "JSONLogic规则", ValueNum: 1, DataType: "string", Description: "var可直接引用字段名，value为当前字段值"},
 }
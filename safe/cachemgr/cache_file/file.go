@@ -0,0 +1,418 @@
+package cache_file
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"helay.net/go/utils/v3/logger/ulogs"
+	"helay.net/go/utils/v3/safe"
+	"helay.net/go/utils/v3/safe/cachemgr/cachekit"
+	"helay.net/go/utils/v3/tools"
+)
+
+// 文件缓存驱动
+// 每个缓存实例一个目录，每个 key 一个文件，文件名为 key 的 sha256。
+// 写入时先写临时文件再重命名，保证读取到的文件总是完整的。
+// 同一进程内通过分段锁保证单个 key 操作的原子性，多进程共享目录时只能保证单次读写的完整性。
+
+const (
+	lockSize  = 1 << 8 // 分段锁数量
+	tmpPrefix = ".tmp-"
+)
+
+// Options 文件缓存配置
+type Options struct {
+	Identity string // 缓存实例标识
+	Path     string // 缓存文件根目录，默认 runtime/cache
+	safe.CacheConfig
+}
+
+// Instance 文件缓存驱动
+type Instance[K comparable, V any] struct {
+	ctx       context.Context
+	dir       string
+	identity  string
+	ttl       cachekit.TTL
+	locks     [lockSize]sync.Mutex
+	onExpired safe.OnExpired[K]
+	flight    safe.SingleFlight[K, V] // 合并同一个 key 的并发加载
+}
+
+// New 创建文件缓存
+func New[K comparable, V any](ctx context.Context, opt Options) (*Instance[K, V], error) {
+	opt.Path = tools.Ternary(opt.Path == "", "runtime/cache", opt.Path)
+	identity := tools.Ternary(opt.Identity == "", "default", opt.Identity)
+	if tools.ContainsDotDot(identity) || strings.ContainsAny(identity, `/\`) {
+		return nil, fmt.Errorf("缓存实例标识[%s]不能包含路径字符", identity)
+	}
+	i := &Instance[K, V]{
+		ctx:      ctx,
+		dir:      filepath.Join(tools.Fileabs(opt.Path), identity),
+		identity: opt.Identity,
+		ttl:      cachekit.NewTTL(opt.CacheConfig),
+	}
+	if err := tools.Mkdir(i.dir); err != nil {
+		return nil, fmt.Errorf("创建缓存文件存放目录失败 %v", err)
+	}
+	tools.RunAsyncTickerWithContext(ctx, i.ttl.Enable, i.ttl.Interval, i.cleanup)
+	return i, nil
+}
+
+// SetOnExpired 设置过期回调函数。
+func (i *Instance[K, V]) SetOnExpired(onExpired safe.OnExpired[K]) {
+	i.onExpired = onExpired
+}
+
+// Load 获取键的值。
+func (i *Instance[K, V]) Load(key K) (V, bool) {
+	var zero V
+	name := i.filename(key)
+	mu := i.lock(name)
+	defer mu.Unlock()
+	e, err := i.read(name)
+	if err != nil || e == nil || e.Expired(time.Now().UnixNano()) {
+		return zero, false
+	}
+	return e.Val, true
+}
+
+// LoadOrStore 获取键的值，如果没有则存储键的值。
+func (i *Instance[K, V]) LoadOrStore(key K, val V, duration ...time.Duration) (V, bool) {
+	name := i.filename(key)
+	mu := i.lock(name)
+	defer mu.Unlock()
+	if e, _ := i.read(name); e != nil && e.Valid(time.Now().UnixNano()) {
+		return e.Val, true
+	}
+	i.write(name, cachekit.NewEntry(i.ttl, key, val, duration...))
+	return val, false
+}
+
+// LoadOrStoreFunc 获取键的值，如果没有则存储键的值。
+// valueFunc 在文件锁之外执行，同一个 key 的并发加载只会执行一次，其他调用共享结果。
+func (i *Instance[K, V]) LoadOrStoreFunc(key K, valueFunc func(k K) (V, error), duration ...time.Duration) (V, bool, error) {
+	if val, ok := i.Load(key); ok {
+		return val, true, nil
+	}
+	var zero V
+	if valueFunc == nil {
+		return zero, false, nil
+	}
+	loaded := false
+	val, shared, err := i.flight.Do(key, func() (V, error) {
+		val, err := valueFunc(key)
+		if err != nil {
+			return val, err
+		}
+		val, loaded = i.LoadOrStore(key, val, duration...)
+		return val, nil
+	})
+	if err != nil {
+		return val, false, err
+	}
+	return val, loaded || shared, nil
+}
+
+// LoadAndDelete 获取键的值并删除键值对。
+func (i *Instance[K, V]) LoadAndDelete(key K) (V, bool) {
+	return i.LoadAndDeleteIf(key, nil)
+}
+
+// LoadAndDeleteIf 获取键的值并删除键值对，条件性删除
+// 返回值第二个参数 如果 == true,表示删除成功
+func (i *Instance[K, V]) LoadAndDeleteIf(key K, condition func(value V) bool) (V, bool) {
+	var zero V
+	name := i.filename(key)
+	mu := i.lock(name)
+	defer mu.Unlock()
+	e, _ := i.read(name)
+	if e == nil {
+		return zero, false
+	}
+	if e.Expired(time.Now().UnixNano()) {
+		// 值无效，但是值存在，就删除。
+		i.remove(name)
+		return zero, false
+	}
+	if condition == nil || condition(e.Val) {
+		return e.Val, i.remove(name)
+	}
+	return e.Val, false
+}
+
+// LoadAndRefresh 获取键的值并刷新过期时间。
+func (i *Instance[K, V]) LoadAndRefresh(key K, duration ...time.Duration) (V, bool) {
+	var zero V
+	name := i.filename(key)
+	mu := i.lock(name)
+	defer mu.Unlock()
+	e, _ := i.read(name)
+	if e == nil {
+		return zero, false
+	}
+	if e.Expired(time.Now().UnixNano()) {
+		i.remove(name)
+		return zero, false
+	}
+	if i.ttl.Enable {
+		cachekit.Refresh(e, duration...)
+		i.write(name, e)
+	}
+	return e.Val, true
+}
+
+// LoadWithExpiry 获取键的值和过期时间
+func (i *Instance[K, V]) LoadWithExpiry(key K) (V, time.Time, bool) {
+	var zero V
+	e := i.get(key)
+	if e == nil || e.Expired(time.Now().UnixNano()) {
+		return zero, time.Time{}, false
+	}
+	return e.Val, time.Unix(0, e.Expire), true
+}
+
+// Refresh 更新键的过期时间
+func (i *Instance[K, V]) Refresh(key K, duration ...time.Duration) bool {
+	// 如果没有启用自动清理功能，则返回false
+	if !i.ttl.Enable {
+		return false
+	}
+	_, ok := i.LoadAndRefresh(key, duration...)
+	return ok
+}
+
+// GetTTL 获取键的剩余存活时间
+func (i *Instance[K, V]) GetTTL(key K) (time.Duration, bool) {
+	e := i.get(key)
+	if e == nil || e.Expired(time.Now().UnixNano()) {
+		return 0, false
+	}
+	return e.Remaining(), true
+}
+
+// IsExpired 检测键是否已过期
+func (i *Instance[K, V]) IsExpired(key K) bool {
+	e := i.get(key)
+	return e != nil && e.Expired(time.Now().UnixNano())
+}
+
+// GetHeartbeat 获取键的最后更新时间
+func (i *Instance[K, V]) GetHeartbeat(key K) (time.Time, bool) {
+	e := i.get(key)
+	if e == nil || e.Expired(time.Now().UnixNano()) {
+		return time.Time{}, false
+	}
+	return time.Unix(0, e.Heartbeat), true
+}
+
+// Store 存储键的值。
+func (i *Instance[K, V]) Store(key K, val V, duration ...time.Duration) {
+	name := i.filename(key)
+	mu := i.lock(name)
+	defer mu.Unlock()
+	i.write(name, cachekit.NewEntry(i.ttl, key, val, duration...))
+}
+
+// Delete 移除键的值。
+func (i *Instance[K, V]) Delete(key K) {
+	name := i.filename(key)
+	mu := i.lock(name)
+	defer mu.Unlock()
+	i.remove(name)
+}
+
+// DeleteAndGetCount 删除多个键并返回删除的数量
+func (i *Instance[K, V]) DeleteAndGetCount(keys ...K) int {
+	count := 0
+	for _, key := range keys {
+		name := i.filename(key)
+		mu := i.lock(name)
+		if i.remove(name) {
+			count++
+		}
+		mu.Unlock()
+	}
+	return count
+}
+
+// DeleteAll 删除所有键值对
+func (i *Instance[K, V]) DeleteAll() {
+	i.walk(func(name string, _ *cachekit.Entry[K, V]) bool {
+		i.remove(name)
+		return true
+	}, false)
+}
+
+// Range 遍历所有未过期的键值对
+func (i *Instance[K, V]) Range(f func(key K, value V) bool) {
+	i.walk(func(_ string, e *cachekit.Entry[K, V]) bool {
+		if e.Valid(time.Now().UnixNano()) {
+			return f(e.Key, e.Val)
+		}
+		return true
+	}, true)
+}
+
+// DeletePrefix 删除指定前缀的键值对
+func (i *Instance[K, V]) DeletePrefix(prefix string) {
+	i.deleteMatch(func(ks string) bool { return cachekit.MatchPrefix(ks, prefix) })
+}
+
+// DeleteSuffix 删除指定后缀的键值对
+func (i *Instance[K, V]) DeleteSuffix(suffix string) {
+	i.deleteMatch(func(ks string) bool { return cachekit.MatchSuffix(ks, suffix) })
+}
+
+func (i *Instance[K, V]) deleteMatch(match func(ks string) bool) {
+	// 如果K是string类型，才执行这个
+	if !cachekit.IsStringKey[K]() {
+		return
+	}
+	i.walk(func(name string, e *cachekit.Entry[K, V]) bool {
+		if !match(cachekit.KeyString(e.Key)) {
+			return true
+		}
+		mu := i.lock(name)
+		defer mu.Unlock()
+		// 加锁后重新读取，文件可能已经被删除或者替换
+		if cur, _ := i.read(name); cur != nil && match(cachekit.KeyString(cur.Key)) {
+			i.remove(name)
+		}
+		return true
+	}, true)
+}
+
+// cleanup 清理过期数据，并触发过期回调
+func (i *Instance[K, V]) cleanup(ctx context.Context) {
+	var expiredKeys []K
+	i.walk(func(name string, e *cachekit.Entry[K, V]) bool {
+		if ctx.Err() != nil {
+			return false
+		}
+		if e.Valid(time.Now().UnixNano()) {
+			return true
+		}
+		mu := i.lock(name)
+		defer mu.Unlock()
+		// 加锁后重新读取，避免删除刚刚续期的数据
+		if cur, _ := i.read(name); cur != nil && cur.Expired(time.Now().UnixNano()) && i.remove(name) && i.onExpired != nil {
+			expiredKeys = append(expiredKeys, cur.Key)
+		}
+		return true
+	}, true)
+	cachekit.Notify(i.onExpired, expiredKeys)
+}
+
+// walk 遍历缓存目录，decode 为 false 时不解析文件内容
+func (i *Instance[K, V]) walk(f func(name string, e *cachekit.Entry[K, V]) bool, decode bool) {
+	files, err := os.ReadDir(i.dir)
+	if err != nil {
+		ulogs.Errorf("缓存[%s]目录读取失败 %v", i.identity, err)
+		return
+	}
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || strings.HasPrefix(name, tmpPrefix) {
+			continue
+		}
+		var e *cachekit.Entry[K, V]
+		if decode {
+			mu := i.lock(name)
+			e, err = i.read(name)
+			mu.Unlock()
+			if err != nil || e == nil {
+				continue
+			}
+		}
+		if !f(name, e) {
+			return
+		}
+	}
+}
+
+// filename key 对应的文件名
+func (i *Instance[K, V]) filename(key K) string {
+	sum := sha256.Sum256([]byte(cachekit.KeyString(key)))
+	return hex.EncodeToString(sum[:])
+}
+
+// lock 获取文件对应的分段锁，并加锁
+func (i *Instance[K, V]) lock(name string) *sync.Mutex {
+	mu := &i.locks[xxhash.Sum64String(name)&(lockSize-1)]
+	mu.Lock()
+	return mu
+}
+
+// get 加锁读取
+func (i *Instance[K, V]) get(key K) *cachekit.Entry[K, V] {
+	name := i.filename(key)
+	mu := i.lock(name)
+	defer mu.Unlock()
+	e, _ := i.read(name)
+	return e
+}
+
+// read 读取缓存文件，文件不存在返回 nil
+func (i *Instance[K, V]) read(name string) (*cachekit.Entry[K, V], error) {
+	b, err := os.ReadFile(filepath.Join(i.dir, name))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		ulogs.Errorf("缓存[%s]文件[%s]读取失败 %v", i.identity, name, err)
+		return nil, err
+	}
+	e, err := cachekit.Decode[K, V](b)
+	if err != nil {
+		ulogs.Errorf("缓存[%s]文件[%s]解析失败 %v", i.identity, name, err)
+		return nil, err
+	}
+	return e, nil
+}
+
+// write 写入缓存文件
+func (i *Instance[K, V]) write(name string, e *cachekit.Entry[K, V]) {
+	b, err := cachekit.Encode(e)
+	if err != nil {
+		ulogs.Errorf("缓存[%s]文件[%s]写入失败 %v", i.identity, name, err)
+		return
+	}
+	f, err := os.CreateTemp(i.dir, tmpPrefix+"*")
+	if err != nil {
+		ulogs.Errorf("缓存[%s]文件[%s]写入失败 %v", i.identity, name, err)
+		return
+	}
+	tmp := f.Name()
+	_, err = f.Write(b)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, filepath.Join(i.dir, name))
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		ulogs.Errorf("缓存[%s]文件[%s]写入失败 %v", i.identity, name, err)
+	}
+}
+
+// remove 删除缓存文件，返回文件是否存在
+func (i *Instance[K, V]) remove(name string) bool {
+	err := os.Remove(filepath.Join(i.dir, name))
+	if err == nil {
+		return true
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		ulogs.Errorf("缓存[%s]文件[%s]删除失败 %v", i.identity, name, err)
+	}
+	return false
+}
@@ -0,0 +1,540 @@
+package cache_redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"helay.net/go/utils/v3/logger/ulogs"
+	"helay.net/go/utils/v3/safe"
+	"helay.net/go/utils/v3/safe/cachemgr/cachekit"
+	"helay.net/go/utils/v3/tools"
+)
+
+// redis 缓存驱动
+// 数据key：cachemgr:{identity}:k:<key>，值为序列化后的 cachekit.Entry
+// 索引key：cachemgr:{identity}:i，有序集合，member 为 key，score 为过期时间（毫秒）
+// 同一个缓存实例的 key 使用相同的 hash tag，在集群模式下落在同一个 slot，保证事务的原子性。
+// 读改写都通过 WATCH 事务完成，不依赖 lua 脚本，可以直接使用 localredis 等不支持 EVAL 的实现。
+
+const (
+	batchSize  = 500     // 遍历、清理时每批处理的数量
+	neverScore = 1 << 52 // 不过期数据在索引中的 score
+	maxTxRetry = 10      // WATCH 事务冲突时的最大重试次数
+)
+
+// Options redis 缓存配置
+type Options struct {
+	Identity string // 缓存实例标识，同时作为 redis cluster 的 hash tag，为空时使用 default
+	safe.CacheConfig
+}
+
+// Instance redis 缓存驱动
+type Instance[K comparable, V any] struct {
+	ctx       context.Context
+	rdb       redis.UniversalClient
+	identity  string
+	prefix    string
+	index     string
+	ttl       cachekit.TTL
+	grace     time.Duration // 数据在 redis 中比逻辑过期时间多保留的时间，用于过期回调还原 key
+	onExpired safe.OnExpired[K]
+	flight    safe.SingleFlight[K, V] // 合并本进程内同一个 key 的并发加载
+}
+
+// New 创建 redis 缓存
+func New[K comparable, V any](ctx context.Context, rdb redis.UniversalClient, opt Options) (*Instance[K, V], error) {
+	if rdb == nil {
+		return nil, errors.New("redis缓存驱动未设置redis连接")
+	}
+	// hash tag 为空时 redis cluster 按完整 key 计算 slot，数据和索引会分散到不同节点
+	opt.Identity = tools.Ternary(opt.Identity == "", "default", opt.Identity)
+	tag := "cachemgr:{" + opt.Identity + "}:"
+	i := &Instance[K, V]{
+		ctx:      ctx,
+		rdb:      rdb,
+		identity: opt.Identity,
+		prefix:   tag + "k:",
+		index:    tag + "i",
+		ttl:      cachekit.NewTTL(opt.CacheConfig),
+	}
+	i.grace = tools.Max(2*i.ttl.Interval, time.Minute)
+	tools.RunAsyncTickerWithContext(ctx, i.ttl.Enable, i.ttl.Interval, i.cleanup)
+	return i, nil
+}
+
+// SetOnExpired 设置过期回调函数。
+func (i *Instance[K, V]) SetOnExpired(onExpired safe.OnExpired[K]) {
+	i.onExpired = onExpired
+}
+
+// Load 获取键的值。
+func (i *Instance[K, V]) Load(key K) (V, bool) {
+	var zero V
+	e, err := i.load(i.rdb, cachekit.KeyString(key))
+	if err != nil || e == nil || e.Expired(time.Now().UnixNano()) {
+		return zero, false
+	}
+	return e.Val, true
+}
+
+// LoadOrStore 获取键的值，如果没有则存储键的值。
+func (i *Instance[K, V]) LoadOrStore(key K, val V, duration ...time.Duration) (V, bool) {
+	var (
+		old *cachekit.Entry[K, V]
+		ks  = cachekit.KeyString(key)
+		dk  = i.prefix + ks
+		e   = cachekit.NewEntry(i.ttl, key, val, duration...)
+	)
+	// 索引中的数据未过期，返回旧数据，否则写入新数据；旧数据无法解析时直接覆盖
+	err := i.watch(func(tx *redis.Tx) error {
+		now := time.Now()
+		old = nil
+		score, err := tx.ZScore(i.ctx, i.index, ks).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+		if err == nil && int64(score) > now.UnixMilli() {
+			if cur, err := i.load(tx, ks); err == nil && cur != nil && !cur.Expired(now.UnixNano()) {
+				old = cur
+				return nil
+			}
+		}
+		_, err = tx.TxPipelined(i.ctx, func(pipe redis.Pipeliner) error {
+			return i.store(pipe, ks, e)
+		})
+		return err
+	}, dk)
+	if err != nil {
+		ulogs.Errorf("缓存[%s]写入key[%s]失败 %v", i.identity, ks, err)
+		return val, false
+	}
+	if old == nil {
+		return val, false
+	}
+	return old.Val, true
+}
+
+// LoadOrStoreFunc 获取键的值，如果没有则存储键的值。
+// 本进程内同一个 key 的并发加载只会执行一次 valueFunc，其他调用共享结果。
+func (i *Instance[K, V]) LoadOrStoreFunc(key K, valueFunc func(k K) (V, error), duration ...time.Duration) (V, bool, error) {
+	if val, ok := i.Load(key); ok {
+		return val, true, nil
+	}
+	var zero V
+	if valueFunc == nil {
+		return zero, false, nil
+	}
+	loaded := false
+	val, shared, err := i.flight.Do(key, func() (V, error) {
+		val, err := valueFunc(key)
+		if err != nil {
+			return val, err
+		}
+		// 其他进程可能已经写入，以已存在的值为准
+		val, loaded = i.LoadOrStore(key, val, duration...)
+		return val, nil
+	})
+	if err != nil {
+		return val, false, err
+	}
+	return val, loaded || shared, nil
+}
+
+// LoadAndDelete 获取键的值并删除键值对。
+func (i *Instance[K, V]) LoadAndDelete(key K) (V, bool) {
+	return i.LoadAndDeleteIf(key, nil)
+}
+
+// LoadAndDeleteIf 获取键的值并删除键值对，条件性删除
+// 返回值第二个参数 如果 == true,表示删除成功
+func (i *Instance[K, V]) LoadAndDeleteIf(key K, condition func(value V) bool) (V, bool) {
+	var (
+		zero    V
+		val     V
+		deleted bool
+		ks      = cachekit.KeyString(key)
+		dk      = i.prefix + ks
+	)
+	err := i.watch(func(tx *redis.Tx) error {
+		e, err := i.load(tx, ks)
+		if err != nil || e == nil {
+			return err
+		}
+		expired := e.Expired(time.Now().UnixNano())
+		if !expired {
+			val = e.Val
+			if condition != nil && !condition(e.Val) {
+				return nil
+			}
+		}
+		_, err = tx.TxPipelined(i.ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(i.ctx, dk)
+			pipe.ZRem(i.ctx, i.index, ks)
+			return nil
+		})
+		deleted = err == nil && !expired
+		return err
+	}, dk)
+	if err != nil {
+		ulogs.Errorf("缓存[%s]删除key[%s]失败 %v", i.identity, ks, err)
+		return zero, false
+	}
+	return val, deleted
+}
+
+// LoadAndRefresh 获取键的值并刷新过期时间。
+func (i *Instance[K, V]) LoadAndRefresh(key K, duration ...time.Duration) (V, bool) {
+	var (
+		zero V
+		val  V
+		ok   bool
+		ks   = cachekit.KeyString(key)
+		dk   = i.prefix + ks
+	)
+	err := i.watch(func(tx *redis.Tx) error {
+		e, err := i.load(tx, ks)
+		if err != nil || e == nil || e.Expired(time.Now().UnixNano()) {
+			return err
+		}
+		val, ok = e.Val, true
+		if !i.ttl.Enable {
+			return nil
+		}
+		cachekit.Refresh(e, duration...)
+		_, err = tx.TxPipelined(i.ctx, func(pipe redis.Pipeliner) error {
+			return i.store(pipe, ks, e)
+		})
+		return err
+	}, dk)
+	if err != nil {
+		ulogs.Errorf("缓存[%s]刷新key[%s]失败 %v", i.identity, ks, err)
+		return zero, false
+	}
+	return val, ok
+}
+
+// LoadWithExpiry 获取键的值和过期时间
+func (i *Instance[K, V]) LoadWithExpiry(key K) (V, time.Time, bool) {
+	var zero V
+	e, err := i.load(i.rdb, cachekit.KeyString(key))
+	if err != nil || e == nil || e.Expired(time.Now().UnixNano()) {
+		return zero, time.Time{}, false
+	}
+	return e.Val, time.Unix(0, e.Expire), true
+}
+
+// Refresh 更新键的过期时间
+func (i *Instance[K, V]) Refresh(key K, duration ...time.Duration) bool {
+	// 如果没有启用自动清理功能，则返回false
+	if !i.ttl.Enable {
+		return false
+	}
+	_, ok := i.LoadAndRefresh(key, duration...)
+	return ok
+}
+
+// GetTTL 获取键的剩余存活时间
+func (i *Instance[K, V]) GetTTL(key K) (time.Duration, bool) {
+	e, err := i.load(i.rdb, cachekit.KeyString(key))
+	if err != nil || e == nil || e.Expired(time.Now().UnixNano()) {
+		return 0, false
+	}
+	return e.Remaining(), true
+}
+
+// IsExpired 检测键是否已过期
+func (i *Instance[K, V]) IsExpired(key K) bool {
+	e, err := i.load(i.rdb, cachekit.KeyString(key))
+	if err != nil || e == nil {
+		return false
+	}
+	return e.Expired(time.Now().UnixNano())
+}
+
+// GetHeartbeat 获取键的最后更新时间
+func (i *Instance[K, V]) GetHeartbeat(key K) (time.Time, bool) {
+	e, err := i.load(i.rdb, cachekit.KeyString(key))
+	if err != nil || e == nil || e.Expired(time.Now().UnixNano()) {
+		return time.Time{}, false
+	}
+	return time.Unix(0, e.Heartbeat), true
+}
+
+// Store 存储键的值。
+func (i *Instance[K, V]) Store(key K, val V, duration ...time.Duration) {
+	ks := cachekit.KeyString(key)
+	e := cachekit.NewEntry(i.ttl, key, val, duration...)
+	_, err := i.rdb.TxPipelined(i.ctx, func(pipe redis.Pipeliner) error {
+		return i.store(pipe, ks, e)
+	})
+	ulogs.CheckErrf(err, "缓存[%s]写入key[%s]失败", i.identity, ks)
+}
+
+// Delete 移除键的值。
+func (i *Instance[K, V]) Delete(key K) {
+	i.DeleteAndGetCount(key)
+}
+
+// DeleteAndGetCount 删除多个键并返回删除的数量
+func (i *Instance[K, V]) DeleteAndGetCount(keys ...K) int {
+	ks := make([]string, 0, len(keys))
+	for _, key := range keys {
+		ks = append(ks, cachekit.KeyString(key))
+	}
+	count, _ := i.deleteKeys(ks)
+	return count
+}
+
+// DeleteAll 删除所有键值对
+func (i *Instance[K, V]) DeleteAll() {
+	// 删除过程中索引会变化，所以每次都从头取一批
+	for {
+		ks, err := i.rdb.ZRange(i.ctx, i.index, 0, batchSize-1).Result()
+		if err != nil {
+			ulogs.Errorf("缓存[%s]清空失败 %v", i.identity, err)
+			return
+		}
+		if len(ks) == 0 {
+			return
+		}
+		if _, err = i.deleteKeys(ks); err != nil {
+			return
+		}
+	}
+}
+
+// Range 遍历所有未过期的键值对
+func (i *Instance[K, V]) Range(f func(key K, value V) bool) {
+	i.scan(func(members []redis.Z) bool {
+		now := time.Now()
+		dks := make([]string, 0, len(members))
+		for _, m := range members {
+			if int64(m.Score) > now.UnixMilli() {
+				dks = append(dks, i.prefix+m.Member.(string))
+			}
+		}
+		if len(dks) == 0 {
+			return true
+		}
+		values, err := i.rdb.MGet(i.ctx, dks...).Result()
+		if err != nil {
+			ulogs.Errorf("缓存[%s]遍历失败 %v", i.identity, err)
+			return false
+		}
+		for idx, v := range values {
+			s, ok := v.(string)
+			if !ok {
+				continue
+			}
+			e, err := cachekit.Decode[K, V]([]byte(s))
+			if err != nil {
+				ulogs.Errorf("缓存[%s]遍历key[%s]解析失败 %v", i.identity, dks[idx], err)
+				continue
+			}
+			if e.Valid(now.UnixNano()) && !f(e.Key, e.Val) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// DeletePrefix 删除指定前缀的键值对
+func (i *Instance[K, V]) DeletePrefix(prefix string) {
+	i.deleteMatch(func(ks string) bool { return cachekit.MatchPrefix(ks, prefix) })
+}
+
+// DeleteSuffix 删除指定后缀的键值对
+func (i *Instance[K, V]) DeleteSuffix(suffix string) {
+	i.deleteMatch(func(ks string) bool { return cachekit.MatchSuffix(ks, suffix) })
+}
+
+func (i *Instance[K, V]) deleteMatch(match func(ks string) bool) {
+	// 如果K是string类型，才执行这个
+	if !cachekit.IsStringKey[K]() {
+		return
+	}
+	var ks []string
+	i.scan(func(members []redis.Z) bool {
+		for _, m := range members {
+			if k := m.Member.(string); match(k) {
+				ks = append(ks, k)
+			}
+		}
+		return true
+	})
+	for _, chunk := range tools.ArrayChunk(ks, batchSize) {
+		if _, err := i.deleteKeys(chunk); err != nil {
+			return
+		}
+	}
+}
+
+// cleanup 清理过期数据，并触发过期回调
+// 多个节点共享同一个 redis 时，由 WATCH 事务保证每个过期 key 只会被一个节点处理
+func (i *Instance[K, V]) cleanup(ctx context.Context) {
+	var expiredKeys []K
+	for {
+		now := time.Now().UnixMilli()
+		ks, err := i.rdb.ZRangeByScore(ctx, i.index, &redis.ZRangeBy{
+			Min:   "-inf",
+			Max:   tools.Any2string(now),
+			Count: batchSize,
+		}).Result()
+		if err != nil {
+			ulogs.Errorf("缓存[%s]清理过期数据失败 %v", i.identity, err)
+			break
+		}
+		for _, k := range ks {
+			res, ok := i.expire(ctx, k, now)
+			if !ok || i.onExpired == nil {
+				// 已经被其他节点处理或者已经续期
+				continue
+			}
+			if e, err := cachekit.Decode[K, V](res); err == nil {
+				expiredKeys = append(expiredKeys, e.Key)
+			} else if key, ok := any(k).(K); ok {
+				// 数据已经被 redis 淘汰，字符串 key 可以直接还原
+				expiredKeys = append(expiredKeys, key)
+			}
+		}
+		if len(ks) < batchSize || ctx.Err() != nil {
+			break
+		}
+	}
+	cachekit.Notify(i.onExpired, expiredKeys)
+}
+
+// expire 删除已过期的数据，并返回被删除的数据
+// 只有 score 仍然小于当前时间时才删除，避免误删刚刚续期的数据；数据已经被 redis 淘汰时返回空
+func (i *Instance[K, V]) expire(ctx context.Context, ks string, now int64) ([]byte, bool) {
+	var (
+		res     []byte
+		deleted bool
+		dk      = i.prefix + ks
+	)
+	err := i.watch(func(tx *redis.Tx) error {
+		res, deleted = nil, false
+		score, err := tx.ZScore(ctx, i.index, ks).Result()
+		if err != nil || int64(score) > now {
+			// redis.Nil 表示已经被其他节点处理
+			return nil
+		}
+		res, err = tx.Get(ctx, dk).Bytes()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.ZRem(ctx, i.index, ks)
+			pipe.Del(ctx, dk)
+			return nil
+		})
+		deleted = err == nil
+		return err
+	}, dk)
+	if err != nil {
+		ulogs.Errorf("缓存[%s]清理key[%s]失败 %v", i.identity, ks, err)
+		return nil, false
+	}
+	return res, deleted
+}
+
+// watch 执行 WATCH 事务，冲突时重试
+func (i *Instance[K, V]) watch(fn func(tx *redis.Tx) error, keys ...string) error {
+	for n := 0; n < maxTxRetry; n++ {
+		err := i.rdb.Watch(i.ctx, fn, keys...)
+		if !errors.Is(err, redis.TxFailedErr) {
+			return err
+		}
+	}
+	return redis.TxFailedErr
+}
+
+// scan 分批遍历索引
+func (i *Instance[K, V]) scan(f func(members []redis.Z) bool) {
+	var cursor uint64
+	for {
+		res, next, err := i.rdb.ZScan(i.ctx, i.index, cursor, "", batchSize).Result()
+		if err != nil {
+			ulogs.Errorf("缓存[%s]遍历索引失败 %v", i.identity, err)
+			return
+		}
+		// ZSCAN 返回 member score 交替排列
+		members := make([]redis.Z, 0, len(res)/2)
+		for idx := 0; idx+1 < len(res); idx += 2 {
+			score, _ := tools.Any2float64(res[idx+1])
+			members = append(members, redis.Z{Member: res[idx], Score: score})
+		}
+		if len(members) > 0 && !f(members) {
+			return
+		}
+		if next == 0 {
+			return
+		}
+		cursor = next
+	}
+}
+
+func (i *Instance[K, V]) deleteKeys(ks []string) (int, error) {
+	if len(ks) == 0 {
+		return 0, nil
+	}
+	dks := make([]string, 0, len(ks))
+	members := make([]any, 0, len(ks))
+	for _, k := range ks {
+		dks = append(dks, i.prefix+k)
+		members = append(members, k)
+	}
+	var del *redis.IntCmd
+	_, err := i.rdb.TxPipelined(i.ctx, func(pipe redis.Pipeliner) error {
+		del = pipe.Del(i.ctx, dks...)
+		pipe.ZRem(i.ctx, i.index, members...)
+		return nil
+	})
+	if err != nil {
+		ulogs.Errorf("缓存[%s]删除失败 %v", i.identity, err)
+		return 0, err
+	}
+	return int(del.Val()), nil
+}
+
+// store 写入数据和索引
+func (i *Instance[K, V]) store(pipe redis.Pipeliner, ks string, e *cachekit.Entry[K, V]) error {
+	b, err := cachekit.Encode(e)
+	if err != nil {
+		return err
+	}
+	pipe.Set(i.ctx, i.prefix+ks, b, i.px(e))
+	pipe.ZAdd(i.ctx, i.index, redis.Z{Score: i.score(e), Member: ks})
+	return nil
+}
+
+// score 索引中的 score，即过期时间毫秒
+func (i *Instance[K, V]) score(e *cachekit.Entry[K, V]) float64 {
+	if e.Expire == 0 {
+		return neverScore
+	}
+	return float64(time.Unix(0, e.Expire).UnixMilli())
+}
+
+// px 数据在 redis 中的有效期，0 表示不过期
+func (i *Instance[K, V]) px(e *cachekit.Entry[K, V]) time.Duration {
+	if e.Expire == 0 {
+		return 0
+	}
+	return e.Remaining() + i.grace
+}
+
+func (i *Instance[K, V]) load(c redis.Cmdable, ks string) (*cachekit.Entry[K, V], error) {
+	b, err := c.Get(i.ctx, i.prefix+ks).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		ulogs.Errorf("缓存[%s]读取key[%s]失败 %v", i.identity, ks, err)
+		return nil, err
+	}
+	return cachekit.Decode[K, V](b)
+}
@@ -0,0 +1,89 @@
+package cache_redis
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"helay.net/go/utils/v3/db/localredis"
+	"helay.net/go/utils/v3/safe"
+)
+
+func newTestInstance(t *testing.T) *Instance[string, string] {
+	t.Helper()
+	rdb := localredis.NewLocalCache()
+	t.Cleanup(func() { _ = rdb.Close() })
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	// 清理间隔足够长，由测试手动触发 cleanup
+	cfg := safe.CacheConfig{EnableCleanup: true, ClearInterval: time.Hour, TTL: time.Hour}
+	i, err := New[string, string](ctx, rdb, Options{Identity: "test", CacheConfig: cfg})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return i
+}
+
+func TestLoadOrStore(t *testing.T) {
+	i := newTestInstance(t)
+	if v, loaded := i.LoadOrStore("k", "a"); loaded || v != "a" {
+		t.Fatalf("first LoadOrStore = %q,%v", v, loaded)
+	}
+	if v, loaded := i.LoadOrStore("k", "b"); !loaded || v != "a" {
+		t.Fatalf("second LoadOrStore = %q,%v", v, loaded)
+	}
+	// 逻辑过期后重新写入
+	i.Store("k", "a", time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if v, loaded := i.LoadOrStore("k", "c"); loaded || v != "c" {
+		t.Fatalf("LoadOrStore after expiry = %q,%v", v, loaded)
+	}
+	if v, ok := i.Load("k"); !ok || v != "c" {
+		t.Fatalf("Load = %q,%v", v, ok)
+	}
+}
+
+func TestCleanupExpired(t *testing.T) {
+	i := newTestInstance(t)
+	ch := make(chan []string, 2)
+	i.SetOnExpired(func(keys []string, _ safe.EvictReason) { ch <- keys })
+	i.Store("gone", "a", time.Millisecond)
+	i.Store("kept", "b")
+	time.Sleep(5 * time.Millisecond)
+
+	i.cleanup(context.Background())
+	select {
+	case keys := <-ch:
+		if !slices.Equal(keys, []string{"gone"}) {
+			t.Fatalf("expired = %v", keys)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expired callback not called")
+	}
+	if n, _ := i.rdb.ZCard(i.ctx, i.index).Result(); n != 1 {
+		t.Fatalf("index size = %d, want 1", n)
+	}
+	// 已经处理过的 key 不会重复回调
+	i.cleanup(context.Background())
+	select {
+	case keys := <-ch:
+		t.Fatalf("expired twice: %v", keys)
+	case <-time.After(20 * time.Millisecond):
+	}
+	if _, ok := i.Load("kept"); !ok {
+		t.Fatal("kept key removed")
+	}
+}
+
+func TestEmptyIdentity(t *testing.T) {
+	rdb := localredis.NewLocalCache()
+	t.Cleanup(func() { _ = rdb.Close() })
+	i, err := New[string, string](context.Background(), rdb, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i.index != "cachemgr:{default}:i" || i.prefix != "cachemgr:{default}:k:" {
+		t.Fatalf("index = %q, prefix = %q", i.index, i.prefix)
+	}
+}
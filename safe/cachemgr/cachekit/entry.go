@@ -0,0 +1,168 @@
+package cachekit
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"strings"
+	"time"
+
+	"helay.net/go/utils/v3/safe"
+	"helay.net/go/utils/v3/tools"
+)
+
+// 非内存缓存驱动的公共工具。
+// 远端存储只能保存字节数据，所以需要把 key、value 和过期元数据一起序列化，
+// 才能在 Range、过期回调等场景还原出原始的 key。
+
+// Entry 缓存条目，非内存驱动统一按这个结构序列化存储
+type Entry[K comparable, V any] struct {
+	Key       K
+	Val       V
+	TTL       time.Duration // 有效期，<=0 表示不过期
+	Expire    int64         // 过期时间，单位纳秒，0表示不过期
+	Heartbeat int64         // 最后一次写入或刷新时间，单位纳秒
+}
+
+// Valid 判断条目在 now 时刻是否有效
+func (e *Entry[K, V]) Valid(now int64) bool {
+	return e.Expire == 0 || e.Expire > now
+}
+
+// Expired 判断条目在 now 时刻是否已过期
+func (e *Entry[K, V]) Expired(now int64) bool {
+	return !e.Valid(now)
+}
+
+// ExpireTime 过期时间，不过期返回 nil
+func (e *Entry[K, V]) ExpireTime() *time.Time {
+	if e.Expire == 0 {
+		return nil
+	}
+	t := time.Unix(0, e.Expire)
+	return &t
+}
+
+// Remaining 剩余有效期，不过期返回 0
+func (e *Entry[K, V]) Remaining() time.Duration {
+	if e.Expire == 0 {
+		return 0
+	}
+	return time.Duration(e.Expire - time.Now().UnixNano())
+}
+
+// Encode 序列化缓存条目
+func Encode[K comparable, V any](e *Entry[K, V]) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(e); err != nil {
+		return nil, fmt.Errorf("缓存数据序列化失败 %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode 反序列化缓存条目
+func Decode[K comparable, V any](b []byte) (*Entry[K, V], error) {
+	e := &Entry[K, V]{}
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(e); err != nil {
+		return nil, fmt.Errorf("缓存数据反序列化失败 %w", err)
+	}
+	return e, nil
+}
+
+// KeyString 将 key 转换为存储用的字符串
+func KeyString[K comparable](key K) string {
+	switch k := any(key).(type) {
+	case string:
+		return k
+	case fmt.Stringer:
+		return k.String()
+	}
+	return tools.Any2string(key)
+}
+
+// IsStringKey 判断 K 是否为字符串类型
+// 与内存驱动保持一致，只有字符串 key 才支持前缀、后缀删除
+func IsStringKey[K comparable]() bool {
+	var zeroK K
+	_, ok := any(zeroK).(string)
+	return ok
+}
+
+// MatchPrefix 判断 key 是否以 prefix 开头
+func MatchPrefix(key, prefix string) bool {
+	return strings.HasPrefix(key, prefix)
+}
+
+// MatchSuffix 判断 key 是否以 suffix 结尾
+func MatchSuffix(key, suffix string) bool {
+	return strings.HasSuffix(key, suffix)
+}
+
+// LikeEscape SQL LIKE 转义字符
+// 不使用反斜杠，避免 mysql 字符串字面量对反斜杠的二次转义
+const LikeEscape = "!"
+
+var likeReplacer = strings.NewReplacer(LikeEscape, LikeEscape+LikeEscape, "%", LikeEscape+"%", "_", LikeEscape+"_")
+
+// EscapeLike 转义 SQL LIKE 中的通配符，配合 ESCAPE '!' 使用
+func EscapeLike(s string) string {
+	return likeReplacer.Replace(s)
+}
+
+// TTL 过期策略，与 safe.Map 的规则保持一致：
+// 只有启用自动清理后过期时间才生效，私有 TTL 优先于默认 TTL，ttl<=0 表示不过期。
+type TTL struct {
+	Enable   bool
+	Default  time.Duration
+	Interval time.Duration // 过期数据清理间隔
+}
+
+// NewTTL 根据缓存配置创建过期策略
+func NewTTL(cfg safe.CacheConfig) TTL {
+	t := TTL{Enable: cfg.EnableCleanup}
+	if t.Enable {
+		t.Default = cfg.TTL
+		t.Interval = tools.AutoTimeDuration(cfg.ClearInterval, time.Second, 30*time.Second) // 默认三十秒清理一次
+	}
+	return t
+}
+
+// NewEntry 创建新的缓存条目
+func NewEntry[K comparable, V any](t TTL, key K, val V, duration ...time.Duration) *Entry[K, V] {
+	e := &Entry[K, V]{
+		Key:       key,
+		Val:       val,
+		Heartbeat: time.Now().UnixNano(),
+	}
+	if t.Enable {
+		e.TTL = t.Default
+		if len(duration) > 0 && duration[0] > 0 {
+			e.TTL = duration[0]
+		}
+		if e.TTL > 0 {
+			e.Expire = time.Now().Add(e.TTL).UnixNano()
+		}
+	}
+	return e
+}
+
+// Refresh 刷新条目的过期时间，传入 duration 时会替换原有 TTL
+func Refresh[K comparable, V any](e *Entry[K, V], duration ...time.Duration) {
+	if len(duration) > 0 {
+		e.TTL = duration[0]
+	}
+	e.Heartbeat = time.Now().UnixNano()
+	// 如果ttl<=0，则表示不过期
+	if e.TTL <= 0 {
+		e.Expire = 0
+	} else {
+		e.Expire = time.Now().Add(e.TTL).UnixNano()
+	}
+}
+
+// Notify 异步触发过期回调
+func Notify[K comparable](onExpired safe.OnExpired[K], keys []K) {
+	if onExpired != nil && len(keys) > 0 {
+		go onExpired(keys, safe.ReasonExpired)
+	}
+}
@@ -0,0 +1,505 @@
+package rdbms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"helay.net/go/utils/v3/dataType"
+	"helay.net/go/utils/v3/db/userDb"
+	"helay.net/go/utils/v3/logger/ulogs"
+	"helay.net/go/utils/v3/safe"
+	"helay.net/go/utils/v3/safe/cachemgr/cachekit"
+	"helay.net/go/utils/v3/tools"
+)
+
+// Mode 缓存表模式
+type Mode string
+
+const (
+	ModeSafe Mode = "safe" // 单hash模式，使用 CacheSafe 表
+	ModeFast Mode = "fast" // 双hash模式，使用 CacheFast 表
+)
+
+const batchSize = 500 // 遍历、清理时每批处理的数量
+
+// Options 关系数据库缓存配置
+type Options struct {
+	Identity string // 缓存实例标识，最长32个字符
+	Mode     Mode   // 缓存表模式，默认 safe
+	safe.CacheConfig
+}
+
+// record 查询缓存行时使用的公共字段
+type record struct {
+	CacheKey    string
+	Value       dataType.SessionValue
+	ExpiresTime *dataType.CustomTime
+}
+
+// Instance 关系数据库缓存驱动
+// 所有缓存实例共用一张表，通过 instance_hash 隔离数据。
+type Instance[K comparable, V any] struct {
+	ctx          context.Context
+	db           *gorm.DB
+	mode         Mode
+	identity     string
+	instanceHash uint64
+	ttl          cachekit.TTL
+	onExpired    safe.OnExpired[K]
+	flight       safe.SingleFlight[K, V] // 合并本进程内同一个 key 的并发加载
+}
+
+// New 创建关系数据库缓存
+func New[K comparable, V any](ctx context.Context, db *gorm.DB, opt Options) (*Instance[K, V], error) {
+	if db == nil {
+		return nil, errors.New("关系数据库缓存驱动未设置数据库连接")
+	}
+	if len(opt.Identity) > 32 {
+		return nil, fmt.Errorf("缓存实例标识[%s]长度不能超过32", opt.Identity)
+	}
+	opt.Mode = tools.Ternary(opt.Mode == "", ModeSafe, opt.Mode)
+	if opt.Mode != ModeSafe && opt.Mode != ModeFast {
+		return nil, fmt.Errorf("不支持的缓存表模式[%s]", opt.Mode)
+	}
+	i := &Instance[K, V]{
+		ctx:          ctx,
+		db:           db.Session(&gorm.Session{}),
+		mode:         opt.Mode,
+		identity:     opt.Identity,
+		instanceHash: hash(opt.Identity),
+		ttl:          cachekit.NewTTL(opt.CacheConfig),
+	}
+	userDb.AutoCreateTableWithStruct(i.db, i.model(), "创建缓存表失败")
+	tools.RunAsyncTickerWithContext(ctx, i.ttl.Enable, i.ttl.Interval, i.cleanup)
+	return i, nil
+}
+
+// SetOnExpired 设置过期回调函数。
+func (i *Instance[K, V]) SetOnExpired(onExpired safe.OnExpired[K]) {
+	i.onExpired = onExpired
+}
+
+// Load 获取键的值。
+func (i *Instance[K, V]) Load(key K) (V, bool) {
+	var zero V
+	e, err := i.load(i.tx(), cachekit.KeyString(key))
+	if err != nil || e == nil || e.Expired(time.Now().UnixNano()) {
+		return zero, false
+	}
+	return e.Val, true
+}
+
+// LoadOrStore 获取键的值，如果没有则存储键的值。
+func (i *Instance[K, V]) LoadOrStore(key K, val V, duration ...time.Duration) (V, bool) {
+	ks := cachekit.KeyString(key)
+	e := cachekit.NewEntry(i.ttl, key, val, duration...)
+	row, err := i.row(ks, e)
+	if err != nil {
+		ulogs.Errorf("缓存[%s]写入key[%s]失败 %v", i.identity, ks, err)
+		return val, false
+	}
+	res := i.tx().Clauses(clause.OnConflict{DoNothing: true}).Create(row)
+	if res.Error != nil {
+		ulogs.Errorf("缓存[%s]写入key[%s]失败 %v", i.identity, ks, res.Error)
+		return val, false
+	}
+	if res.RowsAffected > 0 {
+		return val, false
+	}
+	// 已经存在，如果旧数据已过期，则有条件覆盖，保证并发下只有一个写入者成功
+	if old, _ := i.load(i.tx(), ks); old != nil && old.Valid(time.Now().UnixNano()) {
+		return old.Val, true
+	}
+	res = i.tx().Model(i.model()).Where(i.keyWhere(ks)).
+		Where(clause.Lte{Column: "expires_time", Value: time.Now()}).
+		Updates(i.updates(row))
+	if res.Error == nil && res.RowsAffected > 0 {
+		return val, false
+	}
+	if old, _ := i.load(i.tx(), ks); old != nil && old.Valid(time.Now().UnixNano()) {
+		return old.Val, true
+	}
+	return val, false
+}
+
+// LoadOrStoreFunc 获取键的值，如果没有则存储键的值。
+// 本进程内同一个 key 的并发加载只会执行一次 valueFunc，其他调用共享结果。
+func (i *Instance[K, V]) LoadOrStoreFunc(key K, valueFunc func(k K) (V, error), duration ...time.Duration) (V, bool, error) {
+	if val, ok := i.Load(key); ok {
+		return val, true, nil
+	}
+	var zero V
+	if valueFunc == nil {
+		return zero, false, nil
+	}
+	loaded := false
+	val, shared, err := i.flight.Do(key, func() (V, error) {
+		val, err := valueFunc(key)
+		if err != nil {
+			return val, err
+		}
+		// 其他进程可能已经写入，以已存在的值为准
+		val, loaded = i.LoadOrStore(key, val, duration...)
+		return val, nil
+	})
+	if err != nil {
+		return val, false, err
+	}
+	return val, loaded || shared, nil
+}
+
+// LoadAndDelete 获取键的值并删除键值对。
+func (i *Instance[K, V]) LoadAndDelete(key K) (V, bool) {
+	return i.LoadAndDeleteIf(key, nil)
+}
+
+// LoadAndDeleteIf 获取键的值并删除键值对，条件性删除
+// 返回值第二个参数 如果 == true,表示删除成功
+func (i *Instance[K, V]) LoadAndDeleteIf(key K, condition func(value V) bool) (V, bool) {
+	var (
+		zero    V
+		val     V
+		deleted bool
+		ks      = cachekit.KeyString(key)
+	)
+	err := i.tx().Transaction(func(tx *gorm.DB) error {
+		e, err := i.load(tx, ks)
+		if err != nil || e == nil {
+			return err
+		}
+		if e.Expired(time.Now().UnixNano()) {
+			// 值无效，但是值存在，就删除。
+			return tx.Where(i.keyWhere(ks)).Delete(i.model()).Error
+		}
+		val = e.Val
+		if condition != nil && !condition(e.Val) {
+			return nil
+		}
+		res := tx.Where(i.keyWhere(ks)).Delete(i.model())
+		deleted = res.Error == nil && res.RowsAffected > 0
+		return res.Error
+	})
+	if err != nil {
+		ulogs.Errorf("缓存[%s]删除key[%s]失败 %v", i.identity, ks, err)
+		return zero, false
+	}
+	return val, deleted
+}
+
+// LoadAndRefresh 获取键的值并刷新过期时间。
+func (i *Instance[K, V]) LoadAndRefresh(key K, duration ...time.Duration) (V, bool) {
+	var zero V
+	ks := cachekit.KeyString(key)
+	e, err := i.load(i.tx(), ks)
+	if err != nil || e == nil {
+		return zero, false
+	}
+	if e.Expired(time.Now().UnixNano()) {
+		i.delete(ks)
+		return zero, false
+	}
+	if i.ttl.Enable {
+		cachekit.Refresh(e, duration...)
+		if err = i.save(ks, e, false); err != nil {
+			ulogs.Errorf("缓存[%s]刷新key[%s]失败 %v", i.identity, ks, err)
+		}
+	}
+	return e.Val, true
+}
+
+// LoadWithExpiry 获取键的值和过期时间
+func (i *Instance[K, V]) LoadWithExpiry(key K) (V, time.Time, bool) {
+	var zero V
+	e, err := i.load(i.tx(), cachekit.KeyString(key))
+	if err != nil || e == nil || e.Expired(time.Now().UnixNano()) {
+		return zero, time.Time{}, false
+	}
+	return e.Val, time.Unix(0, e.Expire), true
+}
+
+// Refresh 更新键的过期时间
+func (i *Instance[K, V]) Refresh(key K, duration ...time.Duration) bool {
+	// 如果没有启用自动清理功能，则返回false
+	if !i.ttl.Enable {
+		return false
+	}
+	_, ok := i.LoadAndRefresh(key, duration...)
+	return ok
+}
+
+// GetTTL 获取键的剩余存活时间
+func (i *Instance[K, V]) GetTTL(key K) (time.Duration, bool) {
+	e, err := i.load(i.tx(), cachekit.KeyString(key))
+	if err != nil || e == nil || e.Expired(time.Now().UnixNano()) {
+		return 0, false
+	}
+	return e.Remaining(), true
+}
+
+// IsExpired 检测键是否已过期
+func (i *Instance[K, V]) IsExpired(key K) bool {
+	e, err := i.load(i.tx(), cachekit.KeyString(key))
+	if err != nil || e == nil {
+		return false
+	}
+	return e.Expired(time.Now().UnixNano())
+}
+
+// GetHeartbeat 获取键的最后更新时间
+func (i *Instance[K, V]) GetHeartbeat(key K) (time.Time, bool) {
+	e, err := i.load(i.tx(), cachekit.KeyString(key))
+	if err != nil || e == nil || e.Expired(time.Now().UnixNano()) {
+		return time.Time{}, false
+	}
+	return time.Unix(0, e.Heartbeat), true
+}
+
+// Store 存储键的值。
+func (i *Instance[K, V]) Store(key K, val V, duration ...time.Duration) {
+	ks := cachekit.KeyString(key)
+	if err := i.save(ks, cachekit.NewEntry(i.ttl, key, val, duration...), true); err != nil {
+		ulogs.Errorf("缓存[%s]写入key[%s]失败 %v", i.identity, ks, err)
+	}
+}
+
+// Delete 移除键的值。
+func (i *Instance[K, V]) Delete(key K) {
+	i.delete(cachekit.KeyString(key))
+}
+
+// DeleteAndGetCount 删除多个键并返回删除的数量
+func (i *Instance[K, V]) DeleteAndGetCount(keys ...K) int {
+	count := 0
+	for _, chunk := range tools.ArrayChunk(keys, batchSize) {
+		tx := i.tx().Where("instance_hash = ?", dataType.NewUint64(i.instanceHash))
+		ks := make([]string, 0, len(chunk))
+		for _, key := range chunk {
+			ks = append(ks, cachekit.KeyString(key))
+		}
+		if i.mode == ModeFast {
+			hashes := make([]dataType.Uint64, 0, len(ks))
+			for _, k := range ks {
+				hashes = append(hashes, dataType.NewUint64(hash(k)))
+			}
+			tx = tx.Where("key_hash IN ?", hashes)
+		}
+		res := tx.Where("cache_key IN ?", ks).Delete(i.model())
+		if res.Error != nil {
+			ulogs.Errorf("缓存[%s]批量删除失败 %v", i.identity, res.Error)
+			continue
+		}
+		count += int(res.RowsAffected)
+	}
+	return count
+}
+
+// DeleteAll 删除所有键值对
+func (i *Instance[K, V]) DeleteAll() {
+	err := i.tx().Where("instance_hash = ?", dataType.NewUint64(i.instanceHash)).Delete(i.model()).Error
+	ulogs.CheckErrf(err, "缓存[%s]清空失败", i.identity)
+}
+
+// Range 遍历所有未过期的键值对
+func (i *Instance[K, V]) Range(f func(key K, value V) bool) {
+	last := ""
+	for {
+		var records []record
+		err := i.tx().Model(i.model()).
+			Where("instance_hash = ?", dataType.NewUint64(i.instanceHash)).
+			Where("cache_key > ?", last).
+			Where(clause.Or(clause.Eq{Column: "expires_time", Value: nil}, clause.Gt{Column: "expires_time", Value: time.Now()})).
+			Order("cache_key").Limit(batchSize).Find(&records).Error
+		if err != nil {
+			ulogs.Errorf("缓存[%s]遍历失败 %v", i.identity, err)
+			return
+		}
+		now := time.Now().UnixNano()
+		for _, r := range records {
+			e, err := i.decode(r)
+			if err != nil {
+				ulogs.Errorf("缓存[%s]遍历key[%s]解析失败 %v", i.identity, r.CacheKey, err)
+				continue
+			}
+			if e.Valid(now) && !f(e.Key, e.Val) {
+				return
+			}
+		}
+		if len(records) < batchSize {
+			return
+		}
+		last = records[len(records)-1].CacheKey
+	}
+}
+
+// DeletePrefix 删除指定前缀的键值对
+func (i *Instance[K, V]) DeletePrefix(prefix string) {
+	i.deleteLike(cachekit.EscapeLike(prefix) + "%")
+}
+
+// DeleteSuffix 删除指定后缀的键值对
+func (i *Instance[K, V]) DeleteSuffix(suffix string) {
+	i.deleteLike("%" + cachekit.EscapeLike(suffix))
+}
+
+func (i *Instance[K, V]) deleteLike(pattern string) {
+	// 如果K是string类型，才执行这个
+	if !cachekit.IsStringKey[K]() {
+		return
+	}
+	err := i.tx().Where("instance_hash = ?", dataType.NewUint64(i.instanceHash)).
+		Where("cache_key LIKE ? ESCAPE '"+cachekit.LikeEscape+"'", pattern).
+		Delete(i.model()).Error
+	ulogs.CheckErrf(err, "缓存[%s]按规则[%s]删除失败", i.identity, pattern)
+}
+
+// cleanup 清理过期数据，并触发过期回调
+// 多个节点共享同一张表时，只有真正删除成功的节点才会触发回调
+func (i *Instance[K, V]) cleanup(ctx context.Context) {
+	var expiredKeys []K
+	for {
+		var records []record
+		err := i.db.WithContext(ctx).Model(i.model()).
+			Where("instance_hash = ?", dataType.NewUint64(i.instanceHash)).
+			Where(clause.Lte{Column: "expires_time", Value: time.Now()}).
+			Limit(batchSize).Find(&records).Error
+		if err != nil {
+			ulogs.Errorf("缓存[%s]清理过期数据失败 %v", i.identity, err)
+			break
+		}
+		for _, r := range records {
+			res := i.db.WithContext(ctx).Where(i.keyWhere(r.CacheKey)).
+				Where(clause.Lte{Column: "expires_time", Value: time.Now()}).
+				Delete(i.model())
+			if res.Error != nil || res.RowsAffected < 1 || i.onExpired == nil {
+				continue
+			}
+			if e, err := i.decode(r); err == nil {
+				expiredKeys = append(expiredKeys, e.Key)
+			}
+		}
+		if len(records) < batchSize || ctx.Err() != nil {
+			break
+		}
+	}
+	cachekit.Notify(i.onExpired, expiredKeys)
+}
+
+// hash 计算 key 的 hash，只保留63位，保证在有符号 BIGINT 字段中也能存储
+func hash(s string) uint64 {
+	return xxhash.Sum64String(s) & math.MaxInt64
+}
+
+func (i *Instance[K, V]) tx() *gorm.DB {
+	return i.db.WithContext(i.ctx)
+}
+
+// model 当前模式对应的表模型
+func (i *Instance[K, V]) model() any {
+	if i.mode == ModeFast {
+		return CacheFast{}
+	}
+	return CacheSafe{}
+}
+
+// keyWhere 单个key的查询条件
+func (i *Instance[K, V]) keyWhere(ks string) map[string]any {
+	where := map[string]any{
+		"instance_hash": dataType.NewUint64(i.instanceHash),
+		"cache_key":     ks,
+	}
+	if i.mode == ModeFast {
+		where["key_hash"] = dataType.NewUint64(hash(ks))
+	}
+	return where
+}
+
+// row 根据缓存条目生成表数据
+func (i *Instance[K, V]) row(ks string, e *cachekit.Entry[K, V]) (any, error) {
+	b, err := cachekit.Encode(e)
+	if err != nil {
+		return nil, err
+	}
+	var expires *dataType.CustomTime
+	if t := e.ExpireTime(); t != nil {
+		expires = dataType.NewCustomTime(*t).ToPtr()
+	}
+	if i.mode == ModeFast {
+		return &CacheFast{
+			InstanceHash: dataType.NewUint64(i.instanceHash),
+			KeyHash:      dataType.NewUint64(hash(ks)),
+			InstanceID:   i.identity,
+			CacheKey:     ks,
+			Value:        dataType.NewSessionValue(b),
+			ExpiresTime:  expires,
+		}, nil
+	}
+	return &CacheSafe{
+		InstanceHash: dataType.NewUint64(i.instanceHash),
+		CacheKey:     ks,
+		InstanceID:   i.identity,
+		Value:        dataType.NewSessionValue(b),
+		ExpiresTime:  expires,
+	}, nil
+}
+
+// updates 覆盖写入时需要更新的字段
+func (i *Instance[K, V]) updates(row any) map[string]any {
+	switch r := row.(type) {
+	case *CacheFast:
+		return map[string]any{"cache_key": r.CacheKey, "value": r.Value, "expires_time": r.ExpiresTime, "update_time": time.Now()}
+	case *CacheSafe:
+		return map[string]any{"value": r.Value, "expires_time": r.ExpiresTime, "update_time": time.Now()}
+	}
+	return nil
+}
+
+// save 保存缓存条目，upsert 为 false 时只更新已存在的数据
+func (i *Instance[K, V]) save(ks string, e *cachekit.Entry[K, V], upsert bool) error {
+	row, err := i.row(ks, e)
+	if err != nil {
+		return err
+	}
+	if !upsert {
+		return i.tx().Model(i.model()).Where(i.keyWhere(ks)).Updates(i.updates(row)).Error
+	}
+	columns := []clause.Column{{Name: "instance_hash"}, {Name: "cache_key"}}
+	if i.mode == ModeFast {
+		columns = []clause.Column{{Name: "instance_hash"}, {Name: "key_hash"}}
+	}
+	return i.tx().Clauses(clause.OnConflict{
+		Columns:   columns,
+		DoUpdates: clause.AssignmentColumns([]string{"instance_id", "cache_key", "value", "expires_time", "update_time"}),
+	}).Create(row).Error
+}
+
+func (i *Instance[K, V]) load(tx *gorm.DB, ks string) (*cachekit.Entry[K, V], error) {
+	var r record
+	err := tx.Model(i.model()).Where(i.keyWhere(ks)).Take(&r).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		ulogs.Errorf("缓存[%s]读取key[%s]失败 %v", i.identity, ks, err)
+		return nil, err
+	}
+	return i.decode(r)
+}
+
+func (i *Instance[K, V]) decode(r record) (*cachekit.Entry[K, V], error) {
+	b, ok := r.Value.Val.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("缓存数据格式错误 %T", r.Value.Val)
+	}
+	return cachekit.Decode[K, V](b)
+}
+
+func (i *Instance[K, V]) delete(ks string) {
+	err := i.tx().Where(i.keyWhere(ks)).Delete(i.model()).Error
+	ulogs.CheckErrf(err, "缓存[%s]删除key[%s]失败", i.identity, ks)
+}
@@ -0,0 +1,489 @@
+package cachemgr
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"helay.net/go/utils/v3/logger/ulogs"
+	"helay.net/go/utils/v3/message/pubsub"
+	"helay.net/go/utils/v3/safe"
+	"helay.net/go/utils/v3/safe/cachemgr/cachekit"
+	"helay.net/go/utils/v3/tools"
+)
+
+// 两级缓存：进程内 safe.Map 作为 L1，redis、关系数据库等共享存储作为 L2。
+//
+// 读取先查 L1，未命中时从 L2 读取并回填 L1；写入和删除先落到 L2，再更新本地 L1，
+// 并通过订阅发布通知其他节点删除各自的 L1。
+//
+// L2 中的值带有版本号（混合逻辑时钟），失效消息同样携带版本号：
+//   - 收到其他节点的写入或删除通知时总是删除 L1 中的值，即使本地版本更高。
+//     L2 按到达顺序覆盖写入，并发写入时 L2 中保留的不一定是版本最高的值，以 L2 为准重新读取
+//   - 删除后在 L1 中保留墓碑，回填时版本低于墓碑的值会被丢弃，
+//     避免失效消息到达前发起的 L2 读取在之后把旧值写回 L1；乱序到达的旧消息不会降低墓碑版本
+//   - 清空时提高全局的最低版本；前缀、后缀删除按规则记录最低版本，只影响匹配的 key，
+//     规则的最低版本超过 L1 有效期后不再需要，写入新规则时清理
+//
+// 版本号依赖各节点的时钟，收到通知和从 L2 读取时会推进本地时钟，节点间的时钟偏差应远小于 L1 的有效期。
+// 订阅发布不保证送达，L1 始终有过期时间，作为消息丢失时的兜底。
+
+const (
+	tieredOpSet    = "set"
+	tieredOpDel    = "del"
+	tieredOpAll    = "all"
+	tieredOpPrefix = "prefix"
+	tieredOpSuffix = "suffix"
+
+	tieredLockSize = 256
+)
+
+var _ Cache[string, any] = (*Tiered[string, any])(nil)
+
+// Versioned 两级缓存在 L2 中存储的值
+type Versioned[V any] struct {
+	Ver uint64 // 版本号
+	Val V
+}
+
+// TieredOptions 两级缓存配置
+type TieredOptions struct {
+	Identity string           `json:"identity" yaml:"identity" ini:"identity"` // 缓存标识，同一个标识的节点之间互相通知
+	Topic    string           `json:"topic" yaml:"topic" ini:"topic"`          // 失效通知的主题，默认 cachemgr_tiered
+	Local    safe.CacheConfig `json:"local" yaml:"local" ini:"local"`          // L1 配置，总是启用过期清理，ttl 默认 1 分钟
+}
+
+// tieredEvent 失效通知
+type tieredEvent[K comparable] struct {
+	Node    string `json:"n"`           // 发送节点，忽略自己发出的消息
+	Op      string `json:"o"`           // 操作类型
+	Ver     uint64 `json:"v"`           // 版本号
+	Keys    []K    `json:"k,omitempty"` // 涉及的 key
+	Pattern string `json:"p,omitempty"` // 前缀、后缀删除的规则
+}
+
+// tieredEntry L1 中的条目，deleted 为 true 时是墓碑
+type tieredEntry[V any] struct {
+	ver     uint64
+	val     V
+	deleted bool
+}
+
+// Tiered 两级缓存
+type Tiered[K comparable, V any] struct {
+	ctx    context.Context
+	hasher safe.Hasher[K]
+	l1     *safe.Map[K, tieredEntry[V]]
+	l2     Cache[K, Versioned[V]]
+	ps     pubsub.Handler
+	param  pubsub.Params
+	node   string
+	ttl    time.Duration // L1 默认有效期
+
+	clock atomic.Uint64 // 混合逻辑时钟
+	floor atomic.Uint64 // 清空时的版本，低于该版本的值不回填
+	locks [tieredLockSize]sync.Mutex
+
+	patternMu sync.RWMutex
+	patterns  map[tieredPattern]uint64 // 前缀、后缀删除时的版本，匹配的 key 低于该版本的值不回填
+}
+
+// tieredPattern 前缀、后缀删除的规则
+type tieredPattern struct {
+	op      string
+	pattern string
+}
+
+func (p tieredPattern) match(key string) bool {
+	if p.op == tieredOpPrefix {
+		return cachekit.MatchPrefix(key, p.pattern)
+	}
+	return cachekit.MatchSuffix(key, p.pattern)
+}
+
+// NewTiered 创建两级缓存，l2 可以通过 New[K, Versioned[V]] 创建，ps 为空时只在本节点生效
+func NewTiered[K comparable, V any](ctx context.Context, hasher safe.Hasher[K], l2 Cache[K, Versioned[V]], ps pubsub.Handler, opt TieredOptions) (*Tiered[K, V], error) {
+	if l2 == nil {
+		return nil, errors.New("两级缓存未设置L2缓存")
+	}
+	opt.Local.EnableCleanup = true
+	opt.Local.TTL = tools.AutoTimeDuration(opt.Local.TTL, time.Second, time.Minute)
+	node := make([]byte, 8)
+	_, _ = rand.Read(node)
+	t := &Tiered[K, V]{
+		ctx:    ctx,
+		hasher: hasher,
+		l1:     safe.NewMap[K, tieredEntry[V]](ctx, hasher, opt.Local),
+		l2:     l2,
+		ps:     ps,
+		param:  pubsub.Params{Topic: tools.Ternary(opt.Topic == "", "cachemgr_tiered", opt.Topic), Key: opt.Identity},
+		node:   hex.EncodeToString(node),
+		ttl:    opt.Local.TTL,
+
+		patterns: make(map[tieredPattern]uint64),
+	}
+	if ps != nil {
+		go ps.Subscribe(t.param, &pubsub.Cbfunc{CbByte: t.receive})
+	}
+	return t, nil
+}
+
+// now 生成新的版本号，不小于当前纳秒时间，且大于之前生成或收到的所有版本号
+func (t *Tiered[K, V]) now() uint64 {
+	for {
+		last := t.clock.Load()
+		ver := max(uint64(time.Now().UnixNano()), last+1)
+		if t.clock.CompareAndSwap(last, ver) {
+			return ver
+		}
+	}
+}
+
+// observe 收到其他节点的版本号后推进本地时钟
+func (t *Tiered[K, V]) observe(ver uint64) {
+	for {
+		last := t.clock.Load()
+		if ver <= last || t.clock.CompareAndSwap(last, ver) {
+			return
+		}
+	}
+}
+
+func (t *Tiered[K, V]) lock(key K) *sync.Mutex {
+	return &t.locks[t.hasher.Hash(key)%tieredLockSize]
+}
+
+// fill 回填 L1，版本低于 L1 中已有的值或墓碑时丢弃
+func (t *Tiered[K, V]) fill(key K, v Versioned[V], expire time.Time) {
+	t.observe(v.Ver)
+	if v.Ver < t.floor.Load() || v.Ver < t.patternFloor(key) {
+		return
+	}
+	ttl := t.ttl
+	if ns := expire.UnixNano(); ns > 0 {
+		remain := time.Until(expire)
+		if remain <= 0 {
+			return
+		}
+		ttl = min(ttl, remain)
+	}
+	mu := t.lock(key)
+	mu.Lock()
+	defer mu.Unlock()
+	if cur, ok := t.l1.Load(key); ok && cur.ver > v.Ver {
+		return
+	}
+	t.l1.Store(key, tieredEntry[V]{ver: v.Ver, val: v.Val}, ttl)
+}
+
+// invalidate 删除 L1 中的值并留下版本为 ver 的墓碑，已有更高版本的墓碑时保留原墓碑
+func (t *Tiered[K, V]) invalidate(key K, ver uint64) {
+	mu := t.lock(key)
+	mu.Lock()
+	defer mu.Unlock()
+	if cur, ok := t.l1.Load(key); ok && cur.deleted && cur.ver >= ver {
+		return
+	}
+	t.l1.Store(key, tieredEntry[V]{ver: ver, deleted: true}, t.ttl)
+}
+
+// clear 按规则清理 L1，并提高回填的最低版本
+// 清空时提高全局的最低版本，前缀、后缀删除只记录该规则的最低版本
+func (t *Tiered[K, V]) clear(op, pattern string, ver uint64) {
+	switch op {
+	case tieredOpAll:
+		for {
+			last := t.floor.Load()
+			if ver <= last || t.floor.CompareAndSwap(last, ver) {
+				break
+			}
+		}
+		t.l1.DeleteAll()
+	case tieredOpPrefix, tieredOpSuffix:
+		t.raisePattern(tieredPattern{op: op, pattern: pattern}, ver)
+		if op == tieredOpPrefix {
+			t.l1.DeletePrefix(pattern)
+		} else {
+			t.l1.DeleteSuffix(pattern)
+		}
+	}
+}
+
+// raisePattern 提高规则的最低版本，并清理超过 L1 有效期的规则
+func (t *Tiered[K, V]) raisePattern(p tieredPattern, ver uint64) {
+	expired := uint64(max(time.Now().Add(-t.ttl).UnixNano(), 0))
+	t.patternMu.Lock()
+	defer t.patternMu.Unlock()
+	for k, v := range t.patterns {
+		if v < expired {
+			delete(t.patterns, k)
+		}
+	}
+	t.patterns[p] = max(t.patterns[p], ver)
+}
+
+// patternFloor key 匹配的前缀、后缀删除规则中最高的版本
+func (t *Tiered[K, V]) patternFloor(key K) uint64 {
+	if !cachekit.IsStringKey[K]() {
+		return 0
+	}
+	t.patternMu.RLock()
+	defer t.patternMu.RUnlock()
+	if len(t.patterns) == 0 {
+		return 0
+	}
+	ks := cachekit.KeyString(key)
+	var floor uint64
+	for p, ver := range t.patterns {
+		if ver > floor && p.match(ks) {
+			floor = ver
+		}
+	}
+	return floor
+}
+
+// publish 通知其他节点
+func (t *Tiered[K, V]) publish(ev tieredEvent[K]) {
+	if t.ps == nil {
+		return
+	}
+	ev.Node = t.node
+	byt, err := json.Marshal(ev)
+	if err != nil {
+		ulogs.Errorf("两级缓存[%s]失效通知序列化失败 %v", t.param.Key, err)
+		return
+	}
+	if err = t.ps.Publish(t.param, byt); err != nil {
+		ulogs.Errorf("两级缓存[%s]发送失效通知失败 %v", t.param.Key, err)
+	}
+}
+
+// receive 处理其他节点的失效通知
+func (t *Tiered[K, V]) receive(msg []byte) {
+	var ev tieredEvent[K]
+	if err := json.Unmarshal(msg, &ev); err != nil {
+		ulogs.Errorf("两级缓存[%s]失效通知解析失败 %v", t.param.Key, err)
+		return
+	}
+	if ev.Node == t.node {
+		return
+	}
+	t.observe(ev.Ver)
+	switch ev.Op {
+	case tieredOpSet, tieredOpDel:
+		for _, key := range ev.Keys {
+			t.invalidate(key, ev.Ver)
+		}
+	default:
+		t.clear(ev.Op, ev.Pattern, ev.Ver)
+	}
+}
+
+// written 本节点写入 L2 后更新 L1 并通知其他节点
+func (t *Tiered[K, V]) written(key K, v Versioned[V], duration ...time.Duration) {
+	ttl := t.ttl
+	if len(duration) > 0 && duration[0] > 0 {
+		ttl = min(ttl, duration[0])
+	}
+	mu := t.lock(key)
+	mu.Lock()
+	if cur, ok := t.l1.Load(key); !ok || cur.ver <= v.Ver {
+		t.l1.Store(key, tieredEntry[V]{ver: v.Ver, val: v.Val}, ttl)
+	}
+	mu.Unlock()
+	t.publish(tieredEvent[K]{Op: tieredOpSet, Ver: v.Ver, Keys: []K{key}})
+}
+
+// refill 从 L2 读取值和过期时间后回填 L1，用于 L2 接口不返回过期时间的场景
+func (t *Tiered[K, V]) refill(key K) {
+	if v, expire, ok := t.l2.LoadWithExpiry(key); ok {
+		t.fill(key, v, expire)
+	}
+}
+
+// deleted 本节点删除 L2 后清理 L1 并通知其他节点
+func (t *Tiered[K, V]) deleted(keys ...K) {
+	if len(keys) == 0 {
+		return
+	}
+	ver := t.now()
+	for _, key := range keys {
+		t.invalidate(key, ver)
+	}
+	t.publish(tieredEvent[K]{Op: tieredOpDel, Ver: ver, Keys: keys})
+}
+
+// SetOnExpired 设置过期回调函数，由 L2 触发
+func (t *Tiered[K, V]) SetOnExpired(onExpired safe.OnExpired[K]) {
+	t.l2.SetOnExpired(onExpired)
+}
+
+// Load 获取键的值，L1 未命中时从 L2 读取并回填
+func (t *Tiered[K, V]) Load(key K) (V, bool) {
+	if e, ok := t.l1.Load(key); ok && !e.deleted {
+		return e.val, true
+	}
+	v, expire, ok := t.l2.LoadWithExpiry(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	t.fill(key, v, expire)
+	return v.Val, true
+}
+
+// LoadOrStore 获取键的值，如果没有则存储键的值。
+func (t *Tiered[K, V]) LoadOrStore(key K, val V, duration ...time.Duration) (V, bool) {
+	if e, ok := t.l1.Load(key); ok && !e.deleted {
+		return e.val, true
+	}
+	v, loaded := t.l2.LoadOrStore(key, Versioned[V]{Ver: t.now(), Val: val}, duration...)
+	if loaded {
+		t.refill(key)
+	} else {
+		t.written(key, v, duration...)
+	}
+	return v.Val, loaded
+}
+
+// LoadOrStoreFunc 获取键的值，如果没有则调用 valueFunc 加载并存储，同一个 key 的并发加载由 L2 合并。
+func (t *Tiered[K, V]) LoadOrStoreFunc(key K, valueFunc func(k K) (V, error), duration ...time.Duration) (V, bool, error) {
+	if e, ok := t.l1.Load(key); ok && !e.deleted {
+		return e.val, true, nil
+	}
+	if valueFunc == nil {
+		v, loaded, err := t.l2.LoadOrStoreFunc(key, nil)
+		if loaded {
+			t.refill(key)
+		}
+		return v.Val, loaded, err
+	}
+	stored := false
+	v, loaded, err := t.l2.LoadOrStoreFunc(key, func(k K) (Versioned[V], error) {
+		stored = true
+		val, err := valueFunc(k)
+		return Versioned[V]{Ver: t.now(), Val: val}, err
+	}, duration...)
+	switch {
+	case err != nil:
+		return v.Val, false, err
+	case stored && !loaded:
+		t.written(key, v, duration...)
+	default:
+		t.refill(key)
+	}
+	return v.Val, loaded, nil
+}
+
+// LoadAndDelete 获取键的值并删除键值对。
+func (t *Tiered[K, V]) LoadAndDelete(key K) (V, bool) {
+	return t.LoadAndDeleteIf(key, nil)
+}
+
+// LoadAndDeleteIf 获取键的值并删除键值对，条件性删除
+// 返回值第二个参数 如果 == true,表示删除成功
+func (t *Tiered[K, V]) LoadAndDeleteIf(key K, condition func(value V) bool) (V, bool) {
+	var cond func(v Versioned[V]) bool
+	if condition != nil {
+		cond = func(v Versioned[V]) bool { return condition(v.Val) }
+	}
+	v, ok := t.l2.LoadAndDeleteIf(key, cond)
+	if ok {
+		t.deleted(key)
+	}
+	return v.Val, ok
+}
+
+// LoadAndRefresh 获取键的值并刷新 L2 中的过期时间
+func (t *Tiered[K, V]) LoadAndRefresh(key K, duration ...time.Duration) (V, bool) {
+	v, ok := t.l2.LoadAndRefresh(key, duration...)
+	if ok {
+		t.refill(key)
+	}
+	return v.Val, ok
+}
+
+// LoadWithExpiry 获取键的值和 L2 中的过期时间
+func (t *Tiered[K, V]) LoadWithExpiry(key K) (V, time.Time, bool) {
+	v, expire, ok := t.l2.LoadWithExpiry(key)
+	if ok {
+		t.fill(key, v, expire)
+	}
+	return v.Val, expire, ok
+}
+
+// Refresh 刷新 L2 中的过期时间
+func (t *Tiered[K, V]) Refresh(key K, duration ...time.Duration) bool {
+	return t.l2.Refresh(key, duration...)
+}
+
+// GetTTL 获取 L2 中的剩余过期时间
+func (t *Tiered[K, V]) GetTTL(key K) (time.Duration, bool) {
+	return t.l2.GetTTL(key)
+}
+
+// IsExpired 判断 L2 中的键是否已过期
+func (t *Tiered[K, V]) IsExpired(key K) bool {
+	return t.l2.IsExpired(key)
+}
+
+// GetHeartbeat 获取 L2 中键的更新时间
+func (t *Tiered[K, V]) GetHeartbeat(key K) (time.Time, bool) {
+	return t.l2.GetHeartbeat(key)
+}
+
+// Store 存储键值对
+func (t *Tiered[K, V]) Store(key K, val V, duration ...time.Duration) {
+	v := Versioned[V]{Ver: t.now(), Val: val}
+	t.l2.Store(key, v, duration...)
+	t.written(key, v, duration...)
+}
+
+// Delete 删除键
+func (t *Tiered[K, V]) Delete(key K) {
+	t.l2.Delete(key)
+	t.deleted(key)
+}
+
+// DeleteAndGetCount 删除多个键并返回 L2 中删除的数量
+func (t *Tiered[K, V]) DeleteAndGetCount(keys ...K) int {
+	n := t.l2.DeleteAndGetCount(keys...)
+	t.deleted(keys...)
+	return n
+}
+
+// DeleteAll 删除所有键
+func (t *Tiered[K, V]) DeleteAll() {
+	t.l2.DeleteAll()
+	t.clearAll(tieredOpAll, "")
+}
+
+// Range 遍历 L2 中的键值对
+func (t *Tiered[K, V]) Range(f func(key K, value V) bool) {
+	t.l2.Range(func(key K, v Versioned[V]) bool {
+		return f(key, v.Val)
+	})
+}
+
+// DeletePrefix 删除以指定前缀开头的键
+func (t *Tiered[K, V]) DeletePrefix(prefix string) {
+	t.l2.DeletePrefix(prefix)
+	t.clearAll(tieredOpPrefix, prefix)
+}
+
+// DeleteSuffix 删除以指定后缀结尾的键
+func (t *Tiered[K, V]) DeleteSuffix(suffix string) {
+	t.l2.DeleteSuffix(suffix)
+	t.clearAll(tieredOpSuffix, suffix)
+}
+
+func (t *Tiered[K, V]) clearAll(op, pattern string) {
+	ver := t.now()
+	t.clear(op, pattern, ver)
+	t.publish(tieredEvent[K]{Op: op, Ver: ver, Pattern: pattern})
+}
@@ -0,0 +1,121 @@
+package cachemgr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"helay.net/go/utils/v3/safe"
+)
+
+func newTestTiered(t *testing.T, l2 Cache[string, Versioned[string]]) *Tiered[string, string] {
+	t.Helper()
+	tc, err := NewTiered[string, string](context.Background(), safe.StringHasher{}, l2, nil, TieredOptions{Identity: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tc
+}
+
+func newTestL2() *safe.Map[string, Versioned[string]] {
+	return safe.NewMap[string, Versioned[string]](context.Background(), safe.StringHasher{}, safe.CacheConfig{EnableCleanup: true, ClearInterval: time.Minute})
+}
+
+// send 模拟收到其他节点的失效通知
+func send(t *testing.T, tc *Tiered[string, string], ev tieredEvent[string]) {
+	t.Helper()
+	ev.Node = "other"
+	byt, err := json.Marshal(ev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc.receive(byt)
+}
+
+func TestTieredRemoteSetDropsNewerLocal(t *testing.T) {
+	l2 := newTestL2()
+	b := newTestTiered(t, l2)
+	b.Store("k", "b")
+	local, _ := b.l1.Load("k")
+
+	// 另一个节点的写入版本更低，但后到达 L2
+	l2.Store("k", Versioned[string]{Ver: local.ver - 1, Val: "a"})
+	send(t, b, tieredEvent[string]{Op: tieredOpSet, Ver: local.ver - 1, Keys: []string{"k"}})
+
+	if v, _ := b.Load("k"); v != "a" {
+		t.Fatalf("load = %q, want value held by L2", v)
+	}
+}
+
+func TestTieredTombstone(t *testing.T) {
+	l2 := newTestL2()
+	tc := newTestTiered(t, l2)
+	ver := tc.now()
+	l2.Store("k", Versioned[string]{Ver: ver, Val: "old"})
+
+	send(t, tc, tieredEvent[string]{Op: tieredOpDel, Ver: ver + 10, Keys: []string{"k"}})
+	// 删除通知之前发起的读取，回填时应当被墓碑拦截
+	tc.fill("k", Versioned[string]{Ver: ver, Val: "old"}, time.Time{})
+	if e, ok := tc.l1.Load("k"); !ok || !e.deleted || e.ver != ver+10 {
+		t.Fatalf("l1 = %+v, %v, want tombstone", e, ok)
+	}
+
+	// 乱序到达的旧通知不会降低墓碑版本
+	send(t, tc, tieredEvent[string]{Op: tieredOpSet, Ver: ver + 5, Keys: []string{"k"}})
+	if e, _ := tc.l1.Load("k"); e.ver != ver+10 {
+		t.Fatalf("tombstone ver = %d, want %d", e.ver, ver+10)
+	}
+
+	// 版本不低于墓碑的值可以回填
+	l2.Store("k", Versioned[string]{Ver: ver + 20, Val: "new"})
+	if v, _ := tc.Load("k"); v != "new" {
+		t.Fatalf("load = %q", v)
+	}
+	if e, _ := tc.l1.Load("k"); e.deleted || e.val != "new" {
+		t.Fatalf("l1 = %+v, want filled value", e)
+	}
+}
+
+func TestTieredClearFloor(t *testing.T) {
+	tc := newTestTiered(t, newTestL2())
+	tc.Store("a", "1")
+	ver := tc.now()
+	send(t, tc, tieredEvent[string]{Op: tieredOpPrefix, Ver: ver, Pattern: "a"})
+	if _, ok := tc.l1.Load("a"); ok {
+		t.Fatal("prefix clear did not remove l1 value")
+	}
+	tc.fill("a", Versioned[string]{Ver: ver - 1, Val: "1"}, time.Time{})
+	if _, ok := tc.l1.Load("a"); ok {
+		t.Fatal("value older than clear was filled")
+	}
+
+	// 前缀删除不影响不匹配的 key
+	tc.fill("b", Versioned[string]{Ver: ver - 1, Val: "2"}, time.Time{})
+	if _, ok := tc.l1.Load("b"); !ok {
+		t.Fatal("prefix clear blocked unrelated key")
+	}
+	send(t, tc, tieredEvent[string]{Op: tieredOpAll, Ver: tc.now()})
+	tc.fill("b", Versioned[string]{Ver: ver, Val: "2"}, time.Time{})
+	if _, ok := tc.l1.Load("b"); ok {
+		t.Fatal("value older than clear all was filled")
+	}
+}
+
+func TestTieredFillUsesL2Expiry(t *testing.T) {
+	l2 := newTestL2()
+	tc := newTestTiered(t, l2)
+	l2.Store("k", Versioned[string]{Ver: tc.now(), Val: "v"}, 50*time.Millisecond)
+
+	for name, load := range map[string]func(){
+		"LoadOrStore":     func() { tc.LoadOrStore("k", "x") },
+		"LoadOrStoreFunc": func() { _, _, _ = tc.LoadOrStoreFunc("k", func(string) (string, error) { return "x", nil }) },
+	} {
+		tc.l1.Delete("k")
+		load()
+		ttl, ok := tc.l1.GetTTL("k")
+		if !ok || ttl <= 0 || ttl > 50*time.Millisecond {
+			t.Fatalf("%s: l1 ttl = %v, %v, want within L2 expiry", name, ttl, ok)
+		}
+	}
+}
@@ -2,9 +2,15 @@ package cachemgr
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
 	"helay.net/go/utils/v3/safe"
+	"helay.net/go/utils/v3/safe/cachemgr/cache_file"
+	"helay.net/go/utils/v3/safe/cachemgr/cache_redis"
+	"helay.net/go/utils/v3/safe/cachemgr/rdbms"
 )
 
 // Cache 缓存接口
@@ -45,22 +51,53 @@ const (
 	DriverRedis Driver = "redis" // redis 缓存
 )
 
+// Config 缓存配置
+// 过期相关配置（enable_cleanup、ttl、clear_interval）对所有驱动生效，规则与内存驱动一致。
 type Config struct {
 	Driver           Driver `json:"driver" yaml:"driver" ini:"driver"`       // 缓存驱动
 	Identity         string `json:"identity" yaml:"identity" ini:"identity"` // 缓存标识，在非内存存储下，非常有用。用于隔离多个缓存实例里面的数据。
 	safe.CacheConfig `json:"memory" yaml:"memory" ini:"memory"`
+
+	RdbmsMode rdbms.Mode `json:"rdbms_mode" yaml:"rdbms_mode" ini:"rdbms_mode"` // 关系数据库缓存表模式，safe 或者 fast，默认 safe
+	FilePath  string     `json:"file_path" yaml:"file_path" ini:"file_path"`    // 文件缓存根目录，默认 runtime/cache
+
+	DB    *gorm.DB              `json:"-" yaml:"-" ini:"-"` // 关系数据库缓存使用的连接
+	Redis redis.UniversalClient `json:"-" yaml:"-" ini:"-"` // redis 缓存使用的连接
 }
 
+// New 根据配置创建缓存
+// 驱动初始化失败会 panic，未知驱动返回 nil
 func New[K comparable, V any](ctx context.Context, hasher safe.Hasher[K], cfg Config) Cache[K, V] {
 	var (
 		driver Cache[K, V]
+		err    error
 	)
 	switch cfg.Driver {
 	case DriverMemory:
 		driver = safe.NewMap[K, V](ctx, hasher, cfg.CacheConfig)
+	case DriverRdbms:
+		driver, err = rdbms.New[K, V](ctx, cfg.DB, rdbms.Options{
+			Identity:    cfg.Identity,
+			Mode:        cfg.RdbmsMode,
+			CacheConfig: cfg.CacheConfig,
+		})
+	case DriverFile:
+		driver, err = cache_file.New[K, V](ctx, cache_file.Options{
+			Identity:    cfg.Identity,
+			Path:        cfg.FilePath,
+			CacheConfig: cfg.CacheConfig,
+		})
+	case DriverRedis:
+		driver, err = cache_redis.New[K, V](ctx, cfg.Redis, cache_redis.Options{
+			Identity:    cfg.Identity,
+			CacheConfig: cfg.CacheConfig,
+		})
 	default:
 		return nil
 	}
+	if err != nil {
+		panic(fmt.Errorf("缓存[%s]驱动[%s]初始化失败 %w", cfg.Identity, cfg.Driver, err))
+	}
 
 	return NewWithDriver[K, V](driver)
 }
@@ -0,0 +1,411 @@
+package safe
+
+// 容量淘汰策略，每个分片独立维护。
+// 写入、删除在分片写锁内调用；命中在分片读锁内调用，由分片的 pmu 保证策略数据的互斥。
+
+// EvictPolicy 淘汰策略
+type EvictPolicy string
+
+const (
+	EvictLRU     EvictPolicy = "lru"     // 最近最少使用
+	EvictLFU     EvictPolicy = "lfu"     // 最不经常使用
+	EvictTinyLFU EvictPolicy = "tinylfu" // LRU 淘汰顺序，新数据需要比被淘汰数据访问频率更高才会写入，适合访问分布不均匀的场景
+	EvictARC     EvictPolicy = "arc"     // 自适应替换，根据命中情况在最近使用和经常使用之间动态调整
+)
+
+// EvictReason 数据被移除的原因
+type EvictReason string
+
+const (
+	ReasonExpired  EvictReason = "expired"  // 过期清理
+	ReasonCapacity EvictReason = "capacity" // 超过容量被淘汰
+)
+
+type policy[K comparable, V any] interface {
+	add(v *value[K, V])                        // 新写入
+	hit(v *value[K, V])                        // 命中或者覆盖写入
+	miss(hash uint64)                          // 未命中
+	remove(v *value[K, V])                     // 主动删除、过期清理
+	victim() *value[K, V]                      // 选出下一个淘汰对象
+	admit(candidate, victim *value[K, V]) bool // 是否允许新数据替换淘汰对象
+	evict(v *value[K, V])                      // 因容量淘汰
+}
+
+func newPolicy[K comparable, V any](p EvictPolicy, capacity int) policy[K, V] {
+	switch p {
+	case EvictLFU:
+		return newLFU[K, V]()
+	case EvictTinyLFU:
+		return newTinyLFU[K, V](capacity)
+	case EvictARC:
+		return newARC[K, V](capacity)
+	default:
+		return newLRU[K, V]()
+	}
+}
+
+// itemList 侵入式双向链表，表头为最近使用
+type itemList[K comparable, V any] struct {
+	root value[K, V]
+	len  int
+}
+
+func newItemList[K comparable, V any]() *itemList[K, V] {
+	l := &itemList[K, V]{}
+	l.root.prev = &l.root
+	l.root.next = &l.root
+	return l
+}
+
+func (l *itemList[K, V]) pushFront(v *value[K, V]) {
+	v.prev = &l.root
+	v.next = l.root.next
+	l.root.next.prev = v
+	l.root.next = v
+	v.list = l
+	l.len++
+}
+
+func (l *itemList[K, V]) remove(v *value[K, V]) {
+	if v.list != l {
+		return
+	}
+	v.prev.next = v.next
+	v.next.prev = v.prev
+	v.prev, v.next, v.list = nil, nil, nil
+	l.len--
+}
+
+func (l *itemList[K, V]) moveToFront(v *value[K, V]) {
+	l.remove(v)
+	l.pushFront(v)
+}
+
+func (l *itemList[K, V]) back() *value[K, V] {
+	if l.len == 0 {
+		return nil
+	}
+	return l.root.prev
+}
+
+// ---------------------------------------------------------------- LRU
+
+type lru[K comparable, V any] struct {
+	items *itemList[K, V]
+}
+
+func newLRU[K comparable, V any]() *lru[K, V] {
+	return &lru[K, V]{items: newItemList[K, V]()}
+}
+
+func (p *lru[K, V]) add(v *value[K, V])           { p.items.pushFront(v) }
+func (p *lru[K, V]) hit(v *value[K, V])           { p.items.moveToFront(v) }
+func (p *lru[K, V]) miss(uint64)                  {}
+func (p *lru[K, V]) remove(v *value[K, V])        { p.items.remove(v) }
+func (p *lru[K, V]) victim() *value[K, V]         { return p.items.back() }
+func (p *lru[K, V]) admit(_, _ *value[K, V]) bool { return true }
+func (p *lru[K, V]) evict(v *value[K, V])         { p.items.remove(v) }
+
+// ---------------------------------------------------------------- LFU
+
+const maxFreq = 1 << 16 // 访问次数上限，避免长期热点永远无法淘汰
+
+// lfu 按访问次数分桶，O(1) 找到访问次数最少的数据，同样次数的淘汰最久未使用的
+// 刚写入的数据不会作为淘汰对象，与先淘汰再写入的效果一致，否则其他数据都被访问过时新数据会立即被淘汰
+type lfu[K comparable, V any] struct {
+	buckets map[uint32]*itemList[K, V]
+	minFreq uint32
+	added   *value[K, V] // 最近一次写入的数据
+}
+
+func newLFU[K comparable, V any]() *lfu[K, V] {
+	return &lfu[K, V]{buckets: make(map[uint32]*itemList[K, V])}
+}
+
+func (p *lfu[K, V]) bucket(freq uint32) *itemList[K, V] {
+	b, ok := p.buckets[freq]
+	if !ok {
+		b = newItemList[K, V]()
+		p.buckets[freq] = b
+	}
+	return b
+}
+
+func (p *lfu[K, V]) add(v *value[K, V]) {
+	v.freq = 1
+	p.bucket(1).pushFront(v)
+	p.minFreq = 1
+	p.added = v
+}
+
+func (p *lfu[K, V]) hit(v *value[K, V]) {
+	if v.freq >= maxFreq {
+		v.list.moveToFront(v)
+		return
+	}
+	p.unlink(v)
+	v.freq++
+	p.bucket(v.freq).pushFront(v)
+}
+
+func (p *lfu[K, V]) unlink(v *value[K, V]) {
+	if p.added == v {
+		p.added = nil
+	}
+	b := v.list
+	if b == nil {
+		return
+	}
+	b.remove(v)
+	if b.len == 0 {
+		delete(p.buckets, v.freq)
+		if p.minFreq == v.freq {
+			p.minFreq++
+		}
+	}
+}
+
+func (p *lfu[K, V]) miss(uint64)                  {}
+func (p *lfu[K, V]) remove(v *value[K, V])        { p.unlink(v) }
+func (p *lfu[K, V]) admit(_, _ *value[K, V]) bool { return true }
+func (p *lfu[K, V]) evict(v *value[K, V])         { p.unlink(v) }
+
+func (p *lfu[K, V]) victim() *value[K, V] {
+	if len(p.buckets) == 0 {
+		return nil
+	}
+	if _, ok := p.buckets[p.minFreq]; !ok {
+		// 删除导致 minFreq 失效时重新查找
+		p.minFreq = p.lowest(0)
+	}
+	b := p.buckets[p.minFreq]
+	if b.back() == p.added && b.len == 1 && len(p.buckets) > 1 {
+		// 访问次数最少的只有刚写入的数据，淘汰次少的
+		return p.buckets[p.lowest(p.minFreq)].back()
+	}
+	return b.back()
+}
+
+// lowest 除 skip 以外最小的访问次数
+func (p *lfu[K, V]) lowest(skip uint32) uint32 {
+	freq := uint32(maxFreq)
+	for f := range p.buckets {
+		if f != skip {
+			freq = min(freq, f)
+		}
+	}
+	return freq
+}
+
+// ---------------------------------------------------------------- TinyLFU
+
+// tinyLFU 使用 count-min sketch 统计近期访问频率（包括未命中的访问），
+// 淘汰时只有新数据的频率高于被淘汰数据才会写入，避免一次性访问的数据挤出热点数据
+type tinyLFU[K comparable, V any] struct {
+	lru[K, V]
+	sketch *cmSketch
+}
+
+func newTinyLFU[K comparable, V any](capacity int) *tinyLFU[K, V] {
+	return &tinyLFU[K, V]{lru: lru[K, V]{items: newItemList[K, V]()}, sketch: newCMSketch(capacity)}
+}
+
+func (p *tinyLFU[K, V]) add(v *value[K, V]) {
+	p.sketch.increment(v.hash)
+	p.items.pushFront(v)
+}
+
+func (p *tinyLFU[K, V]) hit(v *value[K, V]) {
+	p.sketch.increment(v.hash)
+	p.items.moveToFront(v)
+}
+
+func (p *tinyLFU[K, V]) miss(hash uint64) {
+	p.sketch.increment(hash)
+}
+
+func (p *tinyLFU[K, V]) admit(candidate, victim *value[K, V]) bool {
+	return p.sketch.estimate(candidate.hash) > p.sketch.estimate(victim.hash)
+}
+
+// cmSketch 4 行、4 位计数器的 count-min sketch，计数总量达到采样上限后全部减半，让频率反映近期访问
+type cmSketch struct {
+	rows      [4][]uint8
+	mask      uint64
+	additions int
+	sample    int
+}
+
+func newCMSketch(capacity int) *cmSketch {
+	width := 64
+	for width < capacity*2 {
+		width <<= 1
+	}
+	s := &cmSketch{mask: uint64(width - 1), sample: max(capacity, width/2) * 10}
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, width)
+	}
+	return s
+}
+
+func (s *cmSketch) index(hash uint64, i int) uint64 {
+	h := hash + uint64(i+1)*0x9e3779b97f4a7c15
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	return h & s.mask
+}
+
+func (s *cmSketch) increment(hash uint64) {
+	for i := range s.rows {
+		if idx := s.index(hash, i); s.rows[i][idx] < 15 {
+			s.rows[i][idx]++
+		}
+	}
+	s.additions++
+	if s.additions >= s.sample {
+		for i := range s.rows {
+			for j := range s.rows[i] {
+				s.rows[i][j] >>= 1
+			}
+		}
+		s.additions /= 2
+	}
+}
+
+func (s *cmSketch) estimate(hash uint64) uint8 {
+	var est uint8 = 15
+	for i := range s.rows {
+		est = min(est, s.rows[i][s.index(hash, i)])
+	}
+	return est
+}
+
+// ---------------------------------------------------------------- ARC
+
+// arc 自适应替换缓存。
+// t1 只访问过一次的数据，t2 访问过多次的数据，b1、b2 分别记录最近从 t1、t2 淘汰的 key 的哈希（幽灵记录）。
+// 命中 b1 说明 t1 偏小，增大 t1 的目标大小 p；命中 b2 则减小 p。
+type arc[K comparable, V any] struct {
+	capacity int // 条目容量，只限制字节时按当前条目数量估算
+	p        int // t1 的目标大小
+	t1, t2   *itemList[K, V]
+	b1, b2   *ghostList
+	fromB2   bool // 最近一次新增数据是否命中 b2
+}
+
+func newARC[K comparable, V any](capacity int) *arc[K, V] {
+	return &arc[K, V]{capacity: capacity, t1: newItemList[K, V](), t2: newItemList[K, V](), b1: newGhostList(), b2: newGhostList()}
+}
+
+func (p *arc[K, V]) size() int {
+	if p.capacity > 0 {
+		return p.capacity
+	}
+	return max(p.t1.len+p.t2.len, 1)
+}
+
+func (p *arc[K, V]) add(v *value[K, V]) {
+	c := p.size()
+	p.fromB2 = false
+	switch {
+	case p.b1.remove(v.hash):
+		p.p = min(c, p.p+max(p.b2.len()/max(p.b1.len(), 1), 1))
+		p.t2.pushFront(v)
+	case p.b2.remove(v.hash):
+		p.p = max(0, p.p-max(p.b1.len()/max(p.b2.len(), 1), 1))
+		p.fromB2 = true
+		p.t2.pushFront(v)
+	default:
+		p.t1.pushFront(v)
+	}
+	// 幽灵记录总量不超过容量
+	for p.t1.len+p.b1.len() > c && p.b1.len() > 0 {
+		p.b1.removeBack()
+	}
+	for p.t1.len+p.t2.len+p.b1.len()+p.b2.len() > 2*c && p.b2.len() > 0 {
+		p.b2.removeBack()
+	}
+}
+
+func (p *arc[K, V]) hit(v *value[K, V]) {
+	v.list.remove(v)
+	p.t2.pushFront(v)
+}
+
+func (p *arc[K, V]) miss(uint64) {}
+
+func (p *arc[K, V]) remove(v *value[K, V]) {
+	if v.list != nil {
+		v.list.remove(v)
+	}
+}
+
+func (p *arc[K, V]) victim() *value[K, V] {
+	if p.t1.len > 0 && (p.t1.len > p.p || (p.t1.len == p.p && p.fromB2) || p.t2.len == 0) {
+		return p.t1.back()
+	}
+	return p.t2.back()
+}
+
+func (p *arc[K, V]) admit(_, _ *value[K, V]) bool { return true }
+
+func (p *arc[K, V]) evict(v *value[K, V]) {
+	switch v.list {
+	case p.t1:
+		p.t1.remove(v)
+		p.b1.pushFront(v.hash)
+	case p.t2:
+		p.t2.remove(v)
+		p.b2.pushFront(v.hash)
+	}
+}
+
+// ghostList 只保存哈希的 LRU 链表
+type ghostList struct {
+	items map[uint64]*ghost
+	root  ghost
+}
+
+type ghost struct {
+	hash       uint64
+	prev, next *ghost
+}
+
+func newGhostList() *ghostList {
+	g := &ghostList{items: make(map[uint64]*ghost)}
+	g.root.prev = &g.root
+	g.root.next = &g.root
+	return g
+}
+
+func (g *ghostList) len() int {
+	return len(g.items)
+}
+
+func (g *ghostList) pushFront(hash uint64) {
+	if _, ok := g.items[hash]; ok {
+		return
+	}
+	n := &ghost{hash: hash, prev: &g.root, next: g.root.next}
+	g.root.next.prev = n
+	g.root.next = n
+	g.items[hash] = n
+}
+
+func (g *ghostList) remove(hash uint64) bool {
+	n, ok := g.items[hash]
+	if !ok {
+		return false
+	}
+	n.prev.next = n.next
+	n.next.prev = n.prev
+	delete(g.items, hash)
+	return true
+}
+
+func (g *ghostList) removeBack() {
+	if n := g.root.prev; n != &g.root {
+		g.remove(n.hash)
+	}
+}
@@ -0,0 +1,150 @@
+package safe
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func newEvictMap(policy EvictPolicy, maxEntries int) *Map[string, string] {
+	return NewMap[string, string](context.Background(), StringHasher{}, CacheConfig{ShardSize: 1, MaxEntries: maxEntries, EvictPolicy: policy})
+}
+
+// present 返回仍然存在的 key，使用 Range 避免影响淘汰顺序
+func present(m *Map[string, string]) []string {
+	var keys []string
+	m.Range(func(k string, _ string) bool {
+		keys = append(keys, k)
+		return true
+	})
+	slices.Sort(keys)
+	return keys
+}
+
+func assertKeys(t *testing.T, m *Map[string, string], want ...string) {
+	t.Helper()
+	if got := present(m); !slices.Equal(got, want) {
+		t.Fatalf("keys = %v, want %v", got, want)
+	}
+}
+
+func TestEvictLRU(t *testing.T) {
+	m := newEvictMap(EvictLRU, 3)
+	m.Store("a", "1")
+	m.Store("b", "2")
+	m.Store("c", "3")
+	m.Load("a")
+	m.Store("d", "4")
+	assertKeys(t, m, "a", "c", "d")
+
+	// 覆盖写入视为一次访问
+	m.Store("c", "5")
+	m.Store("e", "6")
+	assertKeys(t, m, "c", "d", "e")
+	if st := m.Stats(); st.Evictions != 2 || st.Entries != 3 {
+		t.Fatalf("stats = %+v", st)
+	}
+}
+
+func TestEvictLFU(t *testing.T) {
+	m := newEvictMap(EvictLFU, 3)
+	m.Store("a", "1")
+	m.Store("b", "2")
+	m.Store("c", "3")
+	m.Load("a")
+	m.Load("a")
+	m.Load("b")
+	m.Store("d", "4")
+	assertKeys(t, m, "a", "b", "d")
+
+	// 访问次数相同时淘汰最久未使用的
+	m.Store("e", "5")
+	assertKeys(t, m, "a", "b", "e")
+
+	// 删除访问次数最少的数据后仍然能找到下一个淘汰对象
+	m.Delete("e")
+	m.Store("f", "6")
+	m.Load("f")
+	m.Load("f")
+	m.Store("g", "7")
+	assertKeys(t, m, "a", "f", "g")
+}
+
+func TestEvictTinyLFU(t *testing.T) {
+	m := newEvictMap(EvictTinyLFU, 3)
+	for _, k := range []string{"a", "b", "c"} {
+		m.Store(k, k)
+		for range 3 {
+			m.Load(k)
+		}
+	}
+	// 一次性写入的数据频率低于被淘汰数据，不会挤出热点数据
+	m.Store("d", "d")
+	assertKeys(t, m, "a", "b", "c")
+
+	// 多次未命中后频率超过被淘汰数据，允许写入
+	for range 5 {
+		m.Load("d")
+	}
+	m.Store("d", "d")
+	assertKeys(t, m, "b", "c", "d")
+}
+
+func TestEvictARC(t *testing.T) {
+	m := newEvictMap(EvictARC, 3)
+	m.Store("a", "1")
+	m.Store("b", "2")
+	m.Load("a")
+	m.Load("b")
+	// 只访问一次的数据流不会挤出访问过多次的数据
+	for i := range 10 {
+		m.Store(fmt.Sprintf("s%d", i), "x")
+	}
+	assertKeys(t, m, "a", "b", "s9")
+
+	// 刚被淘汰的数据再次写入时直接进入 t2
+	m.Store("s8", "x")
+	m.Store("c", "x")
+	if _, ok := m.Load("s8"); !ok {
+		t.Fatalf("ghost hit evicted, keys = %v", present(m))
+	}
+}
+
+func TestEvictMaxBytes(t *testing.T) {
+	m := NewMap[string, string](context.Background(), StringHasher{}, CacheConfig{ShardSize: 1, MaxBytes: 10})
+	m.SetSizeFunc(func(_ string, v string) int64 { return int64(len(v)) })
+	m.Store("a", "aaaa")
+	m.Store("b", "bbbb")
+	if st := m.Stats(); st.Bytes != 8 || st.Entries != 2 {
+		t.Fatalf("stats = %+v", st)
+	}
+	m.Store("c", "cccc")
+	assertKeys(t, m, "b", "c")
+	if st := m.Stats(); st.Bytes != 8 || st.Evictions != 1 {
+		t.Fatalf("stats = %+v", st)
+	}
+
+	// 覆盖写入按差值计算，超过容量时淘汰其他数据
+	m.Store("c", "cccccccc")
+	assertKeys(t, m, "c")
+	if st := m.Stats(); st.Bytes != 8 {
+		t.Fatalf("bytes after overwrite = %d, want 8", st.Bytes)
+	}
+	m.Store("c", "c")
+	m.Delete("c")
+	if st := m.Stats(); st.Bytes != 0 || st.Entries != 0 {
+		t.Fatalf("stats after delete = %+v", st)
+	}
+}
+
+func TestEvictMaxEntriesSharded(t *testing.T) {
+	// 4 个分片、每个分片 2 个条目，总量不超过配置值
+	m := NewMap[string, string](context.Background(), StringHasher{}, CacheConfig{ShardSize: 4, MaxEntries: 10})
+	for i := range 100 {
+		m.Store(fmt.Sprintf("k%d", i), "v")
+	}
+	if st := m.Stats(); st.Entries > 10 || st.Entries+int(st.Evictions) != 100 {
+		t.Fatalf("stats = %+v", st)
+	}
+}
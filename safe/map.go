@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"helay.net/go/utils/v3/tools"
@@ -15,6 +16,7 @@ import (
 // 支持自动清理，当启用自动清理功能后，也支持设置不过期的key
 // 可指定分片数量，默认为2的8次方。
 // 默认以 hash 结果 uint64作为key，但也支持 key 作为map的 key。
+// 支持限制最大条目数和最大字节数，超过后每个分片按 lru、lfu、tinylfu、arc 策略独立淘汰。
 
 // 使用默认 uint64 key 的安全数据量范围
 // 绝对安全区：n < 100万 (1,000,000)
@@ -37,12 +39,25 @@ type (
 		expire    int64 // 过期时间，单位纳秒，0表示不过期
 		ttl       time.Duration
 		heartbeat time.Time // 用于记录最后一次心跳时间的。
+
+		hash       uint64          // key 的哈希值
+		size       int64           // 占用字节数
+		freq       uint32          // 访问次数，lfu 使用
+		prev, next *value[K, V]    // 淘汰策略链表
+		list       *itemList[K, V] // 所在的淘汰策略链表
 	}
 	shard[K comparable, V any] struct {
 		hashItems map[uint64]*value[K, V]
 		keyItems  map[K]*value[K, V]
 
 		mu sync.RWMutex
+
+		policy policy[K, V] // 淘汰策略，不限制容量时为 nil
+		pmu    sync.Mutex   // 读锁内更新淘汰策略时使用
+		bytes  int64        // 当前占用字节数
+
+		flight  SingleFlight[K, V] // 合并 LoadOrStoreFunc 的并发加载
+		loading map[K]bool         // 正在加载的 key，加载期间被写入或删除时置为 true，加载结果不再写入
 	}
 	Map[K comparable, V any] struct {
 		ctx       context.Context // 上下文
@@ -60,6 +75,17 @@ type (
 		// 当前启用自动清理后，clearInterval必须设置
 		clearInterval time.Duration
 		onExpired     OnExpired[K] // 再过期时刻触发的回调操作。
+
+		maxEntries  int            // 每个分片的最大条目数
+		maxBytes    int64          // 每个分片的最大字节数
+		evictPolicy EvictPolicy    // 淘汰策略
+		sizeFunc    SizeFunc[K, V] // 条目大小计算函数
+
+		refreshAhead float64 // 提前刷新的 ttl 比例
+		staleGrace   int64   // 过期后保留的宽限期，单位纳秒
+
+		hits, misses, evictions, expirations atomic.Uint64
+		loads, loadErrors, staleHits         atomic.Uint64
 	}
 )
 
@@ -76,13 +102,31 @@ func NewMap[K comparable, V any](ctx context.Context, hasher Hasher[K], configs
 				panic(fmt.Errorf("异常的分片数量[%d]，分片数量必须是2的N次幂", config.ShardSize))
 			}
 			m.shardSize = config.ShardSize
+		} else if config.MaxEntries > 0 {
+			// 容量较小时减少分片，保证每个分片至少有 defaultCapacity 个条目，淘汰结果更接近全局策略
+			for m.shardSize > 1 && uint64(config.MaxEntries)/m.shardSize < defaultCapacity {
+				m.shardSize >>= 1
+			}
 		}
+		// 容量按分片向下取整，总量不会超过配置值；分片之间不共享容量，数据分布不均匀时总量未达到上限也可能淘汰。
+		// 只有手动设置的分片数量大于 MaxEntries 时，每个分片至少保留一个条目，总量最多为分片数量。
+		if config.MaxEntries > 0 {
+			m.maxEntries = max(1, config.MaxEntries/int(m.shardSize))
+		}
+		if config.MaxBytes > 0 {
+			m.maxBytes = max(1, config.MaxBytes/int64(m.shardSize))
+		}
+		m.evictPolicy = config.EvictPolicy
 
 		m.useKey = config.UseKey
 		m.enableCleanup = config.EnableCleanup
 		if m.enableCleanup {
 			m.defaultTTL = config.TTL
 			m.clearInterval = tools.AutoTimeDuration(config.ClearInterval, time.Second, 30*time.Second) // 默认三十秒清理一次
+			if config.RefreshAhead > 0 && config.RefreshAhead < 1 {
+				m.refreshAhead = config.RefreshAhead
+			}
+			m.staleGrace = int64(max(config.StaleGrace, 0))
 		}
 
 	}
@@ -95,6 +139,7 @@ func NewMap[K comparable, V any](ctx context.Context, hasher Hasher[K], configs
 		} else {
 			sd.hashItems = make(map[uint64]*value[K, V], defaultCapacity) // 预分配128个空间
 		}
+		sd.policy = m.newPolicy()
 		m.shards[i] = sd
 
 	}
@@ -103,20 +148,79 @@ func NewMap[K comparable, V any](ctx context.Context, hasher Hasher[K], configs
 	return m
 }
 
-// SetOnExpired 设置过期回调函数。
+// SetOnExpired 设置过期回调函数，超过容量被淘汰的 key 也会通过该回调通知。
 func (m *Map[K, V]) SetOnExpired(onExpired OnExpired[K]) {
 	m.onExpired = onExpired
 }
 
+// SetSizeFunc 设置条目大小计算函数，限制最大字节数时必须设置，需要在写入数据前调用。
+func (m *Map[K, V]) SetSizeFunc(fn SizeFunc[K, V]) {
+	m.sizeFunc = fn
+}
+
+// Stats 获取命中、淘汰等统计数据
+func (m *Map[K, V]) Stats() Stats {
+	st := Stats{
+		Hits:        m.hits.Load(),
+		Misses:      m.misses.Load(),
+		Evictions:   m.evictions.Load(),
+		Expirations: m.expirations.Load(),
+		Loads:       m.loads.Load(),
+		LoadErrors:  m.loadErrors.Load(),
+		StaleHits:   m.staleHits.Load(),
+	}
+	for _, sd := range m.shards {
+		sd.mu.RLock()
+		st.Entries += m.count(sd)
+		st.Bytes += sd.bytes
+		sd.mu.RUnlock()
+	}
+	return st
+}
+
+func (m *Map[K, V]) newPolicy() policy[K, V] {
+	if m.maxEntries <= 0 && m.maxBytes <= 0 {
+		return nil
+	}
+	return newPolicy[K, V](m.evictPolicy, m.maxEntries)
+}
+
+func (m *Map[K, V]) count(sd *shard[K, V]) int {
+	if m.useKey {
+		return len(sd.keyItems)
+	}
+	return len(sd.hashItems)
+}
+
 // Load 获取键的值。
 func (m *Map[K, V]) Load(key K) (V, bool) {
 	sd, _, k := m.getShard(key)
 	sd.mu.RLock()
 	defer sd.mu.RUnlock()
-	val, _, ok, _ := m.load(sd, key, k)
+	val, item, ok, _ := m.load(sd, key, k)
+	m.record(sd, item, k, ok)
 	return val, ok
 }
 
+// record 记录命中情况，在读锁内更新淘汰策略
+func (m *Map[K, V]) record(sd *shard[K, V], item *value[K, V], k uint64, ok bool) {
+	if ok {
+		m.hits.Add(1)
+	} else {
+		m.misses.Add(1)
+	}
+	if sd.policy == nil {
+		return
+	}
+	sd.pmu.Lock()
+	if ok {
+		sd.policy.hit(item)
+	} else {
+		sd.policy.miss(k)
+	}
+	sd.pmu.Unlock()
+}
+
 // load 获取键的值。
 // 这个函数都不会独立使用，基于上级函数，已经做了锁功能。
 // 这个函数内容也已经做了过期数据判断。
@@ -153,35 +257,121 @@ func (m *Map[K, V]) LoadOrStore(key K, val V, duration ...time.Duration) (actual
 	sd, _, k := m.getShard(key)
 	sd.mu.Lock()
 	defer sd.mu.Unlock()
-	item, _, ok, _ := m.load(sd, key, k)
+	old, item, ok, _ := m.load(sd, key, k)
+	m.record(sd, item, k, ok)
 	if ok {
-		return item, true
+		return old, true
 	}
 	m.store(sd, key, k, val, duration...)
 	return val, false
 }
 
-// LoadOrStoreFunc 获取键的值，如果没有则存储键的值。
+// LoadOrStoreFunc 获取键的值，如果没有则调用 valueFunc 加载并存储。
+// valueFunc 在分片锁之外执行，同一个 key 的并发加载只会执行一次，其他调用等待并共享结果，此时 loaded 为 true。
+// 配置了 RefreshAhead 时，命中的值已经过了 ttl 的指定比例会在后台重新加载，本次仍返回当前值。
+// 配置了 StaleGrace 时，过期宽限期内重新加载失败会返回旧值，错误被忽略。
 func (m *Map[K, V]) LoadOrStoreFunc(key K, valueFunc func(k K) (V, error), duration ...time.Duration) (V, bool, error) {
 	sd, _, k := m.getShard(key)
-	sd.mu.Lock()
-	defer sd.mu.Unlock()
-	item, _, ok, _ := m.load(sd, key, k)
+	now := time.Now().UnixNano()
+	sd.mu.RLock()
+	val, item, ok, _ := m.load(sd, key, k)
+	m.record(sd, item, k, ok)
+	refresh := ok && m.needRefresh(item, now)
+	stale, hasStale := m.stale(sd, key, k, now)
+	sd.mu.RUnlock()
+
+	if valueFunc == nil {
+		return val, ok, nil
+	}
 	if ok {
-		return item, true, nil
+		if refresh {
+			sd.flight.Go(key, func() (V, error) {
+				return m.loadFunc(sd, key, k, valueFunc, duration...)
+			})
+		}
+		return val, true, nil
 	}
 
-	if valueFunc == nil {
-		return item, false, nil
+	val, shared, err := sd.flight.Do(key, func() (V, error) {
+		// 等待期间可能已经被其他调用加载完成
+		sd.mu.RLock()
+		v, _, ok, _ := m.load(sd, key, k)
+		sd.mu.RUnlock()
+		if ok {
+			return v, nil
+		}
+		return m.loadFunc(sd, key, k, valueFunc, duration...)
+	})
+	if err != nil {
+		if hasStale {
+			m.staleHits.Add(1)
+			return stale, true, nil
+		}
+		return val, false, err
 	}
+	return val, shared, nil
+}
 
+// loadFunc 执行加载函数，成功后写入
+// 加载期间 key 被写入或删除时，丢弃加载结果，避免旧值覆盖新值或已删除的 key 重新出现。
+func (m *Map[K, V]) loadFunc(sd *shard[K, V], key K, k uint64, valueFunc func(k K) (V, error), duration ...time.Duration) (V, error) {
+	m.loads.Add(1)
+	sd.mu.Lock()
+	if sd.loading == nil {
+		sd.loading = make(map[K]bool)
+	}
+	sd.loading[key] = false
+	sd.mu.Unlock()
 	val, err := valueFunc(key)
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+	changed := sd.loading[key]
+	delete(sd.loading, key)
 	if err != nil {
-		return val, false, err
+		m.loadErrors.Add(1)
+		return val, err
 	}
-	m.store(sd, key, k, val, duration...)
+	if !changed {
+		m.store(sd, key, k, val, duration...)
+	}
+	return val, nil
+}
 
-	return val, false, nil
+// touch 标记正在加载的 key 已被修改
+func (m *Map[K, V]) touch(sd *shard[K, V], key K) {
+	if _, ok := sd.loading[key]; ok {
+		sd.loading[key] = true
+	}
+}
+
+// needRefresh 判断有效的值是否需要提前刷新
+func (m *Map[K, V]) needRefresh(item *value[K, V], now int64) bool {
+	if m.refreshAhead <= 0 || item.expire == 0 || item.ttl <= 0 {
+		return false
+	}
+	elapsed := now - (item.expire - int64(item.ttl))
+	return float64(elapsed) >= m.refreshAhead*float64(item.ttl)
+}
+
+// stale 获取已过期但仍在宽限期内的值
+func (m *Map[K, V]) stale(sd *shard[K, V], key K, k uint64, now int64) (V, bool) {
+	var zero V
+	if m.staleGrace <= 0 {
+		return zero, false
+	}
+	var (
+		item *value[K, V]
+		ok   bool
+	)
+	if m.useKey {
+		item, ok = sd.keyItems[key]
+	} else {
+		item, ok = sd.hashItems[k]
+	}
+	if !ok || item.expire == 0 || item.expire > now || now > item.expire+m.staleGrace {
+		return zero, false
+	}
+	return item.val, true
 }
 
 // LoadAndDelete 获取键的值并删除键值对。
@@ -225,6 +415,7 @@ func (m *Map[K, V]) LoadAndRefresh(key K, duration ...time.Duration) (V, bool) {
 	sd.mu.Lock()
 	defer sd.mu.Unlock()
 	val, item, ok, existed := m.load(sd, key, k)
+	m.record(sd, item, k, ok)
 	if !ok {
 		// 值无效，但是值存在，就删除。
 		if existed {
@@ -340,9 +531,30 @@ func (m *Map[K, V]) Store(key K, val V, duration ...time.Duration) {
 
 // store 存储键的值。
 func (m *Map[K, V]) store(sd *shard[K, V], key K, k uint64, val V, duration ...time.Duration) {
-	v := &value[K, V]{
-		key: key,
-		val: val,
+	m.touch(sd, key)
+	var size int64
+	if m.sizeFunc != nil {
+		size = m.sizeFunc(key, val)
+	}
+	var (
+		v       *value[K, V]
+		existed bool
+	)
+	if m.useKey {
+		v, existed = sd.keyItems[key]
+	} else {
+		v, existed = sd.hashItems[k]
+	}
+	if existed {
+		// 覆盖写入保留在淘汰策略中的位置，视为一次访问
+		sd.bytes += size - v.size
+		*v = value[K, V]{key: key, val: val, hash: k, size: size, freq: v.freq, prev: v.prev, next: v.next, list: v.list}
+		if sd.policy != nil {
+			sd.policy.hit(v)
+		}
+	} else {
+		v = &value[K, V]{key: key, val: val, hash: k, size: size}
+		sd.bytes += size
 	}
 	// 启用自动清理功能后，如果有私有TTL，则用私有TTL，否则用默认TTL
 	// 最终还是需要判断 ttl是否大于0。如果ttl <= 0 就表示当前key不过期。
@@ -357,11 +569,64 @@ func (m *Map[K, V]) store(sd *shard[K, V], key K, k uint64, val V, duration ...t
 		}
 	}
 
+	if existed {
+		m.evict(sd, nil)
+		return
+	}
 	if m.useKey {
 		sd.keyItems[key] = v
 	} else {
 		sd.hashItems[k] = v
 	}
+	if sd.policy != nil {
+		sd.policy.add(v)
+		m.evict(sd, v)
+	}
+}
+
+// overflow 分片是否超过容量
+func (m *Map[K, V]) overflow(sd *shard[K, V]) bool {
+	return (m.maxEntries > 0 && m.count(sd) > m.maxEntries) || (m.maxBytes > 0 && sd.bytes > m.maxBytes)
+}
+
+// evict 淘汰数据直到分片不超过容量，added 为刚写入的数据，tinylfu 准入失败时淘汰的是它自己
+func (m *Map[K, V]) evict(sd *shard[K, V], added *value[K, V]) {
+	if sd.policy == nil {
+		return
+	}
+	var keys []K
+	for m.overflow(sd) {
+		victim := sd.policy.victim()
+		if victim == nil {
+			break
+		}
+		if added != nil && victim != added && !sd.policy.admit(added, victim) {
+			victim = added
+		}
+		added = nil // 只对第一个淘汰对象判断准入
+		sd.policy.evict(victim)
+		sd.bytes -= victim.size
+		if m.useKey {
+			delete(sd.keyItems, victim.key)
+		} else {
+			delete(sd.hashItems, victim.hash)
+		}
+		keys = append(keys, victim.key)
+	}
+	if len(keys) > 0 {
+		m.evictions.Add(uint64(len(keys)))
+		if m.onExpired != nil {
+			go m.onExpired(keys, ReasonCapacity)
+		}
+	}
+}
+
+// unlink 从淘汰策略中移除并释放占用的字节数
+func (m *Map[K, V]) unlink(sd *shard[K, V], item *value[K, V]) {
+	if sd.policy != nil {
+		sd.policy.remove(item)
+	}
+	sd.bytes -= item.size
 }
 
 // Delete 移除键的值。
@@ -373,10 +638,17 @@ func (m *Map[K, V]) Delete(key K) {
 }
 
 func (m *Map[K, V]) delete(sd *shard[K, V], key K, k uint64) {
+	m.touch(sd, key)
 	if m.useKey {
-		delete(sd.keyItems, key)
+		if item, ok := sd.keyItems[key]; ok {
+			m.unlink(sd, item)
+			delete(sd.keyItems, key)
+		}
 	} else {
-		delete(sd.hashItems, k)
+		if item, ok := sd.hashItems[k]; ok {
+			m.unlink(sd, item)
+			delete(sd.hashItems, k)
+		}
 	}
 }
 
@@ -397,13 +669,16 @@ func (m *Map[K, V]) DeleteAndGetCount(keys ...K) int {
 		sd := m.shards[idx]
 		sd.mu.Lock()
 		for _, g := range groups {
+			m.touch(sd, g.key)
 			if m.useKey {
-				if _, ok := sd.keyItems[g.key]; ok {
+				if item, ok := sd.keyItems[g.key]; ok {
+					m.unlink(sd, item)
 					delete(sd.keyItems, g.key)
 					count++
 				}
 			} else {
-				if _, ok := sd.hashItems[g.hashKey]; ok {
+				if item, ok := sd.hashItems[g.hashKey]; ok {
+					m.unlink(sd, item)
 					delete(sd.hashItems, g.hashKey)
 					count++
 				}
@@ -423,6 +698,11 @@ func (m *Map[K, V]) DeleteAll() {
 		} else {
 			sd.hashItems = make(map[uint64]*value[K, V])
 		}
+		sd.policy = m.newPolicy()
+		sd.bytes = 0
+		for key := range sd.loading {
+			sd.loading[key] = true
+		}
 		sd.mu.Unlock()
 	}
 }
@@ -470,12 +750,16 @@ func (m *Map[K, V]) DeletePrefix(prefix string) {
 			if m.useKey {
 				for k, v := range sd.keyItems {
 					if strings.HasPrefix(tools.Any2string(v.key), prefix) {
+						m.touch(sd, v.key)
+						m.unlink(sd, v)
 						delete(sd.keyItems, k)
 					}
 				}
 			} else {
 				for k, v := range sd.hashItems {
 					if strings.HasPrefix(tools.Any2string(v.key), prefix) {
+						m.touch(sd, v.key)
+						m.unlink(sd, v)
 						delete(sd.hashItems, k)
 					}
 				}
@@ -495,12 +779,16 @@ func (m *Map[K, V]) DeleteSuffix(suffix string) {
 			if m.useKey {
 				for k, v := range sd.keyItems {
 					if strings.HasSuffix(tools.Any2string(v.key), suffix) {
+						m.touch(sd, v.key)
+						m.unlink(sd, v)
 						delete(sd.keyItems, k)
 					}
 				}
 			} else {
 				for k, v := range sd.hashItems {
 					if strings.HasSuffix(tools.Any2string(v.key), suffix) {
+						m.touch(sd, v.key)
+						m.unlink(sd, v)
 						delete(sd.hashItems, k)
 					}
 				}
@@ -562,8 +850,10 @@ func (m *Map[K, V]) cleanupShard(idx int) {
 			return
 		}
 		for k, item := range sd.keyItems {
-			// 判断过期时间是否 > 0，并且需要时到期。
-			if item.expire > 0 && now > item.expire {
+			// 判断过期时间是否 > 0，并且已超过过期宽限期。
+			if item.expire > 0 && now > item.expire+m.staleGrace {
+				m.unlink(sd, item)
+				m.expirations.Add(1)
 				delete(sd.keyItems, k)
 				if m.onExpired != nil {
 					if expiredKeys == nil {
@@ -578,8 +868,10 @@ func (m *Map[K, V]) cleanupShard(idx int) {
 			return
 		}
 		for k, item := range sd.hashItems {
-			// 判断过期时间是否 > 0，并且需要时到期。
-			if item.expire > 0 && now > item.expire {
+			// 判断过期时间是否 > 0，并且已超过过期宽限期。
+			if item.expire > 0 && now > item.expire+m.staleGrace {
+				m.unlink(sd, item)
+				m.expirations.Add(1)
 				delete(sd.hashItems, k)
 				if m.onExpired != nil {
 					if expiredKeys == nil {
@@ -592,7 +884,7 @@ func (m *Map[K, V]) cleanupShard(idx int) {
 	}
 
 	if m.onExpired != nil && len(expiredKeys) > 0 {
-		go m.onExpired(expiredKeys)
+		go m.onExpired(expiredKeys, ReasonExpired)
 	}
 
 }
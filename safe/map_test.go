@@ -0,0 +1,37 @@
+package safe
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLoadOrStoreFuncDropsStaleLoad(t *testing.T) {
+	m := NewMap[string, int](context.Background(), StringHasher{}, CacheConfig{ShardSize: 1})
+
+	// 加载期间删除，加载结果不能让 key 重新出现
+	v, _, err := m.LoadOrStoreFunc("a", func(k string) (int, error) {
+		m.Delete(k)
+		return 1, nil
+	})
+	if err != nil || v != 1 {
+		t.Fatalf("load = %d, %v", v, err)
+	}
+	if _, ok := m.Load("a"); ok {
+		t.Fatal("deleted key came back after load")
+	}
+
+	// 加载期间写入，新值不能被加载结果覆盖
+	_, _, _ = m.LoadOrStoreFunc("b", func(k string) (int, error) {
+		m.Store(k, 2)
+		return 1, nil
+	})
+	if v, _ := m.Load("b"); v != 2 {
+		t.Fatalf("store during load overwritten, got %d", v)
+	}
+
+	// 没有并发修改时正常写入
+	_, _, _ = m.LoadOrStoreFunc("c", func(string) (int, error) { return 3, nil })
+	if v, ok := m.Load("c"); !ok || v != 3 {
+		t.Fatalf("load c = %d, %v", v, ok)
+	}
+}
@@ -0,0 +1,72 @@
+package safe
+
+import (
+	"fmt"
+	"sync"
+)
+
+// SingleFlight 合并同一个 key 的并发调用，只有第一个调用真正执行，其他调用等待并共享结果。
+// 零值可直接使用。
+type SingleFlight[K comparable, V any] struct {
+	mu    sync.Mutex
+	calls map[K]*flightCall[V]
+}
+
+type flightCall[V any] struct {
+	wg  sync.WaitGroup
+	val V
+	err error
+}
+
+// Do 执行 fn，同一个 key 正在执行时等待其结果，shared 表示结果来自其他调用。
+// fn panic 时转换为错误返回，不会导致等待的调用永久阻塞。
+func (g *SingleFlight[K, V]) Do(key K, fn func() (V, error)) (val V, shared bool, err error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[K]*flightCall[V])
+	}
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		c.wg.Wait()
+		return c.val, true, c.err
+	}
+	c := &flightCall[V]{}
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	g.call(key, c, fn)
+	return c.val, false, c.err
+}
+
+// Go 在后台执行 fn，同一个 key 正在执行时直接返回 false
+func (g *SingleFlight[K, V]) Go(key K, fn func() (V, error)) bool {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[K]*flightCall[V])
+	}
+	if _, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		return false
+	}
+	c := &flightCall[V]{}
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	go g.call(key, c, fn)
+	return true
+}
+
+func (g *SingleFlight[K, V]) call(key K, c *flightCall[V], fn func() (V, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.err = fmt.Errorf("加载数据异常 %v", r)
+		}
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		c.wg.Done()
+	}()
+	c.val, c.err = fn()
+}
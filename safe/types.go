@@ -19,10 +19,39 @@ type CacheConfig struct {
 	TTL           time.Duration `json:"ttl" yaml:"ttl" ini:"ttl"`                                  // 默认TTL为0，表示不过期
 	ShardSize     uint64        `json:"shard_size" yaml:"shard_size" ini:"shard_size"`             // 分片数量，默认为2的8次方
 	UseKey        bool          `json:"use_key" yaml:"use_key" ini:"use_key"`                      // 是否使用 key 作为 hash 值
+	MaxEntries    int           `json:"max_entries" yaml:"max_entries" ini:"max_entries"`          // 最大条目数，0 表示不限制，按分片向下取整平均分配，每个分片独立淘汰，未设置分片数量时会按容量减少分片
+	MaxBytes      int64         `json:"max_bytes" yaml:"max_bytes" ini:"max_bytes"`                // 最大字节数，0 表示不限制，按分片平均分配，需要通过 SetSizeFunc 设置大小计算函数
+	EvictPolicy   EvictPolicy   `json:"evict_policy" yaml:"evict_policy" ini:"evict_policy"`       // 超过容量时的淘汰策略 lru、lfu、tinylfu、arc，默认 lru
+	RefreshAhead  float64       `json:"refresh_ahead" yaml:"refresh_ahead" ini:"refresh_ahead"`    // 提前刷新，LoadOrStoreFunc 命中时已过去的时间超过 ttl 的该比例则后台重新加载，取值 (0,1)，0 表示不启用
+	StaleGrace    time.Duration `json:"stale_grace" yaml:"stale_grace" ini:"stale_grace"`          // 过期后保留的宽限期，期间 LoadOrStoreFunc 重新加载失败时返回旧值
 }
 
-// OnExpired 过期回调
-type OnExpired[K comparable] func(key []K) // 过期回调
+// OnExpired 数据被移除时的回调，包括过期清理和超过容量被淘汰
+type OnExpired[K comparable] func(keys []K, reason EvictReason)
+
+// SizeFunc 计算单个条目占用的字节数
+type SizeFunc[K comparable, V any] func(key K, val V) int64
+
+// Stats 缓存统计
+type Stats struct {
+	Hits        uint64 // 命中次数
+	Misses      uint64 // 未命中次数
+	Evictions   uint64 // 超过容量被淘汰的数量
+	Expirations uint64 // 过期清理的数量
+	Loads       uint64 // LoadOrStoreFunc 实际执行加载的次数，包括后台刷新
+	LoadErrors  uint64 // 加载失败的次数
+	StaleHits   uint64 // 加载失败后返回旧值的次数
+	Entries     int    // 当前条目数，包含已过期但还未清理的
+	Bytes       int64  // 当前占用字节数，设置了大小计算函数时有效
+}
+
+// HitRate 命中率
+func (s Stats) HitRate() float64 {
+	if total := s.Hits + s.Misses; total > 0 {
+		return float64(s.Hits) / float64(total)
+	}
+	return 0
+}
 
 // Hasher 编译时确定的哈希函数
 type Hasher[K comparable] interface {
@@ -0,0 +1,139 @@
+package scheduler
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// MemoryStore 内存任务存储，进程退出后任务丢失，适用于单机和测试
+type MemoryStore struct {
+	mu       sync.Mutex
+	jobs     map[string]*Job
+	deadTime map[string]time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		jobs:     make(map[string]*Job),
+		deadTime: make(map[string]time.Time),
+	}
+}
+
+func (m *MemoryStore) Add(_ context.Context, job *Job) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.jobs[job.ID]; ok {
+		return ErrDuplicate
+	}
+	j := *job
+	m.jobs[job.ID] = &j
+	return nil
+}
+
+func (m *MemoryStore) Claim(_ context.Context, now time.Time, limit int, lease time.Duration) ([]*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var due []*Job
+	for _, j := range m.jobs {
+		if j.Status == StatusPending && !j.RunAt.After(now) {
+			due = append(due, j)
+		}
+	}
+	slices.SortFunc(due, func(a, b *Job) int { return a.RunAt.Compare(b.RunAt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	out := make([]*Job, len(due))
+	for i, j := range due {
+		j.RunAt = now.Add(lease)
+		j.Attempts++
+		c := *j
+		c.Lease = j.RunAt
+		out[i] = &c
+	}
+	return out, nil
+}
+
+func (m *MemoryStore) Complete(_ context.Context, job *Job) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.leased(job); !ok {
+		return ErrLeaseLost
+	}
+	delete(m.jobs, job.ID)
+	delete(m.deadTime, job.ID)
+	return nil
+}
+
+func (m *MemoryStore) Retry(_ context.Context, job *Job) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.leased(job)
+	if !ok {
+		return ErrLeaseLost
+	}
+	j.RunAt, j.LastError = job.RunAt, job.LastError
+	return nil
+}
+
+func (m *MemoryStore) Dead(_ context.Context, job *Job) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.leased(job)
+	if !ok {
+		return ErrLeaseLost
+	}
+	j.Status, j.LastError = StatusDead, job.LastError
+	m.deadTime[job.ID] = time.Now()
+	return nil
+}
+
+func (m *MemoryStore) DeadJobs(_ context.Context, limit int) ([]*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []*Job
+	for id := range m.deadTime {
+		j, ok := m.jobs[id]
+		if !ok {
+			continue
+		}
+		c := *j
+		out = append(out, &c)
+	}
+	slices.SortFunc(out, func(a, b *Job) int { return m.deadTime[b.ID].Compare(m.deadTime[a.ID]) })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func (m *MemoryStore) Requeue(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.jobs[id]
+	if !ok || j.Status != StatusDead {
+		return ErrNotFound
+	}
+	j.Status, j.Attempts, j.RunAt = StatusPending, 0, time.Now()
+	delete(m.deadTime, id)
+	return nil
+}
+
+func (m *MemoryStore) Remove(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.jobs, id)
+	delete(m.deadTime, id)
+	return nil
+}
+
+// leased 获取仍由 job 的租约持有的任务，调用方持有锁
+func (m *MemoryStore) leased(job *Job) (*Job, bool) {
+	j, ok := m.jobs[job.ID]
+	if !ok || !job.Owns(j) {
+		return nil, false
+	}
+	return j, true
+}
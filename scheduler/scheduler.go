@@ -0,0 +1,375 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"helay.net/go/utils/v3/cluster"
+	"helay.net/go/utils/v3/logger/ulogs"
+	"helay.net/go/utils/v3/logger/zaploger"
+	"helay.net/go/utils/v3/objectId"
+	"helay.net/go/utils/v3/tools"
+	"helay.net/go/utils/v3/tools/backoff"
+	"helay.net/go/utils/v3/workerGeneric"
+)
+
+// 持久化任务调度
+//
+// 任务保存在 Store 中，所有节点都可以执行 Run 领取到期任务，任务通过租约保证同一时间只有一个节点执行，
+// 节点异常退出后，租约到期的任务会被其他节点重新领取，因此任务至少执行一次，处理函数需要保证幂等。
+// 周期任务由 RunCron 触发，多节点部署时通过 cluster.RunWithLeader 保证只有主节点触发，
+// 触发时以 名称+触发时间 作为唯一key写入一次性任务，主从切换时不会重复触发。
+//
+//	s := scheduler.New(scheduler.NewMemoryStore(), scheduler.Config{})
+//	s.Handle("email", func(ctx context.Context, job *scheduler.Job) error { ... })
+//	_, err := s.Enqueue(ctx, "email", payload, scheduler.WithDelay(time.Minute), scheduler.WithUniqueKey("email:1"))
+//	_ = s.Cron("0 3 * * *", "report", nil)
+//	s.RunCron(ctx, leaderCh)
+//	go s.Run(ctx)
+
+var cronParser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// Config 调度配置
+type Config struct {
+	Concurrency  int           `json:"concurrency" yaml:"concurrency" ini:"concurrency"`       // 并发执行的任务数，默认4
+	PollInterval time.Duration `json:"poll_interval" yaml:"poll_interval" ini:"poll_interval"` // 轮询间隔，默认1s
+	Lease        time.Duration `json:"lease" yaml:"lease" ini:"lease"`                         // 任务租约，同时也是任务的执行超时，默认5m
+	MaxAttempts  int           `json:"max_attempts" yaml:"max_attempts" ini:"max_attempts"`    // 默认最大尝试次数，默认3
+	MinBackoff   time.Duration `json:"min_backoff" yaml:"min_backoff" ini:"min_backoff"`       // 失败重试的初始等待时间，默认1s
+	MaxBackoff   time.Duration `json:"max_backoff" yaml:"max_backoff" ini:"max_backoff"`       // 失败重试的最大等待时间，默认10m
+}
+
+// HandlerFunc 任务处理函数，返回错误时按退避策略重试
+type HandlerFunc func(ctx context.Context, job *Job) error
+
+type cronEntry struct {
+	spec     string
+	name     string
+	schedule cron.Schedule
+	payload  []byte
+	opts     []EnqueueOption
+}
+
+// Scheduler 任务调度
+type Scheduler struct {
+	store    Store
+	cfg      Config
+	mu       sync.RWMutex
+	handlers map[string]HandlerFunc
+	crons    []*cronEntry
+	cronMu   sync.Mutex
+	cronCtx  context.Context // 当前周期任务循环所属的主节点任期
+	cronDone chan struct{}   // 当前周期任务循环的结束通知
+	Loger    *zaploger.Logger
+}
+
+// New 创建任务调度
+func New(store Store, cfg Config) *Scheduler {
+	cfg.Concurrency = tools.Ternary(cfg.Concurrency <= 0, 4, cfg.Concurrency)
+	cfg.PollInterval = tools.AutoTimeDuration(cfg.PollInterval, time.Second, time.Second)
+	cfg.Lease = tools.AutoTimeDuration(cfg.Lease, time.Minute, 5*time.Minute)
+	cfg.MaxAttempts = tools.Ternary(cfg.MaxAttempts <= 0, 3, cfg.MaxAttempts)
+	cfg.MinBackoff = tools.AutoTimeDuration(cfg.MinBackoff, time.Second, time.Second)
+	cfg.MaxBackoff = tools.AutoTimeDuration(cfg.MaxBackoff, time.Minute, 10*time.Minute)
+	return &Scheduler{
+		store:    store,
+		cfg:      cfg,
+		handlers: make(map[string]HandlerFunc),
+	}
+}
+
+// Handle 注册任务处理函数
+func (s *Scheduler) Handle(name string, h HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[name] = h
+}
+
+// EnqueueOption 任务配置
+type EnqueueOption func(*Job)
+
+// WithDelay 延迟执行
+func WithDelay(d time.Duration) EnqueueOption {
+	return func(j *Job) {
+		j.RunAt = time.Now().Add(d)
+	}
+}
+
+// WithRunAt 指定执行时间
+func WithRunAt(t time.Time) EnqueueOption {
+	return func(j *Job) {
+		j.RunAt = t
+	}
+}
+
+// WithUniqueKey 设置唯一key，相同key的任务未完成前重复添加会返回 ErrDuplicate
+func WithUniqueKey(key string) EnqueueOption {
+	return func(j *Job) {
+		j.ID = key
+	}
+}
+
+// WithMaxAttempts 设置最大尝试次数
+func WithMaxAttempts(n int) EnqueueOption {
+	return func(j *Job) {
+		j.MaxAttempts = n
+	}
+}
+
+// Enqueue 添加一次性任务，payload 使用 tools.Any2bytes 转换
+func (s *Scheduler) Enqueue(ctx context.Context, name string, payload any, opts ...EnqueueOption) (*Job, error) {
+	byt, err := toBytes(payload)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	job := &Job{
+		Name:        name,
+		Payload:     byt,
+		RunAt:       now,
+		MaxAttempts: s.cfg.MaxAttempts,
+		Status:      StatusPending,
+		CreateTime:  now,
+	}
+	for _, opt := range opts {
+		opt(job)
+	}
+	if job.ID == "" {
+		job.ID = objectId.NewId()
+	}
+	job.MaxAttempts = max(job.MaxAttempts, 1)
+	if err = s.store.Add(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Cron 注册周期任务，spec 支持可选的秒字段和 @every 等描述符，需要调用 RunCron 后才会触发
+// opts 中的 WithDelay、WithRunAt、WithUniqueKey 不生效。
+func (s *Scheduler) Cron(spec, name string, payload any, opts ...EnqueueOption) error {
+	schedule, err := cronParser.Parse(spec)
+	if err != nil {
+		return fmt.Errorf("cron表达式[%s]解析失败 %w", spec, err)
+	}
+	byt, err := toBytes(payload)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.crons = append(s.crons, &cronEntry{spec: spec, name: name, schedule: schedule, payload: byt, opts: opts})
+	return nil
+}
+
+// RunCron 开始触发周期任务，不阻塞，ctx 结束后停止
+// leader 为选主结果通知，未开启多节点模式时可以传 nil。
+func (s *Scheduler) RunCron(ctx context.Context, leader chan bool) {
+	go cluster.RunWithLeader(leader, ctx, s.startCron)
+}
+
+// startCron 每个主节点任期启动一个周期任务循环，ctx 在失去主节点身份时取消
+// 同一任期重复收到主节点通知时不重复启动；新任期等待上一个任期的循环退出后再启动，保证同一时间只有一个循环。
+func (s *Scheduler) startCron(ctx context.Context) {
+	s.cronMu.Lock()
+	defer s.cronMu.Unlock()
+	if s.cronCtx == ctx {
+		return
+	}
+	prev, done := s.cronDone, make(chan struct{})
+	s.cronCtx, s.cronDone = ctx, done
+	go func() {
+		defer close(done)
+		if prev != nil {
+			select {
+			case <-prev:
+			case <-ctx.Done():
+				return
+			}
+		}
+		s.cronLoop(ctx)
+	}()
+}
+
+func (s *Scheduler) cronLoop(ctx context.Context) {
+	s.mu.RLock()
+	entries := s.crons
+	s.mu.RUnlock()
+	if len(entries) == 0 {
+		return
+	}
+	next := make([]time.Time, len(entries))
+	now := time.Now()
+	for i, e := range entries {
+		next[i] = e.schedule.Next(now)
+	}
+	for {
+		earliest := next[0]
+		for _, t := range next[1:] {
+			if t.Before(earliest) {
+				earliest = t
+			}
+		}
+		timer := time.NewTimer(time.Until(earliest))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		now = time.Now()
+		for i, e := range entries {
+			if next[i].After(now) {
+				continue
+			}
+			key := fmt.Sprintf("cron:%s:%d", e.name, next[i].Unix())
+			opts := append(append([]EnqueueOption{}, e.opts...), WithUniqueKey(key), WithRunAt(next[i]))
+			if _, err := s.Enqueue(ctx, e.name, e.payload, opts...); err != nil && !errors.Is(err, ErrDuplicate) {
+				s.error("周期任务触发失败", err, "任务", e.name, "cron", e.spec)
+			}
+			next[i] = e.schedule.Next(now)
+		}
+	}
+}
+
+// Run 领取并执行到期任务，阻塞直到 ctx 结束，ctx 结束时等待执行中的任务完成
+func (s *Scheduler) Run(ctx context.Context) {
+	pool := workerGeneric.NewPool[struct{}](workerGeneric.PoolConfig{Size: s.cfg.Concurrency})
+	defer func() { _ = pool.Shutdown(context.Background()) }()
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+	b := backoff.NewBackoff(backoff.Exponential, s.cfg.PollInterval, time.Minute, 2.0)
+	for {
+		for {
+			st := pool.Stats()
+			free := s.cfg.Concurrency - int(st.Running+st.Queued)
+			if free <= 0 {
+				break
+			}
+			jobs, err := s.store.Claim(ctx, time.Now(), free, s.cfg.Lease)
+			if err != nil {
+				if ctx.Err() == nil {
+					s.error("领取任务失败", err)
+					select {
+					case <-time.After(b.Next()):
+					case <-ctx.Done():
+					}
+				}
+				break
+			}
+			b.Reset()
+			for _, job := range jobs {
+				// 任务的执行不受调度退出影响，由租约控制超时
+				_, err = pool.Submit(context.WithoutCancel(ctx), func(ctx context.Context) (struct{}, error) {
+					s.execute(ctx, job)
+					return struct{}{}, nil
+				}, workerGeneric.WithTimeout(s.cfg.Lease))
+				if err != nil {
+					s.error("提交任务失败", err, "任务", job.Name, "ID", job.ID)
+				}
+			}
+			if len(jobs) < free {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// execute 执行任务，成功删除，失败按退避重试，超过最大尝试次数转入死信
+func (s *Scheduler) execute(ctx context.Context, job *Job) {
+	s.mu.RLock()
+	h := s.handlers[job.Name]
+	s.mu.RUnlock()
+	var err error
+	if h == nil {
+		err = fmt.Errorf("任务[%s]未注册处理函数", job.Name)
+	} else {
+		err = call(ctx, h, job)
+	}
+	ctx = context.WithoutCancel(ctx)
+	if err == nil {
+		if err = s.store.Complete(ctx, job); errors.Is(err, ErrLeaseLost) {
+			s.error("任务租约已失效，任务可能已被其他节点重新执行", err, "任务", job.Name, "ID", job.ID)
+		} else if err != nil {
+			s.error("任务完成状态保存失败", err, "任务", job.Name, "ID", job.ID)
+		}
+		return
+	}
+	job.LastError = err.Error()
+	if job.Attempts >= job.MaxAttempts {
+		s.error("任务超过最大尝试次数，转入死信", err, "任务", job.Name, "ID", job.ID, "次数", job.Attempts)
+		err = s.store.Dead(ctx, job)
+	} else {
+		job.RunAt = time.Now().Add(s.retryDelay(job.Attempts))
+		err = s.store.Retry(ctx, job)
+	}
+	if errors.Is(err, ErrLeaseLost) {
+		s.error("任务租约已失效，失败状态不再保存", err, "任务", job.Name, "ID", job.ID)
+	} else if err != nil {
+		s.error("任务失败状态保存失败", err, "任务", job.Name, "ID", job.ID)
+	}
+}
+
+// retryDelay 第 attempts 次失败后的等待时间
+func (s *Scheduler) retryDelay(attempts int) time.Duration {
+	b := backoff.NewBackoff(backoff.Exponential, s.cfg.MinBackoff, s.cfg.MaxBackoff, 2.0)
+	var d time.Duration
+	for i := 0; i < attempts; i++ {
+		if d = b.Next(); d >= s.cfg.MaxBackoff {
+			break
+		}
+	}
+	return d
+}
+
+// DeadJobs 获取死信任务
+func (s *Scheduler) DeadJobs(ctx context.Context, limit int) ([]*Job, error) {
+	return s.store.DeadJobs(ctx, limit)
+}
+
+// Requeue 死信任务重新排队
+func (s *Scheduler) Requeue(ctx context.Context, id string) error {
+	return s.store.Requeue(ctx, id)
+}
+
+// Cancel 取消任务，执行中的任务不会中断
+func (s *Scheduler) Cancel(ctx context.Context, id string) error {
+	return s.store.Remove(ctx, id)
+}
+
+func call(ctx context.Context, h HandlerFunc, job *Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("任务执行异常 %v\n%s", r, debug.Stack())
+		}
+	}()
+	return h(ctx, job)
+}
+
+func toBytes(payload any) ([]byte, error) {
+	if payload == nil {
+		return nil, nil
+	}
+	byt, err := tools.Any2bytes(payload)
+	if err != nil {
+		return nil, fmt.Errorf("任务参数转换失败 %w", err)
+	}
+	return byt, nil
+}
+
+func (s *Scheduler) error(title string, err error, args ...any) {
+	if s.Loger == nil {
+		ulogs.Error(append([]any{title, err}, args...)...)
+	} else {
+		s.Loger.Error(context.Background(), title, zaploger.Auto2Field(append([]any{"错误信息", err}, args...)...))
+	}
+}
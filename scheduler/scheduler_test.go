@@ -0,0 +1,73 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreLeaseLost(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	if err := store.Add(ctx, &Job{ID: "a", Name: "job", RunAt: time.Now(), MaxAttempts: 3}); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	first, _ := store.Claim(ctx, now, 1, time.Millisecond)
+	if len(first) != 1 {
+		t.Fatalf("claimed %d jobs", len(first))
+	}
+	// 租约到期后被其他节点重新领取
+	second, _ := store.Claim(ctx, now.Add(time.Second), 1, time.Minute)
+	if len(second) != 1 {
+		t.Fatalf("reclaimed %d jobs", len(second))
+	}
+
+	if err := store.Complete(ctx, first[0]); !errors.Is(err, ErrLeaseLost) {
+		t.Fatalf("Complete with stale lease = %v", err)
+	}
+	first[0].RunAt = time.Now()
+	if err := store.Retry(ctx, first[0]); !errors.Is(err, ErrLeaseLost) {
+		t.Fatalf("Retry with stale lease = %v", err)
+	}
+	if err := store.Dead(ctx, first[0]); !errors.Is(err, ErrLeaseLost) {
+		t.Fatalf("Dead with stale lease = %v", err)
+	}
+	if err := store.Complete(ctx, second[0]); err != nil {
+		t.Fatalf("Complete with current lease = %v", err)
+	}
+	if err := store.Complete(ctx, second[0]); !errors.Is(err, ErrLeaseLost) {
+		t.Fatalf("Complete after removal = %v", err)
+	}
+}
+
+func TestStartCronNewTerm(t *testing.T) {
+	s := New(NewMemoryStore(), Config{})
+	if err := s.Cron("@every 1h", "job", nil); err != nil {
+		t.Fatal(err)
+	}
+	term1, cancel1 := context.WithCancel(context.Background())
+	s.startCron(term1)
+	s.startCron(term1) // 同一任期重复通知
+	done1 := s.cronDone
+
+	// 失去主节点身份后马上重新当选，旧循环还没有退出
+	cancel1()
+	term2, cancel2 := context.WithCancel(context.Background())
+	defer cancel2()
+	s.startCron(term2)
+	done2 := s.cronDone
+	if done1 == done2 {
+		t.Fatal("new term did not start a new loop")
+	}
+
+	<-done1
+	select {
+	case <-done2:
+		t.Fatal("loop of the current term exited")
+	case <-time.After(20 * time.Millisecond):
+	}
+	cancel2()
+	<-done2
+}
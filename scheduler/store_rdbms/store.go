@@ -0,0 +1,174 @@
+package store_rdbms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"helay.net/go/utils/v3/db/userDb"
+	"helay.net/go/utils/v3/scheduler"
+)
+
+// Store 关系数据库任务存储
+// 领取任务时以 run_at 作为乐观锁条件逐条更新，多个节点同时领取时只有一个节点成功。
+// 完成、重试、转入死信时以领取时的 run_at 和 attempts 作为条件，租约到期后被其他节点重新领取的任务不会被修改。
+type Store struct {
+	db *gorm.DB
+}
+
+// New 创建关系数据库任务存储，并自动创建任务表
+func New(tx *gorm.DB) (*Store, error) {
+	if tx == nil {
+		return nil, errors.New("关系数据库任务存储未设置数据库连接")
+	}
+	s := &Store{db: tx.Session(&gorm.Session{NewDB: true})}
+	userDb.AutoCreateTableWithStruct(s.db, SchedulerJob{}, "创建任务表失败")
+	return s, nil
+}
+
+func (s *Store) tx(ctx context.Context) *gorm.DB {
+	return s.db.WithContext(ctx)
+}
+
+func (s *Store) Add(ctx context.Context, job *scheduler.Job) error {
+	row := &SchedulerJob{
+		Id:          job.ID,
+		Name:        job.Name,
+		Payload:     job.Payload,
+		RunAt:       job.RunAt.UnixMilli(),
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		LastError:   job.LastError,
+		Status:      int(job.Status),
+	}
+	res := s.tx(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(row)
+	if res.Error != nil {
+		return fmt.Errorf("写入任务失败 %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return scheduler.ErrDuplicate
+	}
+	return nil
+}
+
+func (s *Store) Claim(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*scheduler.Job, error) {
+	var rows []SchedulerJob
+	err := s.tx(ctx).Where("status = ? AND run_at <= ?", scheduler.StatusPending, now.UnixMilli()).
+		Order("run_at").Limit(limit).Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	runAt := now.Add(lease).UnixMilli()
+	jobs := make([]*scheduler.Job, 0, len(rows))
+	for _, row := range rows {
+		res := s.tx(ctx).Model(&SchedulerJob{}).
+			Where("id = ? AND status = ? AND run_at = ?", row.Id, scheduler.StatusPending, row.RunAt).
+			Updates(map[string]any{"run_at": runAt, "attempts": gorm.Expr("attempts + 1")})
+		if res.Error != nil {
+			return jobs, res.Error
+		}
+		if res.RowsAffected == 0 {
+			continue // 已被其他节点领取
+		}
+		row.RunAt = runAt
+		row.Attempts++
+		job := toJob(&row)
+		job.Lease = job.RunAt
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+func (s *Store) Complete(ctx context.Context, job *scheduler.Job) error {
+	res := s.leased(ctx, job).Delete(&SchedulerJob{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return scheduler.ErrLeaseLost
+	}
+	return nil
+}
+
+func (s *Store) Retry(ctx context.Context, job *scheduler.Job) error {
+	return s.update(ctx, job, map[string]any{"run_at": job.RunAt.UnixMilli(), "last_error": job.LastError})
+}
+
+func (s *Store) Dead(ctx context.Context, job *scheduler.Job) error {
+	return s.update(ctx, job, map[string]any{
+		"status":     scheduler.StatusDead,
+		"last_error": job.LastError,
+		"dead_time":  time.Now().UnixMilli(),
+	})
+}
+
+func (s *Store) DeadJobs(ctx context.Context, limit int) ([]*scheduler.Job, error) {
+	var rows []SchedulerJob
+	tx := s.tx(ctx).Where("status = ?", scheduler.StatusDead).Order("dead_time DESC")
+	if limit > 0 {
+		tx = tx.Limit(limit)
+	}
+	if err := tx.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	jobs := make([]*scheduler.Job, len(rows))
+	for i := range rows {
+		jobs[i] = toJob(&rows[i])
+	}
+	return jobs, nil
+}
+
+func (s *Store) Requeue(ctx context.Context, id string) error {
+	res := s.tx(ctx).Model(&SchedulerJob{}).Where("id = ? AND status = ?", id, scheduler.StatusDead).Updates(map[string]any{
+		"status":    scheduler.StatusPending,
+		"attempts":  0,
+		"run_at":    time.Now().UnixMilli(),
+		"dead_time": 0,
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return scheduler.ErrNotFound
+	}
+	return nil
+}
+
+func (s *Store) Remove(ctx context.Context, id string) error {
+	return s.tx(ctx).Where("id = ?", id).Delete(&SchedulerJob{}).Error
+}
+
+// update 修改仍由 job 的租约持有的任务
+func (s *Store) update(ctx context.Context, job *scheduler.Job, updates map[string]any) error {
+	res := s.leased(ctx, job).Updates(updates)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return scheduler.ErrLeaseLost
+	}
+	return nil
+}
+
+// leased 以领取时的 run_at 和 attempts 作为条件
+func (s *Store) leased(ctx context.Context, job *scheduler.Job) *gorm.DB {
+	return s.tx(ctx).Model(&SchedulerJob{}).Where("id = ? AND status = ? AND run_at = ? AND attempts = ?",
+		job.ID, scheduler.StatusPending, job.Lease.UnixMilli(), job.Attempts)
+}
+
+func toJob(row *SchedulerJob) *scheduler.Job {
+	return &scheduler.Job{
+		ID:          row.Id,
+		Name:        row.Name,
+		Payload:     row.Payload,
+		RunAt:       time.UnixMilli(row.RunAt),
+		Attempts:    row.Attempts,
+		MaxAttempts: row.MaxAttempts,
+		LastError:   row.LastError,
+		Status:      scheduler.Status(row.Status),
+		CreateTime:  row.CreateTime.Time,
+	}
+}
@@ -0,0 +1,25 @@
+package store_rdbms
+
+import (
+	"helay.net/go/utils/v3/dataType"
+	"helay.net/go/utils/v3/db"
+)
+
+// SchedulerJob 任务表
+// 执行时间使用毫秒时间戳保存，领取任务时作为乐观锁条件。
+type SchedulerJob struct {
+	Id          string          `json:"id" gorm:"primaryKey;type:varchar(256);not null;comment:任务ID"`
+	Name        string          `json:"name" gorm:"type:varchar(128);not null;comment:任务名称"`
+	Payload     dataType.Binary `json:"payload" gorm:"comment:任务参数"`
+	RunAt       int64           `json:"run_at" gorm:"not null;index:idx_scheduler_job_status_run_at,priority:2;comment:下次执行时间，毫秒时间戳"`
+	Attempts    int             `json:"attempts" gorm:"type:int;not null;default:0;comment:已尝试次数"`
+	MaxAttempts int             `json:"max_attempts" gorm:"type:int;not null;default:1;comment:最大尝试次数"`
+	LastError   string          `json:"last_error" gorm:"type:text;comment:最后一次失败原因"`
+	Status      int             `json:"status" gorm:"type:int;not null;default:0;index:idx_scheduler_job_status_run_at,priority:1;comment:状态 0等待执行 1死信"`
+	DeadTime    int64           `json:"dead_time" gorm:"not null;default:0;comment:转入死信时间，毫秒时间戳"`
+	db.TableDefaultTimeField
+}
+
+func (SchedulerJob) TableName() string {
+	return "scheduler_job"
+}
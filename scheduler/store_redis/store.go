@@ -0,0 +1,246 @@
+package store_redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"helay.net/go/utils/v3/scheduler"
+)
+
+// redis 任务存储
+// 任务数据：scheduler:{name}:jobs，hash，field 为任务ID，值为任务 json
+// 等待队列：scheduler:{name}:pending，有序集合，score 为执行时间（毫秒）
+// 死信队列：scheduler:{name}:dead，有序集合，score 为转入死信的时间（毫秒）
+// 所有 key 使用相同的 hash tag，集群模式下落在同一个 slot，通过 WATCH 事务保证多节点领取任务的原子性。
+
+const maxTxRetry = 10 // 事务冲突时的最大重试次数
+
+// Store redis任务存储
+type Store struct {
+	rdb     redis.UniversalClient
+	jobs    string
+	pending string
+	dead    string
+}
+
+// New 创建redis任务存储，name 用于隔离多个调度实例的数据
+func New(rdb redis.UniversalClient, name string) (*Store, error) {
+	if rdb == nil {
+		return nil, errors.New("redis任务存储未设置redis连接")
+	}
+	if name == "" {
+		name = "default"
+	}
+	prefix := fmt.Sprintf("scheduler:{%s}:", name)
+	return &Store{
+		rdb:     rdb,
+		jobs:    prefix + "jobs",
+		pending: prefix + "pending",
+		dead:    prefix + "dead",
+	}, nil
+}
+
+func (s *Store) Add(ctx context.Context, job *scheduler.Job) error {
+	byt, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return s.watch(ctx, func(tx *redis.Tx) error {
+		exists, err := tx.HExists(ctx, s.jobs, job.ID).Result()
+		if err != nil {
+			return err
+		}
+		if exists {
+			return scheduler.ErrDuplicate
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, s.jobs, job.ID, byt)
+			pipe.ZAdd(ctx, s.pending, redis.Z{Score: float64(job.RunAt.UnixMilli()), Member: job.ID})
+			return nil
+		})
+		return err
+	}, s.jobs)
+}
+
+func (s *Store) Claim(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*scheduler.Job, error) {
+	var jobs []*scheduler.Job
+	err := s.watch(ctx, func(tx *redis.Tx) error {
+		jobs = nil
+		ids, err := tx.ZRangeByScore(ctx, s.pending, &redis.ZRangeBy{
+			Min:   "-inf",
+			Max:   fmt.Sprint(now.UnixMilli()),
+			Count: int64(limit),
+		}).Result()
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+		vals, err := tx.HMGet(ctx, s.jobs, ids...).Result()
+		if err != nil {
+			return err
+		}
+		runAt := now.Add(lease)
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			for i, v := range vals {
+				job, err := decode(v)
+				if err != nil {
+					pipe.ZRem(ctx, s.pending, ids[i]) // 数据丢失的任务直接移除
+					continue
+				}
+				job.RunAt = runAt
+				job.Attempts++
+				job.Lease = runAt
+				byt, _ := json.Marshal(job)
+				pipe.HSet(ctx, s.jobs, job.ID, byt)
+				pipe.ZAdd(ctx, s.pending, redis.Z{Score: float64(runAt.UnixMilli()), Member: job.ID})
+				jobs = append(jobs, job)
+			}
+			return nil
+		})
+		return err
+	}, s.pending, s.jobs)
+	return jobs, err
+}
+
+func (s *Store) Complete(ctx context.Context, job *scheduler.Job) error {
+	return s.watch(ctx, func(tx *redis.Tx) error {
+		if _, err := s.leased(ctx, tx, job); err != nil {
+			return err
+		}
+		_, err := tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HDel(ctx, s.jobs, job.ID)
+			pipe.ZRem(ctx, s.pending, job.ID)
+			return nil
+		})
+		return err
+	}, s.jobs)
+}
+
+func (s *Store) Retry(ctx context.Context, job *scheduler.Job) error {
+	return s.modify(ctx, job, func(old *scheduler.Job, pipe redis.Pipeliner) error {
+		old.RunAt, old.LastError = job.RunAt, job.LastError
+		pipe.ZAdd(ctx, s.pending, redis.Z{Score: float64(old.RunAt.UnixMilli()), Member: old.ID})
+		return nil
+	})
+}
+
+func (s *Store) Dead(ctx context.Context, job *scheduler.Job) error {
+	return s.modify(ctx, job, func(old *scheduler.Job, pipe redis.Pipeliner) error {
+		old.Status, old.LastError = scheduler.StatusDead, job.LastError
+		pipe.ZRem(ctx, s.pending, old.ID)
+		pipe.ZAdd(ctx, s.dead, redis.Z{Score: float64(time.Now().UnixMilli()), Member: old.ID})
+		return nil
+	})
+}
+
+func (s *Store) DeadJobs(ctx context.Context, limit int) ([]*scheduler.Job, error) {
+	ids, err := s.rdb.ZRevRange(ctx, s.dead, 0, int64(limit)-1).Result()
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	vals, err := s.rdb.HMGet(ctx, s.jobs, ids...).Result()
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]*scheduler.Job, 0, len(vals))
+	for _, v := range vals {
+		if job, err := decode(v); err == nil {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+func (s *Store) Requeue(ctx context.Context, id string) error {
+	return s.modify(ctx, &scheduler.Job{ID: id}, func(old *scheduler.Job, pipe redis.Pipeliner) error {
+		if old.Status != scheduler.StatusDead {
+			return scheduler.ErrNotFound
+		}
+		old.Status, old.Attempts, old.RunAt = scheduler.StatusPending, 0, time.Now()
+		pipe.ZRem(ctx, s.dead, old.ID)
+		pipe.ZAdd(ctx, s.pending, redis.Z{Score: float64(old.RunAt.UnixMilli()), Member: old.ID})
+		return nil
+	})
+}
+
+func (s *Store) Remove(ctx context.Context, id string) error {
+	_, err := s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HDel(ctx, s.jobs, id)
+		pipe.ZRem(ctx, s.pending, id)
+		pipe.ZRem(ctx, s.dead, id)
+		return nil
+	})
+	return err
+}
+
+// modify 在事务中修改任务，fn 修改任务数据并写入额外的命令
+// job 设置了 Lease 时只修改仍由该租约持有的任务，租约失效时返回 ErrLeaseLost。
+func (s *Store) modify(ctx context.Context, job *scheduler.Job, fn func(old *scheduler.Job, pipe redis.Pipeliner) error) error {
+	return s.watch(ctx, func(tx *redis.Tx) error {
+		old, err := s.leased(ctx, tx, job)
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			if err := fn(old, pipe); err != nil {
+				return err
+			}
+			byt, err := json.Marshal(old)
+			if err != nil {
+				return err
+			}
+			pipe.HSet(ctx, s.jobs, old.ID, byt)
+			return nil
+		})
+		return err
+	}, s.jobs)
+}
+
+// leased 读取任务，job 设置了 Lease 时检查租约是否仍然有效
+func (s *Store) leased(ctx context.Context, tx *redis.Tx, job *scheduler.Job) (*scheduler.Job, error) {
+	lost := scheduler.ErrNotFound
+	if !job.Lease.IsZero() {
+		lost = scheduler.ErrLeaseLost
+	}
+	val, err := tx.HGet(ctx, s.jobs, job.ID).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, lost
+	}
+	if err != nil {
+		return nil, err
+	}
+	old, err := decode(val)
+	if err != nil {
+		return nil, err
+	}
+	if !job.Lease.IsZero() && !job.Owns(old) {
+		return nil, lost
+	}
+	return old, nil
+}
+
+// watch 执行 WATCH 事务，冲突时重试
+func (s *Store) watch(ctx context.Context, fn func(tx *redis.Tx) error, keys ...string) error {
+	for i := 0; i < maxTxRetry; i++ {
+		err := s.rdb.Watch(ctx, fn, keys...)
+		if !errors.Is(err, redis.TxFailedErr) {
+			return err
+		}
+	}
+	return fmt.Errorf("redis任务存储事务冲突 %w", redis.TxFailedErr)
+}
+
+func decode(v any) (*scheduler.Job, error) {
+	str, ok := v.(string)
+	if !ok {
+		return nil, scheduler.ErrNotFound
+	}
+	var job scheduler.Job
+	if err := json.Unmarshal([]byte(str), &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
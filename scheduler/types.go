@@ -0,0 +1,54 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrDuplicate = errors.New("任务已存在")
+	ErrNotFound  = errors.New("任务不存在")
+	ErrLeaseLost = errors.New("任务租约已失效") // 租约到期后任务已被其他节点重新领取，或者已被删除
+)
+
+// Status 任务状态
+type Status int
+
+const (
+	StatusPending Status = 0 // 等待执行，包括执行中的任务
+	StatusDead    Status = 1 // 超过最大尝试次数，进入死信
+)
+
+// Job 任务
+type Job struct {
+	ID          string    `json:"id"`           // 任务ID，设置了唯一key时为唯一key
+	Name        string    `json:"name"`         // 任务名称，对应 Scheduler.Handle 注册的处理函数
+	Payload     []byte    `json:"payload"`      // 任务参数
+	RunAt       time.Time `json:"run_at"`       // 下次执行时间，执行中的任务为租约到期时间
+	Attempts    int       `json:"attempts"`     // 已尝试次数
+	MaxAttempts int       `json:"max_attempts"` // 最大尝试次数
+	LastError   string    `json:"last_error"`   // 最后一次失败原因
+	Status      Status    `json:"status"`       // 状态
+	CreateTime  time.Time `json:"create_time"`  // 创建时间
+	Lease       time.Time `json:"-"`            // 领取时的租约到期时间，由 Store.Claim 设置，与 Attempts 一起用于判断租约是否仍然有效
+}
+
+// Owns 存储中的任务 cur 是否仍由 j 的租约持有，供 Store 实现使用
+// 租约到期后其他节点重新领取会修改 RunAt 和 Attempts，原节点的执行结果不能再写入。
+func (j *Job) Owns(cur *Job) bool {
+	return cur.Status == StatusPending && cur.Attempts == j.Attempts && cur.RunAt.UnixMilli() == j.Lease.UnixMilli()
+}
+
+// Store 任务存储
+// 任务ID在存储中唯一，包括死信中的任务，死信任务需要 Requeue 或 Remove 后才能重新添加。
+type Store interface {
+	Add(ctx context.Context, job *Job) error                                                  // 添加任务，ID 已存在时返回 ErrDuplicate
+	Claim(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*Job, error) // 领取到期任务，RunAt 延后 lease，Attempts 加1，并设置 Lease，租约到期前其他节点领取不到
+	Complete(ctx context.Context, job *Job) error                                             // 任务执行成功，删除任务，租约失效时返回 ErrLeaseLost
+	Retry(ctx context.Context, job *Job) error                                                // 任务执行失败，按 job.RunAt 重新排队，并保存 LastError，租约失效时返回 ErrLeaseLost
+	Dead(ctx context.Context, job *Job) error                                                 // 任务转入死信，租约失效时返回 ErrLeaseLost
+	DeadJobs(ctx context.Context, limit int) ([]*Job, error)                                  // 获取死信任务，按转入时间倒序
+	Requeue(ctx context.Context, id string) error                                             // 死信任务重新排队，尝试次数清零
+	Remove(ctx context.Context, id string) error                                              // 删除任务，包括死信任务
+}
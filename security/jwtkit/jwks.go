@@ -0,0 +1,178 @@
+package jwtkit
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// JWK RFC 7517 公钥
+type JWK struct {
+	Kty string `json:"kty"`           // 密钥类型 RSA、EC、OKP
+	Kid string `json:"kid,omitempty"` // 密钥ID
+	Use string `json:"use,omitempty"` // 用途，固定为 sig
+	Alg string `json:"alg,omitempty"` // 签名算法
+	N   string `json:"n,omitempty"`   // RSA 模数
+	E   string `json:"e,omitempty"`   // RSA 指数
+	Crv string `json:"crv,omitempty"` // 曲线 P-256、P-384、P-521、Ed25519
+	X   string `json:"x,omitempty"`   // EC、OKP 公钥 x 坐标
+	Y   string `json:"y,omitempty"`   // EC 公钥 y 坐标
+}
+
+// JWKSet RFC 7517 公钥集合
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// newJWK 将验证密钥转换为 JWK，HMAC 密钥不能公开，返回错误
+func newJWK(k *signKey) (JWK, error) {
+	jwk := JWK{Kid: k.id, Use: "sig", Alg: string(k.alg)}
+	b64 := base64.RawURLEncoding.EncodeToString
+	switch pub := k.verify.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = b64(pub.N.Bytes())
+		jwk.E = b64(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		jwk.Kty = "EC"
+		jwk.Crv = pub.Curve.Params().Name
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		jwk.X = b64(pub.X.FillBytes(make([]byte, size)))
+		jwk.Y = b64(pub.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = b64(pub)
+	default:
+		return jwk, fmt.Errorf("%s 密钥不能发布为JWK", k.alg)
+	}
+	return jwk, nil
+}
+
+// Thumbprint RFC 7638 指纹，只使用必需字段按字典序计算
+func (k JWK) Thumbprint() string {
+	var fields any
+	switch k.Kty {
+	case "RSA":
+		fields = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{k.E, k.Kty, k.N}
+	case "EC":
+		fields = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{k.Crv, k.Kty, k.X, k.Y}
+	default:
+		fields = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{k.Crv, k.Kty, k.X}
+	}
+	b, _ := json.Marshal(fields)
+	sum := sha256.Sum256(b)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// PublicKey 转换为 Go 的公钥类型
+func (k JWK) PublicKey() (any, error) {
+	b64 := base64.RawURLEncoding.DecodeString
+	switch k.Kty {
+	case "RSA":
+		n, err := b64(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("JWK[%s] n 解码失败 %w", k.Kid, err)
+		}
+		e, err := b64(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("JWK[%s] e 解码失败 %w", k.Kid, err)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("JWK[%s] 不支持的曲线 %s", k.Kid, k.Crv)
+		}
+		x, err := b64(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("JWK[%s] x 解码失败 %w", k.Kid, err)
+		}
+		y, err := b64(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("JWK[%s] y 解码失败 %w", k.Kid, err)
+		}
+		pub := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, fmt.Errorf("JWK[%s] 公钥不在曲线上", k.Kid)
+		}
+		return pub, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("JWK[%s] 不支持的曲线 %s", k.Kid, k.Crv)
+		}
+		x, err := b64(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("JWK[%s] x 解码失败 %w", k.Kid, err)
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("JWK[%s] Ed25519 公钥长度错误", k.Kid)
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("JWK[%s] 不支持的密钥类型 %s", k.Kid, k.Kty)
+}
+
+// JWKS 当前全部非对称密钥的公钥，包括 pending 和 retired 状态，HMAC 密钥不会发布
+func (m *JWTManager) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	for _, k := range m.keys.Load().keys {
+		if jwk, err := newJWK(k); err == nil {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	return set
+}
+
+// JWKSHandler 发布公钥的 http 接口，通常挂载在 /.well-known/jwks.json
+// maxAge 为验证方的缓存时间，<=0 时默认5分钟，pending 密钥至少要发布这么久才能启用。
+func (m *JWTManager) JWKSHandler(maxAge time.Duration) http.Handler {
+	if maxAge <= 0 {
+		maxAge = 5 * time.Minute
+	}
+	cacheControl := "public, max-age=" + strconv.Itoa(int(maxAge.Seconds()))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		b, err := json.Marshal(m.JWKS())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", cacheControl)
+		_, _ = w.Write(b)
+	})
+}
@@ -1,37 +1,58 @@
 package jwtkit
 
 import (
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"helay.net/go/utils/v3/safe/cachemgr"
 	"helay.net/go/utils/v3/tools"
 	"helay.net/go/utils/v3/tools/sonyflakekit"
 )
 
 type JWTManager struct {
-	config    *Options
-	method    jwt.SigningMethod
-	keyLoader *keyLoader
-	snowflake *sonyflakekit.IDGenerator
+	config       *Options
+	keys         atomic.Pointer[keySet]
+	keyMu        sync.Mutex
+	snowflake    *sonyflakekit.IDGenerator
+	revocation   RevocationList
+	refreshStore cachemgr.Cache[string, string]
 }
 
 func NewJWTManager(config *Options, snowflake *sonyflakekit.IDGenerator) (*JWTManager, error) {
 	config.KeyStoreDir = tools.Fileabs(tools.Ternary(config.KeyStoreDir == "", "keys", config.KeyStoreDir))
+	config.AccessTTL = tools.AutoTimeDuration(config.AccessTTL, time.Second, 15*time.Minute)
+	config.RefreshTTL = tools.AutoTimeDuration(config.RefreshTTL, time.Second, 7*24*time.Hour)
+	m := &JWTManager{config: config, snowflake: snowflake}
+	if len(config.Keys) > 0 {
+		if err := m.SetKeys(config.Keys); err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+	// 单密钥配置，兼容原有的密钥加载和自动生成
 	kl := newKeyLoader(config)
 	if err := kl.setSigningKey(); err != nil {
 		return nil, err
 	}
-	m := &JWTManager{config: config, keyLoader: kl, snowflake: snowflake}
-	method, err := m.getSigningMethod(config.Algorithm)
+	k, err := parseKey(config.Algorithm, kl.signingKey, kl.verifyKey)
+	if err != nil {
+		return nil, err
+	}
+	k.id = keyID(k)
+	k.status = KeyPrimary
+	s, err := newKeySet([]*signKey{k})
 	if err != nil {
 		return nil, err
 	}
-	m.method = method
+	m.keys.Store(s)
 	return m, nil
 
 }
 
-func (m *JWTManager) getSigningMethod(alg Algorithm) (jwt.SigningMethod, error) {
+func signingMethod(alg Algorithm) (jwt.SigningMethod, error) {
 	switch alg {
 	case HS256:
 		return jwt.SigningMethodHS256, nil
@@ -65,6 +86,7 @@ func (m *JWTManager) getSigningMethod(alg Algorithm) (jwt.SigningMethod, error)
 	return nil, ErrUnsupportedAlgorithm
 }
 
+// GenerateToken 使用当前签名密钥签发令牌，令牌头中带有 kid
 func (m *JWTManager) GenerateToken(claims *StandardClaims) (string, error) {
 	jit, err := m.snowflake.GenerateID()
 	if err != nil {
@@ -72,24 +94,56 @@ func (m *JWTManager) GenerateToken(claims *StandardClaims) (string, error) {
 	}
 	claims.ID = tools.Any2string(jit)
 	claims.Timestamp = time.Now()
-	token := jwt.NewWithClaims(m.method, claims)
-	return token.SignedString(m.keyLoader.signingKey)
+	return m.sign(claims)
 }
 
+func (m *JWTManager) sign(claims *StandardClaims) (string, error) {
+	key := m.keys.Load().primary
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.id
+	return token.SignedString(key.sign)
+}
+
+// ValidateToken 验证令牌，按令牌头中的 kid 选择密钥，没有 kid 时使用当前签名密钥。
+// 刷新令牌不能作为访问令牌使用，设置了吊销列表时已吊销的令牌和会话验证失败。
 func (m *JWTManager) ValidateToken(tokenString string) (*StandardClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &StandardClaims{}, func(token *jwt.Token) (interface{}, error) {
-		// 验证签名方法是否匹配
-		if token.Method != m.method {
-			return nil, jwt.ErrSignatureInvalid
-		}
-		return m.keyLoader.verifyKey, nil
-	})
+	claims, err := m.parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType == TokenRefresh {
+		return nil, ErrTokenType
+	}
+	return claims, nil
+}
+
+// parse 验证签名、有效期和吊销状态
+func (m *JWTManager) parse(tokenString string) (*StandardClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &StandardClaims{}, m.keyFunc)
 	if err != nil {
 		return nil, err
 	}
-	if claims, ok := token.Claims.(*StandardClaims); ok && token.Valid {
-		return claims, nil
+	claims, ok := token.Claims.(*StandardClaims)
+	if !ok || !token.Valid {
+		return nil, jwt.ErrInvalidKey
+	}
+	if err = m.checkRevoked(claims); err != nil {
+		return nil, err
 	}
+	return claims, nil
+}
 
-	return nil, jwt.ErrInvalidKey
+func (m *JWTManager) keyFunc(token *jwt.Token) (any, error) {
+	set := m.keys.Load()
+	key := set.primary
+	if kid, ok := token.Header["kid"].(string); ok {
+		if key = set.get(kid); key == nil {
+			return nil, fmt.Errorf("%w %s", ErrKeyNotFound, kid)
+		}
+	}
+	// 验证签名方法是否匹配
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, jwt.ErrSignatureInvalid
+	}
+	return key.verify, nil
 }
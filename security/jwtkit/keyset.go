@@ -0,0 +1,283 @@
+package jwtkit
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/golang-jwt/jwt/v5"
+	"helay.net/go/utils/v3/tools"
+)
+
+var (
+	ErrKeyNotFound  = errors.New("密钥不存在")
+	ErrNoPrimaryKey = errors.New("没有可用的签名密钥")
+)
+
+// signKey 解析后的密钥
+type signKey struct {
+	id     string
+	alg    Algorithm
+	method jwt.SigningMethod
+	sign   any // 签名密钥，只有公钥时为 nil
+	verify any // 验证密钥
+	status KeyStatus
+}
+
+// keySet 密钥集合，创建后不再修改，变更时整体替换
+type keySet struct {
+	keys    []*signKey
+	primary *signKey
+}
+
+func (s *keySet) get(kid string) *signKey {
+	for _, k := range s.keys {
+		if k.id == kid {
+			return k
+		}
+	}
+	return nil
+}
+
+// newKeySet 校验并创建密钥集合，必须有且只有一个带私钥的 primary 密钥
+func newKeySet(keys []*signKey) (*keySet, error) {
+	s := &keySet{keys: keys}
+	for i, k := range keys {
+		if slices.ContainsFunc(keys[:i], func(o *signKey) bool { return o.id == k.id }) {
+			return nil, fmt.Errorf("密钥ID[%s]重复", k.id)
+		}
+		if k.status != KeyPrimary {
+			continue
+		}
+		if s.primary != nil {
+			return nil, fmt.Errorf("密钥[%s]和[%s]不能同时为 primary", s.primary.id, k.id)
+		}
+		if k.sign == nil {
+			return nil, fmt.Errorf("密钥[%s]没有私钥，不能作为 primary", k.id)
+		}
+		s.primary = k
+	}
+	if s.primary == nil {
+		return nil, ErrNoPrimaryKey
+	}
+	return s, nil
+}
+
+// newSignKey 根据配置加载密钥
+func (m *JWTManager) newSignKey(cfg KeyConfig) (*signKey, error) {
+	alg := tools.Ternary(cfg.Algorithm == "", m.config.Algorithm, cfg.Algorithm)
+	var private, public []byte
+	switch alg {
+	case HS256, HS384, HS512:
+		private = []byte(cfg.HMAC.Secret)
+		if len(private) == 0 && cfg.HMAC.SecretPath != "" {
+			b, err := tools.FileGetContents(tools.Fileabs(cfg.HMAC.SecretPath))
+			if err != nil {
+				return nil, err
+			}
+			private = b
+		}
+		if len(private) == 0 {
+			return nil, errors.New("未配置HMAC密钥")
+		}
+	case SigningMethodNone:
+	default:
+		var err error
+		if private, err = readKey(cfg.Asymmetric.Private, cfg.Asymmetric.PrivatePath); err != nil {
+			return nil, err
+		}
+		if public, err = readKey(cfg.Asymmetric.Public, cfg.Asymmetric.PublicPath); err != nil {
+			return nil, err
+		}
+		if len(private) == 0 && len(public) == 0 {
+			return nil, fmt.Errorf("未配置%s密钥", alg)
+		}
+	}
+	k, err := parseKey(alg, private, public)
+	if err != nil {
+		return nil, err
+	}
+	k.id = cfg.ID
+	k.status = tools.Ternary(cfg.Status == "", KeyPrimary, cfg.Status)
+	if k.status != KeyPending && k.status != KeyPrimary && k.status != KeyRetired {
+		return nil, fmt.Errorf("未知的密钥状态[%s]", k.status)
+	}
+	if k.id == "" {
+		k.id = keyID(k)
+	}
+	return k, nil
+}
+
+func readKey(content, path string) ([]byte, error) {
+	if content != "" {
+		return []byte(content), nil
+	}
+	if path == "" {
+		return nil, nil
+	}
+	return tools.FileGetContents(tools.Fileabs(path))
+}
+
+// parseKey 解析 PEM 格式的密钥，private 为空时只能用于验证，public 为空时从私钥推导
+func parseKey(alg Algorithm, private, public []byte) (*signKey, error) {
+	k := &signKey{alg: alg}
+	var err error
+	if k.method, err = signingMethod(alg); err != nil {
+		return nil, err
+	}
+	var (
+		parsePrivate func([]byte) (any, error)
+		parsePublic  func([]byte) (any, error)
+	)
+	switch alg {
+	case HS256, HS384, HS512:
+		k.sign, k.verify = private, private
+		return k, nil
+	case SigningMethodNone:
+		k.sign, k.verify = jwt.UnsafeAllowNoneSignatureType, jwt.UnsafeAllowNoneSignatureType
+		return k, nil
+	case RS256, RS384, RS512, PS256, PS384, PS512:
+		parsePrivate = func(b []byte) (any, error) { return jwt.ParseRSAPrivateKeyFromPEM(b) }
+		parsePublic = func(b []byte) (any, error) { return jwt.ParseRSAPublicKeyFromPEM(b) }
+	case ES256, ES384, ES512:
+		parsePrivate = func(b []byte) (any, error) { return jwt.ParseECPrivateKeyFromPEM(b) }
+		parsePublic = func(b []byte) (any, error) { return jwt.ParseECPublicKeyFromPEM(b) }
+	case EdDSA:
+		parsePrivate = func(b []byte) (any, error) { return jwt.ParseEdPrivateKeyFromPEM(b) }
+		parsePublic = func(b []byte) (any, error) { return jwt.ParseEdPublicKeyFromPEM(b) }
+	}
+	if len(private) > 0 {
+		if k.sign, err = parsePrivate(private); err != nil {
+			return nil, fmt.Errorf("%w %s私钥解析失败 %w", ErrInvalidKeyFormat, alg, err)
+		}
+		k.verify = k.sign.(crypto.Signer).Public()
+	}
+	if len(public) > 0 {
+		if k.verify, err = parsePublic(public); err != nil {
+			return nil, fmt.Errorf("%w %s公钥解析失败 %w", ErrInvalidKeyFormat, alg, err)
+		}
+	}
+	return k, nil
+}
+
+// keyID 默认的 kid，非对称密钥使用 RFC 7638 指纹，HMAC 使用密钥的摘要
+func keyID(k *signKey) string {
+	switch k.alg {
+	case HS256, HS384, HS512:
+		sum := sha256.Sum256(k.verify.([]byte))
+		return "hs-" + hex.EncodeToString(sum[:8])
+	case SigningMethodNone:
+		return SigningMethodNone
+	}
+	if jwk, err := newJWK(k); err == nil {
+		return jwk.Thumbprint()
+	}
+	return string(k.alg)
+}
+
+// SetKeys 整体替换密钥，用于配置热更新，必须有且只有一个 primary 密钥
+func (m *JWTManager) SetKeys(cfgs []KeyConfig) error {
+	keys := make([]*signKey, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		k, err := m.newSignKey(cfg)
+		if err != nil {
+			return fmt.Errorf("密钥[%s]加载失败 %w", cfg.ID, err)
+		}
+		keys = append(keys, k)
+	}
+	s, err := newKeySet(keys)
+	if err != nil {
+		return err
+	}
+	m.keyMu.Lock()
+	m.keys.Store(s)
+	m.keyMu.Unlock()
+	return nil
+}
+
+// AddKey 添加密钥，返回 kid。状态为空时默认为 pending，
+// 轮换时先添加 pending 密钥，等验证方刷新 JWKS 后再通过 PromoteKey 启用。
+func (m *JWTManager) AddKey(cfg KeyConfig) (string, error) {
+	cfg.Status = tools.Ternary(cfg.Status == "", KeyPending, cfg.Status)
+	k, err := m.newSignKey(cfg)
+	if err != nil {
+		return "", err
+	}
+	return k.id, m.updateKeys(func(keys []*signKey) ([]*signKey, error) {
+		if k.status == KeyPrimary {
+			keys = retirePrimary(keys)
+		}
+		return append(keys, k), nil
+	})
+}
+
+// PromoteKey 将密钥设置为签名密钥，原签名密钥变为 retired
+func (m *JWTManager) PromoteKey(kid string) error {
+	return m.updateKeys(func(keys []*signKey) ([]*signKey, error) {
+		i := slices.IndexFunc(keys, func(k *signKey) bool { return k.id == kid })
+		if i < 0 {
+			return nil, fmt.Errorf("%w %s", ErrKeyNotFound, kid)
+		}
+		if keys[i].status == KeyPrimary {
+			return keys, nil
+		}
+		keys = retirePrimary(keys)
+		k := *keys[i]
+		k.status = KeyPrimary
+		keys[i] = &k
+		return keys, nil
+	})
+}
+
+// RemoveKey 删除密钥，不能删除签名密钥，删除后该密钥签发的令牌无法通过验证
+func (m *JWTManager) RemoveKey(kid string) error {
+	return m.updateKeys(func(keys []*signKey) ([]*signKey, error) {
+		i := slices.IndexFunc(keys, func(k *signKey) bool { return k.id == kid })
+		if i < 0 {
+			return nil, fmt.Errorf("%w %s", ErrKeyNotFound, kid)
+		}
+		if keys[i].status == KeyPrimary {
+			return nil, fmt.Errorf("密钥[%s]正在用于签名，不能删除", kid)
+		}
+		return slices.Delete(keys, i, i+1), nil
+	})
+}
+
+// KeyIDs 按状态列出密钥
+func (m *JWTManager) KeyIDs() map[KeyStatus][]string {
+	ids := make(map[KeyStatus][]string)
+	for _, k := range m.keys.Load().keys {
+		ids[k.status] = append(ids[k.status], k.id)
+	}
+	return ids
+}
+
+func (m *JWTManager) updateKeys(fn func(keys []*signKey) ([]*signKey, error)) error {
+	m.keyMu.Lock()
+	defer m.keyMu.Unlock()
+	keys, err := fn(slices.Clone(m.keys.Load().keys))
+	if err != nil {
+		return err
+	}
+	s, err := newKeySet(keys)
+	if err != nil {
+		return err
+	}
+	m.keys.Store(s)
+	return nil
+}
+
+// retirePrimary 将当前签名密钥变为 retired，返回的切片中被修改的元素是副本
+func retirePrimary(keys []*signKey) []*signKey {
+	for i, k := range keys {
+		if k.status == KeyPrimary {
+			c := *k
+			c.status = KeyRetired
+			keys[i] = &c
+		}
+	}
+	return keys
+}
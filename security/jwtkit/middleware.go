@@ -0,0 +1,65 @@
+package jwtkit
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+type claimsCtxKey struct{}
+
+// ContextWithClaims 将 claims 放入上下文
+func ContextWithClaims(ctx context.Context, claims *StandardClaims) context.Context {
+	return context.WithValue(ctx, claimsCtxKey{}, claims)
+}
+
+// ClaimsFromContext 从上下文中读取中间件验证通过的 claims
+func ClaimsFromContext(ctx context.Context) (*StandardClaims, bool) {
+	claims, ok := ctx.Value(claimsCtxKey{}).(*StandardClaims)
+	return claims, ok
+}
+
+// BearerToken 读取 Authorization: Bearer 请求头中的令牌
+func BearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// Middleware 验证 Bearer 令牌的 net/http 中间件，验证通过后 claims 放入请求上下文，
+// 通过 ClaimsFromContext 读取；没有令牌或验证失败时返回 401。
+func Middleware(validate func(r *http.Request, token string) (*StandardClaims, error)) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := BearerToken(r)
+			if token == "" {
+				w.Header().Set("WWW-Authenticate", `Bearer`)
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+			claims, err := validate(r, token)
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(ContextWithClaims(r.Context(), claims)))
+		})
+	}
+}
+
+// Middleware 使用第三方身份提供方的 JWKS 验证令牌
+func (v *Verifier) Middleware() func(next http.Handler) http.Handler {
+	return Middleware(func(r *http.Request, token string) (*StandardClaims, error) {
+		return v.Verify(r.Context(), token)
+	})
+}
+
+// Middleware 验证本服务签发的访问令牌
+func (m *JWTManager) Middleware() func(next http.Handler) http.Handler {
+	return Middleware(func(_ *http.Request, token string) (*StandardClaims, error) {
+		return m.ValidateToken(token)
+	})
+}
@@ -0,0 +1,108 @@
+package jwtkit
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"helay.net/go/utils/v3/safe/cachemgr"
+	"helay.net/go/utils/v3/tools"
+)
+
+var (
+	ErrTokenType          = errors.New("令牌类型错误")
+	ErrRefreshTokenReused = errors.New("刷新令牌已失效或被重复使用，会话已吊销")
+)
+
+// SetRefreshStore 设置刷新令牌存储，记录每个会话当前有效的刷新令牌ID，用于重用检测
+// 缓存需要启用过期清理；多实例部署时使用 redis、关系数据库等共享驱动。
+func (m *JWTManager) SetRefreshStore(store cachemgr.Cache[string, string]) {
+	m.refreshStore = store
+}
+
+// GenerateTokenPair 签发新会话的令牌对，claims 中的用户信息会同时写入访问令牌和刷新令牌
+func (m *JWTManager) GenerateTokenPair(claims *StandardClaims) (*TokenPair, error) {
+	if m.refreshStore == nil {
+		return nil, errors.New("未设置刷新令牌存储")
+	}
+	sid, err := m.snowflake.GenerateID()
+	if err != nil {
+		return nil, err
+	}
+	claims.SessionID = tools.Any2string(sid)
+	claims.Timestamp = time.Now()
+	return m.issuePair(claims)
+}
+
+// Refresh 使用刷新令牌换取新的令牌对，旧的刷新令牌立即失效。
+// 同一个刷新令牌第二次使用时视为被盗用，整个会话被吊销，攻击者和用户都需要重新登录。
+// 未设置吊销列表时只能阻止继续刷新，已签发的访问令牌在过期前仍然有效。
+func (m *JWTManager) Refresh(refreshToken string) (*TokenPair, error) {
+	if m.refreshStore == nil {
+		return nil, errors.New("未设置刷新令牌存储")
+	}
+	claims, err := m.parse(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType != TokenRefresh || claims.SessionID == "" {
+		return nil, ErrTokenType
+	}
+	// 只有会话当前的刷新令牌才能换取新令牌，删除成功的调用者获得刷新权，并发重用的请求会失败
+	key := refreshKey(claims.SessionID)
+	if _, ok := m.refreshStore.LoadAndDeleteIf(key, func(current string) bool { return current == claims.ID }); !ok {
+		if m.revocation != nil {
+			if err = m.RevokeSession(claims.SessionID); err != nil {
+				return nil, errors.Join(ErrRefreshTokenReused, err)
+			}
+		} else {
+			m.refreshStore.Delete(key)
+		}
+		return nil, ErrRefreshTokenReused
+	}
+	return m.issuePair(claims)
+}
+
+// issuePair 签发令牌对，并记录会话当前的刷新令牌
+func (m *JWTManager) issuePair(base *StandardClaims) (*TokenPair, error) {
+	now := time.Now()
+	pair := &TokenPair{
+		ExpiresAt:        now.Add(m.config.AccessTTL),
+		RefreshExpiresAt: now.Add(m.config.RefreshTTL),
+	}
+	access, err := m.pairClaims(base, TokenAccess, now, pair.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if pair.AccessToken, err = m.sign(access); err != nil {
+		return nil, err
+	}
+	refresh, err := m.pairClaims(base, TokenRefresh, now, pair.RefreshExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if pair.RefreshToken, err = m.sign(refresh); err != nil {
+		return nil, err
+	}
+	m.refreshStore.Store(refreshKey(base.SessionID), refresh.ID, m.config.RefreshTTL)
+	return pair, nil
+}
+
+// pairClaims 复制用户信息，生成新的 jti 和有效期
+func (m *JWTManager) pairClaims(base *StandardClaims, typ TokenType, now, expiresAt time.Time) (*StandardClaims, error) {
+	jti, err := m.snowflake.GenerateID()
+	if err != nil {
+		return nil, err
+	}
+	c := *base
+	c.TokenType = typ
+	c.ExpiresAt = &expiresAt
+	c.ID = tools.Any2string(jti)
+	c.IssuedAt = jwt.NewNumericDate(now)
+	c.RegisteredClaims.ExpiresAt = jwt.NewNumericDate(expiresAt)
+	return &c, nil
+}
+
+func refreshKey(sessionID string) string {
+	return "jwt:refresh:" + sessionID
+}
@@ -0,0 +1,102 @@
+package jwtkit
+
+import (
+	"errors"
+	"time"
+
+	"helay.net/go/utils/v3/safe/cachemgr"
+)
+
+var ErrTokenRevoked = errors.New("令牌已被吊销")
+
+// RevocationList 吊销列表，记录在过期前失效的令牌ID（jti）和会话ID（sid）
+type RevocationList interface {
+	Revoke(id string, until time.Time) error // 吊销，until 之后记录可以删除，零值表示永久
+	IsRevoked(id string) (bool, error)       // 是否已吊销
+}
+
+// CacheRevocationList 基于 cachemgr.Cache 的吊销列表
+// 缓存需要启用过期清理，记录在令牌过期后自动删除；多实例部署时使用 redis、关系数据库等共享驱动。
+type CacheRevocationList struct {
+	cache  cachemgr.Cache[string, int64]
+	prefix string
+}
+
+// NewCacheRevocationList 创建基于缓存的吊销列表，值为吊销截止时间的 unix 秒
+func NewCacheRevocationList(cache cachemgr.Cache[string, int64]) *CacheRevocationList {
+	return &CacheRevocationList{cache: cache, prefix: "jwt:revoked:"}
+}
+
+func (l *CacheRevocationList) Revoke(id string, until time.Time) error {
+	if until.IsZero() {
+		l.cache.Store(l.prefix+id, 0)
+		return nil
+	}
+	ttl := time.Until(until)
+	if ttl <= 0 {
+		return nil
+	}
+	l.cache.Store(l.prefix+id, until.Unix(), ttl)
+	return nil
+}
+
+func (l *CacheRevocationList) IsRevoked(id string) (bool, error) {
+	until, ok := l.cache.Load(l.prefix + id)
+	if !ok {
+		return false, nil
+	}
+	return until == 0 || until > time.Now().Unix(), nil
+}
+
+// SetRevocationList 设置吊销列表，设置后 ValidateToken 和 Refresh 会检查令牌和会话是否已吊销
+func (m *JWTManager) SetRevocationList(l RevocationList) {
+	m.revocation = l
+}
+
+// Revoke 吊销令牌，令牌过期后吊销记录自动失效
+func (m *JWTManager) Revoke(claims *StandardClaims) error {
+	if m.revocation == nil {
+		return errors.New("未设置吊销列表")
+	}
+	var until time.Time
+	if claims.RegisteredClaims.ExpiresAt != nil {
+		until = claims.RegisteredClaims.ExpiresAt.Time
+	}
+	return m.revocation.Revoke(claims.ID, until)
+}
+
+// RevokeSession 吊销会话，同一个令牌对刷新链上的全部令牌立即失效，用于退出登录
+func (m *JWTManager) RevokeSession(sessionID string) error {
+	if m.revocation == nil {
+		return errors.New("未设置吊销列表")
+	}
+	if m.refreshStore != nil {
+		m.refreshStore.Delete(refreshKey(sessionID))
+	}
+	return m.revocation.Revoke(sessionKey(sessionID), time.Now().Add(m.config.RefreshTTL))
+}
+
+// checkRevoked 检查令牌和所属会话是否已吊销
+func (m *JWTManager) checkRevoked(claims *StandardClaims) error {
+	if m.revocation == nil {
+		return nil
+	}
+	ids := []string{claims.ID}
+	if claims.SessionID != "" {
+		ids = append(ids, sessionKey(claims.SessionID))
+	}
+	for _, id := range ids {
+		revoked, err := m.revocation.IsRevoked(id)
+		if err != nil {
+			return err
+		}
+		if revoked {
+			return ErrTokenRevoked
+		}
+	}
+	return nil
+}
+
+func sessionKey(sessionID string) string {
+	return "sid:" + sessionID
+}
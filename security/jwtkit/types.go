@@ -4,6 +4,8 @@
 // 支持RSA、ECDSA、HMAC
 // 支持手动设置密钥内容，密钥路径
 // 支持自动生成密钥
+// 支持多个密钥按 kid 选择，分阶段轮换，并通过 JWKS 发布公钥
+// 支持刷新令牌、重用检测和令牌吊销
 package jwtkit
 
 import (
@@ -21,6 +23,33 @@ type Options struct {
 	RSA          AsymmetricKeys `json:"rsa" yaml:"rsa" ini:"rsa"`                               // RSA
 	ECDSA        AsymmetricKeys `json:"ecdsa" yaml:"ecdsa" ini:"ecdsa"`                         // ECDSA
 	EdDSA        AsymmetricKeys `json:"eddsa" yaml:"eddsa" ini:"eddsa"`                         // EdDSA
+
+	Keys       []KeyConfig   `json:"keys" yaml:"keys" ini:"keys"`                      // 多密钥配置，设置后忽略上面的单密钥配置
+	AccessTTL  time.Duration `json:"access_ttl" yaml:"access_ttl" ini:"access_ttl"`    // 令牌对中访问令牌的有效期，默认15分钟
+	RefreshTTL time.Duration `json:"refresh_ttl" yaml:"refresh_ttl" ini:"refresh_ttl"` // 令牌对中刷新令牌的有效期，默认7天
+}
+
+// KeyStatus 密钥状态
+type KeyStatus string
+
+const (
+	// KeyPending 待启用，只发布公钥不签名，让验证方提前缓存
+	KeyPending KeyStatus = "pending"
+	// KeyPrimary 当前签名密钥，只能有一个
+	KeyPrimary KeyStatus = "primary"
+	// KeyRetired 已停用，只用于验证轮换前签发的令牌，令牌全部过期后可以删除
+	KeyRetired KeyStatus = "retired"
+)
+
+// KeyConfig 单个密钥配置
+// HMAC 使用 HMAC 中的密钥，其他算法使用 Asymmetric 中的密钥，公钥为空时从私钥推导，
+// 只有公钥的密钥只能用于验证。
+type KeyConfig struct {
+	ID         string         `json:"id" yaml:"id" ini:"id"`                         // kid，为空时非对称密钥使用 RFC 7638 指纹，HMAC 使用密钥摘要
+	Algorithm  Algorithm      `json:"algorithm" yaml:"algorithm" ini:"algorithm"`    // 签名算法，为空时使用 Options.Algorithm
+	Status     KeyStatus      `json:"status" yaml:"status" ini:"status"`             // 密钥状态，默认 primary
+	HMAC       HMAC           `json:"hmac" yaml:"hmac" ini:"hmac"`                   // HMAC
+	Asymmetric AsymmetricKeys `json:"asymmetric" yaml:"asymmetric" ini:"asymmetric"` // RSA、ECDSA、EdDSA
 }
 
 type HMAC struct {
@@ -46,10 +75,28 @@ type AsymmetricKeys struct {
 // jti 唯一标识
 type StandardClaims struct {
 	UserId    dataType.IntString[int64] `json:"user_id"`
-	TenantID  dataType.IntString[int64] `json:"tenant_id"`  // 租户ID
-	Timestamp time.Time                 `json:"timestamp"`  // 登录时间
-	LoginIp   string                    `json:"login_ip"`   // 登录IP
-	ExpiresAt *time.Time                `json:"expires_at"` // 过期时间
-	Extras    map[string]any            `json:"extras"`     // 扩展字段
+	TenantID  dataType.IntString[int64] `json:"tenant_id"`     // 租户ID
+	Timestamp time.Time                 `json:"timestamp"`     // 登录时间
+	LoginIp   string                    `json:"login_ip"`      // 登录IP
+	ExpiresAt *time.Time                `json:"expires_at"`    // 过期时间
+	Extras    map[string]any            `json:"extras"`        // 扩展字段
+	TokenType TokenType                 `json:"typ,omitempty"` // 令牌类型，GenerateToken 签发的令牌为空
+	SessionID string                    `json:"sid,omitempty"` // 会话ID，同一个令牌对刷新链共用，用于重用检测和整体吊销
 	jwt.RegisteredClaims
 }
+
+// TokenType 令牌类型
+type TokenType string
+
+const (
+	TokenAccess  TokenType = "access"  // 访问令牌
+	TokenRefresh TokenType = "refresh" // 刷新令牌
+)
+
+// TokenPair 令牌对
+type TokenPair struct {
+	AccessToken      string    `json:"access_token"`
+	RefreshToken     string    `json:"refresh_token"`
+	ExpiresAt        time.Time `json:"expires_at"`         // 访问令牌过期时间
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"` // 刷新令牌过期时间
+}
@@ -0,0 +1,329 @@
+package jwtkit
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"helay.net/go/utils/v3/logger/ulogs"
+	"helay.net/go/utils/v3/safe"
+	"helay.net/go/utils/v3/tools"
+)
+
+// VerifierOptions 第三方令牌验证配置
+type VerifierOptions struct {
+	JWKSURL            string        `json:"jwks_url" yaml:"jwks_url" ini:"jwks_url"`                                     // JWKS 地址，通常是身份提供方的 /.well-known/jwks.json
+	JWKSFile           string        `json:"jwks_file" yaml:"jwks_file" ini:"jwks_file"`                                  // 本地 JWKS 文件，设置了 JWKSURL 时忽略
+	Issuer             string        `json:"issuer" yaml:"issuer" ini:"issuer"`                                           // 令牌的 iss 必须与之相同，为空时不校验
+	Audience           []string      `json:"audience" yaml:"audience" ini:"audience"`                                     // 令牌的 aud 至少包含其中一个，为空时不校验
+	Algorithms         []Algorithm   `json:"algorithms" yaml:"algorithms" ini:"algorithms"`                               // 允许的签名算法，默认全部非对称算法，不支持 HMAC 和 none
+	ClockSkew          time.Duration `json:"clock_skew" yaml:"clock_skew" ini:"clock_skew"`                               // 校验 exp、nbf、iat 时允许的时钟偏差，默认1分钟
+	RefreshInterval    time.Duration `json:"refresh_interval" yaml:"refresh_interval" ini:"refresh_interval"`             // JWKS 缓存时间，过期后在后台刷新，默认1小时
+	MinRefreshInterval time.Duration `json:"min_refresh_interval" yaml:"min_refresh_interval" ini:"min_refresh_interval"` // 遇到未知 kid 时立即刷新的最小间隔，默认1分钟
+	Timeout            time.Duration `json:"timeout" yaml:"timeout" ini:"timeout"`                                        // 获取 JWKS 的超时时间，默认10秒
+}
+
+// verifyKey JWKS 中解析出的公钥
+type verifyKey struct {
+	kid string
+	alg string
+	key any
+}
+
+// Verifier 使用身份提供方发布的 JWKS 验证第三方签发的令牌，适用于 OIDC 资源服务器
+type Verifier struct {
+	opt    VerifierOptions
+	client *http.Client
+	parser *jwt.Parser
+
+	mu      sync.RWMutex
+	keys    []verifyKey
+	fetched time.Time // 最近一次成功获取的时间
+	tried   time.Time // 最近一次尝试获取的时间
+	flight  safe.SingleFlight[string, []verifyKey]
+}
+
+var asymmetricAlgorithms = []Algorithm{RS256, RS384, RS512, PS256, PS384, PS512, ES256, ES384, ES512, EdDSA}
+
+// NewVerifier 创建验证器，会立即获取一次 JWKS，失败时返回错误
+func NewVerifier(ctx context.Context, opt VerifierOptions) (*Verifier, error) {
+	if opt.JWKSURL == "" && opt.JWKSFile == "" {
+		return nil, errors.New("未配置JWKS地址或文件")
+	}
+	if len(opt.Algorithms) == 0 {
+		opt.Algorithms = asymmetricAlgorithms
+	}
+	methods := make([]string, 0, len(opt.Algorithms))
+	for _, alg := range opt.Algorithms {
+		if !slices.Contains(asymmetricAlgorithms, alg) {
+			return nil, fmt.Errorf("%w %s，只支持非对称算法", ErrUnsupportedAlgorithm, alg)
+		}
+		methods = append(methods, string(alg))
+	}
+	opt.ClockSkew = tools.AutoTimeDuration(opt.ClockSkew, time.Second, time.Minute)
+	opt.RefreshInterval = tools.AutoTimeDuration(opt.RefreshInterval, time.Second, time.Hour)
+	opt.MinRefreshInterval = tools.AutoTimeDuration(opt.MinRefreshInterval, time.Second, time.Minute)
+	opt.Timeout = tools.AutoTimeDuration(opt.Timeout, time.Second, 10*time.Second)
+
+	parserOpts := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithLeeway(opt.ClockSkew),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+	if opt.Issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(opt.Issuer))
+	}
+	if len(opt.Audience) > 0 {
+		parserOpts = append(parserOpts, jwt.WithAudience(opt.Audience...))
+	}
+	v := &Verifier{
+		opt:    opt,
+		client: &http.Client{Timeout: opt.Timeout},
+		parser: jwt.NewParser(parserOpts...),
+	}
+	if err := v.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// SetHTTPClient 设置获取 JWKS 使用的 http 客户端，用于代理、自定义证书等场景
+func (v *Verifier) SetHTTPClient(client *http.Client) {
+	v.client = client
+}
+
+// Refresh 立即重新获取 JWKS，失败时保留原有的公钥
+func (v *Verifier) Refresh(ctx context.Context) error {
+	_, _, err := v.flight.Do("", func() ([]verifyKey, error) {
+		return v.fetch(ctx)
+	})
+	return err
+}
+
+// fetch 获取并解析 JWKS，成功后替换公钥
+func (v *Verifier) fetch(ctx context.Context) ([]verifyKey, error) {
+	v.mu.Lock()
+	v.tried = time.Now()
+	v.mu.Unlock()
+
+	data, err := v.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var set JWKSet
+	if err = json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("JWKS解析失败 %w", err)
+	}
+	keys := make([]verifyKey, 0, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		pub, err := jwk.PublicKey()
+		if err != nil {
+			// 身份提供方可能发布了不支持的密钥类型，跳过即可
+			ulogs.Warnf("JWKS 跳过密钥 %v", err)
+			continue
+		}
+		keys = append(keys, verifyKey{kid: jwk.Kid, alg: jwk.Alg, key: pub})
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("JWKS中没有可用的签名公钥")
+	}
+	v.mu.Lock()
+	v.keys = keys
+	v.fetched = time.Now()
+	v.mu.Unlock()
+	return keys, nil
+}
+
+func (v *Verifier) read(ctx context.Context) ([]byte, error) {
+	if v.opt.JWKSURL == "" {
+		data, err := os.ReadFile(tools.Fileabs(v.opt.JWKSFile))
+		if err != nil {
+			return nil, fmt.Errorf("JWKS文件读取失败 %w", err)
+		}
+		return data, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, v.opt.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.opt.JWKSURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("JWKS获取失败 %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("JWKS获取失败 %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+// candidates 按 kid 和算法筛选公钥，JWKS 缓存过期时在后台刷新，
+// kid 不存在时可能是身份提供方刚轮换了密钥，间隔允许时同步刷新一次
+func (v *Verifier) candidates(ctx context.Context, kid, alg string) []verifyKey {
+	v.mu.RLock()
+	keys, fetched, tried := v.keys, v.fetched, v.tried
+	v.mu.RUnlock()
+
+	if time.Since(fetched) > v.opt.RefreshInterval && time.Since(tried) > v.opt.MinRefreshInterval {
+		v.flight.Go("", func() ([]verifyKey, error) {
+			keys, err := v.fetch(context.WithoutCancel(ctx))
+			if err != nil {
+				ulogs.Errorf("JWKS后台刷新失败 %v", err)
+			}
+			return keys, err
+		})
+	}
+	matched := matchKeys(keys, kid, alg)
+	if len(matched) == 0 && kid != "" && time.Since(tried) > v.opt.MinRefreshInterval {
+		if refreshed, _, err := v.flight.Do("", func() ([]verifyKey, error) { return v.fetch(ctx) }); err == nil {
+			matched = matchKeys(refreshed, kid, alg)
+		}
+	}
+	return matched
+}
+
+// matchKeys 筛选 kid 相同且与算法匹配的公钥，kid 为空时只按算法筛选
+func matchKeys(keys []verifyKey, kid, alg string) []verifyKey {
+	var matched []verifyKey
+	for _, k := range keys {
+		if kid != "" && k.kid != kid {
+			continue
+		}
+		if k.alg != "" && k.alg != alg {
+			continue
+		}
+		if !keyFitsAlg(k, alg) {
+			continue
+		}
+		matched = append(matched, k)
+	}
+	return matched
+}
+
+// keyFitsAlg 判断公钥类型与算法是否匹配，ES 系列还要求曲线匹配
+func keyFitsAlg(k verifyKey, alg string) bool {
+	switch key := k.key.(type) {
+	case *rsa.PublicKey:
+		return strings.HasPrefix(alg, "RS") || strings.HasPrefix(alg, "PS")
+	case *ecdsa.PublicKey:
+		switch Algorithm(alg) {
+		case ES256:
+			return key.Curve == elliptic.P256()
+		case ES384:
+			return key.Curve == elliptic.P384()
+		case ES512:
+			return key.Curve == elliptic.P521()
+		}
+	case ed25519.PublicKey:
+		return Algorithm(alg) == EdDSA
+	}
+	return false
+}
+
+// Verify 验证令牌的签名、exp、nbf、iat、iss、aud，返回的 claims 中非标准字段放在 Extras 中
+func (v *Verifier) Verify(ctx context.Context, tokenString string) (*StandardClaims, error) {
+	mc := jwt.MapClaims{}
+	_, err := v.parser.ParseWithClaims(tokenString, mc, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		keys := v.candidates(ctx, kid, token.Method.Alg())
+		if len(keys) == 0 {
+			return nil, fmt.Errorf("%w kid=%s alg=%s", ErrKeyNotFound, kid, token.Method.Alg())
+		}
+		set := jwt.VerificationKeySet{Keys: make([]jwt.VerificationKey, 0, len(keys))}
+		for _, k := range keys {
+			set.Keys = append(set.Keys, k.key)
+		}
+		return set, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapClaims(mc)
+}
+
+// registeredClaimNames jwt 注册字段
+var registeredClaimNames = []string{"iss", "sub", "aud", "exp", "nbf", "iat", "jti"}
+
+// mapClaims 将第三方令牌的 claims 转换为 StandardClaims
+// 注册字段写入 RegisteredClaims；与 StandardClaims 同名且类型兼容的字段写入对应字段；
+// 其余字段以及类型不兼容的同名字段写入 Extras，令牌中的 extras 对象会合并到 Extras。
+func mapClaims(mc jwt.MapClaims) (*StandardClaims, error) {
+	claims := &StandardClaims{Extras: make(map[string]any)}
+	registered := make(map[string]any, len(registeredClaimNames))
+	for _, name := range registeredClaimNames {
+		if val, ok := mc[name]; ok {
+			registered[name] = val
+		}
+	}
+	b, err := json.Marshal(registered)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(b, &claims.RegisteredClaims); err != nil {
+		return nil, fmt.Errorf("令牌注册字段解析失败 %w", err)
+	}
+	fields := map[string]any{
+		"user_id":    &claims.UserId,
+		"tenant_id":  &claims.TenantID,
+		"timestamp":  &claims.Timestamp,
+		"login_ip":   &claims.LoginIp,
+		"expires_at": &claims.ExpiresAt,
+		"typ":        &claims.TokenType,
+		"sid":        &claims.SessionID,
+	}
+	for name, val := range mc {
+		if slices.Contains(registeredClaimNames, name) {
+			continue
+		}
+		if name == "extras" {
+			if extras, ok := val.(map[string]any); ok {
+				for k, x := range extras {
+					claims.Extras[k] = x
+				}
+				continue
+			}
+		}
+		if ptr, ok := fields[name]; ok {
+			if b, err := json.Marshal(val); err == nil && json.Unmarshal(b, ptr) == nil {
+				continue
+			}
+		}
+		claims.Extras[name] = val
+	}
+	return claims, nil
+}
+
+// Scopes 读取 OAuth2 的 scope（空格分隔）或 scp（数组）字段
+func (c *StandardClaims) Scopes() []string {
+	if s, ok := c.Extras["scope"].(string); ok {
+		return strings.Fields(s)
+	}
+	if list, ok := c.Extras["scp"].([]any); ok {
+		scopes := make([]string, 0, len(list))
+		for _, item := range list {
+			scopes = append(scopes, tools.Any2string(item))
+		}
+		return scopes
+	}
+	return nil
+}
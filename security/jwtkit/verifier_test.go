@@ -0,0 +1,219 @@
+package jwtkit
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// testIdP 模拟身份提供方，通过 httptest 发布 JWKS
+type testIdP struct {
+	mu   sync.Mutex
+	keys map[string]*signKey
+	srv  *httptest.Server
+}
+
+func newTestIdP(t *testing.T) *testIdP {
+	t.Helper()
+	idp := &testIdP{keys: make(map[string]*signKey)}
+	idp.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		idp.mu.Lock()
+		defer idp.mu.Unlock()
+		set := JWKSet{}
+		for _, k := range idp.keys {
+			jwk, err := newJWK(k)
+			if err != nil {
+				t.Error(err)
+			}
+			set.Keys = append(set.Keys, jwk)
+		}
+		_ = json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(idp.srv.Close)
+	return idp
+}
+
+func (idp *testIdP) addKey(t *testing.T, kid string, alg Algorithm) {
+	t.Helper()
+	var (
+		priv crypto.Signer
+		err  error
+	)
+	switch alg {
+	case RS256, PS256:
+		priv, err = rsa.GenerateKey(rand.Reader, 2048)
+	case ES256:
+		priv, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case ES384:
+		priv, err = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case EdDSA:
+		_, priv, err = ed25519.GenerateKey(rand.Reader)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	method, _ := signingMethod(alg)
+	idp.mu.Lock()
+	idp.keys[kid] = &signKey{id: kid, alg: alg, method: method, sign: priv, verify: priv.Public()}
+	idp.mu.Unlock()
+}
+
+func (idp *testIdP) sign(t *testing.T, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	idp.mu.Lock()
+	k := idp.keys[kid]
+	idp.mu.Unlock()
+	token := jwt.NewWithClaims(k.method, claims)
+	token.Header["kid"] = kid
+	s, err := token.SignedString(k.sign)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func validClaims() jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":   "https://idp.example.com",
+		"aud":   []string{"api"},
+		"sub":   "u-1",
+		"exp":   now.Add(time.Hour).Unix(),
+		"iat":   now.Unix(),
+		"scope": "read write",
+		"email": "a@example.com",
+	}
+}
+
+func newTestVerifier(t *testing.T, idp *testIdP) *Verifier {
+	t.Helper()
+	v, err := NewVerifier(context.Background(), VerifierOptions{
+		JWKSURL:            idp.srv.URL,
+		Issuer:             "https://idp.example.com",
+		Audience:           []string{"api"},
+		ClockSkew:          30 * time.Second,
+		MinRefreshInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestVerifierAlgorithms(t *testing.T) {
+	idp := newTestIdP(t)
+	algs := map[string]Algorithm{"rsa": RS256, "pss": PS256, "ec256": ES256, "ec384": ES384, "ed": EdDSA}
+	for kid, alg := range algs {
+		idp.addKey(t, kid, alg)
+	}
+	v := newTestVerifier(t, idp)
+	for kid := range algs {
+		claims, err := v.Verify(context.Background(), idp.sign(t, kid, validClaims()))
+		if err != nil {
+			t.Fatalf("%s: %v", kid, err)
+		}
+		if claims.Subject != "u-1" || claims.Extras["email"] != "a@example.com" {
+			t.Fatalf("%s: claims = %+v", kid, claims)
+		}
+	}
+}
+
+func TestVerifierClaims(t *testing.T) {
+	idp := newTestIdP(t)
+	idp.addKey(t, "k1", ES256)
+	v := newTestVerifier(t, idp)
+	ctx := context.Background()
+
+	cases := map[string]func(c jwt.MapClaims){
+		"wrong issuer":   func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" },
+		"wrong audience": func(c jwt.MapClaims) { c["aud"] = "other" },
+		"expired":        func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() },
+		"not before":     func(c jwt.MapClaims) { c["nbf"] = time.Now().Add(time.Minute).Unix() },
+		"missing exp":    func(c jwt.MapClaims) { delete(c, "exp") },
+	}
+	for name, modify := range cases {
+		c := validClaims()
+		modify(c)
+		if _, err := v.Verify(ctx, idp.sign(t, "k1", c)); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+
+	// 时钟偏差内的 nbf 可以通过
+	c := validClaims()
+	c["nbf"] = time.Now().Add(10 * time.Second).Unix()
+	c["login_ip"] = "10.0.0.1"
+	c["user_id"] = "not-a-number"
+	claims, err := v.Verify(ctx, idp.sign(t, "k1", c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.LoginIp != "10.0.0.1" || claims.Extras["user_id"] != "not-a-number" {
+		t.Fatalf("claims = %+v", claims)
+	}
+	if scopes := claims.Scopes(); len(scopes) != 2 || scopes[1] != "write" {
+		t.Fatalf("scopes = %v", scopes)
+	}
+}
+
+func TestVerifierRotation(t *testing.T) {
+	idp := newTestIdP(t)
+	idp.addKey(t, "old", RS256)
+	v := newTestVerifier(t, idp)
+
+	// 身份提供方轮换后，未知 kid 触发刷新
+	time.Sleep(2 * time.Millisecond)
+	idp.addKey(t, "new", RS256)
+	if _, err := v.Verify(context.Background(), idp.sign(t, "new", validClaims())); err != nil {
+		t.Fatal(err)
+	}
+	// 算法与密钥类型不匹配
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims())
+	token.Header["kid"] = "new"
+	s, _ := token.SignedString([]byte("secret"))
+	if _, err := v.Verify(context.Background(), s); err == nil {
+		t.Fatal("expected error for HS256")
+	}
+}
+
+func TestVerifierMiddleware(t *testing.T) {
+	idp := newTestIdP(t)
+	idp.addKey(t, "k1", EdDSA)
+	v := newTestVerifier(t, idp)
+	h := v.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := ClaimsFromContext(r.Context())
+		if !ok {
+			t.Error("claims not in context")
+			return
+		}
+		_, _ = w.Write([]byte(claims.Subject))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+idp.sign(t, "k1", validClaims()))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || rec.Body.String() != "u-1" {
+		t.Fatalf("code = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer invalid")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") == "" {
+		t.Fatalf("code = %d", rec.Code)
+	}
+}
@@ -2,10 +2,13 @@ package streamdecode_json
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/csv"
 	"fmt"
 	"io"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/xuri/excelize/v2"
 	"helay.net/go/utils/v3/dataType/customWriter"
@@ -38,7 +41,7 @@ func (i *Import) importExcelWithHandlerStream(handler JSONHandler) (int64, error
 		return counter.TotalSize, fmt.Errorf("excel文件打开失败：%s", err.Error())
 	}
 	ulogs.Debugf("excel文件打开完成 耗时 %.2f秒", time.Since(start).Seconds())
-	rows, err := excel.Rows(excel.GetSheetName(0))
+	rows, err := excel.Rows(tools.Ternary(i.SheetName == "", excel.GetSheetName(0), i.SheetName))
 	if err != nil {
 		return counter.TotalSize, fmt.Errorf("sheet读取失败：%s", err.Error())
 	}
@@ -86,7 +89,7 @@ func (i *Import) importExcelWithHandler(handler JSONHandler) (int64, error) {
 	if err != nil {
 		return counter.TotalSize, fmt.Errorf("excel文件打开失败：%s", err.Error())
 	}
-	rows, err := excel.GetRows(excel.GetSheetName(0))
+	rows, err := excel.GetRows(tools.Ternary(i.SheetName == "", excel.GetSheetName(0), i.SheetName))
 	if err != nil {
 		return counter.TotalSize, fmt.Errorf("sheet读取失败：%s", err.Error())
 	}
@@ -112,32 +115,65 @@ func (i *Import) importExcelWithHandler(handler JSONHandler) (int64, error) {
 	return counter.TotalSize, nil
 }
 
+// utf8BOM excel 等工具导出 csv 时在文件开头写入的 BOM
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// importCsvWithHandler 按 RFC 4180 读取 csv，支持引号包裹的字段（字段中可以包含分隔符、换行、引号），
+// 开头的 BOM 会被去掉。行号按记录计算，空行也计入行号，引号中换行的记录只算一行
 func (i *Import) importCsvWithHandler(handler JSONHandler) (int64, error) {
 	if err := i.valid(); err != nil {
 		return 0, err
 	}
+	i.Sep = tools.Ternary(i.Sep == "", ",", i.Sep)
+	comma, size := utf8.DecodeRuneInString(i.Sep)
+	if size != len(i.Sep) {
+		return 0, fmt.Errorf("csv 分割符只能是一个字符：%q", i.Sep)
+	}
 	counter := &customWriter.SizeCounter{}
-	teeReader := io.TeeReader(i.rd, counter)
+	br := bufio.NewReader(io.TeeReader(i.rd, counter))
+	if b, _ := br.Peek(len(utf8BOM)); bytes.Equal(b, utf8BOM) {
+		_, _ = br.Discard(len(utf8BOM))
+	}
+	r := csv.NewReader(br)
+	r.Comma = comma
+	r.LazyQuotes = true
+	r.FieldsPerRecord = -1
 	var (
-		scanner   = bufio.NewScanner(teeReader)
 		idx       int
-		fieldRows []string
+		lastLine  int // 上一条记录结束的行
+		fieldRows = i.fields
 	)
-	i.Sep = tools.Ternary(i.Sep == "", ",", i.Sep)
-	for scanner.Scan() {
+	emit := func(record []string) error {
 		idx++
-		line := scanner.Text()
-		lineRows := strings.Split(line, i.Sep)
 		if idx == i.FieldRow {
-			fieldRows = lineRows
-			continue
+			fieldRows = record
+			return nil
 		}
 		if idx < i.DataRow {
-			continue
+			return nil
 		}
-		if err := handler(i.ctx, tools.Slice2MapWithHeader(lineRows, fieldRows)); err != nil {
-			return 0, err
+		return handler(i.ctx, tools.Slice2MapWithHeader(record, fieldRows))
+	}
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return counter.TotalSize, fmt.Errorf("csv读取失败：%s", err.Error())
+		}
+		// csv.Reader 会跳过空行，这里补上，保持行号与文件一致
+		line, _ := r.FieldPos(0)
+		for ; lastLine+1 < line; lastLine++ {
+			if err = emit(nil); err != nil {
+				return counter.TotalSize, err
+			}
+		}
+		if err = emit(record); err != nil {
+			return counter.TotalSize, err
 		}
+		lastLine, _ = r.FieldPos(len(record) - 1)
+		lastLine += strings.Count(record[len(record)-1], "\n")
 	}
 	return counter.TotalSize, nil
 }
@@ -0,0 +1,62 @@
+package streamdecode_json
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"helay.net/go/utils/v3/dataType"
+)
+
+func TestImportCsv(t *testing.T) {
+	data := "\xEF\xBB\xBFname;note\n" +
+		"a;\"x;y\"\n" +
+		"\n" +
+		"b;\"line1\nline2 \"\"quoted\"\"\"\n" +
+		"c;bare\"quote\n"
+	im := New(context.Background(), dataType.CSV, strings.NewReader(data))
+	im.SetFieldRow(1)
+	im.SetDataRow(2)
+	im.SetSep(";")
+	var rows []map[string]any
+	size, err := im.ImportWithHandler(func(_ context.Context, obj map[string]any) error {
+		rows = append(rows, obj)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != int64(len(data)) {
+		t.Fatalf("size = %d, want %d", size, len(data))
+	}
+	// 空行保留，保持行号与文件一致
+	want := []map[string]any{
+		{"name": "a", "note": "x;y"},
+		{},
+		{"name": "b", "note": "line1\nline2 \"quoted\""},
+		{"name": "c", "note": "bare\"quote"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("rows = %v", rows)
+	}
+	for i := range want {
+		if len(rows[i]) != len(want[i]) {
+			t.Fatalf("row %d = %v, want %v", i, rows[i], want[i])
+		}
+		for k, v := range want[i] {
+			if rows[i][k] != v {
+				t.Fatalf("row %d = %v, want %v", i, rows[i], want[i])
+			}
+		}
+	}
+}
+
+func TestImportCsvInvalidSep(t *testing.T) {
+	im := New(context.Background(), dataType.CSV, strings.NewReader("a,b\n1,2\n"))
+	im.SetFieldRow(1)
+	im.SetDataRow(2)
+	im.SetSep("||")
+	if _, err := im.ImportWithHandler(func(context.Context, map[string]any) error { return nil }); err == nil {
+		t.Fatal("multi-character separator accepted")
+	}
+}
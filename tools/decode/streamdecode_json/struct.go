@@ -11,14 +11,15 @@ import (
 type JSONHandler func(ctx context.Context, obj map[string]interface{}) error
 
 type Import struct {
-	fileType dataType.ContentType
-	ctx      context.Context
-	rd       io.Reader
-	bigLine  bool     // 是否启用大行模式
-	FieldRow int      `json:"field_row"` // 字段所在行
-	DataRow  int      `json:"data_row"`  // 数据开始行
-	Sep      string   `json:"sep"`       // csv 分割符
-	fields   []string // 字段清单
+	fileType  dataType.ContentType
+	ctx       context.Context
+	rd        io.Reader
+	bigLine   bool     // 是否启用大行模式
+	FieldRow  int      `json:"field_row"`  // 字段所在行
+	DataRow   int      `json:"data_row"`   // 数据开始行
+	Sep       string   `json:"sep"`        // csv 分割符，只能是一个字符
+	SheetName string   `json:"sheet_name"` // excel 读取的sheet，默认第一个
+	fields    []string // 字段清单
 }
 
 func New(ctx context.Context, ft dataType.ContentType, rd io.Reader) *Import {
@@ -45,6 +46,10 @@ func (i *Import) SetSep(sep string) {
 	i.Sep = sep
 }
 
+func (i *Import) SetSheetName(sheetName string) {
+	i.SheetName = sheetName
+}
+
 func (i *Import) SetBigLine(bigLine bool) {
 	i.bigLine = bigLine
 }
@@ -33,6 +33,7 @@ func (w worker) start() {
 }
 
 // StartWorker 定义开始工作结构体
+// 需要获取任务结果、超时控制或优雅关闭时使用 workerGeneric.Pool
 type StartWorker struct {
 	// 最大运行数
 	MaxSize    int `ini:"max_size"`
@@ -0,0 +1,332 @@
+package workerGeneric
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrPoolClosed = errors.New("工作池已关闭")
+	ErrQueueFull  = errors.New("工作池队列已满")
+	ErrDiscarded  = errors.New("任务在队列中被丢弃")
+	ErrPanic      = errors.New("任务执行异常")
+)
+
+// Policy 队列满时的处理策略
+type Policy int
+
+const (
+	PolicyBlock         Policy = iota // 阻塞等待，直到队列有空位或提交的 ctx 结束
+	PolicyReject                      // 直接返回 ErrQueueFull
+	PolicyDiscardOldest               // 丢弃队列中最早的任务，被丢弃任务的结果为 ErrDiscarded
+)
+
+// PoolConfig 工作池配置
+type PoolConfig struct {
+	Size       int           `ini:"size" yaml:"size" json:"size"`                      // worker 数量，默认为 CPU 核数
+	QueueSize  int           `ini:"queue_size" yaml:"queue_size" json:"queue_size"`    // 队列长度，0 表示没有空闲 worker 时即视为队列已满
+	Policy     Policy        `ini:"policy" yaml:"policy" json:"policy"`                // 队列满时的处理策略
+	JobTimeout time.Duration `ini:"job_timeout" yaml:"job_timeout" json:"job_timeout"` // 任务默认执行超时，从开始执行时计算，0 表示不限制
+}
+
+// Stats 工作池统计
+type Stats struct {
+	Workers   int   // 当前 worker 数量
+	Queued    int64 // 排队中的任务数
+	Running   int64 // 执行中的任务数
+	Completed int64 // 执行成功的任务数
+	Failed    int64 // 执行失败的任务数，包括返回错误、panic、超时和被丢弃
+	Rejected  int64 // 被拒绝提交的任务数
+}
+
+// SubmitOption 任务配置
+type SubmitOption func(*submitOptions)
+
+type submitOptions struct {
+	timeout  time.Duration
+	deadline time.Time
+}
+
+// WithTimeout 设置任务执行超时，从开始执行时计算，覆盖 PoolConfig.JobTimeout
+func WithTimeout(d time.Duration) SubmitOption {
+	return func(o *submitOptions) {
+		o.timeout = d
+	}
+}
+
+// WithDeadline 设置任务截止时间，从提交时开始生效，排队超过截止时间的任务不会执行
+func WithDeadline(t time.Time) SubmitOption {
+	return func(o *submitOptions) {
+		o.deadline = t
+	}
+}
+
+type task[T any] struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	timeout time.Duration
+	fn      func(ctx context.Context) (T, error)
+	future  *Future[T]
+}
+
+// Pool 带返回值的工作池
+//
+//	p := workerGeneric.NewPool[int](workerGeneric.PoolConfig{Size: 4, QueueSize: 100})
+//	f, err := p.Submit(ctx, func(ctx context.Context) (int, error) { return 1, nil })
+//	v, err := f.Wait()
+//	_ = p.Shutdown(ctx)
+type Pool[T any] struct {
+	cfg     PoolConfig
+	queue   chan *task[T]
+	ctx     context.Context // Shutdown 超时后取消，所有任务的 ctx 都会被取消
+	cancel  context.CancelFunc
+	closing chan struct{} // 停止接收任务
+	drain   chan struct{} // 所有提交都已结束，worker 处理完队列后退出
+
+	mu      sync.Mutex
+	closed  bool
+	size    int           // 目标 worker 数量
+	workers int           // 当前 worker 数量
+	wake    chan struct{} // 调整大小时关闭，唤醒空闲 worker 检查是否需要退出
+	submits sync.WaitGroup
+	wg      sync.WaitGroup
+
+	queued, running, completed, failed, rejected atomic.Int64
+}
+
+// NewPool 创建工作池
+func NewPool[T any](cfg PoolConfig) *Pool[T] {
+	if cfg.Size <= 0 {
+		cfg.Size = runtime.NumCPU()
+	}
+	cfg.QueueSize = max(cfg.QueueSize, 0)
+	p := &Pool[T]{
+		cfg:     cfg,
+		queue:   make(chan *task[T], cfg.QueueSize),
+		closing: make(chan struct{}),
+		drain:   make(chan struct{}),
+		wake:    make(chan struct{}),
+	}
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+	p.Resize(cfg.Size)
+	return p
+}
+
+// Submit 提交任务，任务的 ctx 派生自提交的 ctx
+// 返回错误时任务没有进入队列，否则任务的结果通过 Future 获取。
+func (p *Pool[T]) Submit(ctx context.Context, fn func(ctx context.Context) (T, error), opts ...SubmitOption) (*Future[T], error) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil, ErrPoolClosed
+	}
+	p.submits.Add(1)
+	p.mu.Unlock()
+	defer p.submits.Done()
+
+	o := submitOptions{timeout: p.cfg.JobTimeout}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	t := &task[T]{timeout: o.timeout, fn: fn, future: newFuture[T]()}
+	if o.deadline.IsZero() {
+		t.ctx, t.cancel = context.WithCancel(ctx)
+	} else {
+		t.ctx, t.cancel = context.WithDeadline(ctx, o.deadline)
+	}
+	if err := p.enqueue(ctx, t); err != nil {
+		t.cancel()
+		p.rejected.Add(1)
+		return nil, err
+	}
+	return t.future, nil
+}
+
+func (p *Pool[T]) enqueue(ctx context.Context, t *task[T]) error {
+	select {
+	case p.queue <- t:
+		p.queued.Add(1)
+		return nil
+	default:
+	}
+	policy := p.cfg.Policy
+	if policy == PolicyDiscardOldest && p.cfg.QueueSize == 0 {
+		policy = PolicyBlock // 没有队列时无法丢弃，按阻塞处理
+	}
+	switch policy {
+	case PolicyReject:
+		return ErrQueueFull
+	case PolicyDiscardOldest:
+		for {
+			select {
+			case p.queue <- t:
+				p.queued.Add(1)
+				return nil
+			case <-p.closing:
+				return ErrPoolClosed
+			default:
+			}
+			select {
+			case old := <-p.queue:
+				p.queued.Add(-1)
+				p.finish(old, *new(T), ErrDiscarded)
+			default:
+			}
+		}
+	default:
+		select {
+		case p.queue <- t:
+			p.queued.Add(1)
+			return nil
+		case <-p.closing:
+			return ErrPoolClosed
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Resize 调整 worker 数量，减少时执行中的任务不受影响，worker 在当前任务结束后退出
+func (p *Pool[T]) Resize(n int) {
+	if n <= 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	p.size = n
+	for ; p.workers < n; p.workers++ {
+		p.wg.Add(1)
+		go p.worker()
+	}
+	close(p.wake)
+	p.wake = make(chan struct{})
+}
+
+// Shutdown 停止接收任务，等待队列中的任务全部执行完成
+// ctx 结束时取消所有任务的 ctx 并返回 ctx 的错误，尚未执行的任务结果为取消错误。
+func (p *Pool[T]) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.closing)
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.submits.Wait()
+		p.mu.Lock()
+		select {
+		case <-p.drain:
+		default:
+			close(p.drain)
+		}
+		p.mu.Unlock()
+		p.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		return ctx.Err()
+	}
+}
+
+// Stats 获取统计数据
+func (p *Pool[T]) Stats() Stats {
+	p.mu.Lock()
+	workers := p.workers
+	p.mu.Unlock()
+	return Stats{
+		Workers:   workers,
+		Queued:    p.queued.Load(),
+		Running:   p.running.Load(),
+		Completed: p.completed.Load(),
+		Failed:    p.failed.Load(),
+		Rejected:  p.rejected.Load(),
+	}
+}
+
+func (p *Pool[T]) worker() {
+	defer p.wg.Done()
+	for {
+		p.mu.Lock()
+		if p.workers > p.size {
+			p.workers--
+			p.mu.Unlock()
+			return
+		}
+		wake := p.wake
+		p.mu.Unlock()
+		select {
+		case t := <-p.queue:
+			p.queued.Add(-1)
+			p.run(t)
+		case <-wake:
+		case <-p.drain:
+			for {
+				select {
+				case t := <-p.queue:
+					p.queued.Add(-1)
+					p.run(t)
+				default:
+					p.mu.Lock()
+					p.workers--
+					p.mu.Unlock()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (p *Pool[T]) run(t *task[T]) {
+	stop := context.AfterFunc(p.ctx, t.cancel)
+	defer stop()
+	if err := t.ctx.Err(); err != nil {
+		p.finish(t, *new(T), err)
+		return
+	}
+	ctx := t.ctx
+	if t.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.timeout)
+		defer cancel()
+	}
+	p.running.Add(1)
+	val, err := call(ctx, t.fn)
+	p.running.Add(-1)
+	p.finish(t, val, err)
+}
+
+func (p *Pool[T]) finish(t *task[T], val T, err error) {
+	t.cancel()
+	if err != nil {
+		p.failed.Add(1)
+	} else {
+		p.completed.Add(1)
+	}
+	t.future.complete(val, err)
+}
+
+// call 执行任务，panic 转换为 ErrPanic
+func call[T any](ctx context.Context, fn func(ctx context.Context) (T, error)) (val T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v\n%s", ErrPanic, r, debug.Stack())
+		}
+	}()
+	return fn(ctx)
+}
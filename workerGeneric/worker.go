@@ -32,6 +32,7 @@ func (w worker[T]) start(ctx context.Context) {
 }
 
 // StartWorker 定义开始工作结构体，支持泛型
+// 需要获取任务结果、超时控制或优雅关闭时使用 Pool
 type StartWorker[T any] struct {
 	// 最大运行数
 	MaxSize    int `ini:"max_size"`